@@ -0,0 +1,54 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
+)
+
+const usage = `agic is a set of offline tools for the App Gateway Ingress Controller.
+
+Usage:
+  agic render --appgw <app-gateway.json> -f <manifests> [--diff]   Print the App Gateway config AGIC generates for the manifests
+  agic version                                                     Print the version
+`
+
+func main() {
+	// Log output is buffered... Calling Flush before exiting guarantees all log output is written.
+	defer glog.Flush()
+
+	_ = flag.CommandLine.Parse([]string{})
+	_ = flag.Lookup("logtostderr").Value.Set("true")
+	if verbosity, ok := os.LookupEnv(environment.VerbosityLevelVarName); ok {
+		_ = flag.Set("v", verbosity)
+	}
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "render":
+		if err := render(os.Args[2:], os.Stdout, os.Stderr); err != nil {
+			glog.Flush()
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+	case "version":
+		version.PrintVersionAndExit()
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,180 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	prohibitedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	rewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
+	crdscheme "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/scheme"
+)
+
+// manifests holds the Kubernetes resources read from disk, split by the clientset serving them.
+type manifests struct {
+	kubeObjects []runtime.Object
+	crdObjects  []runtime.Object
+}
+
+var manifestExtensions = map[string]interface{}{
+	".yaml": nil,
+	".yml":  nil,
+	".json": nil,
+}
+
+func newDeserializer() runtime.Decoder {
+	renderScheme := runtime.NewScheme()
+	_ = scheme.AddToScheme(renderScheme)
+	_ = crdscheme.AddToScheme(renderScheme)
+	return serializer.NewCodecFactory(renderScheme).UniversalDeserializer()
+}
+
+// readManifests reads the resources from the given files and directories; resources without a namespace are put in defaultNamespace.
+func readManifests(paths []string, defaultNamespace string) (*manifests, error) {
+	decoder := newDeserializer()
+	result := &manifests{}
+	for _, path := range paths {
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if err := result.add(decoder, content, defaultNamespace); err != nil {
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// manifestFiles returns the path itself for a file, and the manifests directly in it for a directory.
+func manifestFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if _, ok := manifestExtensions[strings.ToLower(filepath.Ext(entry.Name()))]; ok && !entry.IsDir() {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// add decodes every YAML document or JSON object in content.
+func (m *manifests) add(decoder runtime.Decoder, content []byte, defaultNamespace string) error {
+	reader := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		var raw runtime.RawExtension
+		if err := reader.Decode(&raw); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(raw.Raw)) == 0 || string(raw.Raw) == "null" {
+			continue
+		}
+
+		obj, gvk, err := decoder.Decode(raw.Raw, nil, nil)
+		if err != nil {
+			return err
+		}
+		if err := m.addObject(decoder, obj, defaultNamespace); err != nil {
+			return fmt.Errorf("%s: %v", gvk.String(), err)
+		}
+	}
+}
+
+func (m *manifests) addObject(decoder runtime.Decoder, obj runtime.Object, defaultNamespace string) error {
+	if list, ok := obj.(*v1.List); ok {
+		for _, item := range list.Items {
+			itemObj, _, err := decoder.Decode(item.Raw, nil, nil)
+			if err != nil {
+				return err
+			}
+			if err := m.addObject(decoder, itemObj, defaultNamespace); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch obj.(type) {
+	case *v1.Service, *v1.Endpoints, *v1.Pod, *v1.Secret, *v1.ConfigMap, *networking.Ingress:
+		setDefaultNamespace(obj, defaultNamespace)
+		setDefaultUID(obj)
+		m.kubeObjects = append(m.kubeObjects, obj)
+	case *networking.IngressClass:
+		setDefaultUID(obj)
+		m.kubeObjects = append(m.kubeObjects, obj)
+	case *extensions.Ingress, *networkingv1beta1.Ingress:
+		glog.Warningf("Skipping %s; only %s Ingress is supported", obj.GetObjectKind().GroupVersionKind().String(), networking.SchemeGroupVersion.String())
+	case *prohibitedv1.AzureIngressProhibitedTarget, *rewritev1.AzureIngressRewrite:
+		setDefaultNamespace(obj, defaultNamespace)
+		setDefaultUID(obj)
+		m.crdObjects = append(m.crdObjects, obj)
+	default:
+		glog.Warningf("Skipping %s; it is not used to generate the App Gateway config", obj.GetObjectKind().GroupVersionKind().String())
+	}
+	return nil
+}
+
+// countCRDObjects returns the number of AzureIngressProhibitedTargets and AzureIngressRewrites.
+func (m *manifests) countCRDObjects() (prohibitedTargets int, rewrites int) {
+	for _, obj := range m.crdObjects {
+		switch obj.(type) {
+		case *prohibitedv1.AzureIngressProhibitedTarget:
+			prohibitedTargets++
+		case *rewritev1.AzureIngressRewrite:
+			rewrites++
+		}
+	}
+	return prohibitedTargets, rewrites
+}
+
+// setDefaultUID gives resources, which have none, a UID derived from their name; AGIC orders Ingresses by UID.
+func setDefaultUID(obj runtime.Object) {
+	if accessor, err := meta.Accessor(obj); err == nil && accessor.GetUID() == "" {
+		accessor.SetUID(types.UID(fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind().Kind, accessor.GetNamespace(), accessor.GetName())))
+	}
+}
+
+func setDefaultNamespace(obj runtime.Object, defaultNamespace string) {
+	if accessor, err := meta.Accessor(obj); err == nil && accessor.GetNamespace() == "" {
+		accessor.SetNamespace(defaultNamespace)
+	}
+}
@@ -0,0 +1,46 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// writerRecorder is a record.EventRecorder, which writes the events AGIC would emit as lines to out.
+type writerRecorder struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+// Event fulfills the record.EventRecorder interface.
+func (r *writerRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	name := "<unknown>"
+	if accessor, err := meta.Accessor(object); err == nil {
+		name = accessor.GetName()
+		if accessor.GetNamespace() != "" {
+			name = accessor.GetNamespace() + "/" + name
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, _ = fmt.Fprintf(r.out, "%s %s %s %s: %s\n", eventtype, reason, object.GetObjectKind().GroupVersionKind().Kind, name, message)
+}
+
+// Eventf fulfills the record.EventRecorder interface.
+func (r *writerRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf fulfills the record.EventRecorder interface.
+func (r *writerRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Eventf(object, eventtype, reason, messageFmt, args...)
+}
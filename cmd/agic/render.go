@@ -0,0 +1,192 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/golang/glog"
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/controller"
+	crdfake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gatewayfake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istiofake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// cacheSyncTimeout bounds the wait for the in-memory informers to see the prohibited targets.
+const cacheSyncTimeout = 10 * time.Second
+
+var errMissingAppGw = errors.New("the App Gateway JSON is required; set it with --appgw")
+
+// render reads Kubernetes manifests and an App Gateway config from disk and writes the config AGIC would generate for them.
+func render(args []string, stdout, stderr io.Writer) error {
+	flags := pflag.NewFlagSet("render", pflag.ContinueOnError)
+	flags.SetOutput(stderr)
+	manifestPaths := flags.StringArrayP("filename", "f", nil, "Kubernetes manifest file, or directory of manifests, with Ingress, Service, Endpoints, Pod, Secret, ConfigMap, IngressClass, AzureIngressProhibitedTarget and AzureIngressRewrite resources. Repeatable.")
+	appGwFile := flags.String("appgw", "", "App Gateway JSON in the ARM format, as returned by `az resource show --ids <app-gateway-id>`.")
+	namespace := flags.String("namespace", "default", "Namespace of the resources, which do not specify one.")
+	showDiff := flags.Bool("diff", false, "Print the changes to the App Gateway sub-resources instead of the generated config.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *appGwFile == "" {
+		return errMissingAppGw
+	}
+
+	existingJSON, err := ioutil.ReadFile(*appGwFile)
+	if err != nil {
+		return err
+	}
+	var appGw n.ApplicationGateway
+	if err := appGw.UnmarshalJSON(existingJSON); err != nil {
+		return fmt.Errorf("%s: %v", *appGwFile, err)
+	}
+
+	resources, err := readManifests(*manifestPaths, *namespace)
+	if err != nil {
+		return err
+	}
+
+	env := environment.GetEnv()
+	stopChannel := make(chan struct{})
+	defer close(stopChannel)
+	k8sContext, err := newRenderContext(resources, env, stopChannel)
+	if err != nil {
+		return err
+	}
+
+	recorder := &writerRecorder{out: stderr}
+	c := controller.NewAppGwIngressController(n.ApplicationGatewaysClient{}, renderIdentifier(&appGw, env), k8sContext, recorder, metricstore.NewFakeMetricStore())
+	_, generatedAppGw, err := c.MutateAppGateway(&appGw, env)
+	if err != nil {
+		return err
+	}
+
+	if *showDiff {
+		generatedJSON, err := generatedAppGw.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		diff, err := appgw.NewConfigDiffFromJSON(existingJSON, generatedJSON)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, diff.String())
+		return err
+	}
+
+	redactCertificates(generatedAppGw)
+	generatedJSON, err := generatedAppGw.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	prettyJSON, err := utils.PrettyJSON(generatedJSON, "")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, string(prettyJSON))
+	return err
+}
+
+// newRenderContext loads the resources into fake clientsets and returns a k8scontext.Context with synced caches.
+func newRenderContext(resources *manifests, env environment.EnvVariables, stopChannel chan struct{}) (*k8scontext.Context, error) {
+	kubeClient := fake.NewSimpleClientset(resources.kubeObjects...)
+	kubeClient.Fake.Resources = append(kubeClient.Fake.Resources, &metav1.APIResourceList{
+		GroupVersion: "networking.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "ingresses", Namespaced: true, Kind: "Ingress"},
+			{Name: "ingressclasses", Namespaced: false, Kind: "IngressClass"},
+		},
+	})
+	crdClient := crdfake.NewSimpleClientset(resources.crdObjects...)
+
+	k8sContext := k8scontext.NewContext(kubeClient, crdClient, istiofake.NewSimpleClientset(), gatewayfake.NewSimpleClientset(), []string{}, 0, metricstore.NewFakeMetricStore())
+
+	// Nothing processes the events; drain them so the informers never block.
+	go func() {
+		for {
+			select {
+			case <-k8sContext.Work:
+			case <-stopChannel:
+				return
+			}
+		}
+	}()
+
+	if err := k8sContext.Run(stopChannel, true, env); err != nil {
+		return nil, err
+	}
+
+	// Run does not wait for the CRD informers.
+	prohibitedTargets, rewrites := resources.countCRDObjects()
+	deadline := time.Now().Add(cacheSyncTimeout)
+	for (env.EnableBrownfieldDeployment && len(k8sContext.ListAzureProhibitedTargets()) < prohibitedTargets) ||
+		(env.EnableRewrite && len(k8sContext.Caches.AzureIngressRewrite.List()) < rewrites) {
+		if time.Now().After(deadline) {
+			return nil, k8scontext.ErrorFailedInitialCacheSync
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The informers convert the secrets asynchronously; convert the ones the Ingresses use before building the config.
+	for _, ingress := range k8sContext.ListHTTPIngresses() {
+		for _, tls := range ingress.Spec.TLS {
+			secretKey := utils.GetResourceKey(ingress.Namespace, tls.SecretName)
+			if secret := k8sContext.GetSecret(secretKey); secret != nil {
+				if err := k8sContext.CertificateSecretStore.ConvertSecret(secretKey, secret); err != nil {
+					glog.Errorf("Could not convert secret %s: %v", secretKey, err)
+				}
+			}
+		}
+	}
+
+	return k8sContext, nil
+}
+
+// renderIdentifier identifies the App Gateway by the ID in its JSON, falling back to the APPGW_* environment variables.
+func renderIdentifier(appGw *n.ApplicationGateway, env environment.EnvVariables) appgw.Identifier {
+	identifier := appgw.Identifier{
+		SubscriptionID: env.SubscriptionID,
+		ResourceGroup:  env.ResourceGroupName,
+		AppGwName:      env.AppGwName,
+	}
+	if appGw.ID != nil {
+		subscriptionID, resourceGroup, name := azure.ParseResourceID(*appGw.ID)
+		if name != "" {
+			identifier.SubscriptionID = string(subscriptionID)
+			identifier.ResourceGroup = string(resourceGroup)
+			identifier.AppGwName = string(name)
+		}
+	}
+	return identifier
+}
+
+// redactCertificates keeps the PFX and its password out of the output.
+func redactCertificates(appGw *n.ApplicationGateway) {
+	if appGw.SslCertificates == nil {
+		return
+	}
+	for idx := range *appGw.SslCertificates {
+		if properties := (*appGw.SslCertificates)[idx].ApplicationGatewaySslCertificatePropertiesFormat; properties != nil {
+			properties.Data = nil
+			properties.Password = nil
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+)
+
+var _ = Describe("Test agic render", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	})
+
+	Context("ensure manifests are read", func() {
+		It("should read documents and lists, and skip unrelated kinds", func() {
+			resources, err := readManifests([]string{"testdata/manifests"}, "test-ns")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resources.crdObjects).To(BeEmpty())
+			Expect(resources.kubeObjects).To(HaveLen(4))
+
+			ingress, ok := resources.kubeObjects[0].(*networking.Ingress)
+			Expect(ok).To(BeTrue())
+			Expect(ingress.Namespace).To(Equal("test-ns"))
+			Expect(string(ingress.UID)).To(Equal("Ingress/test-ns/aspnetapp"))
+
+			endpoints, ok := resources.kubeObjects[2].(*v1.Endpoints)
+			Expect(ok).To(BeTrue())
+			Expect(endpoints.Subsets[0].Addresses).To(HaveLen(2))
+		})
+
+		It("should fail on a missing path", func() {
+			_, err := readManifests([]string{"testdata/does-not-exist"}, "default")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ensure the App Gateway config is rendered", func() {
+		It("should require the App Gateway JSON", func() {
+			Expect(render([]string{"-f", "testdata/manifests"}, stdout, stderr)).To(Equal(errMissingAppGw))
+		})
+
+		It("should print the generated config", func() {
+			Expect(render([]string{"--appgw", "testdata/appgw.json", "-f", "testdata/manifests"}, stdout, stderr)).To(Succeed())
+
+			var appGw n.ApplicationGateway
+			Expect(appGw.UnmarshalJSON(stdout.Bytes())).To(Succeed())
+
+			var poolNames []string
+			var addresses []string
+			for _, pool := range *appGw.BackendAddressPools {
+				poolNames = append(poolNames, *pool.Name)
+				for _, address := range *pool.BackendAddresses {
+					addresses = append(addresses, *address.IPAddress)
+				}
+			}
+			Expect(poolNames).To(ConsistOf("defaultaddresspool", "pool-default-aspnetapp-80-bp-8080"))
+			Expect(addresses).To(ConsistOf("10.1.0.4", "10.1.0.5"))
+			Expect(*(*appGw.HTTPListeners)[0].HostName).To(Equal("www.contoso.com"))
+		})
+
+		It("should print the diff against the existing config", func() {
+			Expect(render([]string{"--appgw", "testdata/appgw.json", "-f", "testdata/manifests", "--diff"}, stdout, stderr)).To(Succeed())
+			Expect(stdout.String()).To(ContainSubstring("+ listener fl-www.contoso.com-80\n"))
+			Expect(stdout.String()).To(ContainSubstring("+ pool pool-default-aspnetapp-80-bp-8080\n"))
+			Expect(stdout.String()).ToNot(ContainSubstring("defaultaddresspool"))
+		})
+
+		It("should link the rewrite rule sets of AzureIngressRewrites", func() {
+			Expect(os.Setenv(environment.EnableRewriteVarName, "true")).To(Succeed())
+			defer os.Unsetenv(environment.EnableRewriteVarName)
+			Expect(render([]string{"--appgw", "testdata/appgw.json", "-f", "testdata/manifests", "-f", "testdata/rewrite.yaml"}, stdout, stderr)).To(Succeed())
+
+			var appGw n.ApplicationGateway
+			Expect(appGw.UnmarshalJSON(stdout.Bytes())).To(Succeed())
+			Expect(*appGw.RewriteRuleSets).To(HaveLen(1))
+			Expect(*(*appGw.RewriteRuleSets)[0].Name).To(Equal("rw-default-security-headers"))
+
+			var linked []string
+			for _, rule := range *appGw.RequestRoutingRules {
+				if rule.RewriteRuleSet != nil {
+					linked = append(linked, *rule.Name)
+				}
+			}
+			Expect(linked).To(ConsistOf("rr-www.fabrikam.com-80"))
+		})
+	})
+
+	Context("ensure certificates are redacted", func() {
+		It("should remove the PFX and the password", func() {
+			appGw := n.ApplicationGateway{
+				ApplicationGatewayPropertiesFormat: &n.ApplicationGatewayPropertiesFormat{
+					SslCertificates: &[]n.ApplicationGatewaySslCertificate{{
+						ApplicationGatewaySslCertificatePropertiesFormat: &n.ApplicationGatewaySslCertificatePropertiesFormat{
+							Data:     new(string),
+							Password: new(string),
+						},
+					}},
+				},
+			}
+			redactCertificates(&appGw)
+			certJSON, err := json.Marshal((*appGw.SslCertificates)[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(certJSON)).ToNot(ContainSubstring("data"))
+			Expect(string(certJSON)).ToNot(ContainSubstring("password"))
+		})
+	})
+})
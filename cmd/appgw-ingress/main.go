@@ -19,7 +19,7 @@ import (
 	"syscall"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -33,15 +33,18 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/controller"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
+	gateway "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned"
 	istio "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/health"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
 )
 
@@ -60,6 +63,7 @@ var (
 	resyncPeriod   = flags.Duration("sync-period", resyncPause, "Interval at which to re-list and confirm cloud resources.")
 	versionInfo    = flags.Bool("version", false, "Print version")
 	verbosity      = flags.Int(verbosityFlag, 1, "Set logging verbosity level")
+	dryRun         = flags.Bool("dry-run", false, "Compute the App Gateway config and report the changes without applying them. Also set with APPGW_ENABLE_DRY_RUN.")
 )
 
 func main() {
@@ -70,6 +74,7 @@ func main() {
 	}
 
 	env := environment.GetEnv()
+	env.EnableDryRun = env.EnableDryRun || *dryRun
 
 	verbosity = to.IntPtr(getVerbosity(*verbosity, env.VerbosityLevel))
 	if *versionInfo {
@@ -105,9 +110,12 @@ func main() {
 	kubeClient := kubernetes.NewForConfigOrDie(apiConfig)
 	crdClient := versioned.NewForConfigOrDie(apiConfig)
 	istioCrdClient := istio.NewForConfigOrDie(apiConfig)
+	gatewayCrdClient := gateway.NewForConfigOrDie(apiConfig)
 	recorder := getEventRecorder(kubeClient)
 	namespaces := getNamespacesToWatch(env.WatchNamespace)
-	k8sContext := k8scontext.NewContext(kubeClient, crdClient, istioCrdClient, namespaces, *resyncPeriod)
+	metricStore := metricstore.NewMetricStore()
+	workqueue.SetProvider(metricStore)
+	k8sContext := k8scontext.NewContext(kubeClient, crdClient, istioCrdClient, gatewayCrdClient, namespaces, *resyncPeriod, metricStore)
 
 	// namespace validations
 	if err := validateNamespaces(namespaces, kubeClient); err != nil {
@@ -125,15 +133,28 @@ func main() {
 		glog.Fatal("Got a fatal validation error on existing Application Gateway config. Please update Application Gateway or the controller's helm config. Error:", err)
 	}
 
-	appGwIngressController := controller.NewAppGwIngressController(appGwClient, appGwIdentifier, k8sContext, recorder)
+	appGwIngressController := controller.NewAppGwIngressController(appGwClient, appGwIdentifier, k8sContext, recorder, metricStore)
+
+	if env.EnableLeaderElection {
+		leaseSeconds, _ := strconv.Atoi(env.LeaderElectionLeaseSeconds)
+		lock := controller.NewLeaseLock(kubeClient, recorder, env)
+		if err := appGwIngressController.EnableLeaderElection(lock, time.Duration(leaseSeconds)*time.Second); err != nil {
+			glog.Fatal("Could not enable leader election: ", err)
+		}
+		glog.Infof("Leader election is enabled; %s competes for Lease %s/%s", env.AGICPodName, env.AGICPodNamespace, env.LeaderElectionLeaseName)
+	}
 
 	if err := appGwIngressController.Start(env); err != nil {
 		glog.Fatal("Could not start AGIC: ", err)
 	}
 
 	// Start the Health Probe Server (responding to Kubernetes health probes)
+	healthMux := health.NewHealthMux(appGwIngressController, metricStore)
+	if appGwIngressController.IsDryRun() {
+		healthMux.Handle("/dry-run", appGwIngressController.DryRunHandler())
+	}
 	healthServer := &http.Server{
-		Handler: health.NewHealthMux(appGwIngressController),
+		Handler: healthMux,
 		Addr:    fmt.Sprintf(":%s", env.HealthProbeServicePort),
 	}
 	go func() {
@@ -143,16 +164,17 @@ func main() {
 		}
 	}()
 
-	sigChan := make(chan os.Signal)
+	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
+	appGwIngressController.Stop()
 	glog.Info("Goodbye!")
 }
 
 func validateNamespaces(namespaces []string, kubeClient *kubernetes.Clientset) error {
 	var nonExistent []string
 	for _, ns := range namespaces {
-		if _, err := kubeClient.CoreV1().Namespaces().Get(ns, metav1.GetOptions{}); err != nil {
+		if _, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), ns, metav1.GetOptions{}); err != nil {
 			nonExistent = append(nonExistent, ns)
 		}
 	}
@@ -6,11 +6,13 @@
 package annotations
 
 import (
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/knative/pkg/apis/istio/v1alpha3"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
 )
@@ -26,6 +28,9 @@ const (
 	// CookieBasedAffinityKey defines the key to enable/disable cookie based affinity for client connection.
 	CookieBasedAffinityKey = ApplicationGatewayPrefix + "/cookie-based-affinity"
 
+	// AffinityCookieNameKey defines the key for the name of the affinity cookie, so that apps behind the same host don't share it.
+	AffinityCookieNameKey = ApplicationGatewayPrefix + "/affinity-cookie-name"
+
 	// RequestTimeoutKey defines the request timeout to the backend.
 	RequestTimeoutKey = ApplicationGatewayPrefix + "/request-timeout"
 
@@ -41,13 +46,99 @@ const (
 	// UsePrivateIPKey defines the key to determine whether to use private ip with the ingress.
 	UsePrivateIPKey = ApplicationGatewayPrefix + "/use-private-ip"
 
+	// OverrideFrontendPortKey defines the key for the frontend port of the listeners of the ingress: the HTTPS listeners
+	// when the ingress has TLS, the HTTP listeners otherwise.
+	OverrideFrontendPortKey = ApplicationGatewayPrefix + "/override-frontend-port"
+
+	// SslRedirectFrontendPortKey defines the key for the frontend port of the HTTP listeners, which redirect to the HTTPS
+	// listeners of an ingress with ssl-redirect.
+	SslRedirectFrontendPortKey = ApplicationGatewayPrefix + "/ssl-redirect-frontend-port"
+
 	// BackendProtocolKey defines the key to determine whether to use private ip with the ingress.
 	BackendProtocolKey = ApplicationGatewayPrefix + "/backend-protocol"
 
+	// KeyVaultSecretIDKey defines the key for the Azure Key Vault secret ID of the certificate for the HTTPS listeners of the ingress.
+	// App Gateway fetches the certificate from Key Vault, so it takes precedence over the TLS secrets of the ingress.
+	KeyVaultSecretIDKey = ApplicationGatewayPrefix + "/keyvault-secret-id"
+
+	// AppGwSslCertificateKey defines the key for the name of a certificate installed on App Gateway, which the HTTPS listeners
+	// of the ingress use. AGIC never changes the certificate, and it takes precedence over Key Vault and TLS secrets.
+	AppGwSslCertificateKey = ApplicationGatewayPrefix + "/appgw-ssl-certificate"
+
+	// BackendCACertificateKey defines the key for the Secret or ConfigMap with the CA bundle App Gateway trusts
+	// when it connects to an HTTPS backend. The value is "<name>", "secret/<name>" or "configmap/<name>".
+	BackendCACertificateKey = ApplicationGatewayPrefix + "/backend-ca-certificate"
+
+	// BackendHostNameKey defines the key for the host name App Gateway sends to the backend; used for SNI with HTTPS backends.
+	BackendHostNameKey = ApplicationGatewayPrefix + "/backend-hostname"
+
+	// PickHostNameFromBackendKey defines the key to send the host name of the backend address, e.g. of an App Service, to the backends;
+	// the backend host name takes precedence.
+	PickHostNameFromBackendKey = ApplicationGatewayPrefix + "/pick-hostname-from-backend"
+
+	// WAFPolicyKey defines the key for the resource ID of the Web Application Firewall policy, which App Gateway applies
+	// to the listeners and path rules of the ingress.
+	WAFPolicyKey = ApplicationGatewayPrefix + "/waf-policy"
+
+	// RewriteRuleSetKey defines the key for the name of the AzureIngressRewrite, in the namespace of the ingress,
+	// with the header rewrite rules App Gateway applies to the requests and responses of the ingress.
+	RewriteRuleSetKey = ApplicationGatewayPrefix + "/rewrite-rule-set"
+
+	// RedirectTargetURLKey defines the key for the URL App Gateway redirects the requests of the ingress to, e.g. another site
+	// or the canonical host name of the site. It takes precedence over the SSL redirect.
+	RedirectTargetURLKey = ApplicationGatewayPrefix + "/redirect-target-url"
+
+	// RedirectTypeKey defines the key for the status code of the redirect to the target URL: 301, 302, 303 or 307.
+	RedirectTypeKey = ApplicationGatewayPrefix + "/redirect-type"
+
+	// RedirectIncludePathKey defines the key to enable/disable appending the path of the request to the target URL.
+	RedirectIncludePathKey = ApplicationGatewayPrefix + "/redirect-include-path"
+
+	// RedirectIncludeQueryStringKey defines the key to enable/disable appending the query string of the request to the target URL.
+	RedirectIncludeQueryStringKey = ApplicationGatewayPrefix + "/redirect-include-query-string"
+
+	// ErrorPageURL403Key defines the key for the HTTPS URL of the page App Gateway returns instead of its own 403 Forbidden page
+	// on the listeners of the ingress.
+	ErrorPageURL403Key = ApplicationGatewayPrefix + "/error-page-url-403"
+
+	// ErrorPageURL502Key defines the key for the HTTPS URL of the page App Gateway returns instead of its own 502 Bad Gateway page
+	// on the listeners of the ingress.
+	ErrorPageURL502Key = ApplicationGatewayPrefix + "/error-page-url-502"
+
+	// HealthProbeHostNameKey defines the key for the host name App Gateway sends with the health probes of the backends of the ingress.
+	HealthProbeHostNameKey = ApplicationGatewayPrefix + "/health-probe-hostname"
+
+	// HealthProbePathKey defines the key for the path App Gateway probes on the backends of the ingress.
+	HealthProbePathKey = ApplicationGatewayPrefix + "/health-probe-path"
+
+	// HealthProbeIntervalKey defines the key for the interval in seconds between the health probes.
+	HealthProbeIntervalKey = ApplicationGatewayPrefix + "/health-probe-interval"
+
+	// HealthProbeTimeoutKey defines the key for the timeout in seconds of a health probe.
+	HealthProbeTimeoutKey = ApplicationGatewayPrefix + "/health-probe-timeout"
+
+	// HealthProbeUnhealthyThresholdKey defines the key for the number of failed health probes after which a backend is unhealthy.
+	HealthProbeUnhealthyThresholdKey = ApplicationGatewayPrefix + "/health-probe-unhealthy-threshold"
+
+	// HealthProbeStatusCodesKey defines the key for the comma separated status codes and ranges, like "200-399,401",
+	// of the responses of healthy backends.
+	HealthProbeStatusCodesKey = ApplicationGatewayPrefix + "/health-probe-status-codes"
+
+	// HealthProbeBodyMatchKey defines the key for the string the response body of healthy backends contains.
+	HealthProbeBodyMatchKey = ApplicationGatewayPrefix + "/health-probe-body-match"
+
+	// HealthProbeTCPOnlyKey defines the key to stop inferring the health probes from the readiness and liveness probes of the pods;
+	// App Gateway then considers every backend, which accepts the connection and responds, healthy.
+	HealthProbeTCPOnlyKey = ApplicationGatewayPrefix + "/health-probe-tcp-only"
+
 	// IngressClassKey defines the key of the annotation which needs to be set in order to specify
 	// that this is an ingress resource meant for the application gateway ingress controller.
 	IngressClassKey = "kubernetes.io/ingress.class"
 
+	// IsDefaultIngressClassKey defines the key of the annotation which marks an IngressClass as the default
+	// for Ingress resources which do not specify spec.ingressClassName.
+	IsDefaultIngressClassKey = "ingressclass.kubernetes.io/is-default-class"
+
 	// IstioGatewayKey defines the key of the annotation which needs to be set in order to specify
 	// that this is a gateway meant for the application gateway ingress controller.
 	IstioGatewayKey = "appgw.ingress.istio.io/v1alpha3"
@@ -57,6 +148,61 @@ const (
 	ApplicationGatewayIngressClass = "azure/application-gateway"
 )
 
+// keyVaultSecretIDValidator matches https://<vault>.vault.azure.net/secrets/<name> with an optional /<version>.
+var keyVaultSecretIDValidator = regexp.MustCompile(`^https://[0-9a-zA-Z\-]+\.vault\.[0-9a-zA-Z\-\.]+/secrets/[0-9a-zA-Z\-]+(/[0-9a-zA-Z]+)?/?$`)
+
+// wafPolicyValidator matches the resource ID of an ApplicationGatewayWebApplicationFirewallPolicy.
+var wafPolicyValidator = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/resourceGroups/[^/]+/providers/Microsoft\.Network/ApplicationGatewayWebApplicationFirewallPolicies/[^/]+$`)
+
+// backendHostNameValidator matches a DNS name.
+var backendHostNameValidator = regexp.MustCompile(`^[0-9a-zA-Z]([0-9a-zA-Z\-]*[0-9a-zA-Z])?(\.[0-9a-zA-Z]([0-9a-zA-Z\-]*[0-9a-zA-Z])?)*$`)
+
+// cookieNameValidator matches the token characters a cookie name consists of.
+var cookieNameValidator = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+
+// healthProbeStatusCodeValidator matches a status code or a range of status codes.
+var healthProbeStatusCodeValidator = regexp.MustCompile(`^([0-9]{3})(-([0-9]{3}))?$`)
+
+const (
+	// minHealthProbeStatusCode and maxHealthProbeStatusCode are the status codes App Gateway can match.
+	minHealthProbeStatusCode = 200
+	maxHealthProbeStatusCode = 599
+
+	// maxHealthProbeSeconds is the longest interval and timeout of an App Gateway health probe.
+	maxHealthProbeSeconds = 86400
+
+	// maxHealthProbeUnhealthyThreshold is the highest unhealthy threshold of an App Gateway health probe.
+	maxHealthProbeUnhealthyThreshold = 20
+
+	// maxFrontendPort is the highest frontend port of an App Gateway listener; the ports above are reserved for the v2 SKU.
+	maxFrontendPort = 65199
+)
+
+// redirectStatusCodes are the status codes of the redirects App Gateway supports.
+var redirectStatusCodes = map[int32]interface{}{
+	301: nil,
+	302: nil,
+	303: nil,
+	307: nil,
+}
+
+// ResourceKind is the kind of the Kubernetes resource an annotation refers to.
+type ResourceKind string
+
+const (
+	// SecretKind refers to a Secret.
+	SecretKind ResourceKind = "secret"
+
+	// ConfigMapKind refers to a ConfigMap.
+	ConfigMapKind ResourceKind = "configmap"
+)
+
+// ResourceRef refers to a Kubernetes resource in the namespace of the ingress.
+type ResourceRef struct {
+	Kind ResourceKind
+	Name string
+}
+
 // ProtocolEnum is the type for protocol
 type ProtocolEnum int
 
@@ -75,11 +221,27 @@ var ProtocolEnumLookup = map[string]ProtocolEnum{
 }
 
 // IsApplicationGatewayIngress checks if the Ingress resource can be handled by the Application Gateway ingress controller.
-func IsApplicationGatewayIngress(ing *v1beta1.Ingress) (bool, error) {
-	controllerName, err := parseString(ing, IngressClassKey)
+func IsApplicationGatewayIngress(ing *networking.Ingress) (bool, error) {
+	controllerName, err := IngressClass(ing)
 	return controllerName == ApplicationGatewayIngressClass, err
 }
 
+// IngressClass provides the value of the ingress class annotation.
+func IngressClass(ing *networking.Ingress) (string, error) {
+	return parseString(ing, IngressClassKey)
+}
+
+// IsApplicationGatewayIngressClass checks if the IngressClass resource names the Application Gateway ingress controller.
+func IsApplicationGatewayIngressClass(ingressClass *networking.IngressClass) bool {
+	return ingressClass.Spec.Controller == ApplicationGatewayIngressClass
+}
+
+// IsDefaultIngressClass checks if the IngressClass resource is annotated as the cluster default.
+func IsDefaultIngressClass(ingressClass *networking.IngressClass) bool {
+	val, ok := ingressClass.Annotations[IsDefaultIngressClassKey]
+	return ok && strings.ToLower(val) == "true"
+}
+
 // IsIstioGatewayIngress checks if this gateway should be handled by AGIC or not
 func IsIstioGatewayIngress(gateway *v1alpha3.Gateway) (bool, error) {
 	val, ok := gateway.Annotations[IstioGatewayKey]
@@ -90,42 +252,275 @@ func IsIstioGatewayIngress(gateway *v1alpha3.Gateway) (bool, error) {
 }
 
 // IsSslRedirect for HTTP end points.
-func IsSslRedirect(ing *v1beta1.Ingress) (bool, error) {
+func IsSslRedirect(ing *networking.Ingress) (bool, error) {
 	return parseBool(ing, SslRedirectKey)
 }
 
 // BackendPathPrefix override path
-func BackendPathPrefix(ing *v1beta1.Ingress) (string, error) {
+func BackendPathPrefix(ing *networking.Ingress) (string, error) {
 	return parseString(ing, BackendPathPrefixKey)
 }
 
+// AffinityCookieName provides the name of the cookie App Gateway pins the clients of the backends of the ingress with.
+func AffinityCookieName(ing *networking.Ingress) (string, error) {
+	name, err := parseString(ing, AffinityCookieNameKey)
+	if err != nil {
+		return "", err
+	}
+	if !cookieNameValidator.MatchString(name) {
+		return "", errors.NewInvalidAnnotationContent(AffinityCookieNameKey, name)
+	}
+	return name, nil
+}
+
 // RequestTimeout provides value for request timeout on the backend connection
-func RequestTimeout(ing *v1beta1.Ingress) (int32, error) {
+func RequestTimeout(ing *networking.Ingress) (int32, error) {
 	return parseInt32(ing, RequestTimeoutKey)
 }
 
 // IsConnectionDraining provides whether connection draining is enabled or not.
-func IsConnectionDraining(ing *v1beta1.Ingress) (bool, error) {
+func IsConnectionDraining(ing *networking.Ingress) (bool, error) {
 	return parseBool(ing, ConnectionDrainingKey)
 }
 
 // ConnectionDrainingTimeout provides value for draining timeout for backends.
-func ConnectionDrainingTimeout(ing *v1beta1.Ingress) (int32, error) {
+func ConnectionDrainingTimeout(ing *networking.Ingress) (int32, error) {
 	return parseInt32(ing, ConnectionDrainingTimeoutKey)
 }
 
 // IsCookieBasedAffinity provides value to enable/disable cookie based affinity for client connection.
-func IsCookieBasedAffinity(ing *v1beta1.Ingress) (bool, error) {
+func IsCookieBasedAffinity(ing *networking.Ingress) (bool, error) {
 	return parseBool(ing, CookieBasedAffinityKey)
 }
 
 // UsePrivateIP determines whether to use private IP with the ingress
-func UsePrivateIP(ing *v1beta1.Ingress) (bool, error) {
+func UsePrivateIP(ing *networking.Ingress) (bool, error) {
 	return parseBool(ing, UsePrivateIPKey)
 }
 
+// OverrideFrontendPort provides the frontend port of the HTTPS listeners, or of the HTTP listeners without TLS, of the ingress.
+func OverrideFrontendPort(ing *networking.Ingress) (int32, error) {
+	return parseInt32InRange(ing, OverrideFrontendPortKey, 1, maxFrontendPort)
+}
+
+// SslRedirectFrontendPort provides the frontend port of the HTTP listeners of the ingress, which redirect to its HTTPS listeners.
+func SslRedirectFrontendPort(ing *networking.Ingress) (int32, error) {
+	return parseInt32InRange(ing, SslRedirectFrontendPortKey, 1, maxFrontendPort)
+}
+
+// KeyVaultSecretID provides the Azure Key Vault secret ID of the certificate for the HTTPS listeners of the ingress.
+func KeyVaultSecretID(ing *networking.Ingress) (string, error) {
+	secretID, err := parseString(ing, KeyVaultSecretIDKey)
+	if err != nil {
+		return "", err
+	}
+	if !keyVaultSecretIDValidator.MatchString(secretID) {
+		return "", errors.NewInvalidAnnotationContent(KeyVaultSecretIDKey, secretID)
+	}
+	return secretID, nil
+}
+
+// AppGwSslCertificate provides the name of the certificate installed on App Gateway for the HTTPS listeners of the ingress.
+func AppGwSslCertificate(ing *networking.Ingress) (string, error) {
+	name, err := parseString(ing, AppGwSslCertificateKey)
+	if err != nil {
+		return "", err
+	}
+	if len(strings.TrimSpace(name)) == 0 {
+		return "", errors.NewInvalidAnnotationContent(AppGwSslCertificateKey, name)
+	}
+	return name, nil
+}
+
+// BackendCACertificate provides the Secret or ConfigMap with the CA bundle for the HTTPS backends of the ingress.
+func BackendCACertificate(ing *networking.Ingress) (ResourceRef, error) {
+	val, err := parseString(ing, BackendCACertificateKey)
+	if err != nil {
+		return ResourceRef{}, err
+	}
+
+	ref := ResourceRef{Kind: SecretKind, Name: val}
+	if idx := strings.Index(val, "/"); idx >= 0 {
+		ref.Kind = ResourceKind(strings.ToLower(val[:idx]))
+		ref.Name = val[idx+1:]
+	}
+	if (ref.Kind != SecretKind && ref.Kind != ConfigMapKind) || len(strings.TrimSpace(ref.Name)) == 0 || strings.Contains(ref.Name, "/") {
+		return ResourceRef{}, errors.NewInvalidAnnotationContent(BackendCACertificateKey, val)
+	}
+	return ref, nil
+}
+
+// BackendHostName provides the host name App Gateway sends to the backends of the ingress.
+func BackendHostName(ing *networking.Ingress) (string, error) {
+	hostName, err := parseString(ing, BackendHostNameKey)
+	if err != nil {
+		return "", err
+	}
+	if !backendHostNameValidator.MatchString(hostName) {
+		return "", errors.NewInvalidAnnotationContent(BackendHostNameKey, hostName)
+	}
+	return hostName, nil
+}
+
+// IsPickHostNameFromBackend provides whether App Gateway sends the host name of the backend address to the backends of the ingress.
+func IsPickHostNameFromBackend(ing *networking.Ingress) (bool, error) {
+	return parseBool(ing, PickHostNameFromBackendKey)
+}
+
+// WAFPolicy provides the resource ID of the Web Application Firewall policy of the ingress.
+func WAFPolicy(ing *networking.Ingress) (string, error) {
+	policyID, err := parseString(ing, WAFPolicyKey)
+	if err != nil {
+		return "", err
+	}
+	if !wafPolicyValidator.MatchString(policyID) {
+		return "", errors.NewInvalidAnnotationContent(WAFPolicyKey, policyID)
+	}
+	return policyID, nil
+}
+
+// RewriteRuleSet provides the name of the AzureIngressRewrite of the ingress.
+func RewriteRuleSet(ing *networking.Ingress) (string, error) {
+	name, err := parseString(ing, RewriteRuleSetKey)
+	if err != nil {
+		return "", err
+	}
+	if len(strings.TrimSpace(name)) == 0 || strings.Contains(name, "/") {
+		return "", errors.NewInvalidAnnotationContent(RewriteRuleSetKey, name)
+	}
+	return name, nil
+}
+
+// RedirectTargetURL provides the absolute HTTP or HTTPS URL App Gateway redirects the requests of the ingress to.
+func RedirectTargetURL(ing *networking.Ingress) (string, error) {
+	targetURL, err := parseString(ing, RedirectTargetURLKey)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := url.Parse(targetURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", errors.NewInvalidAnnotationContent(RedirectTargetURLKey, targetURL)
+	}
+	return targetURL, nil
+}
+
+// RedirectType provides the status code of the redirect to the target URL of the ingress.
+func RedirectType(ing *networking.Ingress) (int32, error) {
+	statusCode, err := parseInt32(ing, RedirectTypeKey)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := redirectStatusCodes[statusCode]; !ok {
+		return 0, errors.NewInvalidAnnotationContent(RedirectTypeKey, ing.Annotations[RedirectTypeKey])
+	}
+	return statusCode, nil
+}
+
+// IsRedirectIncludePath provides whether the path of the request is appended to the target URL of the ingress.
+func IsRedirectIncludePath(ing *networking.Ingress) (bool, error) {
+	return parseBool(ing, RedirectIncludePathKey)
+}
+
+// IsRedirectIncludeQueryString provides whether the query string of the request is appended to the target URL of the ingress.
+func IsRedirectIncludeQueryString(ing *networking.Ingress) (bool, error) {
+	return parseBool(ing, RedirectIncludeQueryStringKey)
+}
+
+// ErrorPageURL403 provides the HTTPS URL of the 403 Forbidden page of the listeners of the ingress.
+func ErrorPageURL403(ing *networking.Ingress) (string, error) {
+	return parseHTTPSURL(ing, ErrorPageURL403Key)
+}
+
+// ErrorPageURL502 provides the HTTPS URL of the 502 Bad Gateway page of the listeners of the ingress.
+func ErrorPageURL502(ing *networking.Ingress) (string, error) {
+	return parseHTTPSURL(ing, ErrorPageURL502Key)
+}
+
+// HealthProbeHostName provides the host name App Gateway sends with the health probes of the backends of the ingress.
+func HealthProbeHostName(ing *networking.Ingress) (string, error) {
+	hostName, err := parseString(ing, HealthProbeHostNameKey)
+	if err != nil {
+		return "", err
+	}
+	if !backendHostNameValidator.MatchString(hostName) {
+		return "", errors.NewInvalidAnnotationContent(HealthProbeHostNameKey, hostName)
+	}
+	return hostName, nil
+}
+
+// HealthProbePath provides the path App Gateway probes on the backends of the ingress.
+func HealthProbePath(ing *networking.Ingress) (string, error) {
+	path, err := parseString(ing, HealthProbePathKey)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t") {
+		return "", errors.NewInvalidAnnotationContent(HealthProbePathKey, path)
+	}
+	return path, nil
+}
+
+// HealthProbeInterval provides the interval in seconds between the health probes of the backends of the ingress.
+func HealthProbeInterval(ing *networking.Ingress) (int32, error) {
+	return parseInt32InRange(ing, HealthProbeIntervalKey, 1, maxHealthProbeSeconds)
+}
+
+// HealthProbeTimeout provides the timeout in seconds of the health probes of the backends of the ingress.
+func HealthProbeTimeout(ing *networking.Ingress) (int32, error) {
+	return parseInt32InRange(ing, HealthProbeTimeoutKey, 1, maxHealthProbeSeconds)
+}
+
+// HealthProbeUnhealthyThreshold provides the number of failed health probes after which a backend of the ingress is unhealthy.
+func HealthProbeUnhealthyThreshold(ing *networking.Ingress) (int32, error) {
+	return parseInt32InRange(ing, HealthProbeUnhealthyThresholdKey, 1, maxHealthProbeUnhealthyThreshold)
+}
+
+// HealthProbeStatusCodes provides the status codes and ranges of the responses of healthy backends of the ingress.
+func HealthProbeStatusCodes(ing *networking.Ingress) ([]string, error) {
+	val, err := parseString(ing, HealthProbeStatusCodesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var statusCodes []string
+	for _, statusCode := range strings.Split(val, ",") {
+		statusCode = strings.TrimSpace(statusCode)
+		matches := healthProbeStatusCodeValidator.FindStringSubmatch(statusCode)
+		if matches == nil {
+			return nil, errors.NewInvalidAnnotationContent(HealthProbeStatusCodesKey, val)
+		}
+		low, _ := strconv.Atoi(matches[1])
+		high := low
+		if matches[3] != "" {
+			high, _ = strconv.Atoi(matches[3])
+		}
+		if low < minHealthProbeStatusCode || high > maxHealthProbeStatusCode || low > high {
+			return nil, errors.NewInvalidAnnotationContent(HealthProbeStatusCodesKey, val)
+		}
+		statusCodes = append(statusCodes, statusCode)
+	}
+	return statusCodes, nil
+}
+
+// HealthProbeBodyMatch provides the string the response body of healthy backends of the ingress contains.
+func HealthProbeBodyMatch(ing *networking.Ingress) (string, error) {
+	body, err := parseString(ing, HealthProbeBodyMatchKey)
+	if err != nil {
+		return "", err
+	}
+	if len(body) == 0 {
+		return "", errors.NewInvalidAnnotationContent(HealthProbeBodyMatchKey, body)
+	}
+	return body, nil
+}
+
+// IsHealthProbeTCPOnly provides whether the health probes of the backends of the ingress only check that the backends respond.
+func IsHealthProbeTCPOnly(ing *networking.Ingress) (bool, error) {
+	return parseBool(ing, HealthProbeTCPOnlyKey)
+}
+
 // BackendProtocol provides value for protocol to be used with the backend
-func BackendProtocol(ing *v1beta1.Ingress) (ProtocolEnum, error) {
+func BackendProtocol(ing *networking.Ingress) (ProtocolEnum, error) {
 	protocol, err := parseString(ing, BackendProtocolKey)
 	if err != nil {
 		return HTTP, err
@@ -138,7 +533,7 @@ func BackendProtocol(ing *v1beta1.Ingress) (ProtocolEnum, error) {
 	return HTTP, errors.NewInvalidAnnotationContent(BackendProtocolKey, protocol)
 }
 
-func parseBool(ing *v1beta1.Ingress, name string) (bool, error) {
+func parseBool(ing *networking.Ingress, name string) (bool, error) {
 	if val, ok := ing.Annotations[name]; ok {
 		if boolVal, err := strconv.ParseBool(val); err == nil {
 			return boolVal, nil
@@ -148,14 +543,14 @@ func parseBool(ing *v1beta1.Ingress, name string) (bool, error) {
 	return false, errors.ErrMissingAnnotations
 }
 
-func parseString(ing *v1beta1.Ingress, name string) (string, error) {
+func parseString(ing *networking.Ingress, name string) (string, error) {
 	if val, ok := ing.Annotations[name]; ok {
 		return val, nil
 	}
 	return "", errors.ErrMissingAnnotations
 }
 
-func parseInt32(ing *v1beta1.Ingress, name string) (int32, error) {
+func parseInt32(ing *networking.Ingress, name string) (int32, error) {
 	if val, ok := ing.Annotations[name]; ok {
 		if intVal, err := strconv.Atoi(val); err == nil {
 			return int32(intVal), nil
@@ -165,3 +560,27 @@ func parseInt32(ing *v1beta1.Ingress, name string) (int32, error) {
 
 	return 0, errors.ErrMissingAnnotations
 }
+
+func parseInt32InRange(ing *networking.Ingress, name string, min int32, max int32) (int32, error) {
+	intVal, err := parseInt32(ing, name)
+	if err != nil {
+		return 0, err
+	}
+	if intVal < min || intVal > max {
+		return 0, errors.NewInvalidAnnotationContent(name, ing.Annotations[name])
+	}
+	return intVal, nil
+}
+
+// parseHTTPSURL parses an annotation holding an absolute HTTPS URL.
+func parseHTTPSURL(ing *networking.Ingress, name string) (string, error) {
+	val, err := parseString(ing, name)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := url.Parse(val)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return "", errors.NewInvalidAnnotationContent(name, val)
+	}
+	return val, nil
+}
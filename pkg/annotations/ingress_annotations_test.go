@@ -8,16 +8,17 @@ package annotations
 import (
 	"fmt"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
+	"strings"
 	"testing"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-var ingress = v1beta1.Ingress{
+var ingress = networking.Ingress{
 	ObjectMeta: v1.ObjectMeta{
 		Annotations: map[string]string{},
 	},
@@ -48,7 +49,7 @@ var _ = Describe("Test ingress annotation functions", func() {
 		"errorKey":                                                "234error!!",
 	}
 
-	ing := &v1beta1.Ingress{
+	ing := &networking.Ingress{
 		ObjectMeta: v1.ObjectMeta{
 			Annotations: annotations,
 		},
@@ -56,7 +57,7 @@ var _ = Describe("Test ingress annotation functions", func() {
 
 	Context("test IsCookieBasedAffinity", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := IsCookieBasedAffinity(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(false))
@@ -68,9 +69,28 @@ var _ = Describe("Test ingress annotation functions", func() {
 		})
 	})
 
+	Context("test AffinityCookieName", func() {
+		It("returns error when ingress has no annotations", func() {
+			_, err := AffinityCookieName(&networking.Ingress{})
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+		})
+		It("returns valid cookie names only", func() {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{AffinityCookieNameKey: "shop-affinity"}}}
+			name, err := AffinityCookieName(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(name).To(Equal("shop-affinity"))
+
+			for _, val := range []string{"shop affinity", "shop;path=/", "shop=1"} {
+				ing.Annotations[AffinityCookieNameKey] = val
+				_, err = AffinityCookieName(ing)
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
 	Context("test ConnectionDrainingTimeout", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := ConnectionDrainingTimeout(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(int32(0)))
@@ -84,7 +104,7 @@ var _ = Describe("Test ingress annotation functions", func() {
 
 	Context("test IsConnectionDraining", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := IsConnectionDraining(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(false))
@@ -98,7 +118,7 @@ var _ = Describe("Test ingress annotation functions", func() {
 
 	Context("test RequestTimeout", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := RequestTimeout(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(int32(0)))
@@ -112,7 +132,7 @@ var _ = Describe("Test ingress annotation functions", func() {
 
 	Context("test BackendPathPrefix", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := BackendPathPrefix(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(""))
@@ -126,7 +146,7 @@ var _ = Describe("Test ingress annotation functions", func() {
 
 	Context("test IsSslRedirect", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := IsSslRedirect(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(false))
@@ -159,7 +179,7 @@ var _ = Describe("Test ingress annotation functions", func() {
 
 	Context("test IsApplicationGatewayIngress", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := IsApplicationGatewayIngress(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(false))
@@ -171,9 +191,38 @@ var _ = Describe("Test ingress annotation functions", func() {
 		})
 	})
 
+	Context("test IsApplicationGatewayIngressClass", func() {
+		It("returns true when the controller is AGIC", func() {
+			ingressClass := &networking.IngressClass{
+				Spec: networking.IngressClassSpec{Controller: ApplicationGatewayIngressClass},
+			}
+			Expect(IsApplicationGatewayIngressClass(ingressClass)).To(BeTrue())
+		})
+		It("returns false for a different controller", func() {
+			ingressClass := &networking.IngressClass{
+				Spec: networking.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+			}
+			Expect(IsApplicationGatewayIngressClass(ingressClass)).To(BeFalse())
+		})
+	})
+
+	Context("test IsDefaultIngressClass", func() {
+		It("returns false when the IngressClass has no annotations", func() {
+			Expect(IsDefaultIngressClass(&networking.IngressClass{})).To(BeFalse())
+		})
+		It("returns true when annotated as default", func() {
+			ingressClass := &networking.IngressClass{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{IsDefaultIngressClassKey: "true"},
+				},
+			}
+			Expect(IsDefaultIngressClass(ingressClass)).To(BeTrue())
+		})
+	})
+
 	Context("test UsePrivateIP", func() {
 		It("returns error when ingress has no annotations", func() {
-			ing := &v1beta1.Ingress{}
+			ing := &networking.Ingress{}
 			actual, err := UsePrivateIP(ing)
 			Expect(err).To(HaveOccurred())
 			Expect(actual).To(Equal(false))
@@ -185,6 +234,329 @@ var _ = Describe("Test ingress annotation functions", func() {
 		})
 	})
 
+	Context("test KeyVaultSecretID", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			actual, err := KeyVaultSecretID(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the secret ID with or without a version", func() {
+			for _, secretID := range []string{
+				"https://contoso-vault.vault.azure.net/secrets/www-contoso-com",
+				"https://contoso-vault.vault.azure.net/secrets/www-contoso-com/",
+				"https://contoso-vault.vault.azure.net/secrets/www-contoso-com/0123456789abcdef0123456789abcdef",
+				"https://contoso.vault.usgovcloudapi.net/secrets/www-contoso-com",
+			} {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{KeyVaultSecretIDKey: secretID}}}
+				actual, err := KeyVaultSecretID(ing)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual).To(Equal(secretID))
+			}
+		})
+		It("returns an error for anything other than a Key Vault secret ID", func() {
+			for _, secretID := range []string{
+				"",
+				"www-contoso-com",
+				"http://contoso-vault.vault.azure.net/secrets/www-contoso-com",
+				"https://contoso-vault.vault.azure.net/certificates/www-contoso-com",
+				"https://contoso-vault.vault.azure.net/secrets/",
+			} {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{KeyVaultSecretIDKey: secretID}}}
+				_, err := KeyVaultSecretID(ing)
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), secretID)
+			}
+		})
+	})
+
+	Context("test AppGwSslCertificate", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			actual, err := AppGwSslCertificate(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the certificate name", func() {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{AppGwSslCertificateKey: "security-team-cert"}}}
+			actual, err := AppGwSslCertificate(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("security-team-cert"))
+		})
+		It("returns an error for an empty name", func() {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{AppGwSslCertificateKey: " "}}}
+			_, err := AppGwSslCertificate(ing)
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+	})
+
+	Context("test BackendCACertificate", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			_, err := BackendCACertificate(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+		})
+		It("returns the Secret or the ConfigMap", func() {
+			expected := map[string]ResourceRef{
+				"backend-ca":           {Kind: SecretKind, Name: "backend-ca"},
+				"secret/backend-ca":    {Kind: SecretKind, Name: "backend-ca"},
+				"configmap/backend-ca": {Kind: ConfigMapKind, Name: "backend-ca"},
+				"ConfigMap/backend-ca": {Kind: ConfigMapKind, Name: "backend-ca"},
+			}
+			for val, ref := range expected {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{BackendCACertificateKey: val}}}
+				actual, err := BackendCACertificate(ing)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual).To(Equal(ref), val)
+			}
+		})
+		It("returns an error for other kinds and empty names", func() {
+			for _, val := range []string{"", "secret/", "service/backend-ca", "other-ns/secret/backend-ca"} {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{BackendCACertificateKey: val}}}
+				_, err := BackendCACertificate(ing)
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
+	Context("test BackendHostName", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			actual, err := BackendHostName(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the host name", func() {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{BackendHostNameKey: "backend.contoso.com"}}}
+			actual, err := BackendHostName(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("backend.contoso.com"))
+		})
+		It("returns an error for anything other than a DNS name", func() {
+			for _, val := range []string{"", "https://backend.contoso.com", "backend.contoso.com:443", "-backend"} {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{BackendHostNameKey: val}}}
+				_, err := BackendHostName(ing)
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
+	Context("test WAFPolicy", func() {
+		policyID := "/subscriptions/subid/resourceGroups/rg/providers/Microsoft.Network/ApplicationGatewayWebApplicationFirewallPolicies/prevention"
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			actual, err := WAFPolicy(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the policy resource ID", func() {
+			for _, val := range []string{policyID, strings.ToLower(policyID)} {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{WAFPolicyKey: val}}}
+				actual, err := WAFPolicy(ing)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual).To(Equal(val))
+			}
+		})
+		It("returns an error for anything other than a WAF policy resource ID", func() {
+			for _, val := range []string{
+				"",
+				"prevention",
+				"/subscriptions/subid/resourceGroups/rg/providers/Microsoft.Network/applicationGateways/appgw",
+				policyID + "/",
+			} {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{WAFPolicyKey: val}}}
+				_, err := WAFPolicy(ing)
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
+	Context("test IsPickHostNameFromBackend", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			actual, err := IsPickHostNameFromBackend(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			Expect(actual).To(BeFalse())
+		})
+		It("returns the switch", func() {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{PickHostNameFromBackendKey: "true"}}}
+			actual, err := IsPickHostNameFromBackend(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(BeTrue())
+		})
+		It("returns an error for a value which is not a bool", func() {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{PickHostNameFromBackendKey: "yes please"}}}
+			_, err := IsPickHostNameFromBackend(ing)
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+	})
+
+	Context("test RewriteRuleSet", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			actual, err := RewriteRuleSet(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the name of the AzureIngressRewrite", func() {
+			ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{RewriteRuleSetKey: "security-headers"}}}
+			actual, err := RewriteRuleSet(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("security-headers"))
+		})
+		It("returns an error for an empty name or a name in another namespace", func() {
+			for _, val := range []string{"", " ", "other-namespace/security-headers"} {
+				ing := &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{RewriteRuleSetKey: val}}}
+				_, err := RewriteRuleSet(ing)
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
+	Context("test error page annotations", func() {
+		newIngress := func(key, val string) *networking.Ingress {
+			return &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{key: val}}}
+		}
+
+		It("returns error when ingress has no annotations", func() {
+			_, err := ErrorPageURL403(&networking.Ingress{})
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			_, err = ErrorPageURL502(&networking.Ingress{})
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+		})
+		It("returns HTTPS URLs only", func() {
+			pageURL, err := ErrorPageURL502(newIngress(ErrorPageURL502Key, "https://contoso.blob.core.windows.net/errors/502.html"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pageURL).To(Equal("https://contoso.blob.core.windows.net/errors/502.html"))
+
+			for _, val := range []string{"http://contoso.com/403.html", "contoso.com/403.html", "/403.html", "https://"} {
+				_, err = ErrorPageURL403(newIngress(ErrorPageURL403Key, val))
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
+	Context("test frontend port annotations", func() {
+		newIngress := func(key, val string) *networking.Ingress {
+			return &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{key: val}}}
+		}
+
+		It("returns error when ingress has no annotations", func() {
+			_, err := OverrideFrontendPort(&networking.Ingress{})
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			_, err = SslRedirectFrontendPort(&networking.Ingress{})
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+		})
+		It("returns the frontend ports within the App Gateway limits", func() {
+			port, err := OverrideFrontendPort(newIngress(OverrideFrontendPortKey, "8443"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(port).To(Equal(int32(8443)))
+
+			port, err = SslRedirectFrontendPort(newIngress(SslRedirectFrontendPortKey, "8080"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(port).To(Equal(int32(8080)))
+
+			for _, val := range []string{"0", "65200", "http"} {
+				_, err = OverrideFrontendPort(newIngress(OverrideFrontendPortKey, val))
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
+	Context("test redirect annotations", func() {
+		newIngress := func(key, val string) *networking.Ingress {
+			return &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{key: val}}}
+		}
+
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			_, err := RedirectTargetURL(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			_, err = RedirectType(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			_, err = IsRedirectIncludePath(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+		})
+		It("returns the target URL", func() {
+			targetURL, err := RedirectTargetURL(newIngress(RedirectTargetURLKey, "https://www.contoso.com/shop"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targetURL).To(Equal("https://www.contoso.com/shop"))
+
+			for _, val := range []string{"", "www.contoso.com", "/shop", "ftp://www.contoso.com"} {
+				_, err = RedirectTargetURL(newIngress(RedirectTargetURLKey, val))
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+		It("returns the redirect status codes App Gateway supports", func() {
+			for _, statusCode := range []int32{301, 302, 303, 307} {
+				actual, err := RedirectType(newIngress(RedirectTypeKey, fmt.Sprint(statusCode)))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual).To(Equal(statusCode))
+			}
+
+			for _, val := range []string{"308", "200", "Permanent"} {
+				_, err := RedirectType(newIngress(RedirectTypeKey, val))
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+	})
+
+	Context("test health probe annotations", func() {
+		newIngress := func(key, val string) *networking.Ingress {
+			return &networking.Ingress{ObjectMeta: v1.ObjectMeta{Annotations: map[string]string{key: val}}}
+		}
+
+		It("returns error when ingress has no annotations", func() {
+			ing := &networking.Ingress{}
+			_, err := HealthProbePath(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			_, err = HealthProbeStatusCodes(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+			_, err = IsHealthProbeTCPOnly(ing)
+			Expect(errors.IsMissingAnnotations(err)).To(BeTrue())
+		})
+		It("returns the host name and path", func() {
+			hostName, err := HealthProbeHostName(newIngress(HealthProbeHostNameKey, "health.contoso.com"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hostName).To(Equal("health.contoso.com"))
+
+			path, err := HealthProbePath(newIngress(HealthProbePathKey, "/healthz"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(path).To(Equal("/healthz"))
+
+			_, err = HealthProbePath(newIngress(HealthProbePathKey, "healthz"))
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+		It("returns the interval, timeout and unhealthy threshold within the App Gateway limits", func() {
+			interval, err := HealthProbeInterval(newIngress(HealthProbeIntervalKey, "15"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(interval).To(Equal(int32(15)))
+
+			_, err = HealthProbeTimeout(newIngress(HealthProbeTimeoutKey, "0"))
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+
+			_, err = HealthProbeUnhealthyThreshold(newIngress(HealthProbeUnhealthyThresholdKey, "21"))
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+		It("returns the status codes and ranges", func() {
+			statusCodes, err := HealthProbeStatusCodes(newIngress(HealthProbeStatusCodesKey, "200-399, 401"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statusCodes).To(Equal([]string{"200-399", "401"}))
+
+			for _, val := range []string{"", "200,", "100-199", "200-600", "399-200", "2xx"} {
+				_, err = HealthProbeStatusCodes(newIngress(HealthProbeStatusCodesKey, val))
+				Expect(errors.IsInvalidContent(err)).To(BeTrue(), val)
+			}
+		})
+		It("returns the body match", func() {
+			body, err := HealthProbeBodyMatch(newIngress(HealthProbeBodyMatchKey, "healthy"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(body).To(Equal("healthy"))
+
+			_, err = HealthProbeBodyMatch(newIngress(HealthProbeBodyMatchKey, ""))
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+	})
+
 	Context("test parseBol", func() {
 		It("returns true", func() {
 			actual, err := parseBool(ing, UsePrivateIPKey)
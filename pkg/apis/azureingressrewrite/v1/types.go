@@ -0,0 +1,94 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AzureIngressRewrite is a set of header rewrite rules, which Ingresses in the same namespace reference by annotation
+type AzureIngressRewrite struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureIngressRewriteSpec `json:"spec"`
+}
+
+// AzureIngressRewriteSpec defines the rewrite rules App Gateway applies to the requests and responses of an Ingress.
+type AzureIngressRewriteSpec struct {
+	// RewriteRules are evaluated in the order of their rule sequence
+	RewriteRules []RewriteRule `json:"rewriteRules"`
+}
+
+// RewriteRule rewrites the headers of the requests and responses matching all of its conditions.
+type RewriteRule struct {
+	// Name of the rewrite rule; unique within the AzureIngressRewrite
+	Name string `json:"name"`
+
+	// +optional
+	// RuleSequence determines the order in which the rules are evaluated; lower values are evaluated first
+	RuleSequence int32 `json:"ruleSequence,omitempty"`
+
+	// +optional
+	// Conditions must all be met for the actions to be applied; the actions are always applied without conditions
+	Conditions []RewriteRuleCondition `json:"conditions,omitempty"`
+
+	// Actions applied to the requests and responses
+	Actions RewriteRuleActions `json:"actions"`
+}
+
+// RewriteRuleCondition matches a server variable, request header or response header against a pattern.
+type RewriteRuleCondition struct {
+	// Variable is a server variable, like var_uri_path, http_req_<header> or http_resp_<header>
+	Variable string `json:"variable"`
+
+	// Pattern is the regular expression the variable is matched against
+	Pattern string `json:"pattern"`
+
+	// +optional
+	// IgnoreCase makes the pattern case insensitive
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+
+	// +optional
+	// Negate inverts the result of the match
+	Negate bool `json:"negate,omitempty"`
+}
+
+// RewriteRuleActions are the headers rewritten by a rule.
+type RewriteRuleActions struct {
+	// +optional
+	// RequestHeaders are set on the requests sent to the backends
+	RequestHeaders []HeaderConfiguration `json:"requestHeaders,omitempty"`
+
+	// +optional
+	// ResponseHeaders are set on the responses sent to the clients
+	ResponseHeaders []HeaderConfiguration `json:"responseHeaders,omitempty"`
+}
+
+// HeaderConfiguration sets or removes a header.
+type HeaderConfiguration struct {
+	// Name of the header
+	Name string `json:"name"`
+
+	// +optional
+	// Value of the header, which may reference server variables like {var_client_ip}; the header is removed when empty
+	Value string `json:"value,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AzureIngressRewriteList is the list of rewrites
+type AzureIngressRewriteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AzureIngressRewrite `json:"items"`
+}
@@ -0,0 +1,188 @@
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressRewrite) DeepCopyInto(out *AzureIngressRewrite) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressRewrite.
+func (in *AzureIngressRewrite) DeepCopy() *AzureIngressRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureIngressRewrite) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressRewriteList) DeepCopyInto(out *AzureIngressRewriteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureIngressRewrite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressRewriteList.
+func (in *AzureIngressRewriteList) DeepCopy() *AzureIngressRewriteList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressRewriteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureIngressRewriteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressRewriteSpec) DeepCopyInto(out *AzureIngressRewriteSpec) {
+	*out = *in
+	if in.RewriteRules != nil {
+		in, out := &in.RewriteRules, &out.RewriteRules
+		*out = make([]RewriteRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressRewriteSpec.
+func (in *AzureIngressRewriteSpec) DeepCopy() *AzureIngressRewriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressRewriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderConfiguration) DeepCopyInto(out *HeaderConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderConfiguration.
+func (in *HeaderConfiguration) DeepCopy() *HeaderConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RewriteRule) DeepCopyInto(out *RewriteRule) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]RewriteRuleCondition, len(*in))
+		copy(*out, *in)
+	}
+	in.Actions.DeepCopyInto(&out.Actions)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RewriteRule.
+func (in *RewriteRule) DeepCopy() *RewriteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RewriteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RewriteRuleActions) DeepCopyInto(out *RewriteRuleActions) {
+	*out = *in
+	if in.RequestHeaders != nil {
+		in, out := &in.RequestHeaders, &out.RequestHeaders
+		*out = make([]HeaderConfiguration, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make([]HeaderConfiguration, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RewriteRuleActions.
+func (in *RewriteRuleActions) DeepCopy() *RewriteRuleActions {
+	if in == nil {
+		return nil
+	}
+	out := new(RewriteRuleActions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RewriteRuleCondition) DeepCopyInto(out *RewriteRuleCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RewriteRuleCondition.
+func (in *RewriteRuleCondition) DeepCopy() *RewriteRuleCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(RewriteRuleCondition)
+	in.DeepCopyInto(out)
+	return out
+}
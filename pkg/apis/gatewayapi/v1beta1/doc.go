@@ -0,0 +1,10 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+// +k8s:deepcopy-gen=package,register
+// +groupName=gateway.networking.k8s.io
+
+// Package v1beta1 is the v1beta1 version of the Kubernetes Gateway API, restricted to the parts App Gateway implements.
+package v1beta1
@@ -0,0 +1,473 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClass is a class of Gateways, which the controller named by the class implements
+type GatewayClass struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewayClassSpec `json:"spec"`
+
+	// +optional
+	Status GatewayClassStatus `json:"status,omitempty"`
+}
+
+// GatewayClassSpec names the controller of the Gateways of the class.
+type GatewayClassSpec struct {
+	// ControllerName is the name of the controller managing the Gateways of the class
+	ControllerName string `json:"controllerName"`
+
+	// +optional
+	// Description of the class
+	Description *string `json:"description,omitempty"`
+}
+
+// GatewayClassStatus is the status of a GatewayClass, as observed by its controller.
+type GatewayClassStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClassList is a list of GatewayClasses
+type GatewayClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GatewayClass `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Gateway is a set of listeners, which routes attach to
+type Gateway struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewaySpec `json:"spec"`
+
+	// +optional
+	Status GatewayStatus `json:"status,omitempty"`
+}
+
+// GatewaySpec is the class and the listeners of a Gateway.
+type GatewaySpec struct {
+	// GatewayClassName is the name of the GatewayClass of the Gateway
+	GatewayClassName string `json:"gatewayClassName"`
+
+	// Listeners accept the requests of a port, protocol and host name
+	Listeners []Listener `json:"listeners"`
+}
+
+// Listener accepts the requests of a port, protocol and host name.
+type Listener struct {
+	// Name of the listener; unique within the Gateway
+	Name string `json:"name"`
+
+	// +optional
+	// Hostname matches the host of the requests; a listener without host name matches all hosts
+	Hostname *string `json:"hostname,omitempty"`
+
+	// Port the listener listens on
+	Port int32 `json:"port"`
+
+	// Protocol of the listener, like HTTP or HTTPS
+	Protocol ProtocolType `json:"protocol"`
+
+	// +optional
+	// TLS configures the certificates of HTTPS listeners
+	TLS *GatewayTLSConfig `json:"tls,omitempty"`
+
+	// +optional
+	// AllowedRoutes restricts the routes, which may attach to the listener; only routes of the namespace of the Gateway by default
+	AllowedRoutes *AllowedRoutes `json:"allowedRoutes,omitempty"`
+}
+
+// ProtocolType is the protocol of a listener.
+type ProtocolType string
+
+const (
+	// HTTPProtocolType accepts cleartext HTTP requests.
+	HTTPProtocolType ProtocolType = "HTTP"
+
+	// HTTPSProtocolType terminates TLS and accepts HTTP requests.
+	HTTPSProtocolType ProtocolType = "HTTPS"
+)
+
+// TLSModeType is the TLS mode of a listener.
+type TLSModeType string
+
+const (
+	// TLSModeTerminate terminates TLS at the Gateway.
+	TLSModeTerminate TLSModeType = "Terminate"
+
+	// TLSModePassthrough passes the TLS stream to the backends.
+	TLSModePassthrough TLSModeType = "Passthrough"
+)
+
+// GatewayTLSConfig is the TLS configuration of a listener.
+type GatewayTLSConfig struct {
+	// +optional
+	// Mode is Terminate by default
+	Mode *TLSModeType `json:"mode,omitempty"`
+
+	// +optional
+	// CertificateRefs are the secrets with the certificate and key of the listener
+	CertificateRefs []SecretObjectReference `json:"certificateRefs,omitempty"`
+}
+
+// SecretObjectReference references a secret, in the namespace of the Gateway by default.
+type SecretObjectReference struct {
+	// +optional
+	Group *string `json:"group,omitempty"`
+
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	Name string `json:"name"`
+
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// AllowedRoutes restricts the namespaces and kinds of the routes of a listener.
+type AllowedRoutes struct {
+	// +optional
+	Namespaces *RouteNamespaces `json:"namespaces,omitempty"`
+
+	// +optional
+	Kinds []RouteGroupKind `json:"kinds,omitempty"`
+}
+
+// FromNamespaces selects the namespaces, whose routes may attach to a listener.
+type FromNamespaces string
+
+const (
+	// NamespacesFromAll allows the routes of all namespaces.
+	NamespacesFromAll FromNamespaces = "All"
+
+	// NamespacesFromSame allows the routes of the namespace of the Gateway.
+	NamespacesFromSame FromNamespaces = "Same"
+
+	// NamespacesFromSelector allows the routes of the namespaces matching the selector.
+	NamespacesFromSelector FromNamespaces = "Selector"
+)
+
+// RouteNamespaces selects the namespaces, whose routes may attach to a listener.
+type RouteNamespaces struct {
+	// +optional
+	// From is Same by default
+	From *FromNamespaces `json:"from,omitempty"`
+
+	// +optional
+	// Selector selects the namespaces by label, when From is Selector
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// RouteGroupKind is the group and kind of a route.
+type RouteGroupKind struct {
+	// +optional
+	Group *string `json:"group,omitempty"`
+
+	Kind string `json:"kind"`
+}
+
+// GatewayStatus is the status of a Gateway, as observed by its controller.
+type GatewayStatus struct {
+	// +optional
+	// Addresses the Gateway is reachable at
+	Addresses []GatewayAddress `json:"addresses,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	Listeners []ListenerStatus `json:"listeners,omitempty"`
+}
+
+// GatewayAddress is an address of a Gateway.
+type GatewayAddress struct {
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	Value string `json:"value"`
+}
+
+// ListenerStatus is the status of a listener.
+type ListenerStatus struct {
+	Name string `json:"name"`
+
+	SupportedKinds []RouteGroupKind `json:"supportedKinds"`
+
+	// AttachedRoutes is the number of routes attached to the listener
+	AttachedRoutes int32 `json:"attachedRoutes"`
+
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayList is a list of Gateways
+type GatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Gateway `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRoute routes the HTTP requests of the listeners of its parent Gateways to backends
+type HTTPRoute struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+
+	// +optional
+	Status HTTPRouteStatus `json:"status,omitempty"`
+}
+
+// HTTPRouteSpec is the parents, host names and rules of an HTTPRoute.
+type HTTPRouteSpec struct {
+	// +optional
+	// ParentRefs are the Gateways, and optionally their listeners, the route attaches to
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+
+	// +optional
+	// Hostnames match the host of the requests; the route matches the host names of its listeners without host names
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// +optional
+	Rules []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+// ParentReference references a Gateway, in the namespace of the route by default.
+type ParentReference struct {
+	// +optional
+	Group *string `json:"group,omitempty"`
+
+	// +optional
+	Kind *string `json:"kind,omitempty"`
+
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	Name string `json:"name"`
+
+	// +optional
+	// SectionName is the name of the listener the route attaches to
+	SectionName *string `json:"sectionName,omitempty"`
+
+	// +optional
+	// Port restricts the listeners the route attaches to by port
+	Port *int32 `json:"port,omitempty"`
+}
+
+// HTTPRouteRule forwards the requests matching any of its matches to its backends.
+type HTTPRouteRule struct {
+	// +optional
+	// Matches are a path prefix of / by default
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+
+	// +optional
+	Filters []HTTPRouteFilter `json:"filters,omitempty"`
+
+	// +optional
+	BackendRefs []HTTPBackendRef `json:"backendRefs,omitempty"`
+}
+
+// PathMatchType is the way the path of a request is matched.
+type PathMatchType string
+
+const (
+	// PathMatchExact matches the path exactly.
+	PathMatchExact PathMatchType = "Exact"
+
+	// PathMatchPathPrefix matches the path elements of the value.
+	PathMatchPathPrefix PathMatchType = "PathPrefix"
+
+	// PathMatchRegularExpression matches the path with a regular expression.
+	PathMatchRegularExpression PathMatchType = "RegularExpression"
+)
+
+// HTTPRouteMatch matches requests by path, headers, query parameters and method.
+type HTTPRouteMatch struct {
+	// +optional
+	Path *HTTPPathMatch `json:"path,omitempty"`
+
+	// +optional
+	Headers []HTTPHeaderMatch `json:"headers,omitempty"`
+
+	// +optional
+	QueryParams []HTTPQueryParamMatch `json:"queryParams,omitempty"`
+
+	// +optional
+	Method *string `json:"method,omitempty"`
+}
+
+// HTTPPathMatch matches the path of a request.
+type HTTPPathMatch struct {
+	// +optional
+	// Type is PathPrefix by default
+	Type *PathMatchType `json:"type,omitempty"`
+
+	// +optional
+	// Value is / by default
+	Value *string `json:"value,omitempty"`
+}
+
+// HTTPHeaderMatch matches a header of a request.
+type HTTPHeaderMatch struct {
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	Name string `json:"name"`
+
+	Value string `json:"value"`
+}
+
+// HTTPQueryParamMatch matches a query parameter of a request.
+type HTTPQueryParamMatch struct {
+	// +optional
+	Type *string `json:"type,omitempty"`
+
+	Name string `json:"name"`
+
+	Value string `json:"value"`
+}
+
+// HTTPRouteFilter modifies the requests and responses of a rule; AGIC does not implement filters.
+type HTTPRouteFilter struct {
+	Type string `json:"type"`
+}
+
+// HTTPBackendRef references a backend of a rule, in the namespace of the route by default.
+type HTTPBackendRef struct {
+	// +optional
+	// Group is the core group by default
+	Group *string `json:"group,omitempty"`
+
+	// +optional
+	// Kind is Service by default
+	Kind *string `json:"kind,omitempty"`
+
+	Name string `json:"name"`
+
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// +optional
+	// Port of the service
+	Port *int32 `json:"port,omitempty"`
+
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// HTTPRouteStatus is the status of an HTTPRoute for each of its parents.
+type HTTPRouteStatus struct {
+	// +optional
+	Parents []RouteParentStatus `json:"parents,omitempty"`
+}
+
+// RouteParentStatus is the status of a route for one of its parents, as observed by the controller of the parent.
+type RouteParentStatus struct {
+	ParentRef ParentReference `json:"parentRef"`
+
+	ControllerName string `json:"controllerName"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteList is a list of HTTPRoutes
+type HTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []HTTPRoute `json:"items"`
+}
+
+// Condition types and reasons of the status of GatewayClasses, Gateways, listeners and routes.
+const (
+	// ConditionAccepted tells whether the controller accepted the resource.
+	ConditionAccepted = "Accepted"
+
+	// ConditionProgrammed tells whether the Gateway or listener is configured on the data plane.
+	ConditionProgrammed = "Programmed"
+
+	// ConditionResolvedRefs tells whether the references of the route or listener resolved.
+	ConditionResolvedRefs = "ResolvedRefs"
+
+	// ConditionConflicted tells whether the listener conflicts with another listener on App Gateway.
+	ConditionConflicted = "Conflicted"
+
+	// ReasonAccepted is the reason of an accepted resource.
+	ReasonAccepted = "Accepted"
+
+	// ReasonProgrammed is the reason of a programmed Gateway or listener.
+	ReasonProgrammed = "Programmed"
+
+	// ReasonInvalid is the reason of a Gateway, which cannot be configured.
+	ReasonInvalid = "Invalid"
+
+	// ReasonResolvedRefs is the reason of resolved references.
+	ReasonResolvedRefs = "ResolvedRefs"
+
+	// ReasonUnsupportedProtocol is the reason of a listener with a protocol App Gateway does not implement.
+	ReasonUnsupportedProtocol = "UnsupportedProtocol"
+
+	// ReasonInvalidCertificateRef is the reason of a listener with a missing or invalid certificate.
+	ReasonInvalidCertificateRef = "InvalidCertificateRef"
+
+	// ReasonNoConflicts is the reason of a listener, which does not conflict with another listener.
+	ReasonNoConflicts = "NoConflicts"
+
+	// ReasonHostnameConflict is the reason of a listener, whose port and host name are used by another listener.
+	ReasonHostnameConflict = "HostnameConflict"
+
+	// ReasonPortConflict is the reason of a listener, whose port is used by a listener of another frontend IP.
+	ReasonPortConflict = "PortConflict"
+
+	// ReasonNotAllowedByListeners is the reason of a route, which no listener of the parent allows.
+	ReasonNotAllowedByListeners = "NotAllowedByListeners"
+
+	// ReasonNoMatchingListenerHostname is the reason of a route, whose host names match no listener of the parent.
+	ReasonNoMatchingListenerHostname = "NoMatchingListenerHostname"
+
+	// ReasonNoMatchingParent is the reason of a route, whose parent reference matches no listener.
+	ReasonNoMatchingParent = "NoMatchingParent"
+
+	// ReasonRefNotPermitted is the reason of a route referencing a backend in another namespace.
+	ReasonRefNotPermitted = "RefNotPermitted"
+
+	// ReasonInvalidKind is the reason of a route referencing a backend, which is not a Service.
+	ReasonInvalidKind = "InvalidKind"
+
+	// ReasonBackendNotFound is the reason of a route referencing a missing Service.
+	ReasonBackendNotFound = "BackendNotFound"
+)
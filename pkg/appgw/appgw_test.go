@@ -6,17 +6,18 @@
 package appgw
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
@@ -26,9 +27,11 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
@@ -96,7 +99,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	}
 
 	// Create the Ingress resource.
-	ingress := &v1beta1.Ingress{
+	ingress := &networking.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ingressName,
 			Namespace: ingressNS,
@@ -104,20 +107,21 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 				annotations.IngressClassKey: annotations.ApplicationGatewayIngressClass,
 			},
 		},
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
+		Spec: networking.IngressSpec{
+			Rules: []networking.IngressRule{
 				{
 					Host: domainName,
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
+					IngressRuleValue: networking.IngressRuleValue{
+						HTTP: &networking.HTTPIngressRuleValue{
+							Paths: []networking.HTTPIngressPath{
 								{
 									Path: hiPath,
-									Backend: v1beta1.IngressBackend{
-										ServiceName: serviceName,
-										ServicePort: intstr.IntOrString{
-											Type:   intstr.Int,
-											IntVal: int32(servicePort),
+									Backend: networking.IngressBackend{
+										Service: &networking.IngressServiceBackend{
+											Name: serviceName,
+											Port: networking.ServiceBackendPort{
+												Number: int32(servicePort),
+											},
 										},
 									},
 								},
@@ -194,7 +198,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	_ = flag.Set("v", "3")
 
 	// Method to test all the ingress that have been added to the K8s context.
-	testIngress := func() []*v1beta1.Ingress {
+	testIngress := func() []*networking.Ingress {
 		// Get all the ingresses
 		ingressList := ctxt.ListHTTPIngresses()
 		// There should be only one ingress
@@ -207,7 +211,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 	defaultHealthProbesChecker := func(appGW *n.ApplicationGatewayPropertiesFormat) {
 		expectedBackend := &ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend
-		probeName := generateProbeName(expectedBackend.ServiceName, expectedBackend.ServicePort.String(), ingress)
+		probeName := generateProbeName(expectedBackend.Service.Name, serviceBackendPortToStr(expectedBackend.Service.Port), ingress)
 		probe := &n.ApplicationGatewayProbe{
 			Name: &probeName,
 			ID:   to.StringPtr(appGwIdentifier.probeID(probeName)),
@@ -233,7 +237,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 	defaultBackendHTTPSettingsChecker := func(appGW *n.ApplicationGatewayPropertiesFormat) {
 		expectedBackend := &ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend
-		probeID := appGwIdentifier.probeID(generateProbeName(expectedBackend.ServiceName, expectedBackend.ServicePort.String(), ingress))
+		probeID := appGwIdentifier.probeID(generateProbeName(expectedBackend.Service.Name, serviceBackendPortToStr(expectedBackend.Service.Port), ingress))
 		httpSettingsName := generateHTTPSettingsName(generateBackendID(ingress, nil, nil, expectedBackend).serviceFullName(), fmt.Sprintf("%d", servicePort), Port(backendPort), ingress.Name)
 		httpSettings := &n.ApplicationGatewayBackendHTTPSettings{
 			Etag: to.StringPtr("*"),
@@ -325,7 +329,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 		baseURLPathMapsChecker(appGW, 443, domainName)
 	}
 
-	testAGConfig := func(ingressList []*v1beta1.Ingress, serviceList []*v1.Service, settings appGwConfigSettings) {
+	testAGConfig := func(ingressList []*networking.Ingress, serviceList []*v1.Service, settings appGwConfigSettings) {
 		cbCtx := &ConfigBuilderContext{
 			IngressList:  ingressList,
 			ServiceList:  serviceList,
@@ -389,7 +393,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			select {
 			case event := <-ctxt.Work:
 				// Check if we got an event of type secret.
-				if _, ok := event.Value.(*v1beta1.Ingress); ok {
+				if _, ok := event.Value.(*networking.Ingress); ok {
 					return
 				}
 			}
@@ -406,26 +410,27 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 		// Create the mock K8s client.
 		k8sClient = testclient.NewSimpleClientset()
+		tests.AddNetworkingV1Resources(k8sClient)
 
-		_, err := k8sClient.CoreV1().Namespaces().Create(ns)
+		_, err := k8sClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
 		Ω(err).ToNot(HaveOccurred(), "Unable to create the namespace %s: %v", ingressNS, err)
 
-		_, err = k8sClient.CoreV1().Nodes().Create(node)
+		_, err = k8sClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
 		Ω(err).ToNot(HaveOccurred(), "Unable to create node resource due to: %v", err)
 
-		_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Create(ingress)
+		_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Create(context.TODO(), ingress, metav1.CreateOptions{})
 		Ω(err).ToNot(HaveOccurred(), "Unable to create ingress resource due to: %v", err)
 
 		// Create the service.
-		_, err = k8sClient.CoreV1().Services(ingressNS).Create(service)
+		_, err = k8sClient.CoreV1().Services(ingressNS).Create(context.TODO(), service, metav1.CreateOptions{})
 		Ω(err).ToNot(HaveOccurred(), "Unable to create service resource due to: %v", err)
 
 		// Create the endpoints associated with this service.
-		_, err = k8sClient.CoreV1().Endpoints(ingressNS).Create(endpoints)
+		_, err = k8sClient.CoreV1().Endpoints(ingressNS).Create(context.TODO(), endpoints, metav1.CreateOptions{})
 		Ω(err).ToNot(HaveOccurred(), "Unable to create endpoints resource due to: %v", err)
 
 		// Create the pods associated with this service.
-		_, err = k8sClient.CoreV1().Pods(ingressNS).Create(pod)
+		_, err = k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod, metav1.CreateOptions{})
 		Ω(err).ToNot(HaveOccurred(), "Unable to create pods resource due to: %v", err)
 
 		// Create a mock CRD Client
@@ -433,10 +438,11 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 		// Create a mock Istio CRD Client
 		istioCrdClient := istio_fake.NewSimpleClientset()
+		gatewayCrdClient := gateway_fake.NewSimpleClientset()
 
 		// Create a `k8scontext` to start listiening to ingress resources.
 
-		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, []string{ingressNS}, 1000*time.Second)
+		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, 1000*time.Second, metricstore.NewFakeMetricStore())
 		Expect(ctxt).ShouldNot(BeNil(), "Unable to create `k8scontext`")
 
 		// Initialize the `ConfigBuilder`
@@ -496,12 +502,12 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	Context("Tests Ingress Controller when Service doesn't exists", func() {
 		It("Should be able to create Application Gateway Configuration from Ingress with empty backend pool.", func() {
 			// Delete the service
-			options := &metav1.DeleteOptions{}
-			err := k8sClient.CoreV1().Services(ingressNS).Delete(serviceName, options)
+			options := metav1.DeleteOptions{}
+			err := k8sClient.CoreV1().Services(ingressNS).Delete(context.TODO(), serviceName, options)
 			Ω(err).ToNot(HaveOccurred(), "Unable to delete service resource due to: %v", err)
 
 			// Delete the Endpoint
-			err = k8sClient.CoreV1().Endpoints(ingressNS).Delete(serviceName, options)
+			err = k8sClient.CoreV1().Endpoints(ingressNS).Delete(context.TODO(), serviceName, options)
 			Ω(err).ToNot(HaveOccurred(), "Unable to delete endpoint resource due to: %v", err)
 
 			// Start the informers. This will sync the cache with the latest ingress.
@@ -596,24 +602,24 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			ingressSecret.Data["tls.crt"] = cert
 
 			// Create a secret in Kubernetes.
-			_, err = k8sClient.CoreV1().Secrets(ingressNS).Create(ingressSecret)
+			_, err = k8sClient.CoreV1().Secrets(ingressNS).Create(context.TODO(), ingressSecret, metav1.CreateOptions{})
 			Ω(err).ToNot(HaveOccurred(), "Unable to create the secret object in K8s: %v", err)
 
 			// 2. Update the ingress TLS spec with a secret from the k8s secret store.
-			ingressTLS := v1beta1.IngressTLS{
+			ingressTLS := networking.IngressTLS{
 				SecretName: "test-ag-secret",
 			}
 
 			// Currently, when TLS spec is specified for an ingress the expectation is that we will not have any HTTP listeners configured for that ingress.
 			// TODO: This statement will not hold true once we introduce the `ssl-redirect` annotation. Will need to rethink this test-case, or introduce a new one.
 			// after the introduction of the `ssl-redirect` annotation.
-			ingress, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Get(ingressName, metav1.GetOptions{})
+			ingress, err := k8sClient.NetworkingV1().Ingresses(ingressNS).Get(context.TODO(), ingressName, metav1.GetOptions{})
 			Ω(err).ToNot(HaveOccurred(), "Unable to create ingress resource due to: %v", err)
 
 			ingress.Spec.TLS = append(ingress.Spec.TLS, ingressTLS)
 
 			// Update the ingress.
-			_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Update(ingress)
+			_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Update(context.TODO(), ingress, metav1.UpdateOptions{})
 			Ω(err).ToNot(HaveOccurred(), "Unable to update ingress resource due to: %v", err)
 
 			// Start the informers. This will sync the cache with the latest ingress.
@@ -662,7 +668,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			}
 
 			// Method to test all the ingress that have been added to the K8s context.
-			testTLSIngress := func() []*v1beta1.Ingress {
+			testTLSIngress := func() []*networking.Ingress {
 				// Get all the ingresses
 				ingressList := ctxt.ListHTTPIngresses()
 				// There should be only one ingress
@@ -708,7 +714,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 	Context("Tests Ingress Controller Annotations", func() {
 		It("Should be able to create Application Gateway Configuration from Ingress with all annotations.", func() {
-			ingress, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Get(ingressName, metav1.GetOptions{})
+			ingress, err := k8sClient.NetworkingV1().Ingresses(ingressNS).Get(context.TODO(), ingressName, metav1.GetOptions{})
 			Ω(err).ToNot(HaveOccurred(), "Unable to create ingress resource due to: %v", err)
 
 			// Set the ingress annotations for this ingress.
@@ -719,7 +725,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			ingress.Annotations[annotations.RequestTimeoutKey] = "10"
 
 			// Update the ingress.
-			_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Update(ingress)
+			_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Update(context.TODO(), ingress, metav1.UpdateOptions{})
 			Ω(err).ToNot(HaveOccurred(), "Unable to update ingress resource due to: %v", err)
 
 			// Start the informers. This will sync the cache with the latest ingress.
@@ -730,7 +736,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			ingressEvent()
 
 			// Method to test all the ingress that have been added to the K8s context.
-			annotationIngress := func() []*v1beta1.Ingress {
+			annotationIngress := func() []*networking.Ingress {
 				// Get all the ingresses
 				ingressList := ctxt.ListHTTPIngresses()
 				// There should be only one ingress
@@ -746,7 +752,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 			annotationsHTTPSettingsChecker := func(appGW *n.ApplicationGatewayPropertiesFormat) {
 				expectedBackend := &ingress.Spec.Rules[0].IngressRuleValue.HTTP.Paths[0].Backend
-				probeID := appGwIdentifier.probeID(generateProbeName(expectedBackend.ServiceName, expectedBackend.ServicePort.String(), ingress))
+				probeID := appGwIdentifier.probeID(generateProbeName(expectedBackend.Service.Name, serviceBackendPortToStr(expectedBackend.Service.Port), ingress))
 				httpSettingsName := generateHTTPSettingsName(generateBackendID(ingress, nil, nil, expectedBackend).serviceFullName(), fmt.Sprintf("%d", servicePort), Port(backendPort), ingress.Name)
 				httpSettings := &n.ApplicationGatewayBackendHTTPSettings{
 					Etag: to.StringPtr("*"),
@@ -0,0 +1,202 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"sort"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// backendCABundleKey is the key of the PEM encoded CA bundle in the Secret or ConfigMap.
+const backendCABundleKey = "ca.crt"
+
+// backendCACertificate is a certificate of the CA bundle of HTTPS backends.
+type backendCACertificate struct {
+	Name string
+
+	// Data is the base64 encoded DER certificate.
+	Data string
+}
+
+// usesTrustedRootCertificates tells whether App Gateway validates HTTPS backends with trusted root certificates, which
+// only the v2 SKUs support; the v1 SKUs use authentication certificates.
+func (c *appGwConfigBuilder) usesTrustedRootCertificates() bool {
+	if c.appGw.Sku == nil {
+		return true
+	}
+	return c.appGw.Sku.Tier == n.ApplicationGatewayTierStandardV2 || c.appGw.Sku.Tier == n.ApplicationGatewayTierWAFV2
+}
+
+// backendCACertificates sets the trusted root certificates (v2 SKU) or the authentication certificates (v1 SKU)
+// of the Ingresses with HTTPS backends and a backend CA certificate annotation.
+func (c *appGwConfigBuilder) backendCACertificates(cbCtx *ConfigBuilderContext) {
+	certsByName := make(map[string]backendCACertificate)
+	for _, ingress := range cbCtx.IngressList {
+		if protocol, err := annotations.BackendProtocol(ingress); err != nil || protocol != annotations.HTTPS {
+			continue
+		}
+		ref, err := annotations.BackendCACertificate(ingress)
+		if err != nil {
+			continue
+		}
+		caID := backendCAIdentifier{Namespace: ingress.Namespace, Kind: ref.Kind, Name: ref.Name}
+		for _, cert := range c.getBackendCACertificates(cbCtx, caID, ingress) {
+			certsByName[cert.Name] = cert
+		}
+	}
+
+	var names []string
+	for name := range certsByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if c.usesTrustedRootCertificates() {
+		var trustedRootCerts []n.ApplicationGatewayTrustedRootCertificate
+		for _, name := range names {
+			trustedRootCerts = append(trustedRootCerts, n.ApplicationGatewayTrustedRootCertificate{
+				Etag: to.StringPtr("*"),
+				Name: to.StringPtr(name),
+				ID:   to.StringPtr(c.appGwIdentifier.trustedRootCertificateID(name)),
+				ApplicationGatewayTrustedRootCertificatePropertiesFormat: &n.ApplicationGatewayTrustedRootCertificatePropertiesFormat{
+					Data: to.StringPtr(certsByName[name].Data),
+				},
+			})
+		}
+		if cbCtx.EnvVariables.EnableBrownfieldDeployment && c.appGw.TrustedRootCertificates != nil {
+			// Keep the certificates AGIC did not create; the ones with the same name as a generated certificate are overwritten.
+			for _, cert := range *c.appGw.TrustedRootCertificates {
+				if _, generated := certsByName[*cert.Name]; !generated {
+					trustedRootCerts = append(trustedRootCerts, cert)
+				}
+			}
+		}
+		if len(trustedRootCerts) > 0 || c.appGw.TrustedRootCertificates != nil {
+			c.appGw.TrustedRootCertificates = &trustedRootCerts
+		}
+		return
+	}
+
+	var authenticationCerts []n.ApplicationGatewayAuthenticationCertificate
+	for _, name := range names {
+		authenticationCerts = append(authenticationCerts, n.ApplicationGatewayAuthenticationCertificate{
+			Etag: to.StringPtr("*"),
+			Name: to.StringPtr(name),
+			ID:   to.StringPtr(c.appGwIdentifier.authenticationCertificateID(name)),
+			ApplicationGatewayAuthenticationCertificatePropertiesFormat: &n.ApplicationGatewayAuthenticationCertificatePropertiesFormat{
+				Data: to.StringPtr(certsByName[name].Data),
+			},
+		})
+	}
+	if cbCtx.EnvVariables.EnableBrownfieldDeployment && c.appGw.AuthenticationCertificates != nil {
+		for _, cert := range *c.appGw.AuthenticationCertificates {
+			if _, generated := certsByName[*cert.Name]; !generated {
+				authenticationCerts = append(authenticationCerts, cert)
+			}
+		}
+	}
+	if len(authenticationCerts) > 0 || c.appGw.AuthenticationCertificates != nil {
+		c.appGw.AuthenticationCertificates = &authenticationCerts
+	}
+}
+
+// setBackendCACertificates references the certificates of the CA bundle from the HTTP settings.
+func (c *appGwConfigBuilder) setBackendCACertificates(cbCtx *ConfigBuilderContext, httpSettings *n.ApplicationGatewayBackendHTTPSettings, caID backendCAIdentifier, ingress *networking.Ingress) {
+	certs := c.getBackendCACertificates(cbCtx, caID, ingress)
+	if len(certs) == 0 {
+		return
+	}
+
+	var refs []n.SubResource
+	for _, cert := range certs {
+		if c.usesTrustedRootCertificates() {
+			refs = append(refs, *resourceRef(c.appGwIdentifier.trustedRootCertificateID(cert.Name)))
+		} else {
+			refs = append(refs, *resourceRef(c.appGwIdentifier.authenticationCertificateID(cert.Name)))
+		}
+	}
+
+	if c.usesTrustedRootCertificates() {
+		httpSettings.TrustedRootCertificates = &refs
+	} else {
+		httpSettings.AuthenticationCertificates = &refs
+	}
+}
+
+// getBackendCACertificates reads the CA bundle once per Secret or ConfigMap and emits an event on the Ingress when it is unusable.
+// ConfigMaps are only used when the EnableBackendCAConfigMapsVarName env variable is set to true.
+func (c *appGwConfigBuilder) getBackendCACertificates(cbCtx *ConfigBuilderContext, caID backendCAIdentifier, ingress *networking.Ingress) []backendCACertificate {
+	if c.mem.backendCACerts == nil {
+		certsByCA := make(map[backendCAIdentifier][]backendCACertificate)
+		c.mem.backendCACerts = &certsByCA
+	}
+	if certs, exists := (*c.mem.backendCACerts)[caID]; exists {
+		return certs
+	}
+
+	var certs []backendCACertificate
+	var err error
+	if caID.Kind == annotations.ConfigMapKind && !cbCtx.EnvVariables.EnableBackendCAConfigMaps {
+		err = fmt.Errorf("%s is not enabled", environment.EnableBackendCAConfigMapsVarName)
+	} else {
+		certs, err = c.newBackendCACertificates(caID)
+	}
+	if err != nil {
+		logLine := fmt.Sprintf("Unable to use the backend CA certificates in %s %s: %s", caID.Kind, caID.resourceKey(), err)
+		glog.Error(logLine)
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidBackendCACertificate, logLine)
+	}
+	(*c.mem.backendCACerts)[caID] = certs
+	return certs
+}
+
+func (c *appGwConfigBuilder) newBackendCACertificates(caID backendCAIdentifier) ([]backendCACertificate, error) {
+	var bundle []byte
+	if caID.Kind == annotations.ConfigMapKind {
+		configMap := c.k8sContext.GetConfigMap(caID.resourceKey())
+		if configMap == nil {
+			return nil, ErrBackendCACertificateNotFound
+		}
+		bundle = []byte(configMap.Data[backendCABundleKey])
+	} else {
+		secret := c.k8sContext.GetSecret(caID.resourceKey())
+		if secret == nil {
+			return nil, ErrBackendCACertificateNotFound
+		}
+		bundle = secret.Data[backendCABundleKey]
+	}
+
+	var certs []backendCACertificate
+	for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, err
+		}
+		certs = append(certs, backendCACertificate{
+			Name: generateBackendCACertificateName(caID, len(certs)),
+			Data: base64.StdEncoding.EncodeToString(block.Bytes),
+		})
+	}
+	if len(certs) == 0 {
+		return nil, ErrNoBackendCACertificates
+	}
+	return certs, nil
+}
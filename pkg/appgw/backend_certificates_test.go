@@ -0,0 +1,252 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+func newCACertificateFixture(commonName string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	Expect(err).ToNot(HaveOccurred())
+	return der
+}
+
+var _ = Describe("Test the backend CA certificates of HTTPS backends", func() {
+	var rootCA, intermediateCA []byte
+	var configBuilder appGwConfigBuilder
+	var ingress *networking.Ingress
+	var cbCtx *ConfigBuilderContext
+
+	BeforeEach(func() {
+		rootCA = newCACertificateFixture("root-ca")
+		intermediateCA = newCACertificateFixture("intermediate-ca")
+		bundle := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootCA}), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediateCA})...)
+
+		configBuilder = newConfigBuilderFixture(nil)
+		endpoint := tests.NewEndpointsFixture()
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+		pod := tests.NewPodTestFixture(service.Namespace, "mybackend")
+		ingress = tests.NewIngressFixture()
+		ingress.Annotations[annotations.BackendProtocolKey] = "https"
+		ingress.Annotations[annotations.BackendCACertificateKey] = "backend-ca"
+		_ = configBuilder.k8sContext.Caches.Pods.Add(&pod)
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoint)
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+		_ = configBuilder.k8sContext.Caches.Secret.Add(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "backend-ca", Namespace: tests.Namespace},
+			Data:       map[string][]byte{"ca.crt": bundle},
+		})
+		_ = configBuilder.k8sContext.Caches.ConfigMap.Add(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "backend-ca", Namespace: tests.Namespace},
+			Data:       map[string]string{"ca.crt": string(bundle)},
+		})
+
+		cbCtx = &ConfigBuilderContext{
+			IngressList:  []*networking.Ingress{ingress},
+			ServiceList:  []*v1.Service{service},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+		cbCtx.EnvVariables.EnableBackendCAConfigMaps = true
+	})
+
+	// ingressSettings returns the HTTP settings of the ingress backends; the default settings never use the CA certificates.
+	ingressSettings := func() []n.ApplicationGatewayBackendHTTPSettings {
+		var settings []n.ApplicationGatewayBackendHTTPSettings
+		for _, setting := range *configBuilder.appGw.BackendHTTPSettingsCollection {
+			if *setting.Name != DefaultBackendHTTPSettingsName {
+				settings = append(settings, setting)
+			}
+		}
+		Expect(settings).ToNot(BeEmpty())
+		return settings
+	}
+
+	Context("with a v2 SKU", func() {
+		It("creates trusted root certificates and references them from the HTTP settings", func() {
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(configBuilder.appGw.AuthenticationCertificates).To(BeNil())
+			Expect(*configBuilder.appGw.TrustedRootCertificates).To(Equal([]n.ApplicationGatewayTrustedRootCertificate{
+				{
+					Etag: to.StringPtr("*"),
+					Name: to.StringPtr("ca---namespace---secret-backend-ca-0"),
+					ID:   to.StringPtr(configBuilder.appGwIdentifier.trustedRootCertificateID("ca---namespace---secret-backend-ca-0")),
+					ApplicationGatewayTrustedRootCertificatePropertiesFormat: &n.ApplicationGatewayTrustedRootCertificatePropertiesFormat{
+						Data: to.StringPtr(base64.StdEncoding.EncodeToString(rootCA)),
+					},
+				},
+				{
+					Etag: to.StringPtr("*"),
+					Name: to.StringPtr("ca---namespace---secret-backend-ca-1"),
+					ID:   to.StringPtr(configBuilder.appGwIdentifier.trustedRootCertificateID("ca---namespace---secret-backend-ca-1")),
+					ApplicationGatewayTrustedRootCertificatePropertiesFormat: &n.ApplicationGatewayTrustedRootCertificatePropertiesFormat{
+						Data: to.StringPtr(base64.StdEncoding.EncodeToString(intermediateCA)),
+					},
+				},
+			}))
+
+			for _, setting := range ingressSettings() {
+				Expect(setting.Protocol).To(Equal(n.HTTPS))
+				Expect(setting.AuthenticationCertificates).To(BeNil())
+				Expect(*setting.TrustedRootCertificates).To(Equal([]n.SubResource{
+					*resourceRef(configBuilder.appGwIdentifier.trustedRootCertificateID("ca---namespace---secret-backend-ca-0")),
+					*resourceRef(configBuilder.appGwIdentifier.trustedRootCertificateID("ca---namespace---secret-backend-ca-1")),
+				}))
+			}
+		})
+
+		It("reads the CA bundle from a ConfigMap", func() {
+			ingress.Annotations[annotations.BackendCACertificateKey] = "configmap/backend-ca"
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(len(*configBuilder.appGw.TrustedRootCertificates)).To(Equal(2))
+			Expect(*(*configBuilder.appGw.TrustedRootCertificates)[0].Name).To(Equal("ca---namespace---configmap-backend-ca-0"))
+			for _, setting := range ingressSettings() {
+				Expect(len(*setting.TrustedRootCertificates)).To(Equal(2))
+			}
+		})
+
+		It("keeps the trusted root certificates AGIC did not create in brownfield deployments", func() {
+			existing := n.ApplicationGatewayTrustedRootCertificate{Name: to.StringPtr("security-team-root")}
+			configBuilder.appGw.TrustedRootCertificates = &[]n.ApplicationGatewayTrustedRootCertificate{existing}
+			cbCtx.EnvVariables.EnableBrownfieldDeployment = true
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(len(*configBuilder.appGw.TrustedRootCertificates)).To(Equal(3))
+			Expect(*configBuilder.appGw.TrustedRootCertificates).To(ContainElement(existing))
+		})
+
+		It("replaces the existing trusted root certificates otherwise", func() {
+			configBuilder.appGw.TrustedRootCertificates = &[]n.ApplicationGatewayTrustedRootCertificate{{Name: to.StringPtr("stale-root")}}
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(len(*configBuilder.appGw.TrustedRootCertificates)).To(Equal(2))
+		})
+	})
+
+	Context("with a v1 SKU", func() {
+		It("creates authentication certificates and references them from the HTTP settings", func() {
+			configBuilder.appGw.Sku = &n.ApplicationGatewaySku{Name: n.StandardMedium, Tier: n.ApplicationGatewayTierStandard}
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(configBuilder.appGw.TrustedRootCertificates).To(BeNil())
+			Expect(len(*configBuilder.appGw.AuthenticationCertificates)).To(Equal(2))
+			authenticationCert := (*configBuilder.appGw.AuthenticationCertificates)[0]
+			Expect(*authenticationCert.ID).To(Equal(configBuilder.appGwIdentifier.authenticationCertificateID("ca---namespace---secret-backend-ca-0")))
+			Expect(*authenticationCert.Data).To(Equal(base64.StdEncoding.EncodeToString(rootCA)))
+
+			for _, setting := range ingressSettings() {
+				Expect(setting.TrustedRootCertificates).To(BeNil())
+				Expect(len(*setting.AuthenticationCertificates)).To(Equal(2))
+			}
+		})
+	})
+
+	Context("without usable CA certificates", func() {
+		It("ignores the annotation for HTTP backends", func() {
+			ingress.Annotations[annotations.BackendProtocolKey] = "http"
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(configBuilder.appGw.TrustedRootCertificates).To(BeNil())
+			for _, setting := range ingressSettings() {
+				Expect(setting.TrustedRootCertificates).To(BeNil())
+			}
+		})
+
+		It("emits an event when the Secret does not exist", func() {
+			ingress.Annotations[annotations.BackendCACertificateKey] = "secret/missing"
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(configBuilder.appGw.TrustedRootCertificates).To(BeNil())
+			for _, setting := range ingressSettings() {
+				Expect(setting.TrustedRootCertificates).To(BeNil())
+			}
+
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonInvalidBackendCACertificate))
+			Expect(strings.Contains(event, ErrBackendCACertificateNotFound.Error())).To(BeTrue(), event)
+		})
+
+		It("emits an event when the ConfigMaps are not enabled", func() {
+			cbCtx.EnvVariables.EnableBackendCAConfigMaps = false
+			ingress.Annotations[annotations.BackendCACertificateKey] = "configmap/backend-ca"
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(configBuilder.appGw.TrustedRootCertificates).To(BeNil())
+			event := <-configBuilder.recorder.(*record.FakeRecorder).Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonInvalidBackendCACertificate))
+			Expect(strings.Contains(event, environment.EnableBackendCAConfigMapsVarName)).To(BeTrue(), event)
+		})
+
+		It("emits an event when the CA bundle has no certificates", func() {
+			_ = configBuilder.k8sContext.Caches.ConfigMap.Update(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend-ca", Namespace: tests.Namespace},
+				Data:       map[string]string{"ca.crt": "not a certificate"},
+			})
+			ingress.Annotations[annotations.BackendCACertificateKey] = "configmap/backend-ca"
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(configBuilder.appGw.TrustedRootCertificates).To(BeNil())
+			event := <-configBuilder.recorder.(*record.FakeRecorder).Events
+			Expect(strings.Contains(event, ErrNoBackendCACertificates.Error())).To(BeTrue(), event)
+		})
+	})
+
+	Context("with the backend host name annotation", func() {
+		It("sets the host name of the HTTP settings and the probes", func() {
+			ingress.Annotations[annotations.BackendHostNameKey] = "backend.contoso.com"
+			Expect(configBuilder.HealthProbesCollection(cbCtx)).ToNot(HaveOccurred())
+			Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+
+			for _, setting := range ingressSettings() {
+				Expect(*setting.HostName).To(Equal("backend.contoso.com"))
+			}
+			for _, probe := range *configBuilder.appGw.Probes {
+				if strings.HasPrefix(*probe.Name, "defaultprobe") {
+					continue
+				}
+				Expect(*probe.Host).To(Equal("backend.contoso.com"))
+			}
+		})
+	})
+})
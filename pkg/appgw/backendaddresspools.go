@@ -9,7 +9,7 @@ import (
 	"fmt"
 	"sort"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
@@ -47,7 +47,7 @@ func (c appGwConfigBuilder) getPools(cbCtx *ConfigBuilderContext) []n.Applicatio
 	}
 
 	if cbCtx.EnvVariables.EnableIstioIntegration {
-		_, _, istioServiceBackendPairMap, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+		_, _, istioServiceBackendPairMap := c.getIstioDestinationsAndSettingsMap(cbCtx)
 		for destinationID, serviceBackendPair := range istioServiceBackendPairMap {
 			if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, managedPoolsByName); pool != nil {
 				managedPoolsByName[*pool.Name] = pool
@@ -56,13 +56,23 @@ func (c appGwConfigBuilder) getPools(cbCtx *ConfigBuilderContext) []n.Applicatio
 		}
 	}
 
+	if cbCtx.EnvVariables.EnableGatewayAPI {
+		_, _, gatewayAPIServiceBackendPairMap := c.getGatewayAPIBackendsAndSettingsMap(cbCtx)
+		for backendID, serviceBackendPair := range gatewayAPIServiceBackendPairMap {
+			if pool := c.getGatewayAPIBackendAddressPool(backendID, serviceBackendPair, managedPoolsByName); pool != nil {
+				managedPoolsByName[*pool.Name] = pool
+				glog.V(5).Infof("Created backend pool %s for service %s", *pool.Name, backendID.serviceKey())
+			}
+		}
+	}
+
 	var agicCreatedPools []n.ApplicationGatewayBackendAddressPool
 	for _, managedPool := range managedPoolsByName {
 		agicCreatedPools = append(agicCreatedPools, *managedPool)
 	}
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, &defaultPool)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, &defaultPool, cbCtx.FrontendIPAddresses)
 
 		// Split the existing pools we obtained from App Gateway into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedPools()
@@ -95,6 +105,14 @@ func (c *appGwConfigBuilder) newBackendPoolMap(cbCtx *ConfigBuilderContext) map[
 }
 
 func (c *appGwConfigBuilder) getBackendAddressPool(backendID backendIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+	if service := c.k8sContext.GetService(backendID.serviceKey()); isExternalNameService(service) {
+		poolName := generateAddressPoolName(backendID.serviceFullName(), serviceBackendPortToStr(backendID.Backend.Service.Port), serviceBackendPair.BackendPort)
+		if pool, ok := addressPools[poolName]; ok {
+			return pool
+		}
+		return c.newExternalNamePool(poolName, service)
+	}
+
 	endpoints, err := c.k8sContext.GetEndpointsByService(backendID.serviceKey())
 	if err != nil {
 		logLine := fmt.Sprintf("Failed fetching endpoints for service: %s", backendID.serviceKey())
@@ -105,7 +123,7 @@ func (c *appGwConfigBuilder) getBackendAddressPool(backendID backendIdentifier,
 
 	for _, subset := range endpoints.Subsets {
 		if _, portExists := getUniqueTCPPorts(subset)[serviceBackendPair.BackendPort]; portExists {
-			poolName := generateAddressPoolName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), serviceBackendPair.BackendPort)
+			poolName := generateAddressPoolName(backendID.serviceFullName(), serviceBackendPortToStr(backendID.Backend.Service.Port), serviceBackendPair.BackendPort)
 			// The same service might be referenced in multiple ingress resources, this might result in multiple `serviceBackendPairMap` having the same service key but different
 			// ingress resource. Thus, while generating the backend address pool, we should make sure that we are generating unique backend address pools.
 			if pool, ok := addressPools[poolName]; ok {
@@ -141,6 +159,18 @@ func (c *appGwConfigBuilder) newPool(poolName string, subset v1.EndpointSubset)
 	}
 }
 
+// newExternalNamePool creates a pool with the DNS name of an ExternalName service.
+func (c *appGwConfigBuilder) newExternalNamePool(poolName string, service *v1.Service) *n.ApplicationGatewayBackendAddressPool {
+	pool := c.newPool(poolName, v1.EndpointSubset{})
+	pool.BackendAddresses = &[]n.ApplicationGatewayBackendAddress{{Fqdn: to.StringPtr(service.Spec.ExternalName)}}
+	return pool
+}
+
+// isExternalNameService tells whether the service is an alias for a DNS name, which has no endpoints.
+func isExternalNameService(service *v1.Service) bool {
+	return service != nil && service.Spec.Type == v1.ServiceTypeExternalName
+}
+
 func getAddressesForSubset(subset v1.EndpointSubset) *[]n.ApplicationGatewayBackendAddress {
 	// We make separate maps for IP and FQDN to ensure uniqueness within the 2 groups
 	// We cannot use ApplicationGatewayBackendAddress as it contains pointer to strings and the same IP string
@@ -6,14 +6,18 @@
 package appgw
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
@@ -38,7 +42,7 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 	Context("build a list of BackendAddressPools", func() {
 		ing1 := tests.NewIngressFixture()
 		ing2 := tests.NewIngressFixture()
-		ingressList := []*v1beta1.Ingress{
+		ingressList := []*networking.Ingress{
 			ing1,
 			ing2,
 		}
@@ -66,7 +70,7 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 	})
 
 	Context("ensure unique IP addresses", func() {
-		ingressList := []*v1beta1.Ingress{tests.NewIngressFixture()}
+		ingressList := []*networking.Ingress{tests.NewIngressFixture()}
 		cb := newConfigBuilderFixture(nil)
 		for _, ingress := range ingressList {
 			_ = cb.k8sContext.Caches.Ingress.Add(ingress)
@@ -100,7 +104,7 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 	})
 
 	Context("ensure correct creation of ApplicationGatewayBackendAddress", func() {
-		ingressList := []*v1beta1.Ingress{tests.NewIngressFixture()}
+		ingressList := []*networking.Ingress{tests.NewIngressFixture()}
 		cb := newConfigBuilderFixture(nil)
 		for _, ingress := range ingressList {
 			_ = cb.k8sContext.Caches.Ingress.Add(ingress)
@@ -152,13 +156,93 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 							IPAddress: to.StringPtr("10.9.8.7"),
 						},
 					},
-					ProvisioningState: nil,
+					ProvisioningState: "",
 				},
 			}
 			Expect(*actual).To(Equal(expected))
 		})
 	})
 
+	Context("ensure ExternalName services are used as FQDN backends", func() {
+		var cb appGwConfigBuilder
+		var ingress *networking.Ingress
+		var cbCtx *ConfigBuilderContext
+
+		BeforeEach(func() {
+			cb = newConfigBuilderFixture(nil)
+			service := &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: tests.ServiceName, Namespace: tests.Namespace},
+				Spec: v1.ServiceSpec{
+					Type:         v1.ServiceTypeExternalName,
+					ExternalName: "contoso.azurewebsites.net",
+				},
+			}
+			_ = cb.k8sContext.Caches.Service.Add(service)
+			// The pod must not be mistaken for a backend of the service, which has no selector.
+			_ = cb.k8sContext.Caches.Pods.Add(tests.NewPodFixture(tests.ServiceName, tests.Namespace, tests.ContainerName, tests.ContainerPort))
+
+			backend := tests.NewIngressBackendFixture(tests.ServiceName, 443)
+			ingress = tests.NewIngressFixture()
+			ingress.Spec.TLS = nil
+			ingress.Spec.Rules = []networking.IngressRule{tests.NewIngressRuleFixture(tests.Host, "/", *backend)}
+			_ = cb.k8sContext.Caches.Ingress.Add(ingress)
+
+			cbCtx = &ConfigBuilderContext{
+				IngressList:  []*networking.Ingress{ingress},
+				ServiceList:  cb.k8sContext.ListServices(),
+				EnvVariables: environment.GetFakeEnv(),
+			}
+		})
+
+		It("should not report the service as missing", func() {
+			Expect(cbCtx.ServiceList).To(HaveLen(1))
+			Expect(validateServiceDefinition(cb.recorder, cb.appGw.ApplicationGatewayPropertiesFormat, cbCtx.EnvVariables, cbCtx.IngressList, cbCtx.ServiceList)).To(Succeed())
+			Expect(cb.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+		})
+
+		It("should create a pool with the DNS name and the port of the ingress backend", func() {
+			Expect(cb.BackendAddressPools(cbCtx)).To(Succeed())
+
+			poolName := generateAddressPoolName(tests.Namespace+"-"+tests.ServiceName, "443", 443)
+			Expect(*cb.appGw.BackendAddressPools).To(ContainElement(n.ApplicationGatewayBackendAddressPool{
+				Etag: to.StringPtr("*"),
+				Name: to.StringPtr(poolName),
+				ID:   to.StringPtr(cb.appGwIdentifier.AddressPoolID(poolName)),
+				ApplicationGatewayBackendAddressPoolPropertiesFormat: &n.ApplicationGatewayBackendAddressPoolPropertiesFormat{
+					BackendAddresses: &[]n.ApplicationGatewayBackendAddress{{Fqdn: to.StringPtr("contoso.azurewebsites.net")}},
+				},
+			}))
+		})
+
+		It("should pick the host name from the backend address for the settings and the probe", func() {
+			_, settingsByBackend, _, err := cb.getBackendsAndSettingsMap(cbCtx)
+			Expect(err).ToNot(HaveOccurred())
+			_, probesByBackend := cb.newProbesMap(cbCtx)
+
+			Expect(settingsByBackend).To(HaveLen(1))
+			for backendID, settings := range settingsByBackend {
+				Expect(*settings.Port).To(Equal(int32(443)))
+				Expect(*settings.PickHostNameFromBackendAddress).To(BeTrue())
+				Expect(settings.HostName).To(BeNil())
+
+				probe := probesByBackend[backendID]
+				Expect(*probe.PickHostNameFromBackendHTTPSettings).To(BeTrue())
+				Expect(probe.Host).To(BeNil())
+				Expect(probe.Port).To(BeNil())
+			}
+		})
+
+		It("should send the host of the request when the annotation says so", func() {
+			ingress.Annotations[annotations.PickHostNameFromBackendKey] = "false"
+			_, settingsByBackend, _, err := cb.getBackendsAndSettingsMap(cbCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, settings := range settingsByBackend {
+				Expect(settings.PickHostNameFromBackendAddress).To(BeNil())
+			}
+		})
+	})
+
 	Context("Test Istio components", func() {
 		cb := newConfigBuilderFixture(nil)
 		istioDest := istioDestinationIdentifier{}
@@ -216,7 +300,7 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 						DefaultRewriteRuleSet:        nil,
 						DefaultRedirectConfiguration: nil,
 						PathRules:                    &[]n.ApplicationGatewayPathRule{},
-						ProvisioningState:            nil,
+						ProvisioningState:            "",
 					},
 					Name: to.StringPtr("url-80"),
 					Etag: to.StringPtr("*"),
@@ -237,12 +321,11 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 			expectedSettinsgPerDestination := map[istioDestinationIdentifier]*n.ApplicationGatewayBackendHTTPSettings{}
 			expectedPortsPerDestination := map[istioDestinationIdentifier]serviceBackendPortPair{}
 
-			settingsList, settingsPerDestination, portsPerDestination, err := cb.getIstioDestinationsAndSettingsMap(cbCtx)
+			settingsList, settingsPerDestination, portsPerDestination := cb.getIstioDestinationsAndSettingsMap(cbCtx)
 
 			Expect(expectedSettingsList).To(Equal(settingsList))
 			Expect(expectedSettinsgPerDestination).To(Equal(settingsPerDestination))
 			Expect(expectedPortsPerDestination).To(Equal(portsPerDestination))
-			Expect(err).ToNot(HaveOccurred())
 		})
 	})
 })
@@ -9,10 +9,11 @@ import (
 	"fmt"
 	"sort"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
@@ -20,6 +21,7 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
 const (
@@ -31,7 +33,7 @@ func (c *appGwConfigBuilder) BackendHTTPSettingsCollection(cbCtx *ConfigBuilderC
 	agicHTTPSettings, _, _, err := c.getBackendsAndSettingsMap(cbCtx)
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil, cbCtx.FrontendIPAddresses)
 		allExistingSettings := rCtx.HTTPSettings
 
 		// PathMaps we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
@@ -44,18 +46,24 @@ func (c *appGwConfigBuilder) BackendHTTPSettingsCollection(cbCtx *ConfigBuilderC
 		agicHTTPSettings = brownfield.MergeHTTPSettings(allExistingSettings, agicHTTPSettings)
 	}
 	if cbCtx.EnvVariables.EnableIstioIntegration {
-		istioHTTPSettings, _, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+		istioHTTPSettings, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
 		if istioHTTPSettings != nil {
 			sort.Sort(sorter.BySettingsName(istioHTTPSettings))
 		}
 		agicHTTPSettings = append(agicHTTPSettings, istioHTTPSettings...)
 	}
 
+	if cbCtx.EnvVariables.EnableGatewayAPI {
+		gatewayAPIHTTPSettings, _, _ := c.getGatewayAPIBackendsAndSettingsMap(cbCtx)
+		agicHTTPSettings = append(agicHTTPSettings, gatewayAPIHTTPSettings...)
+	}
+
 	if agicHTTPSettings != nil {
 		sort.Sort(sorter.BySettingsName(agicHTTPSettings))
 	}
 
 	c.appGw.BackendHTTPSettingsCollection = &agicHTTPSettings
+	c.backendCACertificates(cbCtx)
 	return err
 }
 
@@ -88,10 +96,19 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 			c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonServiceNotFound, logLine)
 			glog.Errorf(logLine)
 			pair := serviceBackendPortPair{
-				ServicePort: Port(backendID.Backend.ServicePort.IntVal),
-				BackendPort: Port(backendID.Backend.ServicePort.IntVal),
+				ServicePort: Port(backendID.Backend.Service.Port.Number),
+				BackendPort: Port(backendID.Backend.Service.Port.Number),
 			}
 			resolvedBackendPorts[pair] = nil
+		} else if isExternalNameService(service) {
+			// ExternalName services have no endpoints; App Gateway connects to the DNS name on the port of the Ingress backend.
+			if port := externalNameServicePort(service, backendID.Backend.Service.Port); port != 0 {
+				pair := serviceBackendPortPair{
+					ServicePort: port,
+					BackendPort: port,
+				}
+				resolvedBackendPorts[pair] = nil
+			}
 		} else {
 			for _, sp := range service.Spec.Ports {
 				// find the backend port number
@@ -100,9 +117,9 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 					// ignore UDP ports
 					continue
 				}
-				if fmt.Sprint(sp.Port) == backendID.Backend.ServicePort.String() ||
-					sp.Name == backendID.Backend.ServicePort.String() ||
-					sp.TargetPort.String() == backendID.Backend.ServicePort.String() {
+				if fmt.Sprint(sp.Port) == serviceBackendPortToStr(backendID.Backend.Service.Port) ||
+					sp.Name == serviceBackendPortToStr(backendID.Backend.Service.Port) ||
+					sp.TargetPort.String() == serviceBackendPortToStr(backendID.Backend.Service.Port) {
 					// matched a service port with a port from the service
 
 					if sp.TargetPort.String() == "" {
@@ -123,7 +140,7 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 							resolvedBackendPorts[pair] = nil
 						} else {
 							// if service port is defined by name, need to resolve
-							glog.V(5).Infof("resolving port name [%s] for service [%s] and service port [%s] for Ingress [%s]", sp.Name, backendID.serviceKey(), backendID.Backend.ServicePort.String(), backendID.Ingress.Name)
+							glog.V(5).Infof("resolving port name [%s] for service [%s] and service port [%s] for Ingress [%s]", sp.Name, backendID.serviceKey(), serviceBackendPortToStr(backendID.Backend.Service.Port), backendID.Ingress.Name)
 							targetPortsResolved := c.resolvePortName(sp.Name, &backendID)
 							for targetPort := range targetPortsResolved {
 								pair := serviceBackendPortPair{
@@ -140,7 +157,7 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 		}
 
 		if len(resolvedBackendPorts) == 0 {
-			logLine := fmt.Sprintf("unable to resolve any backend port for service [%s] and service port [%s] for Ingress [%s]", backendID.serviceKey(), backendID.Backend.ServicePort.String(), backendID.Ingress.Name)
+			logLine := fmt.Sprintf("unable to resolve any backend port for service [%s] and service port [%s] for Ingress [%s]", backendID.serviceKey(), serviceBackendPortToStr(backendID.Backend.Service.Port), backendID.Ingress.Name)
 			c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonPortResolutionError, logLine)
 			glog.Error(logLine)
 
@@ -165,12 +182,16 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 	defaultBackend := defaultBackendHTTPSettings(c.appGwIdentifier, n.HTTP)
 	httpSettingsCollection[*defaultBackend.Name] = defaultBackend
 
+	// Backends of different Ingresses may share HTTP settings, as their names don't identify the Ingress uniquely;
+	// the settings of the Ingress sorting first by namespace and name are used.
+	settingsOwners := make(map[string]backendIdentifier)
+
 	// enforce single pair relationship between service port and backend port
 	for backendID, serviceBackendPairs := range serviceBackendPairsMap {
 		if len(serviceBackendPairs) > 1 {
 			// more than one possible backend port exposed through ingress
 			logLine := fmt.Sprintf("service:port [%s:%s] has more than one service-backend port binding",
-				backendID.serviceKey(), backendID.Backend.ServicePort.String())
+				backendID.serviceKey(), serviceBackendPortToStr(backendID.Backend.Service.Port))
 			c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonPortResolutionError, logLine)
 			glog.Warning(logLine)
 			return nil, nil, nil, ErrMultipleServiceBackendPortBinding
@@ -185,6 +206,24 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 		finalServiceBackendPairMap[backendID] = uniquePair
 		httpSettings := c.generateHTTPSettings(backendID, uniquePair.BackendPort, cbCtx)
 		glog.V(5).Infof("Created backend http settings %s for ingress %s/%s and service %s", *httpSettings.Name, backendID.Ingress.Namespace, backendID.Ingress.Name, backendID.serviceKey())
+		ingressKey := utils.GetResourceKey(backendID.Ingress.Namespace, backendID.Ingress.Name)
+		if owner, exists := settingsOwners[*httpSettings.Name]; exists {
+			ownerKey := utils.GetResourceKey(owner.Ingress.Namespace, owner.Ingress.Name)
+			existing := httpSettingsCollection[*httpSettings.Name]
+			if !sameSessionAffinity(existing, httpSettings) {
+				logLine := fmt.Sprintf("Ingresses %s and %s share HTTP settings %s with different session affinity; only one of them is applied", ownerKey, ingressKey, *httpSettings.Name)
+				glog.Warning(logLine)
+				c.recorder.Event(owner.Ingress, v1.EventTypeWarning, events.ReasonSessionAffinityMismatch, logLine)
+				c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonSessionAffinityMismatch, logLine)
+			}
+			if ownerKey <= ingressKey {
+				httpSettings = existing
+			} else {
+				settingsOwners[*httpSettings.Name] = backendID
+			}
+		} else {
+			settingsOwners[*httpSettings.Name] = backendID
+		}
 		httpSettingsCollection[*httpSettings.Name] = httpSettings
 		backendHTTPSettingsMap[backendID] = &httpSettings
 	}
@@ -201,7 +240,7 @@ func (c *appGwConfigBuilder) getBackendsAndSettingsMap(cbCtx *ConfigBuilderConte
 }
 
 func (c *appGwConfigBuilder) generateHTTPSettings(backendID backendIdentifier, port Port, cbCtx *ConfigBuilderContext) n.ApplicationGatewayBackendHTTPSettings {
-	httpSettingsName := generateHTTPSettingsName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), port, backendID.Ingress.Name)
+	httpSettingsName := generateHTTPSettingsName(backendID.serviceFullName(), serviceBackendPortToStr(backendID.Backend.Service.Port), port, backendID.Ingress.Name)
 	httpSettings := n.ApplicationGatewayBackendHTTPSettings{
 		Etag: to.StringPtr("*"),
 		Name: &httpSettingsName,
@@ -240,8 +279,14 @@ func (c *appGwConfigBuilder) generateHTTPSettings(backendID backendIdentifier, p
 		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
 	}
 
-	if affinity, err := annotations.IsCookieBasedAffinity(backendID.Ingress); err == nil && affinity {
+	if affinity, err := c.cookieBasedAffinity(backendID); err == nil && affinity {
 		httpSettings.CookieBasedAffinity = n.Enabled
+
+		if cookieName, err := annotations.AffinityCookieName(backendID.Ingress); err == nil {
+			httpSettings.AffinityCookieName = to.StringPtr(cookieName)
+		} else if !errors.IsMissingAnnotations(err) {
+			c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+		}
 	} else if err != nil && !errors.IsMissingAnnotations(err) {
 		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
 	}
@@ -258,5 +303,65 @@ func (c *appGwConfigBuilder) generateHTTPSettings(backendID backendIdentifier, p
 		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
 	}
 
+	if caRef, err := annotations.BackendCACertificate(backendID.Ingress); err == nil && httpSettings.Protocol == n.HTTPS {
+		caID := backendCAIdentifier{Namespace: backendID.Ingress.Namespace, Kind: caRef.Kind, Name: caRef.Name}
+		c.setBackendCACertificates(cbCtx, &httpSettings, caID, backendID.Ingress)
+	} else if err != nil && !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
+	if hostName, err := annotations.BackendHostName(backendID.Ingress); err == nil {
+		httpSettings.HostName = to.StringPtr(hostName)
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
+	// App Gateway sends either a fixed host name or the one of the backend address.
+	if pickHostName, err := c.pickHostNameFromBackend(backendID); err == nil && pickHostName && httpSettings.HostName == nil {
+		httpSettings.PickHostNameFromBackendAddress = to.BoolPtr(true)
+	} else if err != nil && !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(backendID.Ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
 	return httpSettings
 }
+
+// cookieBasedAffinity tells whether App Gateway pins the clients of the backend to a pod with a cookie. Unless the annotation says
+// otherwise it does for services with ClientIP session affinity, which App Gateway can't provide, as all requests come from its subnet.
+func (c *appGwConfigBuilder) cookieBasedAffinity(backendID backendIdentifier) (bool, error) {
+	affinity, err := annotations.IsCookieBasedAffinity(backendID.Ingress)
+	if errors.IsMissingAnnotations(err) {
+		service := c.k8sContext.GetService(backendID.serviceKey())
+		return service != nil && service.Spec.SessionAffinity == v1.ServiceAffinityClientIP, nil
+	}
+	return affinity, err
+}
+
+// sameSessionAffinity tells whether two HTTP settings pin clients the same way.
+func sameSessionAffinity(settings, other n.ApplicationGatewayBackendHTTPSettings) bool {
+	return settings.CookieBasedAffinity == other.CookieBasedAffinity &&
+		to.String(settings.AffinityCookieName) == to.String(other.AffinityCookieName)
+}
+
+// pickHostNameFromBackend tells whether App Gateway sends the host name of the backend address to the backend. Unless the annotation says
+// otherwise it does for ExternalName services, which usually point outside the cluster at endpoints only answering to their own host name.
+func (c *appGwConfigBuilder) pickHostNameFromBackend(backendID backendIdentifier) (bool, error) {
+	pickHostName, err := annotations.IsPickHostNameFromBackend(backendID.Ingress)
+	if errors.IsMissingAnnotations(err) {
+		return isExternalNameService(c.k8sContext.GetService(backendID.serviceKey())), nil
+	}
+	return pickHostName, err
+}
+
+// externalNameServicePort returns the port of the Ingress backend, resolving a port name with the ports of the ExternalName service; 0 when unresolved.
+func externalNameServicePort(service *v1.Service, backendPort networking.ServiceBackendPort) Port {
+	if backendPort.Number != 0 {
+		return Port(backendPort.Number)
+	}
+	for _, sp := range service.Spec.Ports {
+		if sp.Name == backendPort.Name && sp.Protocol == v1.ProtocolTCP {
+			return Port(sp.Port)
+		}
+	}
+	return 0
+}
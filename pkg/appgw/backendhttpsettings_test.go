@@ -6,14 +6,18 @@
 package appgw
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
@@ -43,7 +47,7 @@ var _ = Describe("Test the creation of Backend http settings from Ingress defini
 			Expect(annotations.BackendProtocol(ingress)).To(Equal(protocolEnum))
 
 			cbCtx := &ConfigBuilderContext{
-				IngressList: []*v1beta1.Ingress{ingress},
+				IngressList: []*networking.Ingress{ingress},
 				ServiceList: []*v1.Service{service},
 			}
 
@@ -72,4 +76,155 @@ var _ = Describe("Test the creation of Backend http settings from Ingress defini
 			checkBackendProtocolAnnotation("HttP", annotations.HTTP, n.HTTP)
 		})
 	})
+
+	Context("test host name annotations configure httpsettings and probes consistently", func() {
+		// checkHostName generates the backend http settings and probes with the given annotations, and checks the non default ones.
+		checkHostName := func(ingressAnnotations map[string]string, check func(n.ApplicationGatewayBackendHTTPSettings, n.ApplicationGatewayProbe)) {
+			for key, val := range ingressAnnotations {
+				ingress.Annotations[key] = val
+			}
+			defer func() {
+				for key := range ingressAnnotations {
+					delete(ingress.Annotations, key)
+				}
+			}()
+
+			cbCtx := &ConfigBuilderContext{
+				IngressList: []*networking.Ingress{ingress},
+				ServiceList: []*v1.Service{service},
+			}
+			configBuilder.mem = memoization{}
+			probes, _ := configBuilder.newProbesMap(cbCtx)
+			httpSettings, _, _, _ := configBuilder.getBackendsAndSettingsMap(cbCtx)
+
+			for _, setting := range httpSettings {
+				if *setting.Name != DefaultBackendHTTPSettingsName {
+					check(setting, probes[utils.GetLastChunkOfSlashed(*setting.Probe.ID)])
+				}
+			}
+		}
+
+		It("should send the backend host name with the requests and the probes", func() {
+			checkHostName(map[string]string{annotations.BackendHostNameKey: "www.contoso.com"}, func(setting n.ApplicationGatewayBackendHTTPSettings, probe n.ApplicationGatewayProbe) {
+				Expect(*setting.HostName).To(Equal("www.contoso.com"))
+				Expect(setting.PickHostNameFromBackendAddress).To(BeNil())
+				Expect(*probe.Host).To(Equal("www.contoso.com"))
+				Expect(probe.PickHostNameFromBackendHTTPSettings).To(BeNil())
+			})
+		})
+
+		It("should pick the host name from the backend address for the requests and the probes", func() {
+			checkHostName(map[string]string{annotations.PickHostNameFromBackendKey: "true"}, func(setting n.ApplicationGatewayBackendHTTPSettings, probe n.ApplicationGatewayProbe) {
+				Expect(setting.HostName).To(BeNil())
+				Expect(*setting.PickHostNameFromBackendAddress).To(BeTrue())
+				Expect(probe.Host).To(BeNil())
+				Expect(*probe.PickHostNameFromBackendHTTPSettings).To(BeTrue())
+			})
+		})
+
+		It("should prefer the backend host name over picking the host name from the backend address", func() {
+			checkHostName(map[string]string{annotations.BackendHostNameKey: "www.contoso.com", annotations.PickHostNameFromBackendKey: "true"}, func(setting n.ApplicationGatewayBackendHTTPSettings, probe n.ApplicationGatewayProbe) {
+				Expect(*setting.HostName).To(Equal("www.contoso.com"))
+				Expect(setting.PickHostNameFromBackendAddress).To(BeNil())
+				Expect(*probe.Host).To(Equal("www.contoso.com"))
+				Expect(probe.PickHostNameFromBackendHTTPSettings).To(BeNil())
+			})
+		})
+	})
+})
+
+var _ = Describe("Test the session affinity of Backend http settings", func() {
+	var configBuilder appGwConfigBuilder
+
+	// newBackend creates an Ingress named web with a single path to port 80 of the service, which has the given session affinity.
+	newBackend := func(namespace, serviceName string, sessionAffinity v1.ServiceAffinity, ingressAnnotations map[string]string) (*networking.Ingress, *v1.Service) {
+		service := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace},
+			Spec: v1.ServiceSpec{
+				Ports:           []v1.ServicePort{{Name: "http", Protocol: v1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt(8080)}},
+				SessionAffinity: sessionAffinity,
+			},
+		}
+		ingress := tests.NewIngressFixture()
+		ingress.Namespace = namespace
+		ingress.Name = "web"
+		ingress.Annotations = ingressAnnotations
+		ingress.Spec.TLS = nil
+		ingress.Spec.Rules = []networking.IngressRule{tests.NewIngressRuleFixture(tests.Host, "/", *tests.NewIngressBackendFixture(serviceName, 80))}
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+		return ingress, service
+	}
+
+	// settingsOf returns the http settings of the ingresses except the default ones.
+	settingsOf := func(ingresses []*networking.Ingress, services []*v1.Service) []n.ApplicationGatewayBackendHTTPSettings {
+		cbCtx := &ConfigBuilderContext{
+			IngressList: ingresses,
+			ServiceList: services,
+		}
+		httpSettings, _, _, err := configBuilder.getBackendsAndSettingsMap(cbCtx)
+		Expect(err).ToNot(HaveOccurred())
+
+		var settings []n.ApplicationGatewayBackendHTTPSettings
+		for _, setting := range httpSettings {
+			if *setting.Name != DefaultBackendHTTPSettingsName {
+				settings = append(settings, setting)
+			}
+		}
+		return settings
+	}
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+	})
+
+	It("should name the affinity cookie", func() {
+		ingress, service := newBackend(tests.Namespace, "shop", v1.ServiceAffinityNone, map[string]string{
+			annotations.CookieBasedAffinityKey: "true",
+			annotations.AffinityCookieNameKey:  "shop-affinity",
+		})
+
+		settings := settingsOf([]*networking.Ingress{ingress}, []*v1.Service{service})
+		Expect(settings).To(HaveLen(1))
+		Expect(settings[0].CookieBasedAffinity).To(Equal(n.Enabled))
+		Expect(*settings[0].AffinityCookieName).To(Equal("shop-affinity"))
+	})
+
+	It("should enable cookie affinity for services with ClientIP session affinity", func() {
+		ingress, service := newBackend(tests.Namespace, "shop", v1.ServiceAffinityClientIP, map[string]string{})
+
+		settings := settingsOf([]*networking.Ingress{ingress}, []*v1.Service{service})
+		Expect(settings).To(HaveLen(1))
+		Expect(settings[0].CookieBasedAffinity).To(Equal(n.Enabled))
+		Expect(settings[0].AffinityCookieName).To(BeNil())
+	})
+
+	It("should prefer the annotation over the session affinity of the service", func() {
+		ingress, service := newBackend(tests.Namespace, "shop", v1.ServiceAffinityClientIP, map[string]string{
+			annotations.CookieBasedAffinityKey: "false",
+		})
+
+		settings := settingsOf([]*networking.Ingress{ingress}, []*v1.Service{service})
+		Expect(settings).To(HaveLen(1))
+		Expect(settings[0].CookieBasedAffinity).To(BeEmpty())
+	})
+
+	It("should report Ingresses sharing http settings with different session affinity", func() {
+		// The http settings names of prod-web/api and prod/web-api are both bp-prod-web-api-80-8080-web
+		ingress1, service1 := newBackend("prod-web", "api", v1.ServiceAffinityNone, map[string]string{annotations.CookieBasedAffinityKey: "true"})
+		ingress2, service2 := newBackend("prod", "web-api", v1.ServiceAffinityNone, map[string]string{})
+
+		settings := settingsOf([]*networking.Ingress{ingress1, ingress2}, []*v1.Service{service1, service2})
+		Expect(settings).To(HaveLen(1))
+		// The settings of prod-web/web are used, as it sorts first
+		Expect(settings[0].CookieBasedAffinity).To(Equal(n.Enabled))
+
+		recorder := configBuilder.recorder.(*record.FakeRecorder)
+		Expect(len(recorder.Events)).To(Equal(2))
+		for range []int{1, 2} {
+			event := <-recorder.Events
+			Expect(event).To(ContainSubstring(events.ReasonSessionAffinityMismatch))
+			Expect(event).To(ContainSubstring(*settings[0].Name))
+		}
+	})
 })
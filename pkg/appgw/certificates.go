@@ -8,15 +8,21 @@ package appgw
 import (
 	"encoding/base64"
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
 )
 
@@ -25,18 +31,52 @@ func (c *appGwConfigBuilder) getSslCertificates(cbCtx *ConfigBuilderContext) *[]
 	if c.mem.certs != nil {
 		return c.mem.certs
 	}
-	secretIDCertificateMap := make(map[secretIdentifier]*string)
+	secretIDCertificateMap := make(map[secretIdentifier]*k8scontext.PfxCertificate)
+	keyVaultCertificateMap := make(map[string]string)
+	installedCertificateNames := make(map[string]interface{})
 
 	for _, ingress := range cbCtx.IngressList {
+		// A certificate installed on App Gateway takes precedence over Key Vault and the TLS secrets.
+		if installedCertificateName, err := annotations.AppGwSslCertificate(ingress); err == nil {
+			installedCertificateNames[installedCertificateName] = nil
+			continue
+		}
+
+		// The Key Vault certificate takes precedence; the TLS secrets of the ingress would not be used by any listener.
+		if keyVaultSecretID, err := annotations.KeyVaultSecretID(ingress); err == nil {
+			keyVaultCertificateMap[generateKeyVaultCertificateName(keyVaultSecretID)] = keyVaultSecretID
+			continue
+		} else if !errors.IsMissingAnnotations(err) {
+			glog.Errorf("Ingress %s/%s has an invalid Key Vault secret ID; using the TLS secrets instead: %s", ingress.Namespace, ingress.Name, err)
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+		}
+
 		for k, v := range c.getSecretToCertificateMap(ingress) {
 			secretIDCertificateMap[k] = v
 		}
 	}
 
+	if cbCtx.EnvVariables.EnableIstioIntegration {
+		for k, v := range c.getIstioSecretToCertificateMap(cbCtx) {
+			secretIDCertificateMap[k] = v
+		}
+	}
+
+	if cbCtx.EnvVariables.EnableGatewayAPI {
+		for k, v := range c.getGatewayAPISecretToCertificateMap(cbCtx) {
+			secretIDCertificateMap[k] = v
+		}
+	}
+
 	var sslCertificates []n.ApplicationGatewaySslCertificate
 	for secretID, cert := range secretIDCertificateMap {
 		sslCertificates = append(sslCertificates, c.newCert(secretID, cert))
 	}
+	for certName, keyVaultSecretID := range keyVaultCertificateMap {
+		sslCertificates = append(sslCertificates, c.newKeyVaultCert(certName, keyVaultSecretID))
+	}
+
+	sslCertificates = c.keepInstalledCertificates(sslCertificates, installedCertificateNames)
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
 		// MergePools would produce unique list of pools based on Name. Blacklisted pools, which have the same name
@@ -49,8 +89,57 @@ func (c *appGwConfigBuilder) getSslCertificates(cbCtx *ConfigBuilderContext) *[]
 	return &sslCertificates
 }
 
-func (c *appGwConfigBuilder) getSecretToCertificateMap(ingress *v1beta1.Ingress) map[secretIdentifier]*string {
-	secretIDCertificateMap := make(map[secretIdentifier]*string)
+// keepInstalledCertificates copies the certificates of the existing App Gateway config, which AGIC did not create, as they are,
+// replacing any generated certificate with the same name; AGIC must never delete or overwrite them. A certificate referenced by
+// an ingress is always kept; any other certificate is kept unless its name follows AGIC's naming of certificates.
+func (c *appGwConfigBuilder) keepInstalledCertificates(sslCertificates []n.ApplicationGatewaySslCertificate, installedCertificateNames map[string]interface{}) []n.ApplicationGatewaySslCertificate {
+	if c.appGw.SslCertificates == nil {
+		return sslCertificates
+	}
+
+	generated := make(map[string]interface{})
+	for _, cert := range sslCertificates {
+		generated[*cert.Name] = nil
+	}
+
+	installed := make(map[string]interface{})
+	var installedCertificates []n.ApplicationGatewaySslCertificate
+	for _, cert := range *c.appGw.SslCertificates {
+		if cert.Name == nil {
+			continue
+		}
+		_, referenced := installedCertificateNames[*cert.Name]
+		_, isGenerated := generated[*cert.Name]
+		if !referenced && (isGenerated || isAGICCertificateName(*cert.Name)) {
+			continue
+		}
+		installed[*cert.Name] = nil
+		installedCertificates = append(installedCertificates, cert)
+	}
+
+	var kept []n.ApplicationGatewaySslCertificate
+	for _, cert := range sslCertificates {
+		if _, exists := installed[*cert.Name]; exists {
+			glog.Warningf("Not overwriting certificate %s installed on App Gateway", *cert.Name)
+			continue
+		}
+		kept = append(kept, cert)
+	}
+	return append(kept, installedCertificates...)
+}
+
+// agicSecretCertificateName matches the names AGIC gives the certificates of TLS secrets: the namespace and the name of the secret,
+// joined with a hyphen.
+var agicSecretCertificateName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?-[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`)
+
+// isAGICCertificateName tells whether AGIC could have created a certificate with this name, for a TLS secret or a Key Vault secret ID.
+// It does not depend on the secrets in the cluster, so that the certificates of deleted secrets are removed too.
+func isAGICCertificateName(name string) bool {
+	return strings.HasPrefix(name, agPrefix+prefixKeyVaultCert+"-") || agicSecretCertificateName.MatchString(name)
+}
+
+func (c *appGwConfigBuilder) getSecretToCertificateMap(ingress *networking.Ingress) map[secretIdentifier]*k8scontext.PfxCertificate {
+	secretIDCertificateMap := make(map[secretIdentifier]*k8scontext.PfxCertificate)
 	for _, tls := range ingress.Spec.TLS {
 		if len(tls.SecretName) == 0 {
 			continue
@@ -63,7 +152,7 @@ func (c *appGwConfigBuilder) getSecretToCertificateMap(ingress *v1beta1.Ingress)
 
 		// add hostname-tlsSecret mapping to a per-ingress map
 		if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(tlsSecret.secretKey()); cert != nil {
-			secretIDCertificateMap[tlsSecret] = to.StringPtr(base64.StdEncoding.EncodeToString(cert))
+			secretIDCertificateMap[tlsSecret] = cert
 		} else {
 			logLine := fmt.Sprintf("Unable to find the secret associated to secretId: [%s]", tlsSecret.secretKey())
 			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonSecretNotFound, logLine)
@@ -72,7 +161,7 @@ func (c *appGwConfigBuilder) getSecretToCertificateMap(ingress *v1beta1.Ingress)
 	return secretIDCertificateMap
 }
 
-func (c *appGwConfigBuilder) getCertificate(ingress *v1beta1.Ingress, hostname string, hostnameSecretIDMap map[string]secretIdentifier) (*string, *secretIdentifier) {
+func (c *appGwConfigBuilder) getCertificate(ingress *networking.Ingress, hostname string, hostnameSecretIDMap map[string]secretIdentifier) (*string, *secretIdentifier) {
 	if hostnameSecretIDMap == nil {
 		return nil, nil
 	}
@@ -91,10 +180,10 @@ func (c *appGwConfigBuilder) getCertificate(ingress *v1beta1.Ingress, hostname s
 		// secret referred does not correspond to a certificate
 		return nil, nil
 	}
-	return cert, &secID
+	return to.StringPtr(base64.StdEncoding.EncodeToString(cert.Data)), &secID
 }
 
-func (c *appGwConfigBuilder) newHostToSecretMap(ingress *v1beta1.Ingress) map[string]secretIdentifier {
+func (c *appGwConfigBuilder) newHostToSecretMap(ingress *networking.Ingress) map[string]secretIdentifier {
 	hostToSecretMap := make(map[string]secretIdentifier)
 	for _, tls := range ingress.Spec.TLS {
 		if len(tls.SecretName) == 0 {
@@ -129,14 +218,26 @@ func (c *appGwConfigBuilder) newHostToSecretMap(ingress *v1beta1.Ingress) map[st
 	return hostToSecretMap
 }
 
-func (c *appGwConfigBuilder) newCert(secretID secretIdentifier, cert *string) n.ApplicationGatewaySslCertificate {
+func (c *appGwConfigBuilder) newCert(secretID secretIdentifier, cert *k8scontext.PfxCertificate) n.ApplicationGatewaySslCertificate {
 	return n.ApplicationGatewaySslCertificate{
 		Etag: to.StringPtr("*"),
 		Name: to.StringPtr(secretID.secretFullName()),
 		ID:   to.StringPtr(c.appGwIdentifier.sslCertificateID(secretID.secretFullName())),
 		ApplicationGatewaySslCertificatePropertiesFormat: &n.ApplicationGatewaySslCertificatePropertiesFormat{
-			Data:     cert,
-			Password: to.StringPtr("msazure"),
+			Data:     to.StringPtr(base64.StdEncoding.EncodeToString(cert.Data)),
+			Password: to.StringPtr(cert.Password),
+		},
+	}
+}
+
+// newKeyVaultCert references a certificate, which App Gateway fetches from Key Vault with its managed identity.
+func (c *appGwConfigBuilder) newKeyVaultCert(certName string, keyVaultSecretID string) n.ApplicationGatewaySslCertificate {
+	return n.ApplicationGatewaySslCertificate{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(certName),
+		ID:   to.StringPtr(c.appGwIdentifier.sslCertificateID(certName)),
+		ApplicationGatewaySslCertificatePropertiesFormat: &n.ApplicationGatewaySslCertificatePropertiesFormat{
+			KeyVaultSecretID: to.StringPtr(keyVaultSecretID),
 		},
 	}
 }
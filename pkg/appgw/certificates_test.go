@@ -1,9 +1,20 @@
 package appgw
 
 import (
-	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
 // appgw_suite_test.go launches these Ginkgo tests
@@ -77,3 +88,195 @@ var _ = Describe("Testing function newHostToSecretMap", func() {
 		})
 	})
 })
+
+var _ = Describe("Testing certificates from Key Vault", func() {
+	const keyVaultSecretID = "https://contoso-vault.vault.azure.net/secrets/www-contoso-com"
+	keyVaultCertName := "kv-contoso-vault-www-contoso-com"
+
+	Context("Test an ingress annotated with a Key Vault secret ID", func() {
+		cb := newConfigBuilderFixture(nil)
+		ingress := tests.NewIngressFixture()
+		ingress.Annotations[annotations.KeyVaultSecretIDKey] = keyVaultSecretID
+		cbCtx := &ConfigBuilderContext{
+			IngressList:  []*networking.Ingress{ingress},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+
+		It("should reference the Key Vault secret instead of embedding the TLS secret", func() {
+			certs := cb.getSslCertificates(cbCtx)
+			Expect(*certs).To(HaveLen(1))
+			cert := (*certs)[0]
+			Expect(*cert.Name).To(Equal(keyVaultCertName))
+			Expect(*cert.ID).To(Equal(cb.appGwIdentifier.sslCertificateID(keyVaultCertName)))
+			Expect(*cert.KeyVaultSecretID).To(Equal(keyVaultSecretID))
+			Expect(cert.Data).To(BeNil())
+			Expect(cert.Password).To(BeNil())
+		})
+
+		It("should use the Key Vault certificate for the HTTPS listeners", func() {
+			listeners, _ := cb.getListeners(cbCtx)
+			var httpsListeners int
+			for _, listener := range *listeners {
+				if listener.Protocol != n.HTTPS {
+					continue
+				}
+				httpsListeners++
+				Expect(*listener.SslCertificate.ID).To(Equal(cb.appGwIdentifier.sslCertificateID(keyVaultCertName)))
+			}
+			Expect(httpsListeners).To(BeNumerically(">", 0))
+		})
+	})
+
+	Context("Test an ingress annotated with an invalid Key Vault secret ID", func() {
+		cb := newConfigBuilderFixture(nil)
+		ingress := tests.NewIngressFixture()
+		ingress.Annotations[annotations.KeyVaultSecretIDKey] = "www-contoso-com"
+		cbCtx := &ConfigBuilderContext{
+			IngressList:  []*networking.Ingress{ingress},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+
+		It("should fall back to the TLS secret", func() {
+			certs := cb.getSslCertificates(cbCtx)
+			Expect(*certs).To(HaveLen(1))
+			Expect((*certs)[0].KeyVaultSecretID).To(BeNil())
+			Expect(*(*certs)[0].Data).To(Equal("eHl6"))
+			Expect(cb.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring(events.ReasonInvalidAnnotation)))
+		})
+	})
+
+	Context("Test generateKeyVaultCertificateName", func() {
+		It("should include the version of the secret", func() {
+			Expect(generateKeyVaultCertificateName(keyVaultSecretID)).To(Equal(keyVaultCertName))
+			Expect(generateKeyVaultCertificateName(keyVaultSecretID + "/")).To(Equal(keyVaultCertName))
+			Expect(generateKeyVaultCertificateName(keyVaultSecretID + "/0123abcd")).To(Equal(keyVaultCertName + "-0123abcd"))
+		})
+	})
+})
+
+var _ = Describe("Testing certificates installed on App Gateway", func() {
+	const installedCertName = "security-team-cert"
+	installedCert := n.ApplicationGatewaySslCertificate{
+		Name: to.StringPtr(installedCertName),
+		ID:   to.StringPtr("--installed-cert-id--"),
+		ApplicationGatewaySslCertificatePropertiesFormat: &n.ApplicationGatewaySslCertificatePropertiesFormat{
+			PublicCertData: to.StringPtr("--public-cert-data--"),
+		},
+	}
+	otherCert := n.ApplicationGatewaySslCertificate{
+		Name: to.StringPtr("ContosoWildcard"),
+	}
+
+	var cb appGwConfigBuilder
+	var ingress *networking.Ingress
+
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+		cb.appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{installedCert, otherCert}
+		ingress = tests.NewIngressFixture()
+		ingress.Annotations[annotations.AppGwSslCertificateKey] = installedCertName
+		ingress.Annotations[annotations.KeyVaultSecretIDKey] = "https://contoso-vault.vault.azure.net/secrets/www-contoso-com"
+	})
+
+	Context("Test an ingress annotated with an installed certificate", func() {
+		It("should keep the installed certificates as they are, even without brownfield", func() {
+			cbCtx := &ConfigBuilderContext{
+				IngressList:  []*networking.Ingress{ingress},
+				EnvVariables: environment.GetFakeEnv(),
+			}
+			Expect(cbCtx.EnvVariables.EnableBrownfieldDeployment).To(BeFalse())
+
+			certs := cb.getSslCertificates(cbCtx)
+			Expect(*certs).To(Equal([]n.ApplicationGatewaySslCertificate{otherCert, installedCert}))
+		})
+
+		It("should use the installed certificate for the HTTPS listeners", func() {
+			cbCtx := &ConfigBuilderContext{
+				IngressList:  []*networking.Ingress{ingress},
+				EnvVariables: environment.GetFakeEnv(),
+			}
+
+			listeners, _ := cb.getListeners(cbCtx)
+			var httpsListeners int
+			for _, listener := range *listeners {
+				if listener.Protocol != n.HTTPS {
+					continue
+				}
+				httpsListeners++
+				Expect(*listener.SslCertificate.ID).To(Equal(cb.appGwIdentifier.sslCertificateID(installedCertName)))
+			}
+			Expect(httpsListeners).To(BeNumerically(">", 0))
+		})
+	})
+
+	Context("Test installed certificates no ingress references", func() {
+		var plainIngress *networking.Ingress
+
+		BeforeEach(func() {
+			plainIngress = tests.NewIngressFixture()
+			plainIngress.Spec.TLS = nil
+		})
+
+		It("should keep the installed certificates, and remove the certificates AGIC created", func() {
+			agicCert := n.ApplicationGatewaySslCertificate{Name: to.StringPtr(secretIdentifier{Namespace: "default", Name: "old-secret"}.secretFullName())}
+			keyVaultCert := n.ApplicationGatewaySslCertificate{Name: to.StringPtr(generateKeyVaultCertificateName("https://contoso-vault.vault.azure.net/secrets/old"))}
+			cb.appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{installedCert, otherCert, agicCert, keyVaultCert}
+
+			cbCtx := &ConfigBuilderContext{
+				IngressList:  []*networking.Ingress{plainIngress},
+				EnvVariables: environment.GetFakeEnv(),
+			}
+			certs := cb.getSslCertificates(cbCtx)
+			Expect(*certs).To(Equal([]n.ApplicationGatewaySslCertificate{otherCert}))
+		})
+
+		It("should remove the certificate of a deleted TLS secret", func() {
+			secretIngress := tests.NewIngressFixture()
+			secretIngress.Namespace = "default"
+			secretIngress.Annotations = map[string]string{}
+			secretIngress.Spec.TLS = []networking.IngressTLS{{SecretName: "old-secret"}}
+			secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "old-secret"}}
+			Expect(cb.k8sContext.Caches.Secret.Add(secret)).To(Succeed())
+			secretStore := cb.k8sContext.CertificateSecretStore.(*k8scontext.SecretsStore)
+			secretStore.Cache.Add("default/old-secret", &k8scontext.PfxCertificate{Data: []byte("xyz"), Password: "--password--"})
+			cb.appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{otherCert}
+
+			cbCtx := &ConfigBuilderContext{
+				IngressList:  []*networking.Ingress{secretIngress},
+				EnvVariables: environment.GetFakeEnv(),
+			}
+			cb.appGw.SslCertificates = cb.getSslCertificates(cbCtx)
+			Expect(*cb.appGw.SslCertificates).To(HaveLen(2))
+			Expect(*(*cb.appGw.SslCertificates)[1].Name).To(Equal(secretIdentifier{Namespace: "default", Name: "old-secret"}.secretFullName()))
+
+			Expect(cb.k8sContext.Caches.Secret.Delete(secret)).To(Succeed())
+			secretStore.Cache.Delete("default/old-secret")
+			cb.mem = memoization{}
+			cbCtx.IngressList = []*networking.Ingress{plainIngress}
+			certs := cb.getSslCertificates(cbCtx)
+			Expect(*certs).To(Equal([]n.ApplicationGatewaySslCertificate{otherCert}))
+		})
+	})
+
+	Context("Test a TLS secret with the same name as an installed certificate", func() {
+		It("should not overwrite the installed certificate", func() {
+			secretIngress := tests.NewIngressFixture()
+			secretIngress.Namespace = "security"
+			secretIngress.Spec.TLS = []networking.IngressTLS{{SecretName: "team-cert"}}
+			secretCertName := secretIdentifier{Namespace: "security", Name: "team-cert"}.secretFullName()
+			secretStore := cb.k8sContext.CertificateSecretStore.(*k8scontext.SecretsStore)
+			secretStore.Cache.Add("security/team-cert", &k8scontext.PfxCertificate{Data: []byte("xyz"), Password: "--password--"})
+
+			installedSecretCert := n.ApplicationGatewaySslCertificate{Name: to.StringPtr(secretCertName), ID: to.StringPtr("--installed--")}
+			cb.appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{installedSecretCert}
+			ingress.Annotations[annotations.AppGwSslCertificateKey] = secretCertName
+
+			cbCtx := &ConfigBuilderContext{
+				IngressList:  []*networking.Ingress{ingress, secretIngress},
+				EnvVariables: environment.GetFakeEnv(),
+			}
+			certs := cb.getSslCertificates(cbCtx)
+			Expect(*certs).To(Equal([]n.ApplicationGatewaySslCertificate{installedSecretCert}))
+		})
+	})
+})
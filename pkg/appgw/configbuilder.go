@@ -8,11 +8,11 @@ package appgw
 import (
 	"fmt"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure"
@@ -40,10 +40,24 @@ type memoization struct {
 	settings                     *[]n.ApplicationGatewayBackendHTTPSettings
 	settingsByBackend            *map[backendIdentifier]*n.ApplicationGatewayBackendHTTPSettings
 	serviceBackendPairsByBackend *map[backendIdentifier]serviceBackendPortPair
+	istioSettings                *[]n.ApplicationGatewayBackendHTTPSettings
+	istioSettingsByDestination   *map[istioDestinationIdentifier]*n.ApplicationGatewayBackendHTTPSettings
+	istioPairsByDestination      *map[istioDestinationIdentifier]serviceBackendPortPair
+	gatewayAPISettings           *[]n.ApplicationGatewayBackendHTTPSettings
+	gatewayAPISettingsByBackend  *map[gatewayAPIBackendIdentifier]*n.ApplicationGatewayBackendHTTPSettings
+	gatewayAPIPairsByBackend     *map[gatewayAPIBackendIdentifier]serviceBackendPortPair
+	gatewayAPIProbes             *map[string]n.ApplicationGatewayProbe
 	pools                        *[]n.ApplicationGatewayBackendAddressPool
 	certs                        *[]n.ApplicationGatewaySslCertificate
 	redirectConfigs              *[]n.ApplicationGatewayRedirectConfiguration
+	urlRedirectConfigs           *map[string]*n.ApplicationGatewayRedirectConfiguration
+	frontendPortOverrides        *map[string]frontendPortOverrides
+	customErrorPages             *map[string]customErrorPages
 	ports                        *[]n.ApplicationGatewayFrontendPort
+	backendCACerts               *map[backendCAIdentifier][]backendCACertificate
+	firewallPolicies             *map[string]string
+	rewriteRuleSets              *map[string]*n.ApplicationGatewayRewriteRuleSet
+	probeAnnotations             *map[string]probeAnnotations
 }
 
 type appGwConfigBuilder struct {
@@ -104,10 +118,16 @@ func (c *appGwConfigBuilder) Build(cbCtx *ConfigBuilderContext) (*n.ApplicationG
 
 	c.addTags()
 
+	err = c.sslPolicy(cbCtx)
+	if err != nil {
+		glog.Errorf("unable to generate SSL policy, error [%v]", err.Error())
+		return nil, ErrGeneratingSslPolicy
+	}
+
 	return &c.appGw, nil
 }
 
-type valFunc func(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*v1beta1.Ingress, serviceList []*v1.Service) error
+type valFunc func(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*networking.Ingress, serviceList []*v1.Service) error
 
 // PreBuildValidate runs all the validators that suggest misconfiguration in Kubernetes resources.
 func (c *appGwConfigBuilder) PreBuildValidate(cbCtx *ConfigBuilderContext) error {
@@ -161,11 +181,11 @@ func (c *appGwConfigBuilder) resolvePortName(portName string, backendID *backend
 	return resolvedPorts
 }
 
-func generateBackendID(ingress *v1beta1.Ingress, rule *v1beta1.IngressRule, path *v1beta1.HTTPIngressPath, backend *v1beta1.IngressBackend) backendIdentifier {
+func generateBackendID(ingress *networking.Ingress, rule *networking.IngressRule, path *networking.HTTPIngressPath, backend *networking.IngressBackend) backendIdentifier {
 	return backendIdentifier{
 		serviceIdentifier: serviceIdentifier{
 			Namespace: ingress.Namespace,
-			Name:      backend.ServiceName,
+			Name:      backend.Service.Name,
 		},
 		Ingress: ingress,
 		Rule:    rule,
@@ -174,7 +194,20 @@ func generateBackendID(ingress *v1beta1.Ingress, rule *v1beta1.IngressRule, path
 	}
 }
 
-func generateListenerID(rule *v1beta1.IngressRule, protocol n.ApplicationGatewayProtocol, overridePort *Port, usePrivateIP bool) listenerIdentifier {
+// isServiceBackend determines whether the Ingress backend references a Service; Resource backends are not supported.
+func isServiceBackend(backend *networking.IngressBackend) bool {
+	return backend != nil && backend.Service != nil
+}
+
+// serviceBackendPortToStr returns the port of an Ingress Service backend as a string - either its name or its number.
+func serviceBackendPortToStr(port networking.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprint(port.Number)
+}
+
+func generateListenerID(rule *networking.IngressRule, protocol n.ApplicationGatewayProtocol, overridePort *Port, usePrivateIP bool) listenerIdentifier {
 	frontendPort := Port(80)
 	if protocol == n.HTTPS {
 		frontendPort = Port(443)
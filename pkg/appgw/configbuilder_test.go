@@ -6,17 +6,18 @@
 package appgw
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"strings"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -25,9 +26,11 @@ import (
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
 )
@@ -70,21 +73,22 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	}
 
 	// Create the Ingress resource.
-	ingress := &v1beta1.Ingress{
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
+	ingress := &networking.Ingress{
+		Spec: networking.IngressSpec{
+			Rules: []networking.IngressRule{
 				{
 					Host: "foo.baz",
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
+					IngressRuleValue: networking.IngressRuleValue{
+						HTTP: &networking.HTTPIngressRuleValue{
+							Paths: []networking.HTTPIngressPath{
 								{
 									Path: "/",
-									Backend: v1beta1.IngressBackend{
-										ServiceName: tests.ServiceName,
-										ServicePort: intstr.IntOrString{
-											Type:   intstr.Int,
-											IntVal: 80,
+									Backend: networking.IngressBackend{
+										Service: &networking.IngressServiceBackend{
+											Name: tests.ServiceName,
+											Port: networking.ServiceBackendPort{
+												Number: 80,
+											},
 										},
 									},
 								},
@@ -93,7 +97,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 					},
 				},
 			},
-			TLS: []v1beta1.IngressTLS{
+			TLS: []networking.IngressTLS{
 				{
 					Hosts: []string{
 						"www.contoso.com",
@@ -185,27 +189,29 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 	// Create the mock K8s client.
 	k8sClient := testclient.NewSimpleClientset()
-	_, err := k8sClient.CoreV1().Namespaces().Create(ns)
+	tests.AddNetworkingV1Resources(k8sClient)
+	_, err := k8sClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.CoreV1().Nodes().Create(node)
+	_, err = k8sClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Create(ingress)
+	_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Create(context.TODO(), ingress, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.CoreV1().Services(ingressNS).Create(service)
+	_, err = k8sClient.CoreV1().Services(ingressNS).Create(context.TODO(), service, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.CoreV1().Endpoints(ingressNS).Create(endpoints)
+	_, err = k8sClient.CoreV1().Endpoints(ingressNS).Create(context.TODO(), endpoints, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.CoreV1().Pods(ingressNS).Create(pod)
+	_, err = k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
 	crdClient := fake.NewSimpleClientset()
 	istioCrdClient := istio_fake.NewSimpleClientset()
-	ctxt := k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, []string{ingressNS}, 1000*time.Second)
+	gatewayCrdClient := gateway_fake.NewSimpleClientset()
+	ctxt := k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, 1000*time.Second, metricstore.NewFakeMetricStore())
 
 	appGwy := &n.ApplicationGateway{
 		ApplicationGatewayPropertiesFormat: newAppGwyConfigFixture(),
@@ -216,7 +222,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 	Context("Tests Application Gateway config creation", func() {
 		cbCtx := &ConfigBuilderContext{
-			IngressList:  []*v1beta1.Ingress{ingress},
+			IngressList:  []*networking.Ingress{ingress},
 			ServiceList:  serviceList,
 			EnvVariables: environment.GetFakeEnv(),
 		}
@@ -250,7 +256,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        ],
 --        "backendHttpSettingsCollection": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/backendHttpSettingsCollection/bp---namespace-----service-name---80-80---name--",
 --                "name": "bp---namespace-----service-name---80-80---name--",
 --                "properties": {
@@ -275,29 +280,24 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        ],
 --        "frontendIPConfigurations": [
 --            {
---                "etag": "xx2",
 --                "id": "--front-end-ip-id-1--",
 --                "name": "xx3",
 --                "properties": {
 --                    "publicIPAddress": {
 --                        "id": "xyz"
 --                    }
---                },
---                "type": "xx1"
+--                }
 --            },
 --            {
---                "etag": "yy2",
 --                "id": "--front-end-ip-id-2--",
 --                "name": "yy3",
 --                "properties": {
 --                    "privateIPAddress": "abc"
---                },
---                "type": "yy1"
+--                }
 --            }
 --        ],
 --        "frontendPorts": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/frontEndPorts/fp-80",
 --                "name": "fp-80",
 --                "properties": {
@@ -307,7 +307,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        ],
 --        "httpListeners": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/httpListeners/fl-foo.baz-80",
 --                "name": "fl-foo.baz-80",
 --                "properties": {
@@ -351,7 +350,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        "redirectConfigurations": null,
 --        "requestRoutingRules": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/requestRoutingRules/rr-foo.baz-80",
 --                "name": "rr-foo.baz-80",
 --                "properties": {
@@ -0,0 +1,212 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+)
+
+// ChangeType is the kind of change of an App Gateway sub-resource.
+type ChangeType string
+
+const (
+	// ChangeAdded is a sub-resource, which does not exist on App Gateway yet.
+	ChangeAdded ChangeType = "added"
+
+	// ChangeRemoved is a sub-resource, which exists on App Gateway and will be removed.
+	ChangeRemoved ChangeType = "removed"
+
+	// ChangeModified is a sub-resource, which exists on App Gateway and will be updated.
+	ChangeModified ChangeType = "changed"
+)
+
+// ResourceChange describes the change of a single App Gateway sub-resource.
+type ResourceChange struct {
+	Kind   string     `json:"kind"`
+	Name   string     `json:"name"`
+	Change ChangeType `json:"change"`
+
+	// Properties lists the properties, which differ for a changed sub-resource.
+	Properties []string `json:"properties,omitempty"`
+}
+
+// ConfigDiff lists the sub-resources, which differ between the existing and the generated App Gateway config.
+type ConfigDiff struct {
+	Changes []ResourceChange `json:"changes"`
+}
+
+// diffedCollections maps the JSON name of the App Gateway sub-resource collections to the kind reported in the diff.
+var diffedCollections = []struct {
+	property string
+	kind     string
+}{
+	{"frontendPorts", "frontend port"},
+	{"sslCertificates", "certificate"},
+	{"trustedRootCertificates", "trusted root certificate"},
+	{"authenticationCertificates", "authentication certificate"},
+	{"httpListeners", "listener"},
+	{"redirectConfigurations", "redirect"},
+	{"rewriteRuleSets", "rewrite rule set"},
+	{"requestRoutingRules", "rule"},
+	{"urlPathMaps", "url path map"},
+	{"backendAddressPools", "pool"},
+	{"backendHttpSettingsCollection", "settings"},
+	{"probes", "probe"},
+}
+
+// diffIgnoredProperties are either read-only or never returned by ARM, so they would show up as changes on every diff.
+var diffIgnoredProperties = map[string]interface{}{
+	"provisioningState": nil,
+	"data":              nil,
+	"password":          nil,
+	"publicCertData":    nil,
+}
+
+// NewConfigDiff compares the sub-resources of the existing App Gateway with the generated one.
+func NewConfigDiff(existing, generated *n.ApplicationGateway) (*ConfigDiff, error) {
+	existingJSON, err := existing.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	generatedJSON, err := generated.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return NewConfigDiffFromJSON(existingJSON, generatedJSON)
+}
+
+// NewConfigDiffFromJSON compares the sub-resources of two App Gateway configs in their ARM JSON form.
+// Use it when the existing config has to be captured before the ConfigBuilder mutates it.
+func NewConfigDiffFromJSON(existingJSON, generatedJSON []byte) (*ConfigDiff, error) {
+	existing, err := subResourcesByName(existingJSON)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := subResourcesByName(generatedJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ConfigDiff{}
+	for _, collection := range diffedCollections {
+		existingResources := existing[collection.property]
+		generatedResources := generated[collection.property]
+
+		for _, name := range sortedNames(generatedResources) {
+			existingProperties, exists := existingResources[name]
+			if !exists {
+				diff.Changes = append(diff.Changes, ResourceChange{Kind: collection.kind, Name: name, Change: ChangeAdded})
+				continue
+			}
+			if changed := changedProperties(existingProperties, generatedResources[name]); len(changed) > 0 {
+				diff.Changes = append(diff.Changes, ResourceChange{Kind: collection.kind, Name: name, Change: ChangeModified, Properties: changed})
+			}
+		}
+
+		for _, name := range sortedNames(existingResources) {
+			if _, exists := generatedResources[name]; !exists {
+				diff.Changes = append(diff.Changes, ResourceChange{Kind: collection.kind, Name: name, Change: ChangeRemoved})
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// IsEmpty tells whether the configs have the same sub-resources.
+func (d *ConfigDiff) IsEmpty() bool {
+	return len(d.Changes) == 0
+}
+
+// Lines formats every change on a line: "+" added, "-" removed, "~" changed.
+func (d *ConfigDiff) Lines() []string {
+	var lines []string
+	for _, change := range d.Changes {
+		switch change.Change {
+		case ChangeAdded:
+			lines = append(lines, fmt.Sprintf("+ %s %s", change.Kind, change.Name))
+		case ChangeRemoved:
+			lines = append(lines, fmt.Sprintf("- %s %s", change.Kind, change.Name))
+		default:
+			lines = append(lines, fmt.Sprintf("~ %s %s (%s)", change.Kind, change.Name, strings.Join(change.Properties, ", ")))
+		}
+	}
+	return lines
+}
+
+func (d *ConfigDiff) String() string {
+	if d.IsEmpty() {
+		return "no changes"
+	}
+	return strings.Join(d.Lines(), "\n")
+}
+
+// subResourcesByName returns the properties of every sub-resource keyed by collection and name.
+func subResourcesByName(appGwJSON []byte) (map[string]map[string]map[string]interface{}, error) {
+	var appGw struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(appGwJSON, &appGw); err != nil {
+		return nil, err
+	}
+
+	resources := make(map[string]map[string]map[string]interface{})
+	for _, collection := range diffedCollections {
+		resources[collection.property] = make(map[string]map[string]interface{})
+		raw, exists := appGw.Properties[collection.property]
+		if !exists {
+			continue
+		}
+		var subResources []struct {
+			Name       string                 `json:"name"`
+			Properties map[string]interface{} `json:"properties"`
+		}
+		if err := json.Unmarshal(raw, &subResources); err != nil {
+			return nil, err
+		}
+		for _, resource := range subResources {
+			resources[collection.property][resource.Name] = resource.Properties
+		}
+	}
+	return resources, nil
+}
+
+func changedProperties(existing, generated map[string]interface{}) []string {
+	keys := make(map[string]interface{})
+	for key := range existing {
+		keys[key] = nil
+	}
+	for key := range generated {
+		keys[key] = nil
+	}
+
+	var changed []string
+	for key := range keys {
+		if _, ignored := diffIgnoredProperties[key]; ignored {
+			continue
+		}
+		if !reflect.DeepEqual(existing[key], generated[key]) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func sortedNames(resources map[string]map[string]interface{}) []string {
+	var names []string
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("Test the App Gateway config diff", func() {
+	var existing n.ApplicationGateway
+	var generated n.ApplicationGateway
+
+	BeforeEach(func() {
+		existing = fixtures.GetAppGateway()
+		generated = fixtures.GetAppGateway()
+	})
+
+	It("should report no changes for the same config", func() {
+		diff, err := NewConfigDiff(&existing, &generated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeTrue())
+		Expect(diff.String()).To(Equal("no changes"))
+	})
+
+	It("should only compare sub-resource collections", func() {
+		existing.Sku = &n.ApplicationGatewaySku{Name: n.StandardV2, Tier: n.ApplicationGatewayTierStandardV2}
+		generated.Sku = &n.ApplicationGatewaySku{Name: n.WAFV2, Tier: n.ApplicationGatewayTierWAFV2}
+		diff, err := NewConfigDiff(&existing, &generated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeTrue())
+	})
+
+	It("should report added, removed and changed sub-resources", func() {
+		generated.BackendHTTPSettingsCollection = &[]n.ApplicationGatewayBackendHTTPSettings{
+			fixtures.GetHTTPSettings1(),
+			fixtures.GetHTTPSettings2(),
+		}
+		pool := fixtures.GetBackendPool1()
+		pool.Name = to.StringPtr("added-pool")
+		generated.BackendAddressPools = &[]n.ApplicationGatewayBackendAddressPool{pool}
+		listener := fixtures.GetListenerBasic()
+		listener.HostName = to.StringPtr("changed.com")
+		generated.HTTPListeners = &[]n.ApplicationGatewayHTTPListener{
+			*fixtures.GetDefaultListener(),
+			*listener,
+			*fixtures.GetListenerPathBased1(),
+			*fixtures.GetListenerPathBased2(),
+			*fixtures.GetListenerUnassociated(),
+		}
+
+		diff, err := NewConfigDiff(&existing, &generated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.Changes).To(ConsistOf(
+			ResourceChange{Kind: "listener", Name: *listener.Name, Change: ChangeModified, Properties: []string{"hostName"}},
+			ResourceChange{Kind: "pool", Name: "added-pool", Change: ChangeAdded},
+			ResourceChange{Kind: "settings", Name: fixtures.BackendHTTPSettingsName3, Change: ChangeRemoved},
+		))
+		Expect(diff.Lines()).To(ConsistOf(
+			"~ listener "+*listener.Name+" (hostName)",
+			"+ pool added-pool",
+			"- settings "+fixtures.BackendHTTPSettingsName3,
+		))
+	})
+
+	It("should ignore certificate data, which ARM never returns", func() {
+		certificates := []n.ApplicationGatewaySslCertificate{}
+		for _, cert := range *generated.SslCertificates {
+			cert.ApplicationGatewaySslCertificatePropertiesFormat = &n.ApplicationGatewaySslCertificatePropertiesFormat{
+				Data:     to.StringPtr("--pfx--"),
+				Password: to.StringPtr("--password--"),
+			}
+			certificates = append(certificates, cert)
+		}
+		generated.SslCertificates = &certificates
+
+		diff, err := NewConfigDiff(&existing, &generated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diff.IsEmpty()).To(BeTrue())
+	})
+})
@@ -0,0 +1,85 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// customErrorPages are the URLs of the pages App Gateway returns instead of its own error pages; empty for the App Gateway pages.
+type customErrorPages struct {
+	url403 string
+	url502 string
+}
+
+// customErrorConfigurations creates the App Gateway custom errors of the pages; nil when there are none.
+func (pages customErrorPages) customErrorConfigurations() *[]n.ApplicationGatewayCustomError {
+	var customErrors []n.ApplicationGatewayCustomError
+	if pages.url403 != "" {
+		customErrors = append(customErrors, n.ApplicationGatewayCustomError{
+			StatusCode:         n.HTTPStatus403,
+			CustomErrorPageURL: to.StringPtr(pages.url403),
+		})
+	}
+	if pages.url502 != "" {
+		customErrors = append(customErrors, n.ApplicationGatewayCustomError{
+			StatusCode:         n.HTTPStatus502,
+			CustomErrorPageURL: to.StringPtr(pages.url502),
+		})
+	}
+	if len(customErrors) == 0 {
+		return nil
+	}
+	return &customErrors
+}
+
+// customErrorConfigurations sets the gateway-wide error pages of the environment, which apply to all listeners without
+// error pages of their own. App Gateway keeps the error pages configured outside of AGIC when the environment has none.
+func (c *appGwConfigBuilder) customErrorConfigurations(cbCtx *ConfigBuilderContext) {
+	pages := customErrorPages{
+		url403: cbCtx.EnvVariables.ErrorPageURL403,
+		url502: cbCtx.EnvVariables.ErrorPageURL502,
+	}
+	if customErrors := pages.customErrorConfigurations(); customErrors != nil {
+		c.appGw.CustomErrorConfigurations = customErrors
+	}
+}
+
+// getCustomErrorPages reads the error page annotations of the ingress once per config build, and emits an event on the ingress
+// when they are invalid.
+func (c *appGwConfigBuilder) getCustomErrorPages(ingress *networking.Ingress) customErrorPages {
+	if c.mem.customErrorPages == nil {
+		pagesByIngress := make(map[string]customErrorPages)
+		c.mem.customErrorPages = &pagesByIngress
+	}
+	ingressKey := utils.GetResourceKey(ingress.Namespace, ingress.Name)
+	if pages, exists := (*c.mem.customErrorPages)[ingressKey]; exists {
+		return pages
+	}
+
+	var pages customErrorPages
+	if pageURL, err := annotations.ErrorPageURL403(ingress); err == nil {
+		pages.url403 = pageURL
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+	if pageURL, err := annotations.ErrorPageURL502(ingress); err == nil {
+		pages.url502 = pageURL
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
+	(*c.mem.customErrorPages)[ingressKey] = pages
+	return pages
+}
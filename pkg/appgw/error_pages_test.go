@@ -0,0 +1,103 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test the custom error pages of listeners and of the gateway", func() {
+	page403 := "https://contoso.blob.core.windows.net/errors/403.html"
+	page502 := "https://contoso.blob.core.windows.net/errors/502.html"
+
+	var configBuilder appGwConfigBuilder
+	var ingress *networking.Ingress
+	var cbCtx *ConfigBuilderContext
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+		endpoint := tests.NewEndpointsFixture()
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+		ingress = tests.NewIngressFixture()
+		ingress.Annotations[annotations.ErrorPageURL403Key] = page403
+		ingress.Annotations[annotations.ErrorPageURL502Key] = page502
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoint)
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+
+		cbCtx = &ConfigBuilderContext{
+			IngressList:  []*networking.Ingress{ingress},
+			ServiceList:  []*v1.Service{service},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+	})
+
+	Context("with error page annotations", func() {
+		It("sets the error pages on every listener of the ingress", func() {
+			Expect(configBuilder.Listeners(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(*configBuilder.appGw.HTTPListeners).To(HaveLen(2))
+			for _, listener := range *configBuilder.appGw.HTTPListeners {
+				Expect(*listener.CustomErrorConfigurations).To(Equal([]n.ApplicationGatewayCustomError{
+					{StatusCode: n.HTTPStatus403, CustomErrorPageURL: to.StringPtr(page403)},
+					{StatusCode: n.HTTPStatus502, CustomErrorPageURL: to.StringPtr(page502)},
+				}))
+			}
+			Expect(configBuilder.appGw.CustomErrorConfigurations).To(BeNil())
+		})
+
+		It("emits an event and ignores error pages, which are not HTTPS URLs", func() {
+			ingress.Annotations[annotations.ErrorPageURL403Key] = "http://contoso.com/403.html"
+			Expect(configBuilder.Listeners(cbCtx)).ToNot(HaveOccurred())
+
+			for _, listener := range *configBuilder.appGw.HTTPListeners {
+				Expect(*listener.CustomErrorConfigurations).To(Equal([]n.ApplicationGatewayCustomError{
+					{StatusCode: n.HTTPStatus502, CustomErrorPageURL: to.StringPtr(page502)},
+				}))
+			}
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidAnnotation))
+		})
+	})
+
+	Context("with error pages in the environment", func() {
+		It("sets the error pages of the gateway", func() {
+			delete(ingress.Annotations, annotations.ErrorPageURL403Key)
+			delete(ingress.Annotations, annotations.ErrorPageURL502Key)
+			cbCtx.EnvVariables.ErrorPageURL502 = page502
+			Expect(configBuilder.Listeners(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(*configBuilder.appGw.CustomErrorConfigurations).To(Equal([]n.ApplicationGatewayCustomError{
+				{StatusCode: n.HTTPStatus502, CustomErrorPageURL: to.StringPtr(page502)},
+			}))
+			for _, listener := range *configBuilder.appGw.HTTPListeners {
+				Expect(listener.CustomErrorConfigurations).To(BeNil())
+			}
+		})
+
+		It("keeps the error pages of the gateway without error pages in the environment", func() {
+			existing := &[]n.ApplicationGatewayCustomError{
+				{StatusCode: n.HTTPStatus403, CustomErrorPageURL: to.StringPtr(page403)},
+			}
+			configBuilder.appGw.CustomErrorConfigurations = existing
+			Expect(configBuilder.Listeners(cbCtx)).ToNot(HaveOccurred())
+
+			Expect(configBuilder.appGw.CustomErrorConfigurations).To(Equal(existing))
+		})
+	})
+})
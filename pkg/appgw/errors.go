@@ -29,6 +29,9 @@ var (
 	// ErrGeneratingRoutingRules is an error.
 	ErrGeneratingRoutingRules            = errors.New("unable to generate request routing rules")
 
+	// ErrGeneratingSslPolicy is an error.
+	ErrGeneratingSslPolicy               = errors.New("unable to generate SSL policy")
+
 	// ErrKeyNoDefaults is an error.
 	ErrKeyNoDefaults                     = errors.New("either a DefaultRedirectConfiguration or (DefaultBackendAddressPool + DefaultBackendHTTPSettings) must be configured")
 
@@ -46,4 +49,28 @@ var (
 
 	// ErrKeyNoPublicIP is an error.
 	ErrKeyNoPublicIP                     = errors.New("A Public IP must be present in the Application Gateway FrontendIPConfiguration")
+
+	// ErrBackendCACertificateNotFound is an error.
+	ErrBackendCACertificateNotFound      = errors.New("the Secret or ConfigMap with the backend CA certificates does not exist")
+
+	// ErrNoBackendCACertificates is an error.
+	ErrNoBackendCACertificates           = errors.New("no PEM encoded certificates under the ca.crt key")
+
+	// ErrRewriteRuleSetNotFound is an error.
+	ErrRewriteRuleSetNotFound            = errors.New("the AzureIngressRewrite does not exist")
+
+	// ErrNoRewriteRules is an error.
+	ErrNoRewriteRules                    = errors.New("the AzureIngressRewrite has no rewrite rules")
+
+	// ErrInvalidRewriteRule is an error.
+	ErrInvalidRewriteRule                = errors.New("rewrite rules need a unique name, conditions with a variable, and at least one header action with a header name")
+
+	// ErrKeyEitherRedirectTarget is an error.
+	ErrKeyEitherRedirectTarget           = errors.New("A Redirect Configuration must have either a TargetListener or a TargetURL but not both")
+
+	// ErrInvalidSslPolicy is an error.
+	ErrInvalidSslPolicy                  = errors.New("the SSL policy must be either a predefined policy name, or a minimum protocol version with cipher suites App Gateway supports")
+
+	// ErrUnsupportedSslCipherSuite is an error.
+	ErrUnsupportedSslCipherSuite         = errors.New("the v2 SKUs of App Gateway do not support the DHE cipher suites")
 )
@@ -6,13 +6,17 @@
 package appgw
 
 import (
+	"fmt"
 	"sort"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
 
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
 )
 
@@ -43,6 +47,9 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) (*[]n.App
 				publIPPorts[*port.Name] = *listener.Name
 			}
 
+			if config.Protocol == n.HTTPS {
+				listener.SslCertificate = resourceRef(c.appGwIdentifier.sslCertificateID(config.Secret.secretFullName()))
+			}
 			listeners = append(listeners, *listener)
 			if _, exists := portSet[*port.Name]; !exists {
 				portSet[*port.Name] = nil
@@ -51,7 +58,16 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) (*[]n.App
 		}
 	}
 
-	for listenerID, config := range c.getListenerConfigs(cbCtx) {
+	listenerConfigs := c.getListenerConfigs(cbCtx)
+
+	// Claim the ports of the Public IP listeners first, so that conflicting Private IP listeners are dropped regardless of order.
+	for listenerID := range listenerConfigs {
+		if !listenerID.UsePrivateIP {
+			publIPPorts[generateFrontendPortName(listenerID.FrontendPort)] = generateListenerName(listenerID)
+		}
+	}
+
+	for listenerID, config := range listenerConfigs {
 		listener, port, err := c.newListener(cbCtx, listenerID, config.Protocol)
 		if err != nil {
 			glog.Errorf("Failed creating listener %+v: %s", listenerID, err)
@@ -59,7 +75,9 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) (*[]n.App
 		}
 
 		if listenerName, exists := publIPPorts[*port.Name]; exists && listenerID.UsePrivateIP {
-			glog.Errorf("Can't assign port %s to Private IP Listener %s; already assigned to Public IP Listener %s; Will not create listener %+v", *port.Name, *listener.Name, listenerName, listenerID)
+			logLine := fmt.Sprintf("Can't assign port %s to Private IP Listener %s; already assigned to Public IP Listener %s; Will not create listener %+v", *port.Name, *listener.Name, listenerName, listenerID)
+			glog.Error(logLine)
+			c.emitListenerEvent(cbCtx, listenerID, events.ReasonFrontendPortConflict, logLine)
 			continue
 		}
 
@@ -68,9 +86,16 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) (*[]n.App
 		}
 
 		if config.Protocol == n.HTTPS {
-			sslCertificateID := c.appGwIdentifier.sslCertificateID(config.Secret.secretFullName())
-			listener.SslCertificate = resourceRef(sslCertificateID)
+			sslCertificateName := config.Secret.secretFullName()
+			if config.SslCertificateName != "" {
+				sslCertificateName = config.SslCertificateName
+			}
+			listener.SslCertificate = resourceRef(c.appGwIdentifier.sslCertificateID(sslCertificateName))
 		}
+		if config.FirewallPolicy != "" {
+			listener.FirewallPolicy = resourceRef(config.FirewallPolicy)
+		}
+		listener.CustomErrorConfigurations = config.CustomErrorPages.customErrorConfigurations()
 		listeners = append(listeners, *listener)
 		if _, exists := portSet[*port.Name]; !exists {
 			portSet[*port.Name] = nil
@@ -78,8 +103,38 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) (*[]n.App
 		}
 	}
 
+	// The listeners of the Gateway API come after the listeners of the Ingresses, which keep their ports and host names.
+	if cbCtx.EnvVariables.EnableGatewayAPI {
+		for listenerID, config := range c.getListenerConfigsFromGatewayAPI(cbCtx) {
+			if _, exists := listenerConfigs[listenerID]; exists {
+				logLine := fmt.Sprintf("Listener %s is already used by an Ingress; Will not create listener %+v", generateListenerName(listenerID), listenerID)
+				c.conflictGatewayAPIListeners(cbCtx, listenerID, gatewayv1beta1.ReasonHostnameConflict, events.ReasonGatewayAPIListenerConflict, logLine)
+				continue
+			}
+			listener, port, err := c.newListener(cbCtx, listenerID, config.Protocol)
+			if err != nil {
+				glog.Errorf("Failed creating listener %+v: %s", listenerID, err)
+				continue
+			}
+			if listenerName, exists := publIPPorts[*port.Name]; exists && listenerID.UsePrivateIP {
+				logLine := fmt.Sprintf("Can't assign port %s to Private IP Listener %s; already assigned to Public IP Listener %s; Will not create listener %+v", *port.Name, *listener.Name, listenerName, listenerID)
+				c.conflictGatewayAPIListeners(cbCtx, listenerID, gatewayv1beta1.ReasonPortConflict, events.ReasonFrontendPortConflict, logLine)
+				continue
+			}
+
+			if config.Protocol == n.HTTPS {
+				listener.SslCertificate = resourceRef(c.appGwIdentifier.sslCertificateID(config.Secret.secretFullName()))
+			}
+			listeners = append(listeners, *listener)
+			if _, exists := portSet[*port.Name]; !exists {
+				portSet[*port.Name] = nil
+				ports = append(ports, *port)
+			}
+		}
+	}
+
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil, cbCtx.FrontendIPAddresses)
 
 		// Listeners we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedListeners()
@@ -113,12 +168,27 @@ func (c *appGwConfigBuilder) getListenerConfigs(cbCtx *ConfigBuilderContext) map
 		glog.V(5).Infof("Processing Rules for Ingress: %s/%s", ingress.Namespace, ingress.Name)
 		azListenerConfigs := c.getListenersFromIngress(ingress, cbCtx.EnvVariables)
 		for listenerID, azConfig := range azListenerConfigs {
+			// Ingresses sharing a listener may reference different WAF policies; the listener keeps the first one,
+			// while the path rules of each ingress use its own.
+			if existing, exists := allListeners[listenerID]; exists && existing.FirewallPolicy != "" && existing.FirewallPolicy != azConfig.FirewallPolicy {
+				if azConfig.FirewallPolicy != "" {
+					glog.Warningf("Listener %s already uses WAF policy %s; Ingress %s/%s references %s", generateListenerName(listenerID), existing.FirewallPolicy, ingress.Namespace, ingress.Name, azConfig.FirewallPolicy)
+				}
+				azConfig.FirewallPolicy = existing.FirewallPolicy
+			}
+			// Likewise the listener keeps the error pages of the first ingress, which has them.
+			if existing, exists := allListeners[listenerID]; exists && existing.CustomErrorPages != (customErrorPages{}) && existing.CustomErrorPages != azConfig.CustomErrorPages {
+				if azConfig.CustomErrorPages != (customErrorPages{}) {
+					glog.Warningf("Listener %s already uses the error pages %+v; Ingress %s/%s references %+v", generateListenerName(listenerID), existing.CustomErrorPages, ingress.Namespace, ingress.Name, azConfig.CustomErrorPages)
+				}
+				azConfig.CustomErrorPages = existing.CustomErrorPages
+			}
 			allListeners[listenerID] = azConfig
 		}
 	}
 
-	// App Gateway must have at least one listener - the default one!
-	if len(allListeners) == 0 {
+	// App Gateway must have at least one listener - the default one, unless the Gateway API provides one!
+	if len(allListeners) == 0 && !(cbCtx.EnvVariables.EnableGatewayAPI && len(c.getListenerConfigsFromGatewayAPI(cbCtx)) > 0) {
 		allListeners[defaultFrontendListenerIdentifier()] = listenerAzConfig{
 			// Default protocol
 			Protocol: n.HTTP,
@@ -167,6 +237,15 @@ func (c *appGwConfigBuilder) newListener(cbCtx *ConfigBuilderContext, listenerID
 	return &listener, &frontendPort, nil
 }
 
+// emitListenerEvent emits a warning on the ingresses, which have rules for the listener.
+func (c *appGwConfigBuilder) emitListenerEvent(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, reason, message string) {
+	for _, ingress := range cbCtx.IngressList {
+		if _, exists := c.getListenersFromIngress(ingress, cbCtx.EnvVariables)[listenerID]; exists {
+			c.recorder.Event(ingress, v1.EventTypeWarning, reason, message)
+		}
+	}
+}
+
 func (c *appGwConfigBuilder) groupListenersByListenerIdentifier(cbCtx *ConfigBuilderContext) map[listenerIdentifier]*n.ApplicationGatewayHTTPListener {
 	listeners, ports := c.getListeners(cbCtx)
 	portsByID := make(map[string]n.ApplicationGatewayFrontendPort)
@@ -9,14 +9,16 @@ import (
 	"math/rand"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
@@ -150,7 +152,7 @@ var _ = Describe("Process ingress rules and parse frontend listener configs", fu
 		cb := newConfigBuilderFixture(&certs)
 		ingress := tests.NewIngressFixture()
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingress},
+			IngressList: []*networking.Ingress{ingress},
 		}
 
 		httpListenersAzureConfigMap := cb.getListenerConfigs(cbCtx)
@@ -170,7 +172,7 @@ var _ = Describe("Process ingress rules and parse frontend listener configs", fu
 			certs := newCertsFixture()
 			cb := newConfigBuilderFixture(&certs)
 			cbCtx := &ConfigBuilderContext{
-				IngressList: []*v1beta1.Ingress{
+				IngressList: []*networking.Ingress{
 					tests.NewIngressFixture(),
 					tests.NewIngressFixture(),
 				},
@@ -192,7 +194,7 @@ var _ = Describe("Process ingress rules and parse frontend listener configs", fu
 			certs := newCertsFixture()
 			cb := newConfigBuilderFixture(&certs)
 			cbCtx := &ConfigBuilderContext{
-				IngressList: []*v1beta1.Ingress{
+				IngressList: []*networking.Ingress{
 					tests.NewIngressFixture(),
 					tests.NewIngressFixture(),
 				},
@@ -233,7 +235,7 @@ var _ = Describe("Process ingress rules and parse frontend listener configs", fu
 			certs := newCertsFixture()
 			cb := newConfigBuilderFixture(&certs)
 			cbCtx := &ConfigBuilderContext{
-				IngressList: []*v1beta1.Ingress{
+				IngressList: []*networking.Ingress{
 					tests.NewIngressFixture(),
 				},
 				EnvVariables: envVariables,
@@ -257,7 +259,7 @@ var _ = Describe("Process ingress rules and parse frontend listener configs", fu
 			envVariablesCopy := envVariables
 			envVariablesCopy.UsePrivateIP = "true"
 			cbCtx := &ConfigBuilderContext{
-				IngressList: []*v1beta1.Ingress{
+				IngressList: []*networking.Ingress{
 					tests.NewIngressFixture(),
 					tests.NewIngressFixture(),
 				},
@@ -283,7 +285,7 @@ var _ = Describe("Process ingress rules and parse frontend listener configs", fu
 			certs := newCertsFixture()
 			cb := newConfigBuilderFixture(&certs)
 			cbCtx := &ConfigBuilderContext{
-				IngressList: []*v1beta1.Ingress{
+				IngressList: []*networking.Ingress{
 					tests.NewIngressFixture(),
 				},
 				EnvVariables: envVariables,
@@ -307,10 +309,88 @@ var _ = Describe("Process ingress rules and parse frontend listener configs", fu
 		})
 	})
 
+	Context("frontend port annotations", func() {
+		var cb appGwConfigBuilder
+		var ingress *networking.Ingress
+		var cbCtx *ConfigBuilderContext
+
+		BeforeEach(func() {
+			certs := newCertsFixture()
+			cb = newConfigBuilderFixture(&certs)
+			ingress = tests.NewIngressFixture()
+			ingress.Annotations[annotations.OverrideFrontendPortKey] = "8443"
+			ingress.Annotations[annotations.SslRedirectFrontendPortKey] = "8080"
+			cbCtx = &ConfigBuilderContext{
+				IngressList:  []*networking.Ingress{ingress},
+				EnvVariables: envVariables,
+			}
+		})
+
+		It("should create the HTTPS and redirecting HTTP listeners on the annotated ports", func() {
+			listenerConfigs := cb.getListenerConfigs(cbCtx)
+			httpsListenerID := listenerIdentifier{FrontendPort: 8443, HostName: tests.Host}
+			httpListenerID := listenerIdentifier{FrontendPort: 8080, HostName: tests.Host}
+			Expect(listenerConfigs).To(HaveLen(2))
+			Expect(listenerConfigs[httpsListenerID].Protocol).To(Equal(n.HTTPS))
+			Expect(listenerConfigs[httpsListenerID].SslRedirectConfigurationName).To(Equal(generateSSLRedirectConfigurationName(httpsListenerID)))
+			Expect(listenerConfigs[httpListenerID].Protocol).To(Equal(n.HTTP))
+
+			_, ports := cb.getListeners(cbCtx)
+			var portNumbers []int32
+			for _, port := range *ports {
+				portNumbers = append(portNumbers, *port.Port)
+			}
+			Expect(portNumbers).To(ConsistOf(int32(8080), int32(8443)))
+
+			// The HTTP listener redirects to the HTTPS listener on the annotated port
+			Expect(cb.sslRedirectTargetListener(httpListenerID, ingress)).To(Equal(httpsListenerID))
+		})
+
+		It("should use the override port for the HTTP listeners of an ingress without TLS", func() {
+			ingress.Spec.TLS = nil
+			listenerConfigs := cb.getListenerConfigs(cbCtx)
+			Expect(listenerConfigs).To(HaveLen(1))
+			Expect(listenerConfigs[listenerIdentifier{FrontendPort: 8443, HostName: tests.Host}].Protocol).To(Equal(n.HTTP))
+		})
+
+		It("should use the default ports and emit an event when the HTTP and HTTPS listeners share a port", func() {
+			ingress.Annotations[annotations.SslRedirectFrontendPortKey] = "8443"
+			listenerConfigs := cb.getListenerConfigs(cbCtx)
+			Expect(listenerConfigs).To(HaveKey(listenerIdentifier{FrontendPort: 443, HostName: tests.Host}))
+			Expect(listenerConfigs).To(HaveKey(listenerIdentifier{FrontendPort: 80, HostName: tests.Host}))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidAnnotation))
+		})
+
+		It("should emit a conflict event instead of creating a Private IP listener on a port of the Public IP", func() {
+			privateIngress := tests.NewIngressFixture()
+			privateIngress.Name = "private-ingress"
+			privateIngress.Spec.TLS = nil
+			privateIngress.Spec.Rules[0].Host = "internal.contoso.com"
+			privateIngress.Spec.Rules = privateIngress.Spec.Rules[:1]
+			privateIngress.Annotations[annotations.UsePrivateIPKey] = "true"
+			privateIngress.Annotations[annotations.OverrideFrontendPortKey] = "8080"
+			cbCtx.IngressList = append(cbCtx.IngressList, privateIngress)
+
+			listeners, _ := cb.getListeners(cbCtx)
+			for _, listener := range *listeners {
+				Expect(*listener.HostName).To(Equal(tests.Host))
+			}
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			event := <-recorder.Events
+			Expect(event).To(ContainSubstring(events.ReasonFrontendPortConflict))
+			Expect(event).To(ContainSubstring(generateListenerName(listenerIdentifier{FrontendPort: 8080, HostName: tests.Host})))
+		})
+	})
+
 	Context("create a new App Gateway HTTP Listener for V1 gateway", func() {
 		ing1 := tests.NewIngressFixture()
 		ing2 := tests.NewIngressFixture()
-		ingressList := []*v1beta1.Ingress{
+		ingressList := []*networking.Ingress{
 			ing1,
 			ing2,
 		}
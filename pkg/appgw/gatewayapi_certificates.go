@@ -0,0 +1,25 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+)
+
+// getGatewayAPISecretToCertificateMap returns the certificates of the TLS secrets of the HTTPS listeners with attached routes.
+// Listeners with a missing certificate are not attached to, and are reported in the status of their Gateway instead.
+func (c *appGwConfigBuilder) getGatewayAPISecretToCertificateMap(cbCtx *ConfigBuilderContext) map[secretIdentifier]*k8scontext.PfxCertificate {
+	secretIDCertificateMap := make(map[secretIdentifier]*k8scontext.PfxCertificate)
+	for _, config := range c.getListenerConfigsFromGatewayAPI(cbCtx) {
+		if config.Secret == (secretIdentifier{}) {
+			continue
+		}
+		if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(config.Secret.secretKey()); cert != nil {
+			secretIDCertificateMap[config.Secret] = cert
+		}
+	}
+	return secretIDCertificateMap
+}
@@ -0,0 +1,105 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+)
+
+// getGatewayAPIAttachments returns the listeners the HTTPRoutes attach to, oldest route first; listeners App Gateway
+// cannot implement are left out.
+func (c *appGwConfigBuilder) getGatewayAPIAttachments(cbCtx *ConfigBuilderContext) []k8scontext.HTTPRouteAttachment {
+	var attachments []k8scontext.HTTPRouteAttachment
+	for _, route := range cbCtx.GatewayAPIRoutes {
+		for _, parentRef := range route.Spec.ParentRefs {
+			gateway := k8scontext.GetHTTPRouteParent(route, parentRef, cbCtx.GatewayAPIGateways)
+			if gateway == nil {
+				continue
+			}
+			routeAttachments, _ := c.k8sContext.AttachHTTPRoute(route, parentRef, gateway)
+			for _, attachment := range routeAttachments {
+				if _, reason := c.k8sContext.ResolveGatewayListener(gateway, attachment.Listener); reason != "" {
+					continue
+				}
+				attachments = append(attachments, attachment)
+			}
+		}
+	}
+	return attachments
+}
+
+// getListenerConfigsFromGatewayAPI returns a listener per port and host name of the Gateway listeners with attached routes.
+// Listeners of several Gateways with the same port and host name share the App Gateway listener of the first one.
+func (c *appGwConfigBuilder) getListenerConfigsFromGatewayAPI(cbCtx *ConfigBuilderContext) map[listenerIdentifier]listenerAzConfig {
+	allListeners := make(map[listenerIdentifier]listenerAzConfig)
+	for _, attachment := range c.getGatewayAPIAttachments(cbCtx) {
+		config := listenerAzConfig{Protocol: n.HTTP}
+		if attachment.Listener.Protocol == gatewayv1beta1.HTTPSProtocolType {
+			config = listenerAzConfig{
+				Protocol: n.HTTPS,
+				Secret: secretIdentifier{
+					Namespace: attachment.Gateway.Namespace,
+					Name:      attachment.Listener.TLS.CertificateRefs[0].Name,
+				},
+			}
+		}
+		for _, hostname := range attachment.Hostnames {
+			listenerID := gatewayAPIListenerID(cbCtx, attachment, hostname)
+			if existing, exists := allListeners[listenerID]; exists {
+				if existing != config {
+					logLine := fmt.Sprintf("Listener %s of Gateway %s/%s conflicts with another Gateway listener for port %d and host %q; using the first one",
+						attachment.Listener.Name, attachment.Gateway.Namespace, attachment.Gateway.Name, listenerID.FrontendPort, hostname)
+					c.conflictGatewayAPIListener(cbCtx, attachment, gatewayv1beta1.ReasonHostnameConflict, events.ReasonGatewayAPIListenerConflict, logLine)
+				}
+				continue
+			}
+			allListeners[listenerID] = config
+		}
+	}
+	return allListeners
+}
+
+// conflictGatewayAPIListeners records the Gateway listeners, which would produce the App Gateway listener, as conflicted.
+func (c *appGwConfigBuilder) conflictGatewayAPIListeners(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, reason, eventReason, logLine string) {
+	for _, attachment := range c.getGatewayAPIAttachments(cbCtx) {
+		for _, hostname := range attachment.Hostnames {
+			if gatewayAPIListenerID(cbCtx, attachment, hostname) == listenerID {
+				c.conflictGatewayAPIListener(cbCtx, attachment, reason, eventReason, logLine)
+			}
+		}
+	}
+}
+
+// conflictGatewayAPIListener records the listener of the attachment as conflicted, and emits a warning on its Gateway once.
+func (c *appGwConfigBuilder) conflictGatewayAPIListener(cbCtx *ConfigBuilderContext, attachment k8scontext.HTTPRouteAttachment, reason, eventReason, logLine string) {
+	key := k8scontext.GatewayListenerKey(attachment.Gateway, attachment.Listener.Name)
+	if _, exists := cbCtx.GatewayAPIConflictedListeners[key]; exists {
+		return
+	}
+	if cbCtx.GatewayAPIConflictedListeners == nil {
+		cbCtx.GatewayAPIConflictedListeners = make(map[string]string)
+	}
+	cbCtx.GatewayAPIConflictedListeners[key] = reason
+	glog.Warningf("[gateway-api] %s", logLine)
+	c.recorder.Event(attachment.Gateway, v1.EventTypeWarning, eventReason, logLine)
+}
+
+// gatewayAPIListenerID identifies the App Gateway listener of a host name of the attachment.
+func gatewayAPIListenerID(cbCtx *ConfigBuilderContext, attachment k8scontext.HTTPRouteAttachment, hostname string) listenerIdentifier {
+	return listenerIdentifier{
+		FrontendPort: Port(attachment.Listener.Port),
+		HostName:     hostname,
+		UsePrivateIP: cbCtx.EnvVariables.UsePrivateIP == "true",
+	}
+}
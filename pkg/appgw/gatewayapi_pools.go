@@ -0,0 +1,57 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/golang/glog"
+)
+
+func (c *appGwConfigBuilder) getGatewayAPIBackendAddressPool(backendID gatewayAPIBackendIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+	poolName := generateAddressPoolName(backendID.serviceFullName(), backendID.servicePort(), serviceBackendPair.BackendPort)
+	if pool, ok := addressPools[poolName]; ok {
+		return pool
+	}
+
+	if service := c.k8sContext.GetService(backendID.serviceKey()); isExternalNameService(service) {
+		return c.newExternalNamePool(poolName, service)
+	}
+
+	endpoints, err := c.k8sContext.GetEndpointsByService(backendID.serviceKey())
+	if err != nil {
+		logLine := fmt.Sprintf("Failed fetching endpoints for service: %s", backendID.serviceKey())
+		glog.Errorf(logLine)
+		return nil
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if _, portExists := getUniqueTCPPorts(subset)[serviceBackendPair.BackendPort]; portExists {
+			return c.newPool(poolName, subset)
+		}
+		logLine := fmt.Sprintf("Backend target port %d does not have matching endpoint port", serviceBackendPair.BackendPort)
+		glog.Error(logLine)
+	}
+	return nil
+}
+
+func (c *appGwConfigBuilder) newGatewayAPIBackendPoolMap(cbCtx *ConfigBuilderContext) map[gatewayAPIBackendIdentifier]*n.ApplicationGatewayBackendAddressPool {
+	defaultPool := defaultBackendAddressPool(c.appGwIdentifier)
+	addressPools := map[string]*n.ApplicationGatewayBackendAddressPool{
+		*defaultPool.Name: &defaultPool,
+	}
+	backendPoolMap := make(map[gatewayAPIBackendIdentifier]*n.ApplicationGatewayBackendAddressPool)
+	_, _, serviceBackendPairMap := c.getGatewayAPIBackendsAndSettingsMap(cbCtx)
+	for backendID, serviceBackendPair := range serviceBackendPairMap {
+		backendPoolMap[backendID] = &defaultPool
+		if pool := c.getGatewayAPIBackendAddressPool(backendID, serviceBackendPair, addressPools); pool != nil {
+			addressPools[*pool.Name] = pool
+			backendPoolMap[backendID] = pool
+		}
+	}
+	return backendPoolMap
+}
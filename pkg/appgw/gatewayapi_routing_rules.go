@@ -0,0 +1,188 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// gatewayAPIPathRule is a match of a rule of an HTTPRoute; a path rule without paths is the default of the path map.
+type gatewayAPIPathRule struct {
+	name    string
+	paths   []string
+	pool    *n.ApplicationGatewayBackendAddressPool
+	setting *n.ApplicationGatewayBackendHTTPSettings
+}
+
+// getGatewayAPIPathMaps creates a path map for every listener of the HTTPRoutes. The paths of the routes attached to a listener
+// are added oldest route first; a path already routed by an older route is left out.
+func (c *appGwConfigBuilder) getGatewayAPIPathMaps(cbCtx *ConfigBuilderContext) map[listenerIdentifier]*n.ApplicationGatewayURLPathMap {
+	defaultAddressPoolID := to.StringPtr(c.appGwIdentifier.AddressPoolID(DefaultBackendAddressPoolName))
+	defaultHTTPSettingsID := to.StringPtr(c.appGwIdentifier.HTTPSettingsID(DefaultBackendHTTPSettingsName))
+
+	urlPathMaps := make(map[listenerIdentifier]*n.ApplicationGatewayURLPathMap)
+	for listenerID := range c.getListenerConfigsFromGatewayAPI(cbCtx) {
+		urlPathMaps[listenerID] = &n.ApplicationGatewayURLPathMap{
+			Etag: to.StringPtr("*"),
+			Name: to.StringPtr(generateURLPathMapName(listenerID)),
+			ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
+			ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+				DefaultBackendAddressPool:  &n.SubResource{ID: defaultAddressPoolID},
+				DefaultBackendHTTPSettings: &n.SubResource{ID: defaultHTTPSettingsID},
+				PathRules:                  &[]n.ApplicationGatewayPathRule{},
+			},
+		}
+	}
+
+	_, settingsByBackend, _ := c.getGatewayAPIBackendsAndSettingsMap(cbCtx)
+	backendPoolMap := c.newGatewayAPIBackendPoolMap(cbCtx)
+	pathRulesByRoute := make(map[*gatewayv1beta1.HTTPRoute][]gatewayAPIPathRule)
+	for _, route := range c.getGatewayAPIRoutes(cbCtx) {
+		pathRulesByRoute[route] = c.getGatewayAPIPathRules(route, backendPoolMap, settingsByBackend)
+	}
+
+	routedPaths := make(map[listenerIdentifier]map[string]interface{})
+	for _, attachment := range c.getGatewayAPIAttachments(cbCtx) {
+		for _, hostname := range attachment.Hostnames {
+			listenerID := gatewayAPIListenerID(cbCtx, attachment, hostname)
+			pathMap, exists := urlPathMaps[listenerID]
+			if !exists {
+				continue
+			}
+			if _, exists := routedPaths[listenerID]; !exists {
+				routedPaths[listenerID] = make(map[string]interface{})
+			}
+
+			for _, pathRule := range pathRulesByRoute[attachment.Route] {
+				var paths []string
+				for _, path := range pathRule.paths {
+					if _, routed := routedPaths[listenerID][path]; !routed {
+						routedPaths[listenerID][path] = nil
+						paths = append(paths, path)
+					}
+				}
+
+				// The default of the path map is routed like any other path; it is keyed with the empty path.
+				if len(pathRule.paths) == 0 {
+					if _, routed := routedPaths[listenerID][""]; !routed {
+						routedPaths[listenerID][""] = nil
+						pathMap.DefaultBackendAddressPool = &n.SubResource{ID: pathRule.pool.ID}
+						pathMap.DefaultBackendHTTPSettings = &n.SubResource{ID: pathRule.setting.ID}
+					}
+					continue
+				}
+				if len(paths) == 0 {
+					continue
+				}
+
+				*pathMap.PathRules = append(*pathMap.PathRules, n.ApplicationGatewayPathRule{
+					Etag: to.StringPtr("*"),
+					Name: to.StringPtr(pathRule.name),
+					ApplicationGatewayPathRulePropertiesFormat: &n.ApplicationGatewayPathRulePropertiesFormat{
+						Paths:               &paths,
+						BackendAddressPool:  &n.SubResource{ID: pathRule.pool.ID},
+						BackendHTTPSettings: &n.SubResource{ID: pathRule.setting.ID},
+					},
+				})
+			}
+		}
+	}
+
+	return urlPathMaps
+}
+
+// getGatewayAPIPathRules returns the path rules of the matches of the rules of the route. Matches App Gateway cannot route, and
+// rules with filters, are left out and an event is emitted on the route.
+func (c *appGwConfigBuilder) getGatewayAPIPathRules(route *gatewayv1beta1.HTTPRoute, backendPoolMap map[gatewayAPIBackendIdentifier]*n.ApplicationGatewayBackendAddressPool, settingsByBackend map[gatewayAPIBackendIdentifier]*n.ApplicationGatewayBackendHTTPSettings) []gatewayAPIPathRule {
+	var pathRules []gatewayAPIPathRule
+	for ruleIdx := range route.Spec.Rules {
+		rule := &route.Spec.Rules[ruleIdx]
+		if len(rule.Filters) > 0 {
+			c.emitInvalidGatewayAPIRoute(route, fmt.Sprintf("Rule %d of HTTPRoute %s/%s has filters, which App Gateway does not implement; the rule is ignored", ruleIdx, route.Namespace, route.Name))
+			continue
+		}
+
+		backendRef := gatewayAPIRuleBackend(rule)
+		if backendRef == nil {
+			continue
+		}
+		backendID := generateGatewayAPIBackendID(route, *backendRef)
+		pool, found := backendPoolMap[backendID]
+		if !found {
+			continue
+		}
+		httpSettings, found := settingsByBackend[backendID]
+		if !found {
+			continue
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			// A rule without matches matches all requests.
+			matches = []gatewayv1beta1.HTTPRouteMatch{{}}
+		}
+		for matchIdx := range matches {
+			paths, err := gatewayAPIMatchPaths(&matches[matchIdx])
+			if err != nil {
+				c.emitInvalidGatewayAPIRoute(route, fmt.Sprintf("Match %d of rule %d of HTTPRoute %s/%s is ignored: %s", matchIdx, ruleIdx, route.Namespace, route.Name, err))
+				continue
+			}
+			pathRules = append(pathRules, gatewayAPIPathRule{
+				name:    generatePathRuleName(route.Namespace, route.Name, fmt.Sprintf("%d-%d", ruleIdx, matchIdx)),
+				paths:   paths,
+				pool:    pool,
+				setting: httpSettings,
+			})
+		}
+	}
+	return pathRules
+}
+
+// gatewayAPIMatchPaths returns the App Gateway paths of the path of the match; none for the prefix /, which matches all requests.
+// App Gateway routes requests by path only.
+func gatewayAPIMatchPaths(match *gatewayv1beta1.HTTPRouteMatch) ([]string, error) {
+	if len(match.Headers) > 0 || len(match.QueryParams) > 0 || match.Method != nil {
+		return nil, errors.New("App Gateway does not match requests by headers, query parameters or method")
+	}
+
+	pathType, value := gatewayv1beta1.PathMatchPathPrefix, "/"
+	if match.Path != nil {
+		if match.Path.Type != nil {
+			pathType = *match.Path.Type
+		}
+		if match.Path.Value != nil {
+			value = *match.Path.Value
+		}
+	}
+
+	switch pathType {
+	case gatewayv1beta1.PathMatchExact:
+		return []string{value}, nil
+	case gatewayv1beta1.PathMatchPathPrefix:
+		prefix := strings.TrimRight(value, "/")
+		if prefix == "" {
+			return nil, nil
+		}
+		return []string{prefix, prefix + "/*"}, nil
+	default:
+		return nil, fmt.Errorf("App Gateway does not match paths of type %s", pathType)
+	}
+}
+
+func (c *appGwConfigBuilder) emitInvalidGatewayAPIRoute(route *gatewayv1beta1.HTTPRoute, logLine string) {
+	glog.Warning(logLine)
+	c.recorder.Event(route, v1.EventTypeWarning, events.ReasonInvalidGatewayAPIRoute, logLine)
+}
@@ -0,0 +1,147 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test the listeners and path maps of the Gateway API", func() {
+	var configBuilder appGwConfigBuilder
+	var gateway *gatewayv1beta1.Gateway
+	var route *gatewayv1beta1.HTTPRoute
+	var cbCtx *ConfigBuilderContext
+
+	listenerID := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+	pathType := func(pathType gatewayv1beta1.PathMatchType) *gatewayv1beta1.PathMatchType { return &pathType }
+	backendRef := gatewayv1beta1.HTTPBackendRef{Name: tests.ServiceName, Port: to.Int32Ptr(80)}
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(tests.NewEndpointsFixture())
+		_ = configBuilder.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+
+		gateway = &gatewayv1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "contoso", Namespace: tests.Namespace},
+			Spec: gatewayv1beta1.GatewaySpec{
+				GatewayClassName: "azure-application-gateway",
+				Listeners: []gatewayv1beta1.Listener{{
+					Name:     "http",
+					Hostname: to.StringPtr("*.com"),
+					Port:     80,
+					Protocol: gatewayv1beta1.HTTPProtocolType,
+				}},
+			},
+		}
+		route = &gatewayv1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: tests.Namespace},
+			Spec: gatewayv1beta1.HTTPRouteSpec{
+				ParentRefs: []gatewayv1beta1.ParentReference{{Name: gateway.Name}},
+				Hostnames:  []string{tests.Host},
+				Rules: []gatewayv1beta1.HTTPRouteRule{
+					{
+						Matches: []gatewayv1beta1.HTTPRouteMatch{
+							{Path: &gatewayv1beta1.HTTPPathMatch{Type: pathType(gatewayv1beta1.PathMatchPathPrefix), Value: to.StringPtr("/api/")}},
+							{Path: &gatewayv1beta1.HTTPPathMatch{Type: pathType(gatewayv1beta1.PathMatchExact), Value: to.StringPtr("/health")}},
+						},
+						BackendRefs: []gatewayv1beta1.HTTPBackendRef{backendRef},
+					},
+					{
+						BackendRefs: []gatewayv1beta1.HTTPBackendRef{backendRef},
+					},
+				},
+			},
+		}
+
+		env := environment.GetFakeEnv()
+		env.EnableGatewayAPI = true
+		cbCtx = &ConfigBuilderContext{
+			GatewayAPIGateways: []*gatewayv1beta1.Gateway{gateway},
+			GatewayAPIRoutes:   []*gatewayv1beta1.HTTPRoute{route},
+			EnvVariables:       env,
+		}
+	})
+
+	It("creates a listener for the host names of the route matching the listener", func() {
+		Expect(configBuilder.getListenerConfigsFromGatewayAPI(cbCtx)).To(Equal(map[listenerIdentifier]listenerAzConfig{
+			listenerID: {Protocol: n.HTTP},
+		}))
+	})
+
+	It("creates no listener for a route in a namespace the listener does not allow", func() {
+		route.Namespace = "other"
+		Expect(configBuilder.getListenerConfigsFromGatewayAPI(cbCtx)).To(BeEmpty())
+	})
+
+	It("routes the prefixes and exact paths of the route, and the prefix / by default", func() {
+		_, settingsByBackend, _ := configBuilder.getGatewayAPIBackendsAndSettingsMap(cbCtx)
+		backendID := generateGatewayAPIBackendID(route, backendRef)
+		Expect(settingsByBackend).To(HaveKey(backendID))
+		settingsID := settingsByBackend[backendID].ID
+
+		pathMap := configBuilder.getGatewayAPIPathMaps(cbCtx)[listenerID]
+		Expect(pathMap).ToNot(BeNil())
+		Expect(*pathMap.DefaultBackendHTTPSettings.ID).To(Equal(*settingsID))
+		Expect(*pathMap.PathRules).To(HaveLen(2))
+		Expect(*(*pathMap.PathRules)[0].Paths).To(Equal([]string{"/api", "/api/*"}))
+		Expect(*(*pathMap.PathRules)[1].Paths).To(Equal([]string{"/health"}))
+		Expect(*(*pathMap.PathRules)[0].BackendHTTPSettings.ID).To(Equal(*settingsID))
+	})
+
+	It("keeps the paths of the oldest route", func() {
+		newer := route.DeepCopy()
+		newer.Name = "newer"
+		newer.Spec.Rules = newer.Spec.Rules[:1]
+		cbCtx.GatewayAPIRoutes = append(cbCtx.GatewayAPIRoutes, newer)
+
+		pathMap := configBuilder.getGatewayAPIPathMaps(cbCtx)[listenerID]
+		Expect(*pathMap.PathRules).To(HaveLen(2))
+		Expect(*(*pathMap.PathRules)[0].Name).To(Equal(generatePathRuleName(route.Namespace, route.Name, "0-0")))
+		Expect(*(*pathMap.PathRules)[1].Name).To(Equal(generatePathRuleName(route.Namespace, route.Name, "0-1")))
+	})
+
+	It("ignores the matches by header with an event on the route", func() {
+		route.Spec.Rules[0].Matches[0].Headers = []gatewayv1beta1.HTTPHeaderMatch{{Name: "version", Value: "v2"}}
+
+		pathMap := configBuilder.getGatewayAPIPathMaps(cbCtx)[listenerID]
+		Expect(*pathMap.PathRules).To(HaveLen(1))
+		Expect(*(*pathMap.PathRules)[0].Paths).To(Equal([]string{"/health"}))
+
+		recorder := configBuilder.recorder.(*record.FakeRecorder)
+		Expect(len(recorder.Events)).To(Equal(1))
+		Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidGatewayAPIRoute))
+	})
+
+	It("marks a listener used by an Ingress as conflicted, with an event on the Gateway", func() {
+		ingress := tests.NewIngressFixture()
+		cbCtx.IngressList = []*networking.Ingress{ingress}
+		Expect(configBuilder.getListenerConfigs(cbCtx)).To(HaveKey(listenerID))
+
+		configBuilder.getListeners(cbCtx)
+		Expect(cbCtx.GatewayAPIConflictedListeners).To(Equal(map[string]string{
+			k8scontext.GatewayListenerKey(gateway, "http"): gatewayv1beta1.ReasonHostnameConflict,
+		}))
+
+		recorder := configBuilder.recorder.(*record.FakeRecorder)
+		var emitted []string
+		for len(recorder.Events) > 0 {
+			emitted = append(emitted, <-recorder.Events)
+		}
+		Expect(emitted).To(ContainElement(ContainSubstring(events.ReasonGatewayAPIListenerConflict)))
+	})
+})
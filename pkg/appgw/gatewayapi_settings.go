@@ -0,0 +1,229 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// getGatewayAPIRoutes returns the HTTPRoutes attached to a listener, oldest first.
+func (c *appGwConfigBuilder) getGatewayAPIRoutes(cbCtx *ConfigBuilderContext) []*gatewayv1beta1.HTTPRoute {
+	var routes []*gatewayv1beta1.HTTPRoute
+	attached := make(map[*gatewayv1beta1.HTTPRoute]interface{})
+	for _, attachment := range c.getGatewayAPIAttachments(cbCtx) {
+		if _, exists := attached[attachment.Route]; !exists {
+			attached[attachment.Route] = nil
+			routes = append(routes, attachment.Route)
+		}
+	}
+	return routes
+}
+
+// gatewayAPIRuleBackend returns the backend requests matching the rule are sent to; the first one with a weight, as App Gateway
+// does not split the traffic of a path between backends.
+func gatewayAPIRuleBackend(rule *gatewayv1beta1.HTTPRouteRule) *gatewayv1beta1.HTTPBackendRef {
+	for idx := range rule.BackendRefs {
+		backendRef := &rule.BackendRefs[idx]
+		if backendRef.Weight == nil || *backendRef.Weight > 0 {
+			return backendRef
+		}
+	}
+	return nil
+}
+
+// getGatewayAPIBackendsAndSettingsMap creates the HTTP settings and the health probes of the backends of the HTTPRoutes once per
+// config build. Backends, which cannot be resolved to a service port, are skipped and an event is emitted on their route.
+func (c *appGwConfigBuilder) getGatewayAPIBackendsAndSettingsMap(cbCtx *ConfigBuilderContext) ([]n.ApplicationGatewayBackendHTTPSettings, map[gatewayAPIBackendIdentifier]*n.ApplicationGatewayBackendHTTPSettings, map[gatewayAPIBackendIdentifier]serviceBackendPortPair) {
+	if c.mem.gatewayAPISettings != nil && c.mem.gatewayAPISettingsByBackend != nil && c.mem.gatewayAPIPairsByBackend != nil {
+		return *c.mem.gatewayAPISettings, *c.mem.gatewayAPISettingsByBackend, *c.mem.gatewayAPIPairsByBackend
+	}
+
+	backendHTTPSettingsMap := make(map[gatewayAPIBackendIdentifier]*n.ApplicationGatewayBackendHTTPSettings)
+	finalServiceBackendPairMap := make(map[gatewayAPIBackendIdentifier]serviceBackendPortPair)
+	httpSettingsCollection := make(map[string]n.ApplicationGatewayBackendHTTPSettings)
+	probesByName := make(map[string]n.ApplicationGatewayProbe)
+
+	for _, route := range c.getGatewayAPIRoutes(cbCtx) {
+		for ruleIdx := range route.Spec.Rules {
+			backendRef := gatewayAPIRuleBackend(&route.Spec.Rules[ruleIdx])
+			if backendRef == nil {
+				continue
+			}
+			backendID := generateGatewayAPIBackendID(route, *backendRef)
+			if _, exists := backendHTTPSettingsMap[backendID]; exists {
+				continue
+			}
+
+			service, reason := c.k8sContext.ResolveHTTPBackendRef(route, *backendRef)
+			if reason != "" {
+				logLine := fmt.Sprintf("HTTPRoute %s/%s references the backend %s, which cannot be used: %s", route.Namespace, route.Name, backendRef.Name, reason)
+				glog.Error(logLine)
+				c.recorder.Event(route, v1.EventTypeWarning, events.ReasonInvalidGatewayAPIRoute, logLine)
+				continue
+			}
+
+			serviceBackendPairs := c.resolveGatewayAPIServicePorts(backendID, service)
+			if len(serviceBackendPairs) != 1 {
+				logLine := fmt.Sprintf("Unable to resolve a single backend port for service:port [%s:%s]", backendID.serviceKey(), backendID.servicePort())
+				glog.Error(logLine)
+				c.recorder.Event(route, v1.EventTypeWarning, events.ReasonPortResolutionError, logLine)
+				continue
+			}
+
+			// At this point there will be only one pair
+			var uniquePair serviceBackendPortPair
+			for k := range serviceBackendPairs {
+				uniquePair = k
+			}
+
+			finalServiceBackendPairMap[backendID] = uniquePair
+			httpSettings := c.generateGatewayAPIHTTPSettings(backendID, uniquePair.BackendPort, service)
+			if probe := c.generateGatewayAPIHealthProbe(backendID, service); probe != nil {
+				probesByName[*probe.Name] = *probe
+				httpSettings.Probe = resourceRef(*probe.ID)
+			}
+			httpSettingsCollection[*httpSettings.Name] = httpSettings
+			backendHTTPSettingsMap[backendID] = &httpSettings
+		}
+	}
+
+	httpSettings := make([]n.ApplicationGatewayBackendHTTPSettings, 0, len(httpSettingsCollection))
+	for _, backend := range httpSettingsCollection {
+		httpSettings = append(httpSettings, backend)
+	}
+
+	c.mem.gatewayAPISettings = &httpSettings
+	c.mem.gatewayAPISettingsByBackend = &backendHTTPSettingsMap
+	c.mem.gatewayAPIPairsByBackend = &finalServiceBackendPairMap
+	c.mem.gatewayAPIProbes = &probesByName
+	return httpSettings, backendHTTPSettingsMap, finalServiceBackendPairMap
+}
+
+// getGatewayAPIProbes returns the health probes of the backends of the HTTPRoutes by name.
+func (c *appGwConfigBuilder) getGatewayAPIProbes(cbCtx *ConfigBuilderContext) map[string]n.ApplicationGatewayProbe {
+	c.getGatewayAPIBackendsAndSettingsMap(cbCtx)
+	return *c.mem.gatewayAPIProbes
+}
+
+// resolveGatewayAPIServicePorts returns the service and backend ports of the service port of the backend; ExternalName services
+// are reached on the port of the backend.
+func (c *appGwConfigBuilder) resolveGatewayAPIServicePorts(backendID gatewayAPIBackendIdentifier, service *v1.Service) map[serviceBackendPortPair]interface{} {
+	resolvedBackendPorts := make(map[serviceBackendPortPair]interface{})
+	if isExternalNameService(service) {
+		pair := serviceBackendPortPair{
+			ServicePort: Port(backendID.Port),
+			BackendPort: Port(backendID.Port),
+		}
+		resolvedBackendPorts[pair] = nil
+		return resolvedBackendPorts
+	}
+
+	for _, sp := range service.Spec.Ports {
+		if sp.Protocol != v1.ProtocolTCP || sp.Port != backendID.Port {
+			continue
+		}
+
+		if sp.TargetPort.String() == "" {
+			// targetPort is not defined, by default targetPort == port
+			pair := serviceBackendPortPair{
+				ServicePort: Port(sp.Port),
+				BackendPort: Port(sp.Port),
+			}
+			resolvedBackendPorts[pair] = nil
+		} else if sp.TargetPort.Type == intstr.Int {
+			// port is defined as port number
+			pair := serviceBackendPortPair{
+				ServicePort: Port(sp.Port),
+				BackendPort: Port(sp.TargetPort.IntVal),
+			}
+			resolvedBackendPorts[pair] = nil
+		} else {
+			// if service port is defined by name, need to resolve
+			ingressBackendID := backendID.ingressBackendID()
+			for targetPort := range c.resolvePortName(sp.Name, &ingressBackendID) {
+				pair := serviceBackendPortPair{
+					ServicePort: Port(sp.Port),
+					BackendPort: Port(targetPort),
+				}
+				resolvedBackendPorts[pair] = nil
+			}
+		}
+		break
+	}
+	return resolvedBackendPorts
+}
+
+// generateGatewayAPIHTTPSettings creates the HTTP settings of a backend; they use the default probe unless the backend has its own.
+func (c *appGwConfigBuilder) generateGatewayAPIHTTPSettings(backendID gatewayAPIBackendIdentifier, port Port, service *v1.Service) n.ApplicationGatewayBackendHTTPSettings {
+	httpSettingsName := generateHTTPSettingsName(backendID.serviceFullName(), backendID.servicePort(), port, backendID.gatewayAPIRouteIdentifier.Name)
+	glog.V(5).Infof("Created a new HTTP setting w/ name: %s\n", httpSettingsName)
+	httpSettings := n.ApplicationGatewayBackendHTTPSettings{
+		Etag: to.StringPtr("*"),
+		Name: &httpSettingsName,
+		ID:   to.StringPtr(c.appGwIdentifier.HTTPSettingsID(httpSettingsName)),
+		ApplicationGatewayBackendHTTPSettingsPropertiesFormat: &n.ApplicationGatewayBackendHTTPSettingsPropertiesFormat{
+			Protocol: n.HTTP,
+			Port:     to.Int32Ptr(int32(port)),
+			Probe:    resourceRef(c.appGwIdentifier.probeID(defaultProbeName(n.HTTP))),
+		},
+	}
+
+	// ExternalName services usually point outside the cluster at endpoints only answering to their own host name.
+	if isExternalNameService(service) {
+		httpSettings.PickHostNameFromBackendAddress = to.BoolPtr(true)
+	}
+	return httpSettings
+}
+
+// generateGatewayAPIHealthProbe creates the health probe of a backend from the readiness or liveness probe of its pods; nil when
+// the pods have none. The probe of an ExternalName service sends the host name of the backend address.
+func (c *appGwConfigBuilder) generateGatewayAPIHealthProbe(backendID gatewayAPIBackendIdentifier, service *v1.Service) *n.ApplicationGatewayProbe {
+	probe := defaultProbe(c.appGwIdentifier, n.HTTP)
+	probe.Name = to.StringPtr(generateGatewayAPIProbeName(backendID))
+	probe.ID = to.StringPtr(c.appGwIdentifier.probeID(*probe.Name))
+
+	if isExternalNameService(service) {
+		probe.Host = nil
+		probe.PickHostNameFromBackendHTTPSettings = to.BoolPtr(true)
+		return &probe
+	}
+
+	k8sProbeForServiceContainer := c.getProbeForServiceContainer(service, backendID.ingressBackendID())
+	if k8sProbeForServiceContainer == nil {
+		return nil
+	}
+	if len(k8sProbeForServiceContainer.Handler.HTTPGet.Host) != 0 {
+		probe.Host = to.StringPtr(k8sProbeForServiceContainer.Handler.HTTPGet.Host)
+	}
+	if len(k8sProbeForServiceContainer.Handler.HTTPGet.Path) != 0 {
+		probe.Path = to.StringPtr(k8sProbeForServiceContainer.Handler.HTTPGet.Path)
+	}
+	if len(k8sProbeForServiceContainer.Handler.HTTPGet.Port.String()) != 0 {
+		probe.Port = to.Int32Ptr(k8sProbeForServiceContainer.Handler.HTTPGet.Port.IntVal)
+	}
+	if k8sProbeForServiceContainer.Handler.HTTPGet.Scheme == v1.URISchemeHTTPS {
+		probe.Protocol = n.HTTPS
+	}
+	if k8sProbeForServiceContainer.PeriodSeconds != 0 {
+		probe.Interval = to.Int32Ptr(k8sProbeForServiceContainer.PeriodSeconds)
+	}
+	if k8sProbeForServiceContainer.TimeoutSeconds != 0 {
+		probe.Timeout = to.Int32Ptr(k8sProbeForServiceContainer.TimeoutSeconds)
+	}
+	if k8sProbeForServiceContainer.FailureThreshold != 0 {
+		probe.UnhealthyThreshold = to.Int32Ptr(k8sProbeForServiceContainer.FailureThreshold)
+	}
+	return &probe
+}
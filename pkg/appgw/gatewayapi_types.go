@@ -0,0 +1,62 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	networking "k8s.io/api/networking/v1"
+
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+)
+
+type gatewayAPIRouteIdentifier struct {
+	Namespace string
+	Name      string
+}
+
+// gatewayAPIBackendIdentifier is the port of a service referenced by the backends of an HTTPRoute.
+type gatewayAPIBackendIdentifier struct {
+	serviceIdentifier
+	gatewayAPIRouteIdentifier
+
+	Port int32
+}
+
+// servicePort returns the port number of the backend.
+func (b gatewayAPIBackendIdentifier) servicePort() string {
+	return fmt.Sprint(b.Port)
+}
+
+// ingressBackendID returns the backend of an Ingress for the same service port, so that the ports and probes of the service
+// are resolved the same way for HTTPRoutes and Ingresses.
+func (b gatewayAPIBackendIdentifier) ingressBackendID() backendIdentifier {
+	backend := &networking.IngressBackend{
+		Service: &networking.IngressServiceBackend{
+			Name: b.serviceIdentifier.Name,
+			Port: networking.ServiceBackendPort{Number: b.Port},
+		},
+	}
+	return backendIdentifier{serviceIdentifier: b.serviceIdentifier, Backend: backend}
+}
+
+func generateGatewayAPIBackendID(route *gatewayv1beta1.HTTPRoute, backendRef gatewayv1beta1.HTTPBackendRef) gatewayAPIBackendIdentifier {
+	backendID := gatewayAPIBackendIdentifier{
+		serviceIdentifier: serviceIdentifier{
+			Namespace: route.Namespace,
+			Name:      backendRef.Name,
+		},
+
+		gatewayAPIRouteIdentifier: gatewayAPIRouteIdentifier{
+			Namespace: route.Namespace,
+			Name:      route.Name,
+		},
+	}
+	if backendRef.Port != nil {
+		backendID.Port = *backendRef.Port
+	}
+	return backendID
+}
@@ -10,17 +10,36 @@ import (
 	"sort"
 	"strings"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
+// anyStatusCode matches the status code of every response; App Gateway then only checks that the backend responds.
+const anyStatusCode = "200-599"
+
+// probeAnnotations are the health probe properties set with annotations on an ingress; nil when not set.
+type probeAnnotations struct {
+	host               *string
+	path               *string
+	interval           *int32
+	timeout            *int32
+	unhealthyThreshold *int32
+	statusCodes        *[]string
+	body               *string
+	tcpOnly            bool
+}
+
 func (c *appGwConfigBuilder) HealthProbesCollection(cbCtx *ConfigBuilderContext) error {
 	healthProbeCollection, _ := c.newProbesMap(cbCtx)
 	agicCreatedProbes := make([]n.ApplicationGatewayProbe, 0, len(healthProbeCollection))
@@ -29,7 +48,7 @@ func (c *appGwConfigBuilder) HealthProbesCollection(cbCtx *ConfigBuilderContext)
 	}
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil, cbCtx.FrontendIPAddresses)
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedProbes()
 		brownfield.LogProbes(glog.V(3), existingBlacklisted, existingNonBlacklisted, agicCreatedProbes)
 		agicCreatedProbes = brownfield.MergeProbes(existingBlacklisted, agicCreatedProbes)
@@ -70,6 +89,12 @@ func (c *appGwConfigBuilder) newProbesMap(cbCtx *ConfigBuilderContext) (map[stri
 		glog.V(5).Infof("Created probe %s for ingress %s/%s and service %s", *probesMap[backendID].Name, backendID.Ingress.Namespace, backendID.Ingress.Name, backendID.serviceKey())
 	}
 
+	if cbCtx.EnvVariables.EnableGatewayAPI {
+		for probeName, probe := range c.getGatewayAPIProbes(cbCtx) {
+			healthProbeCollection[probeName] = probe
+		}
+	}
+
 	c.mem.probesByName = &healthProbeCollection
 	c.mem.probesByBackend = &probesMap
 	return healthProbeCollection, probesMap
@@ -82,7 +107,7 @@ func (c *appGwConfigBuilder) generateHealthProbe(backendID backendIdentifier) *n
 		return nil
 	}
 	probe := defaultProbe(c.appGwIdentifier, n.HTTP)
-	probe.Name = to.StringPtr(generateProbeName(backendID.Path.Backend.ServiceName, backendID.Path.Backend.ServicePort.String(), backendID.Ingress))
+	probe.Name = to.StringPtr(generateProbeName(backendID.Path.Backend.Service.Name, serviceBackendPortToStr(backendID.Path.Backend.Service.Port), backendID.Ingress))
 	probe.ID = to.StringPtr(c.appGwIdentifier.probeID(*probe.Name))
 	if backendID.Rule != nil && len(backendID.Rule.Host) != 0 {
 		probe.Host = to.StringPtr(backendID.Rule.Host)
@@ -99,8 +124,10 @@ func (c *appGwConfigBuilder) generateHealthProbe(backendID backendIdentifier) *n
 		probe.Protocol = n.HTTPS
 	}
 
-	k8sProbeForServiceContainer := c.getProbeForServiceContainer(service, backendID)
-	if k8sProbeForServiceContainer != nil {
+	probeAnnotations := c.getProbeAnnotations(backendID.Ingress)
+	if probeAnnotations.tcpOnly {
+		probe.Path = to.StringPtr("/")
+	} else if k8sProbeForServiceContainer := c.getProbeForServiceContainer(service, backendID); k8sProbeForServiceContainer != nil {
 		if len(k8sProbeForServiceContainer.Handler.HTTPGet.Host) != 0 {
 			probe.Host = to.StringPtr(k8sProbeForServiceContainer.Handler.HTTPGet.Host)
 		}
@@ -124,13 +151,102 @@ func (c *appGwConfigBuilder) generateHealthProbe(backendID backendIdentifier) *n
 		}
 	}
 
+	// The probes send the host name of the HTTP settings, so that they check the backends like the requests use them.
+	if hostName, err := annotations.BackendHostName(backendID.Ingress); err == nil {
+		probe.Host = to.StringPtr(hostName)
+	} else if pickHostName, err := c.pickHostNameFromBackend(backendID); err == nil && pickHostName {
+		probe.Host = nil
+		probe.PickHostNameFromBackendHTTPSettings = to.BoolPtr(true)
+	}
+
+	// The annotations take precedence over the properties inferred from the ingress and the pods.
+	if probeAnnotations.host != nil {
+		probe.Host = probeAnnotations.host
+		probe.PickHostNameFromBackendHTTPSettings = nil
+	}
+	if probeAnnotations.path != nil {
+		probe.Path = probeAnnotations.path
+	}
+	if probeAnnotations.interval != nil {
+		probe.Interval = probeAnnotations.interval
+	}
+	if probeAnnotations.timeout != nil {
+		probe.Timeout = probeAnnotations.timeout
+	}
+	if probeAnnotations.unhealthyThreshold != nil {
+		probe.UnhealthyThreshold = probeAnnotations.unhealthyThreshold
+	}
+	if probeAnnotations.tcpOnly {
+		probe.Match = &n.ApplicationGatewayProbeHealthResponseMatch{StatusCodes: &[]string{anyStatusCode}}
+	} else if probeAnnotations.statusCodes != nil || probeAnnotations.body != nil {
+		probe.Match = &n.ApplicationGatewayProbeHealthResponseMatch{
+			StatusCodes: probeAnnotations.statusCodes,
+			Body:        probeAnnotations.body,
+		}
+	}
+
 	if probe.Path != nil {
 		probe.Path = to.StringPtr(strings.TrimRight(*probe.Path, "*"))
 	}
 	return &probe
 }
 
+// getProbeAnnotations reads the health probe annotations of the ingress once per config build, and emits an event on the ingress
+// for every invalid one.
+func (c *appGwConfigBuilder) getProbeAnnotations(ingress *networking.Ingress) probeAnnotations {
+	if c.mem.probeAnnotations == nil {
+		probeAnnotationsByIngress := make(map[string]probeAnnotations)
+		c.mem.probeAnnotations = &probeAnnotationsByIngress
+	}
+	ingressKey := utils.GetResourceKey(ingress.Namespace, ingress.Name)
+	if result, exists := (*c.mem.probeAnnotations)[ingressKey]; exists {
+		return result
+	}
+
+	// isSet tells whether the annotation has a valid value.
+	isSet := func(err error) bool {
+		if err != nil && !errors.IsMissingAnnotations(err) {
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+		}
+		return err == nil
+	}
+
+	var result probeAnnotations
+	if host, err := annotations.HealthProbeHostName(ingress); isSet(err) {
+		result.host = to.StringPtr(host)
+	}
+	if path, err := annotations.HealthProbePath(ingress); isSet(err) {
+		result.path = to.StringPtr(path)
+	}
+	if interval, err := annotations.HealthProbeInterval(ingress); isSet(err) {
+		result.interval = to.Int32Ptr(interval)
+	}
+	if timeout, err := annotations.HealthProbeTimeout(ingress); isSet(err) {
+		result.timeout = to.Int32Ptr(timeout)
+	}
+	if threshold, err := annotations.HealthProbeUnhealthyThreshold(ingress); isSet(err) {
+		result.unhealthyThreshold = to.Int32Ptr(threshold)
+	}
+	if statusCodes, err := annotations.HealthProbeStatusCodes(ingress); isSet(err) {
+		result.statusCodes = &statusCodes
+	}
+	if body, err := annotations.HealthProbeBodyMatch(ingress); isSet(err) {
+		result.body = to.StringPtr(body)
+	}
+	if tcpOnly, err := annotations.IsHealthProbeTCPOnly(ingress); isSet(err) {
+		result.tcpOnly = tcpOnly
+	}
+
+	(*c.mem.probeAnnotations)[ingressKey] = result
+	return result
+}
+
 func (c *appGwConfigBuilder) getProbeForServiceContainer(service *v1.Service, backendID backendIdentifier) *v1.Probe {
+	// ExternalName services select no pods.
+	if isExternalNameService(service) {
+		return nil
+	}
+
 	// find all the target ports used by the service
 	allPorts := make(map[int32]interface{})
 	for _, sp := range service.Spec.Ports {
@@ -138,9 +254,9 @@ func (c *appGwConfigBuilder) getProbeForServiceContainer(service *v1.Service, ba
 			continue
 		}
 
-		if fmt.Sprint(sp.Port) == backendID.Backend.ServicePort.String() ||
-			sp.Name == backendID.Backend.ServicePort.String() ||
-			sp.TargetPort.String() == backendID.Backend.ServicePort.String() {
+		if fmt.Sprint(sp.Port) == serviceBackendPortToStr(backendID.Backend.Service.Port) ||
+			sp.Name == serviceBackendPortToStr(backendID.Backend.Service.Port) ||
+			sp.TargetPort.String() == serviceBackendPortToStr(backendID.Backend.Service.Port) {
 
 			// Matched a service port in the service
 			if sp.TargetPort.String() == "" {
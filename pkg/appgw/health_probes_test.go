@@ -8,20 +8,23 @@ package appgw
 import (
 	"fmt"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
 // appgw_suite_test.go launches these Ginkgo tests
 
 var _ = Describe("configure App Gateway health probes", func() {
-	ingressList := []*v1beta1.Ingress{tests.NewIngressFixture()}
+	ingressList := []*networking.Ingress{tests.NewIngressFixture()}
 	serviceList := []*v1.Service{tests.NewServiceFixture()}
 
 	Context("create probes", func() {
@@ -58,7 +61,7 @@ var _ = Describe("configure App Gateway health probes", func() {
 				PickHostNameFromBackendHTTPSettings: nil,
 				MinServers:                          nil,
 				Match:                               nil,
-				ProvisioningState:                   nil,
+				ProvisioningState:                   "",
 				Port:                                to.Int32Ptr(9090),
 			},
 			Name: to.StringPtr(probeName),
@@ -79,7 +82,7 @@ var _ = Describe("configure App Gateway health probes", func() {
 				PickHostNameFromBackendHTTPSettings: nil,
 				MinServers:                          nil,
 				Match:                               nil,
-				ProvisioningState:                   nil,
+				ProvisioningState:                   "",
 				Port:                                to.Int32Ptr(9090),
 			},
 			Name: to.StringPtr(probeName),
@@ -127,7 +130,7 @@ var _ = Describe("configure App Gateway health probes", func() {
 		probeMap, _ := cb.newProbesMap(cbCtx)
 
 		backend := ingressList[0].Spec.Rules[0].HTTP.Paths[0].Backend
-		probeName := generateProbeName(backend.ServiceName, backend.ServicePort.String(), ingressList[0])
+		probeName := generateProbeName(backend.Service.Name, serviceBackendPortToStr(backend.Service.Port), ingressList[0])
 		It("uses the readiness probe to set the protocol on the probe", func() {
 			Expect(probeMap[probeName].Protocol).To(Equal(n.HTTPS))
 		})
@@ -157,4 +160,83 @@ var _ = Describe("configure App Gateway health probes", func() {
 			Expect(*actual).To(ContainElement(defaultProbe(cb.appGwIdentifier, n.HTTPS)))
 		})
 	})
+
+	Context("apply the health probe annotations", func() {
+		var cb appGwConfigBuilder
+		var ingress *networking.Ingress
+		var cbCtx *ConfigBuilderContext
+
+		BeforeEach(func() {
+			cb = newConfigBuilderFixture(nil)
+			_ = cb.k8sContext.Caches.Endpoints.Add(tests.NewEndpointsFixture())
+			_ = cb.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+			_ = cb.k8sContext.Caches.Pods.Add(tests.NewPodFixture(tests.ServiceName, tests.Namespace, tests.ContainerName, tests.ContainerPort))
+
+			ingress = tests.NewIngressFixture()
+			cbCtx = &ConfigBuilderContext{
+				IngressList: []*networking.Ingress{ingress},
+				ServiceList: serviceList,
+			}
+		})
+
+		// probes returns the probes AGIC created for the backends of the ingress.
+		probes := func() []n.ApplicationGatewayProbe {
+			_, probesByBackend := cb.newProbesMap(cbCtx)
+			var result []n.ApplicationGatewayProbe
+			for _, probe := range probesByBackend {
+				result = append(result, *probe)
+			}
+			Expect(result).To(HaveLen(2))
+			return result
+		}
+
+		It("takes precedence over the readiness probe", func() {
+			ingress.Annotations[annotations.HealthProbeHostNameKey] = "health.contoso.com"
+			ingress.Annotations[annotations.HealthProbePathKey] = "/healthz"
+			ingress.Annotations[annotations.HealthProbeIntervalKey] = "10"
+			ingress.Annotations[annotations.HealthProbeTimeoutKey] = "2"
+			ingress.Annotations[annotations.HealthProbeUnhealthyThresholdKey] = "5"
+			ingress.Annotations[annotations.HealthProbeStatusCodesKey] = "200-399,401"
+			ingress.Annotations[annotations.HealthProbeBodyMatchKey] = "healthy"
+
+			for _, probe := range probes() {
+				Expect(*probe.Host).To(Equal("health.contoso.com"))
+				Expect(*probe.Path).To(Equal("/healthz"))
+				Expect(*probe.Interval).To(Equal(int32(10)))
+				Expect(*probe.Timeout).To(Equal(int32(2)))
+				Expect(*probe.UnhealthyThreshold).To(Equal(int32(5)))
+				Expect(*probe.Port).To(Equal(int32(9090)))
+				Expect(probe.Match).To(Equal(&n.ApplicationGatewayProbeHealthResponseMatch{
+					StatusCodes: &[]string{"200-399", "401"},
+					Body:        to.StringPtr("healthy"),
+				}))
+			}
+		})
+
+		It("ignores the readiness probe and matches any status code when TCP only", func() {
+			ingress.Annotations[annotations.HealthProbeTCPOnlyKey] = "true"
+
+			for _, probe := range probes() {
+				Expect(*probe.Path).To(Equal("/"))
+				Expect(probe.Port).To(BeNil())
+				Expect(*probe.Interval).To(Equal(int32(30)))
+				Expect(probe.Match).To(Equal(&n.ApplicationGatewayProbeHealthResponseMatch{StatusCodes: &[]string{anyStatusCode}}))
+			}
+		})
+
+		It("emits one event per invalid annotation and keeps the inferred properties", func() {
+			ingress.Annotations[annotations.HealthProbeIntervalKey] = "0"
+			ingress.Annotations[annotations.HealthProbeStatusCodesKey] = "2xx"
+
+			for _, probe := range probes() {
+				Expect(*probe.Path).To(Equal(tests.HealthPath))
+				Expect(*probe.Interval).To(Equal(int32(20)))
+				Expect(probe.Match).To(BeNil())
+			}
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(2))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidAnnotation))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidAnnotation))
+		})
+	})
 })
@@ -15,5 +15,8 @@ func (c *appGwConfigBuilder) Listeners(cbCtx *ConfigBuilderContext) error {
 	// in the RequestRoutingRules step, which must be executed after Listeners.
 	c.appGw.RedirectConfigurations = c.getRedirectConfigurations(cbCtx)
 
+	// Listeners without error pages of their own return the error pages of the gateway.
+	c.customErrorConfigurations(cbCtx)
+
 	return nil
 }
@@ -8,7 +8,7 @@ package appgw
 import (
 	"fmt"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 )
 
@@ -46,6 +46,14 @@ func (agw Identifier) sslCertificateID(certname string) string {
 	return agw.gatewayResourceID("sslCertificates", certname)
 }
 
+func (agw Identifier) trustedRootCertificateID(certName string) string {
+	return agw.gatewayResourceID("trustedRootCertificates", certName)
+}
+
+func (agw Identifier) authenticationCertificateID(certName string) string {
+	return agw.gatewayResourceID("authenticationCertificates", certName)
+}
+
 // HTTPSettingsID generates an ID for App Gateway HTTP settings resource.
 func (agw Identifier) HTTPSettingsID(settingsName string) string {
 	return agw.gatewayResourceID("backendHttpSettingsCollection", settingsName)
@@ -63,6 +71,10 @@ func (agw Identifier) redirectConfigurationID(configurationName string) string {
 	return agw.gatewayResourceID("redirectConfigurations", configurationName)
 }
 
+func (agw Identifier) rewriteRuleSetID(ruleSetName string) string {
+	return agw.gatewayResourceID("rewriteRuleSets", ruleSetName)
+}
+
 func (agw Identifier) probeID(probeName string) string {
 	return agw.gatewayResourceID("probes", probeName)
 }
@@ -6,15 +6,24 @@
 package appgw
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/golang/glog"
-	"k8s.io/api/extensions/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
-func (c *appGwConfigBuilder) getFrontendPortsFromIngress(ingress *v1beta1.Ingress, env environment.EnvVariables) map[Port]interface{} {
+func (c *appGwConfigBuilder) getFrontendPortsFromIngress(ingress *networking.Ingress, env environment.EnvVariables) map[Port]interface{} {
 	frontendPorts := make(map[Port]interface{})
 	for ruleIdx := range ingress.Spec.Rules {
 		rule := &ingress.Spec.Rules[ruleIdx]
@@ -30,7 +39,7 @@ func (c *appGwConfigBuilder) getFrontendPortsFromIngress(ingress *v1beta1.Ingres
 	return frontendPorts
 }
 
-func (c *appGwConfigBuilder) getListenersFromIngress(ingress *v1beta1.Ingress, env environment.EnvVariables) map[listenerIdentifier]listenerAzConfig {
+func (c *appGwConfigBuilder) getListenersFromIngress(ingress *networking.Ingress, env environment.EnvVariables) map[listenerIdentifier]listenerAzConfig {
 	listeners := make(map[listenerIdentifier]listenerAzConfig)
 	for ruleIdx := range ingress.Spec.Rules {
 		rule := &ingress.Spec.Rules[ruleIdx]
@@ -46,7 +55,39 @@ func (c *appGwConfigBuilder) getListenersFromIngress(ingress *v1beta1.Ingress, e
 	return listeners
 }
 
-func (c *appGwConfigBuilder) processIngressRule(rule *v1beta1.IngressRule, ingress *v1beta1.Ingress, env environment.EnvVariables) (map[Port]interface{}, map[listenerIdentifier]listenerAzConfig) {
+// IngressRuleFrontends returns the frontend IP address and port of the listeners, which the config builder creates for the rule
+// of the ingress. It lets brownfield deployments prune the rules on prohibited frontends before the config is built.
+func IngressRuleFrontends(k8sContext *k8scontext.Context, appGw *n.ApplicationGateway, recorder record.EventRecorder, cbCtx *ConfigBuilderContext, ingress *networking.Ingress, rule *networking.IngressRule) []brownfield.ListenerFrontend {
+	c := &appGwConfigBuilder{
+		k8sContext: k8sContext,
+		appGw:      *appGw,
+		recorder:   recorder,
+	}
+	return c.getIngressRuleFrontends(cbCtx, ingress, rule)
+}
+
+// getIngressRuleFrontends returns the frontends of the listeners of the rule; the address of a public IP comes from
+// cbCtx.FrontendIPAddresses, and is left empty when it is not known.
+func (c *appGwConfigBuilder) getIngressRuleFrontends(cbCtx *ConfigBuilderContext, ingress *networking.Ingress, rule *networking.IngressRule) []brownfield.ListenerFrontend {
+	var frontends []brownfield.ListenerFrontend
+	_, listeners := c.processIngressRule(rule, ingress, cbCtx.EnvVariables)
+	for listenerID := range listeners {
+		frontend := brownfield.ListenerFrontend{Port: int32(listenerID.FrontendPort)}
+		if c.appGw.FrontendIPConfigurations != nil {
+			if ipConf := LookupIPConfigurationByType(c.appGw.FrontendIPConfigurations, listenerID.UsePrivateIP); ipConf != nil {
+				if ipConf.PrivateIPAddress != nil {
+					frontend.IP = *ipConf.PrivateIPAddress
+				} else if ipConf.ID != nil {
+					frontend.IP = cbCtx.FrontendIPAddresses[*ipConf.ID]
+				}
+			}
+		}
+		frontends = append(frontends, frontend)
+	}
+	return frontends
+}
+
+func (c *appGwConfigBuilder) processIngressRule(rule *networking.IngressRule, ingress *networking.Ingress, env environment.EnvVariables) (map[Port]interface{}, map[listenerIdentifier]listenerAzConfig) {
 	frontendPorts := make(map[Port]interface{})
 	ingressHostnameSecretIDMap := c.newHostToSecretMap(ingress)
 	listeners := make(map[listenerIdentifier]listenerAzConfig)
@@ -55,13 +96,28 @@ func (c *appGwConfigBuilder) processIngressRule(rule *v1beta1.IngressRule, ingre
 	usePrivateIPFromAnnotation, _ := annotations.UsePrivateIP(ingress)
 	usePrivateIPForIngress := usePrivateIPFromAnnotation || env.UsePrivateIP == "true"
 
-	cert, secID := c.getCertificate(ingress, rule.Host, ingressHostnameSecretIDMap)
-	hasTLS := cert != nil
+	// A certificate installed on App Gateway takes precedence over one from Key Vault, which takes precedence over
+	// the TLS secrets of the ingress.
+	var cert *string
+	var secID *secretIdentifier
+	sslCertificateName, installedErr := annotations.AppGwSslCertificate(ingress)
+	hasInstalledCert := installedErr == nil
+	keyVaultSecretID, keyVaultErr := annotations.KeyVaultSecretID(ingress)
+	hasKeyVaultCert := !hasInstalledCert && keyVaultErr == nil
+	if hasKeyVaultCert {
+		sslCertificateName = generateKeyVaultCertificateName(keyVaultSecretID)
+	} else if !hasInstalledCert {
+		cert, secID = c.getCertificate(ingress, rule.Host, ingressHostnameSecretIDMap)
+	}
+	hasTLS := hasInstalledCert || hasKeyVaultCert || cert != nil
 	sslRedirect, _ := annotations.IsSslRedirect(ingress)
+	firewallPolicy := c.getFirewallPolicy(ingress)
+	portOverrides := c.getFrontendPortOverrides(ingress)
+	errorPages := c.getCustomErrorPages(ingress)
 	// If a certificate is available we enable only HTTPS; unless ingress is annotated with ssl-redirect - then
 	// we enable HTTPS as well as HTTP, and redirect HTTP to HTTPS.
 	if hasTLS {
-		listenerID := generateListenerID(rule, n.HTTPS, nil, usePrivateIPForIngress)
+		listenerID := generateListenerID(rule, n.HTTPS, portOverrides.listener, usePrivateIPForIngress)
 		frontendPorts[Port(listenerID.FrontendPort)] = nil
 		// Only associate the Listener with a Redirect if redirect is enabled
 		redirect := ""
@@ -69,24 +125,91 @@ func (c *appGwConfigBuilder) processIngressRule(rule *v1beta1.IngressRule, ingre
 			redirect = generateSSLRedirectConfigurationName(listenerID)
 		}
 
-		listeners[listenerID] = listenerAzConfig{
+		listenerConfig := listenerAzConfig{
 			Protocol:                     n.HTTPS,
-			Secret:                       *secID,
 			SslRedirectConfigurationName: redirect,
+			FirewallPolicy:               firewallPolicy,
+			CustomErrorPages:             errorPages,
+		}
+		if hasInstalledCert || hasKeyVaultCert {
+			listenerConfig.SslCertificateName = sslCertificateName
+		} else {
+			listenerConfig.Secret = *secID
 		}
+		listeners[listenerID] = listenerConfig
 	}
 
 	// Enable HTTP only if HTTPS is not configured OR if ingress annotated with 'ssl-redirect'
 	if sslRedirect || !hasTLS {
-		listenerID := generateListenerID(rule, n.HTTP, nil, usePrivateIPForIngress)
+		overridePort := portOverrides.listener
+		if hasTLS {
+			overridePort = portOverrides.sslRedirect
+		}
+		listenerID := generateListenerID(rule, n.HTTP, overridePort, usePrivateIPForIngress)
 		frontendPorts[Port(listenerID.FrontendPort)] = nil
 		listeners[listenerID] = listenerAzConfig{
-			Protocol: n.HTTP,
+			Protocol:         n.HTTP,
+			FirewallPolicy:   firewallPolicy,
+			CustomErrorPages: errorPages,
 		}
 	}
 	return frontendPorts, listeners
 }
 
+// frontendPortOverrides are the frontend ports the annotations of an ingress set; nil for the default ports.
+type frontendPortOverrides struct {
+	// listener is the port of the HTTPS listeners, or of the HTTP listeners of an ingress without TLS.
+	listener *Port
+
+	// sslRedirect is the port of the HTTP listeners, which redirect to the HTTPS listeners.
+	sslRedirect *Port
+}
+
+// getFrontendPortOverrides reads the frontend port annotations of the ingress once per config build, and emits an event
+// on the ingress when they are invalid.
+func (c *appGwConfigBuilder) getFrontendPortOverrides(ingress *networking.Ingress) frontendPortOverrides {
+	if c.mem.frontendPortOverrides == nil {
+		overrides := make(map[string]frontendPortOverrides)
+		c.mem.frontendPortOverrides = &overrides
+	}
+	ingressKey := utils.GetResourceKey(ingress.Namespace, ingress.Name)
+	if overrides, exists := (*c.mem.frontendPortOverrides)[ingressKey]; exists {
+		return overrides
+	}
+
+	var overrides frontendPortOverrides
+	if port, err := annotations.OverrideFrontendPort(ingress); err == nil {
+		overrides.listener = (*Port)(&port)
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+	if port, err := annotations.SslRedirectFrontendPort(ingress); err == nil {
+		overrides.sslRedirect = (*Port)(&port)
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
+	// The HTTP listener redirecting to the HTTPS listener can't share its port
+	if sslRedirect, _ := annotations.IsSslRedirect(ingress); sslRedirect {
+		httpsPort, httpPort := Port(443), Port(80)
+		if overrides.listener != nil {
+			httpsPort = *overrides.listener
+		}
+		if overrides.sslRedirect != nil {
+			httpPort = *overrides.sslRedirect
+		}
+		if httpsPort == httpPort {
+			logLine := fmt.Sprintf("Ingress %s/%s uses frontend port %d for both its HTTPS listeners and the HTTP listeners redirecting to them; using the default ports 443 and 80", ingress.Namespace, ingress.Name, httpsPort)
+			glog.Error(logLine)
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, logLine)
+			overrides = frontendPortOverrides{}
+		}
+	}
+
+	(*c.mem.frontendPortOverrides)[ingressKey] = overrides
+	return overrides
+}
+
 func (c *appGwConfigBuilder) newBackendIdsFiltered(cbCtx *ConfigBuilderContext) map[backendIdentifier]interface{} {
 	if c.mem.backendIDs != nil {
 		return *c.mem.backendIDs
@@ -94,8 +217,8 @@ func (c *appGwConfigBuilder) newBackendIdsFiltered(cbCtx *ConfigBuilderContext)
 
 	backendIDs := make(map[backendIdentifier]interface{})
 	for _, ingress := range cbCtx.IngressList {
-		if ingress.Spec.Backend != nil {
-			backendID := generateBackendID(ingress, nil, nil, ingress.Spec.Backend)
+		if isServiceBackend(ingress.Spec.DefaultBackend) {
+			backendID := generateBackendID(ingress, nil, nil, ingress.Spec.DefaultBackend)
 			glog.V(3).Info("Found default backend:", backendID.serviceKey())
 			backendIDs[backendID] = nil
 		}
@@ -108,6 +231,10 @@ func (c *appGwConfigBuilder) newBackendIdsFiltered(cbCtx *ConfigBuilderContext)
 			}
 			for pathIdx := range rule.HTTP.Paths {
 				path := &rule.HTTP.Paths[pathIdx]
+				if !isServiceBackend(&path.Backend) {
+					glog.V(3).Infof("[%s] Skip path %s of Ingress %s - only Service backends are supported.", ingress.Namespace, path.Path, ingress.Name)
+					continue
+				}
 				backendID := generateBackendID(ingress, rule, path, &path.Backend)
 				glog.V(5).Info("Found backend:", backendID.serviceKey())
 				backendIDs[backendID] = nil
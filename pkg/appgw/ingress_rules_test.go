@@ -2,10 +2,12 @@ package appgw
 
 import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 )
 
 // appgw_suite_test.go launches these Ginkgo tests
@@ -47,7 +49,7 @@ var _ = Describe("Process ingress rules, listeners, and ports", func() {
 		cb := newConfigBuilderFixture(&certs)
 		ingress := tests.NewIngressFixture()
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingress},
+			IngressList: []*networking.Ingress{ingress},
 		}
 		listenersAzureConfigMap := cb.getListenerConfigs(cbCtx)
 
@@ -96,7 +98,7 @@ var _ = Describe("Process ingress rules, listeners, and ports", func() {
 		cb := newConfigBuilderFixture(&certs)
 		ingress := tests.NewIngressFixture()
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingress},
+			IngressList: []*networking.Ingress{ingress},
 		}
 		It("should have setup tests with some TLS certs", func() {
 			Ω(len(ingress.Spec.TLS)).Should(BeNumerically(">=", 2))
@@ -121,7 +123,7 @@ var _ = Describe("Process ingress rules, listeners, and ports", func() {
 		ingress := tests.NewIngressFixture()
 		ingress.Annotations[annotations.SslRedirectKey] = "one/two/three"
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingress},
+			IngressList: []*networking.Ingress{ingress},
 		}
 
 		// !! Action !!
@@ -170,6 +172,58 @@ var _ = Describe("Process ingress rules, listeners, and ports", func() {
 			Expect(actualVal.SslRedirectConfigurationName).To(Equal(""))
 		})
 	})
+
+	Context("frontends of the rules of an ingress on an overridden frontend port", func() {
+		var cb appGwConfigBuilder
+		var ingress *networking.Ingress
+		var cbCtx *ConfigBuilderContext
+
+		BeforeEach(func() {
+			certs := newCertsFixture()
+			cb = newConfigBuilderFixture(&certs)
+			ingress = tests.NewIngressFixture()
+			ingress.Annotations[annotations.OverrideFrontendPortKey] = "8443"
+			cbCtx = &ConfigBuilderContext{
+				IngressList:         []*networking.Ingress{ingress},
+				FrontendIPAddresses: map[string]string{tests.PublicIPID: "1.2.3.4"},
+			}
+		})
+
+		It("should return the address of the public IP and the overridden port of the HTTPS listener", func() {
+			Expect(cb.getIngressRuleFrontends(cbCtx, ingress, &ingress.Spec.Rules[0])).To(ContainElement(brownfield.ListenerFrontend{IP: "1.2.3.4", Port: 8443}))
+		})
+
+		It("should return the address of the private IP", func() {
+			ingress.Annotations[annotations.UsePrivateIPKey] = "true"
+			Expect(cb.getIngressRuleFrontends(cbCtx, ingress, &ingress.Spec.Rules[0])).To(ContainElement(brownfield.ListenerFrontend{IP: "abc", Port: 8443}))
+		})
+
+		It("should leave the address of the public IP empty when it is not known", func() {
+			cbCtx.FrontendIPAddresses = nil
+			Expect(cb.getIngressRuleFrontends(cbCtx, ingress, &ingress.Spec.Rules[0])).To(ContainElement(brownfield.ListenerFrontend{Port: 8443}))
+		})
+
+		It("should prune the rules routed onto a prohibited port", func() {
+			prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+				{
+					Spec: ptv1.AzureIngressProhibitedTargetSpec{
+						IP:   "1.2.3.4",
+						Port: 8443,
+					},
+				},
+			}
+			getFrontends := func(rule *networking.IngressRule) []brownfield.ListenerFrontend {
+				return cb.getIngressRuleFrontends(cbCtx, ingress, rule)
+			}
+			Expect(brownfield.PruneIngressRules(ingress, prohibitedTargets, getFrontends)).To(BeEmpty())
+
+			// The frontend ports of an ingress are memoized by the config builder
+			delete(ingress.Annotations, annotations.OverrideFrontendPortKey)
+			certs := newCertsFixture()
+			cb = newConfigBuilderFixture(&certs)
+			Expect(brownfield.PruneIngressRules(ingress, prohibitedTargets, getFrontends)).ToNot(BeEmpty())
+		})
+	})
 })
 
 func getMapKeys(m *map[listenerIdentifier]listenerAzConfig) []listenerIdentifier {
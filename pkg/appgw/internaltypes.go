@@ -12,13 +12,16 @@ package appgw
 import (
 	"crypto/md5"
 	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 )
 
@@ -32,14 +35,18 @@ const (
 	prefixRoutingRule  = "rr"
 	prefixRedirect     = "sslr"
 	prefixPathRule     = "pr"
+	prefixKeyVaultCert = "kv"
+	prefixBackendCA    = "ca"
+	prefixRewrite      = "rw"
+	prefixURLRedirect  = "rd"
 )
 
 type backendIdentifier struct {
 	serviceIdentifier
-	Ingress *v1beta1.Ingress
-	Rule    *v1beta1.IngressRule
-	Path    *v1beta1.HTTPIngressPath
-	Backend *v1beta1.IngressBackend
+	Ingress *networking.Ingress
+	Rule    *networking.IngressRule
+	Path    *networking.HTTPIngressPath
+	Backend *networking.IngressBackend
 }
 
 type serviceBackendPortPair struct {
@@ -63,6 +70,13 @@ type secretIdentifier struct {
 	Name      string
 }
 
+// backendCAIdentifier identifies the Secret or ConfigMap with the CA bundle of HTTPS backends.
+type backendCAIdentifier struct {
+	Namespace string
+	Kind      annotations.ResourceKind
+	Name      string
+}
+
 // Max length for a property name is 80 characters. We hash w/ MD5 when length is > 80, which is 32 characters
 var agPrefixValidator = regexp.MustCompile(`^[0-9a-zA-Z\-]{0,47}$`)
 var agPrefix = environment.GetEnvironmentVariable("APPGW_CONFIG_NAME_PREFIX", "", agPrefixValidator)
@@ -72,6 +86,15 @@ type listenerAzConfig struct {
 	Protocol                     n.ApplicationGatewayProtocol
 	Secret                       secretIdentifier
 	SslRedirectConfigurationName string
+
+	// SslCertificateName is the certificate of an HTTPS listener, which does not come from a Kubernetes secret.
+	SslCertificateName string
+
+	// FirewallPolicy is the resource ID of the WAF policy of the listener and its path rules.
+	FirewallPolicy string
+
+	// CustomErrorPages are the pages the listener returns instead of the App Gateway error pages.
+	CustomErrorPages customErrorPages
 }
 
 // formatPropName ensures that the string generated is not longer than 80 characters.
@@ -105,6 +128,10 @@ func (s secretIdentifier) secretFullName() string {
 	return fmt.Sprintf("%v-%v", s.Namespace, s.Name)
 }
 
+func (s backendCAIdentifier) resourceKey() string {
+	return fmt.Sprintf("%v/%v", s.Namespace, s.Name)
+}
+
 func getResourceKey(namespace, name string) string {
 	return formatPropName(fmt.Sprintf("%v/%v", namespace, name))
 }
@@ -113,10 +140,15 @@ func generateHTTPSettingsName(serviceName string, servicePort string, backendPor
 	return formatPropName(fmt.Sprintf("%s%s-%v-%v-%v-%s", agPrefix, prefixHTTPSettings, serviceName, servicePort, backendPort, ingress))
 }
 
-func generateProbeName(serviceName string, servicePort string, ingress *v1beta1.Ingress) string {
+func generateProbeName(serviceName string, servicePort string, ingress *networking.Ingress) string {
 	return formatPropName(fmt.Sprintf("%s%s-%s-%v-%v-%s", agPrefix, prefixProbe, ingress.Namespace, serviceName, servicePort, ingress.Name))
 }
 
+// generateGatewayAPIProbeName names the probe of a backend of an HTTPRoute after the route, like the probes of Ingress backends.
+func generateGatewayAPIProbeName(backendID gatewayAPIBackendIdentifier) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%v-%v-%s", agPrefix, prefixProbe, backendID.gatewayAPIRouteIdentifier.Namespace, backendID.serviceIdentifier.Name, backendID.servicePort(), backendID.gatewayAPIRouteIdentifier.Name))
+}
+
 func generateAddressPoolName(serviceName string, servicePort string, backendPort Port) string {
 	return formatPropName(fmt.Sprintf("%s%s-%v-%v-bp-%v", agPrefix, prefixPool, serviceName, servicePort, backendPort))
 }
@@ -144,6 +176,31 @@ func generateSSLRedirectConfigurationName(targetListener listenerIdentifier) str
 	return formatPropName(fmt.Sprintf("%s%s-%s", agPrefix, prefixRedirect, generateListenerName(targetListener)))
 }
 
+// generateKeyVaultCertificateName names the certificate after the vault, the secret and its version, so Ingresses
+// referencing the same Key Vault secret share the certificate.
+func generateKeyVaultCertificateName(keyVaultSecretID string) string {
+	vault, secret := keyVaultSecretID, ""
+	if secretIDURL, err := url.Parse(keyVaultSecretID); err == nil {
+		vault = strings.Split(secretIDURL.Host, ".")[0]
+		secret = strings.Join(strings.Split(strings.Trim(strings.TrimPrefix(secretIDURL.Path, "/secrets/"), "/"), "/"), "-")
+	}
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s", agPrefix, prefixKeyVaultCert, vault, secret))
+}
+
+// generateBackendCACertificateName names a certificate of a CA bundle after the Secret or ConfigMap holding it and its position in the bundle.
+func generateBackendCACertificateName(caID backendCAIdentifier, idx int) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s-%s-%d", agPrefix, prefixBackendCA, caID.Namespace, caID.Kind, caID.Name, idx))
+}
+
+// generateRewriteRuleSetName names the rewrite rule set after the AzureIngressRewrite, so Ingresses referencing it share it.
+func generateRewriteRuleSetName(namespace, name string) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s", agPrefix, prefixRewrite, namespace, name))
+}
+
+func generateURLRedirectConfigurationName(namespace, ingress string) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s", agPrefix, prefixURLRedirect, namespace, ingress))
+}
+
 func generatePathRuleName(namespace, ingress, suffix string) string {
 	return formatPropName(fmt.Sprintf("%s%s-%s-%s-%s", agPrefix, prefixPathRule, namespace, ingress, suffix))
 }
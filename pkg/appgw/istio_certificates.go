@@ -0,0 +1,36 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+)
+
+// getIstioSecretToCertificateMap returns the certificates of the TLS secrets of the Istio Gateway servers, and emits an event
+// on the Gateways referencing a secret, which is missing or is not a TLS secret.
+func (c *appGwConfigBuilder) getIstioSecretToCertificateMap(cbCtx *ConfigBuilderContext) map[secretIdentifier]*k8scontext.PfxCertificate {
+	secretIDCertificateMap := make(map[secretIdentifier]*k8scontext.PfxCertificate)
+	for _, gateway := range cbCtx.IstioGateways {
+		for _, server := range gateway.Spec.Servers {
+			secretID, exists := c.getIstioServerSecret(gateway, server)
+			if !exists {
+				continue
+			}
+			if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(secretID.secretKey()); cert != nil {
+				secretIDCertificateMap[secretID] = cert
+			} else {
+				logLine := fmt.Sprintf("Unable to find the secret associated to secretId: [%s]", secretID.secretKey())
+				c.recorder.Event(gateway, v1.EventTypeWarning, events.ReasonSecretNotFound, logLine)
+			}
+		}
+	}
+	return secretIDCertificateMap
+}
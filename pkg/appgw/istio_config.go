@@ -54,8 +54,9 @@ func generateIstioDestinationID(virtualService *v1alpha3.VirtualService, destina
 			Name:      virtualService.Name,
 		},
 
-		DestinationHost:   destination.Host,
-		DestinationSubset: destination.Subset,
-		DestinationPort:   destination.Port.Number,
+		DestinationHost:     destination.Host,
+		DestinationSubset:   destination.Subset,
+		DestinationPort:     destination.Port.Number,
+		DestinationPortName: destination.Port.Name,
 	}
 }
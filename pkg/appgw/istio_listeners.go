@@ -6,9 +6,13 @@
 package appgw
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"sort"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/golang/glog"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 )
 
 func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alpha3.Gateway, istioVirtualServices []*v1alpha3.VirtualService) map[listenerIdentifier]listenerAzConfig {
@@ -20,9 +24,25 @@ func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alph
 	}
 
 	allListeners := make(map[listenerIdentifier]listenerAzConfig)
+	httpsRedirectHosts := make(map[string]interface{})
 	for _, igwy := range istioGateways {
 		for _, server := range igwy.Spec.Servers {
-			if server.Port.Protocol != v1alpha3.ProtocolHTTP {
+			var config listenerAzConfig
+			switch server.Port.Protocol {
+			case v1alpha3.ProtocolHTTP:
+				config = listenerAzConfig{Protocol: n.HTTP}
+			case v1alpha3.ProtocolHTTPS:
+				secretID, exists := c.getIstioServerSecret(igwy, server)
+				if !exists {
+					glog.Infof("[istio] AGIC does not support Gateway HTTPS Server with Server.TLS.Mode=%+v and without a TLS secret", istioServerTLSMode(server))
+					continue
+				}
+				if c.k8sContext.CertificateSecretStore.GetPfxCertificate(secretID.secretKey()) == nil {
+					glog.Errorf("[istio] Will not create HTTPS listeners for Gateway %s/%s; unable to find the secret %s", igwy.Namespace, igwy.Name, secretID.secretKey())
+					continue
+				}
+				config = listenerAzConfig{Protocol: n.HTTPS, Secret: secretID}
+			default:
 				glog.Infof("[istio] AGIC does not support Gateway with Server.Port.Protocol=%+v", server.Port.Protocol)
 				continue
 			}
@@ -34,11 +54,33 @@ func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alph
 					FrontendPort: Port(server.Port.Number),
 					HostName:     host,
 				}
-				allListeners[listenerID] = listenerAzConfig{Protocol: n.HTTP}
+				allListeners[listenerID] = config
+				if config.Protocol == n.HTTP && server.TLS != nil && server.TLS.HTTPSRedirect {
+					httpsRedirectHosts[host] = nil
+				}
 			}
 		}
 	}
 
+	// HTTP servers with httpsRedirect redirect to the HTTPS listener of the host, just like the ssl-redirect annotation of an Ingress.
+	// Both listeners name the redirect: the HTTPS listener creates it and the HTTP listener links to it.
+	for listenerID, config := range allListeners {
+		if _, redirect := httpsRedirectHosts[listenerID.HostName]; !redirect || config.Protocol != n.HTTP {
+			continue
+		}
+		targetListener, exists := istioHTTPSListener(allListeners, listenerID.HostName)
+		if !exists {
+			glog.Errorf("[istio] Will not redirect listener %s to HTTPS; there is no HTTPS Server for host %s", generateListenerName(listenerID), listenerID.HostName)
+			continue
+		}
+		redirectName := generateSSLRedirectConfigurationName(targetListener)
+		config.SslRedirectConfigurationName = redirectName
+		allListeners[listenerID] = config
+		targetConfig := allListeners[targetListener]
+		targetConfig.SslRedirectConfigurationName = redirectName
+		allListeners[targetListener] = targetConfig
+	}
+
 	// App Gateway must have at least one listener - the default one!
 	if len(allListeners) == 0 {
 		allListeners[defaultFrontendListenerIdentifier()] = listenerAzConfig{
@@ -49,3 +91,40 @@ func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alph
 
 	return allListeners
 }
+
+// getIstioServerSecret returns the TLS secret of a Gateway server, which terminates TLS; false when App Gateway cannot terminate TLS for it.
+func (c *appGwConfigBuilder) getIstioServerSecret(gateway *v1alpha3.Gateway, server v1alpha3.Server) (secretIdentifier, bool) {
+	secretName := k8scontext.IstioServerSecretName(server)
+	if secretName == "" {
+		return secretIdentifier{}, false
+	}
+	return secretIdentifier{Namespace: gateway.Namespace, Name: secretName}, true
+}
+
+// istioHTTPSListener returns the HTTPS listener of the host; the one on port 443, or else the one with the lowest port.
+func istioHTTPSListener(listeners map[listenerIdentifier]listenerAzConfig, host string) (listenerIdentifier, bool) {
+	var ports []int
+	for listenerID, config := range listeners {
+		if listenerID.HostName == host && config.Protocol == n.HTTPS {
+			ports = append(ports, int(listenerID.FrontendPort))
+		}
+	}
+	if len(ports) == 0 {
+		return listenerIdentifier{}, false
+	}
+	sort.Ints(ports)
+	port := Port(ports[0])
+	for _, p := range ports {
+		if p == 443 {
+			port = 443
+		}
+	}
+	return listenerIdentifier{FrontendPort: port, HostName: host}, true
+}
+
+func istioServerTLSMode(server v1alpha3.Server) v1alpha3.TLSMode {
+	if server.TLS == nil {
+		return ""
+	}
+	return server.TLS.Mode
+}
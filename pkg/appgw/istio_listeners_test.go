@@ -0,0 +1,149 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test the TLS servers of Istio Gateways", func() {
+	var configBuilder appGwConfigBuilder
+	var gateway *v1alpha3.Gateway
+	var cbCtx *ConfigBuilderContext
+
+	host := "www.contoso.com"
+	httpListenerID := listenerIdentifier{FrontendPort: 80, HostName: host}
+	httpsListenerID := listenerIdentifier{FrontendPort: 443, HostName: host}
+	secretID := secretIdentifier{Namespace: tests.Namespace, Name: "contoso-tls"}
+	redirectName := generateSSLRedirectConfigurationName(httpsListenerID)
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+		secretStore := configBuilder.k8sContext.CertificateSecretStore.(*k8scontext.SecretsStore)
+		secretStore.Cache.Add(secretID.secretKey(), &k8scontext.PfxCertificate{Data: []byte("xyz"), Password: "--password--"})
+
+		gateway = &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "contoso",
+				Namespace:   tests.Namespace,
+				Annotations: map[string]string{annotations.IstioGatewayKey: annotations.ApplicationGatewayIngressClass},
+			},
+			Spec: v1alpha3.GatewaySpec{
+				Servers: []v1alpha3.Server{
+					{
+						Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+						Hosts: []string{host},
+						TLS:   &v1alpha3.TLSOptions{HTTPSRedirect: true},
+					},
+					{
+						Port:  v1alpha3.Port{Number: 443, Protocol: v1alpha3.ProtocolHTTPS},
+						Hosts: []string{host},
+						TLS:   &v1alpha3.TLSOptions{Mode: v1alpha3.TLSModeSimple, CredentialName: secretID.Name},
+					},
+				},
+			},
+		}
+		virtualService := &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "contoso", Namespace: tests.Namespace},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts:    []string{host},
+				Gateways: []string{gateway.Name},
+			},
+		}
+
+		env := environment.GetFakeEnv()
+		env.EnableIstioIntegration = true
+		cbCtx = &ConfigBuilderContext{
+			IstioGateways:        []*v1alpha3.Gateway{gateway},
+			IstioVirtualServices: []*v1alpha3.VirtualService{virtualService},
+			EnvVariables:         env,
+		}
+	})
+
+	It("creates an HTTPS listener with the certificate of the credentialName secret", func() {
+		Expect(configBuilder.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)).To(Equal(map[listenerIdentifier]listenerAzConfig{
+			httpListenerID:  {Protocol: n.HTTP, SslRedirectConfigurationName: redirectName},
+			httpsListenerID: {Protocol: n.HTTPS, Secret: secretID, SslRedirectConfigurationName: redirectName},
+		}))
+
+		Expect(configBuilder.Listeners(cbCtx)).To(Succeed())
+
+		certificateID := configBuilder.appGwIdentifier.sslCertificateID(secretID.secretFullName())
+		Expect(*configBuilder.appGw.SslCertificates).To(HaveLen(1))
+		Expect(*(*configBuilder.appGw.SslCertificates)[0].ID).To(Equal(certificateID))
+		httpsListener := configBuilder.groupListenersByListenerIdentifier(cbCtx)[httpsListenerID]
+		Expect(httpsListener).ToNot(BeNil())
+		Expect(httpsListener.Protocol).To(Equal(n.HTTPS))
+		Expect(*httpsListener.SslCertificate.ID).To(Equal(certificateID))
+	})
+
+	It("uses the istio-ingressgateway-certs secret for a serverCertificate", func() {
+		gateway.Spec.Servers[1].TLS = &v1alpha3.TLSOptions{
+			Mode:              v1alpha3.TLSModeSimple,
+			ServerCertificate: "/etc/istio/ingressgateway-certs/tls.crt",
+			PrivateKey:        "/etc/istio/ingressgateway-certs/tls.key",
+		}
+		certsID := secretIdentifier{Namespace: tests.Namespace, Name: "istio-ingressgateway-certs"}
+		secretStore := configBuilder.k8sContext.CertificateSecretStore.(*k8scontext.SecretsStore)
+		secretStore.Cache.Add(certsID.secretKey(), &k8scontext.PfxCertificate{Data: []byte("xyz"), Password: "--password--"})
+
+		configs := configBuilder.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+		Expect(configs[httpsListenerID].Secret).To(Equal(certsID))
+	})
+
+	It("redirects the HTTP listener of a server with httpsRedirect to the HTTPS listener", func() {
+		Expect(configBuilder.Listeners(cbCtx)).To(Succeed())
+		Expect(configBuilder.RequestRoutingRules(cbCtx)).To(Succeed())
+
+		redirectID := configBuilder.appGwIdentifier.redirectConfigurationID(redirectName)
+		Expect(*configBuilder.appGw.RedirectConfigurations).To(ContainElement(configBuilder.newSSLRedirectConfig(
+			listenerAzConfig{SslRedirectConfigurationName: redirectName},
+			resourceRef(configBuilder.appGwIdentifier.listenerID(generateListenerName(httpsListenerID))),
+		)))
+
+		httpRule := n.ApplicationGatewayRequestRoutingRule{}
+		for _, rule := range *configBuilder.appGw.RequestRoutingRules {
+			if *rule.Name == generateRequestRoutingRuleName(httpListenerID) {
+				httpRule = rule
+			}
+		}
+		Expect(httpRule.RuleType).To(Equal(n.Basic))
+		Expect(*httpRule.RedirectConfiguration.ID).To(Equal(redirectID))
+		Expect(httpRule.BackendAddressPool).To(BeNil())
+	})
+
+	It("skips the HTTPS server and emits an event on the Gateway when the secret is missing", func() {
+		gateway.Spec.Servers[1].TLS.CredentialName = "missing"
+
+		Expect(configBuilder.Listeners(cbCtx)).To(Succeed())
+
+		Expect(configBuilder.groupListenersByListenerIdentifier(cbCtx)).ToNot(HaveKey(httpsListenerID))
+		Expect(*configBuilder.appGw.SslCertificates).To(BeEmpty())
+		recorder := configBuilder.recorder.(*record.FakeRecorder)
+		Expect(len(recorder.Events)).To(Equal(1))
+		Expect(<-recorder.Events).To(ContainSubstring(events.ReasonSecretNotFound))
+	})
+
+	It("skips TLS servers App Gateway cannot terminate", func() {
+		gateway.Spec.Servers[1].TLS.Mode = v1alpha3.TLSModePassThrough
+
+		configs := configBuilder.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+		Expect(configs).To(Equal(map[listenerIdentifier]listenerAzConfig{
+			httpListenerID: {Protocol: n.HTTP},
+		}))
+	})
+})
@@ -6,11 +6,16 @@
 package appgw
 
 import (
+	"errors"
 	"fmt"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
 func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDestinationIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
@@ -18,40 +23,71 @@ func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDesti
 	if err != nil {
 		logLine := fmt.Sprintf("Failed fetching endpoints for service: %s", destinationID.serviceKey())
 		glog.Errorf(logLine)
-		//TODO(rhea): add recorder event for error
 		return nil
 	}
 
 	for _, subset := range endpoints.Subsets {
 		if _, portExists := getUniqueTCPPorts(subset)[serviceBackendPair.BackendPort]; portExists {
-			backendServicePort := ""
-			if destinationID.DestinationPort != 0 {
-				backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-			} else {
-				// TODO(delqn): lookup port by name
-			}
-			poolName := generateAddressPoolName(destinationID.serviceFullName(), backendServicePort, serviceBackendPair.BackendPort)
+			poolName := generateAddressPoolName(destinationID.destinationFullName(), destinationID.servicePort(), serviceBackendPair.BackendPort)
 			if pool, ok := addressPools[poolName]; ok {
 				return pool
 			}
+			if destinationID.DestinationSubset != "" {
+				subset = c.getIstioSubsetAddresses(destinationID, subset)
+			}
 			pool := c.newPool(poolName, subset)
 			pool.ID = to.StringPtr(c.appGwIdentifier.AddressPoolID(poolName))
 			return pool
 		}
 		logLine := fmt.Sprintf("Backend target port %d does not have matching endpoint port", serviceBackendPair.BackendPort)
 		glog.Error(logLine)
-		//TODO(rhea): add recorder event for error
 	}
 	return nil
 }
 
+// getIstioSubsetLabels returns the labels of the subset of the destination, as defined by the DestinationRule of its host.
+func (c *appGwConfigBuilder) getIstioSubsetLabels(destinationID istioDestinationIdentifier) (map[string]string, error) {
+	destinationRule := c.k8sContext.GetIstioDestinationRule(destinationID.serviceIdentifier.Namespace, destinationID.DestinationHost)
+	if destinationRule == nil {
+		return nil, errors.New("there is no DestinationRule for the host")
+	}
+	for _, subset := range destinationRule.Spec.Subsets {
+		if subset.Name == destinationID.DestinationSubset {
+			return subset.Labels, nil
+		}
+	}
+	return nil, fmt.Errorf("DestinationRule %s/%s has no such subset", destinationRule.Namespace, destinationRule.Name)
+}
+
+// getIstioSubsetAddresses keeps the addresses of the endpoints subset, whose pods have the labels of the subset of the destination.
+func (c *appGwConfigBuilder) getIstioSubsetAddresses(destinationID istioDestinationIdentifier, subset v1.EndpointSubset) v1.EndpointSubset {
+	subsetLabels, err := c.getIstioSubsetLabels(destinationID)
+	if err != nil {
+		glog.Errorf("Unable to use the subset %s of service [%s]: %s", destinationID.DestinationSubset, destinationID.serviceKey(), err)
+		return v1.EndpointSubset{Ports: subset.Ports}
+	}
+	selector := labels.SelectorFromSet(subsetLabels)
+
+	var addresses []v1.EndpointAddress
+	for _, address := range subset.Addresses {
+		if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
+			continue
+		}
+		pod := c.k8sContext.GetPod(utils.GetResourceKey(address.TargetRef.Namespace, address.TargetRef.Name))
+		if pod != nil && selector.Matches(labels.Set(pod.Labels)) {
+			addresses = append(addresses, address)
+		}
+	}
+	return v1.EndpointSubset{Addresses: addresses, Ports: subset.Ports}
+}
+
 func (c *appGwConfigBuilder) newIstioBackendPoolMap(cbCtx *ConfigBuilderContext) map[istioDestinationIdentifier]*n.ApplicationGatewayBackendAddressPool {
 	defaultPool := defaultBackendAddressPool(c.appGwIdentifier)
 	addressPools := map[string]*n.ApplicationGatewayBackendAddressPool{
 		*defaultPool.Name: &defaultPool,
 	}
 	backendPoolMap := make(map[istioDestinationIdentifier]*n.ApplicationGatewayBackendAddressPool)
-	_, _, istioServiceBackendPairMap, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+	_, _, istioServiceBackendPairMap := c.getIstioDestinationsAndSettingsMap(cbCtx)
 	for destinationID, serviceBackendPair := range istioServiceBackendPairMap {
 		backendPoolMap[destinationID] = &defaultPool
 		if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, addressPools); pool != nil {
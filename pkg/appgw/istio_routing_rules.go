@@ -7,68 +7,44 @@ package appgw
 
 import (
 	"fmt"
-	"strconv"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
 )
 
 func (c *appGwConfigBuilder) getIstioPathMaps(cbCtx *ConfigBuilderContext) map[listenerIdentifier]*n.ApplicationGatewayURLPathMap {
 	defaultAddressPoolID := to.StringPtr(c.appGwIdentifier.AddressPoolID(DefaultBackendAddressPoolName))
 	defaultHTTPSettingsID := to.StringPtr(c.appGwIdentifier.HTTPSettingsID(DefaultBackendHTTPSettingsName))
 
-	// TODO(delqn)
-	istioHTTPSettings, _, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+	_, settingsByDestination, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
 
 	backendByDestination := c.newIstioBackendPoolMap(cbCtx)
 
+	istioListeners := c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+
 	urlPathMaps := make(map[listenerIdentifier]*n.ApplicationGatewayURLPathMap)
 	for virtSvcIdx, virtSvc := range cbCtx.IstioVirtualServices {
-		for _, http := range virtSvc.Spec.HTTP {
+		for ruleIdx := range virtSvc.Spec.HTTP {
+			http := &virtSvc.Spec.HTTP[ruleIdx]
+			if len(http.Route) == 0 {
+				continue
+			}
 			// TODO(delqn): consider weights
-			host := http.Route[0].Destination.Host
-			var port uint32
-			if http.Route[0].Destination.Port.Number != 0 {
-				port = http.Route[0].Destination.Port.Number
-			} else {
-				port64, _ := strconv.ParseUint(http.Route[0].Destination.Port.Name, 10, 32)
-				port = uint32(port64)
+			dst := generateIstioDestinationID(virtSvc, &http.Route[0].Destination)
+			pool, found := backendByDestination[dst]
+			if !found {
+				continue
+			}
+			httpSettings, found := settingsByDestination[dst]
+			if !found {
+				continue
 			}
-			for matchIdx, match := range http.Match {
-				dst := istioDestinationIdentifier{
-					serviceIdentifier: serviceIdentifier{
-						Namespace: virtSvc.Namespace,
-						Name:      virtSvc.Name,
-					},
-					istioVirtualServiceIdentifier: istioVirtualServiceIdentifier{
-						Namespace: virtSvc.Namespace,
-						Name:      virtSvc.Name,
-					},
-					// TODO(delqn)
-					DestinationHost: host,
-					DestinationPort: port,
-				}
-
-				// TODO(delqn)
-				listenerID := listenerIdentifier{
-					FrontendPort: 80,
-					HostName:     virtSvc.Spec.Hosts[0], // TODO(delqn),
-				}
-				pool, found := backendByDestination[dst]
 
-				if !found {
+			for matchIdx, match := range http.Match {
+				if match.URI == nil {
 					continue
 				}
-				pathMap := n.ApplicationGatewayURLPathMap{
-					Etag: to.StringPtr("*"),
-					Name: to.StringPtr(generateURLPathMapName(listenerID)),
-					ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
-					ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
-						DefaultBackendAddressPool:  &n.SubResource{ID: defaultAddressPoolID},
-						DefaultBackendHTTPSettings: &n.SubResource{ID: defaultHTTPSettingsID},
-						PathRules:                  &[]n.ApplicationGatewayPathRule{},
-					},
-				}
 
 				pathRuleIdx := fmt.Sprintf("%d-%d", virtSvcIdx, matchIdx)
 
@@ -79,19 +55,51 @@ func (c *appGwConfigBuilder) getIstioPathMaps(cbCtx *ConfigBuilderContext) map[l
 						Paths: &[]string{
 							match.URI.Prefix,
 						},
-						BackendAddressPool: &n.SubResource{ID: pool.ID},
-						// TODO(delqn)
-						BackendHTTPSettings: &n.SubResource{ID: istioHTTPSettings[0].ID},
+						BackendAddressPool:  &n.SubResource{ID: pool.ID},
+						BackendHTTPSettings: &n.SubResource{ID: httpSettings.ID},
 					},
 				}
-				pathMap.PathRules = &[]n.ApplicationGatewayPathRule{
-					pathRule,
+
+				// The virtual service is served by the listeners of its hosts; HTTP listeners redirecting to HTTPS need no path rules.
+				for listenerID, listenerConfig := range istioListeners {
+					if !hasIstioHost(virtSvc, listenerID.HostName) || isIstioHTTPSRedirect(listenerConfig) {
+						continue
+					}
+					pathMap, exists := urlPathMaps[listenerID]
+					if !exists {
+						pathMap = &n.ApplicationGatewayURLPathMap{
+							Etag: to.StringPtr("*"),
+							Name: to.StringPtr(generateURLPathMapName(listenerID)),
+							ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
+							ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+								DefaultBackendAddressPool:  &n.SubResource{ID: defaultAddressPoolID},
+								DefaultBackendHTTPSettings: &n.SubResource{ID: defaultHTTPSettingsID},
+								PathRules:                  &[]n.ApplicationGatewayPathRule{},
+							},
+						}
+						urlPathMaps[listenerID] = pathMap
+					}
+					*pathMap.PathRules = append(*pathMap.PathRules, pathRule)
 				}
-				urlPathMaps[listenerID] = &pathMap
 			}
 		}
 	}
 
+	// HTTP listeners of servers with httpsRedirect redirect all requests to the HTTPS listener of their host.
+	for listenerID, listenerConfig := range istioListeners {
+		if !isIstioHTTPSRedirect(listenerConfig) {
+			continue
+		}
+		urlPathMaps[listenerID] = &n.ApplicationGatewayURLPathMap{
+			Etag: to.StringPtr("*"),
+			Name: to.StringPtr(generateURLPathMapName(listenerID)),
+			ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
+			ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+				DefaultRedirectConfiguration: resourceRef(c.appGwIdentifier.redirectConfigurationID(listenerConfig.SslRedirectConfigurationName)),
+			},
+		}
+	}
+
 	// if no url pathmaps were created, then add a default path map since this will be translated to
 	// a basic request routing rule which is needed on Application Gateway to avoid validation error.
 	if len(urlPathMaps) == 0 {
@@ -111,3 +119,18 @@ func (c *appGwConfigBuilder) getIstioPathMaps(cbCtx *ConfigBuilderContext) map[l
 
 	return urlPathMaps
 }
+
+// isIstioHTTPSRedirect tells whether the listener is an HTTP listener of a server with httpsRedirect.
+func isIstioHTTPSRedirect(listenerConfig listenerAzConfig) bool {
+	return listenerConfig.Protocol == n.HTTP && listenerConfig.SslRedirectConfigurationName != ""
+}
+
+// hasIstioHost tells whether the host is one of the hosts of the virtual service.
+func hasIstioHost(virtSvc *v1alpha3.VirtualService, host string) bool {
+	for _, virtSvcHost := range virtSvc.Spec.Hosts {
+		if virtSvcHost == host {
+			return true
+		}
+	}
+	return false
+}
@@ -8,22 +8,35 @@ package appgw
 import (
 	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
-func istioMatchDestinationIds(cbCtx *ConfigBuilderContext) ([]istioMatchIdentifier, map[istioDestinationIdentifier]interface{}) {
+// maxIstioRequestTimeout is the longest request timeout of App Gateway HTTP settings, in seconds.
+const maxIstioRequestTimeout = 86400
+
+// istioMatchDestinationIds returns the matches of the HTTP routes, and the destinations of the routes with the route, which sets
+// their HTTP settings. A destination of several routes of a virtual service uses the first route; an event is emitted on the
+// virtual service when a later route to it rewrites or times out differently.
+func (c *appGwConfigBuilder) istioMatchDestinationIds(cbCtx *ConfigBuilderContext) ([]istioMatchIdentifier, map[istioDestinationIdentifier]istioRoute) {
 	matchIDs := make([]istioMatchIdentifier, 0)
-	destinationIDs := make(map[istioDestinationIdentifier]interface{})
+	destinationIDs := make(map[istioDestinationIdentifier]istioRoute)
 	for _, virtualService := range cbCtx.IstioVirtualServices {
-		for _, rule := range virtualService.Spec.HTTP {
+		for ruleIdx := range virtualService.Spec.HTTP {
+			rule := &virtualService.Spec.HTTP[ruleIdx]
 			destinations := make([]*v1alpha3.Destination, 0)
-			for _, routeDestination := range rule.Route {
+			for destinationIdx := range rule.Route {
+				routeDestination := &rule.Route[destinationIdx]
 				if routeDestination.Weight != 0 {
 					destinations = append(destinations, &routeDestination.Destination)
 					/* TODO(rhea): Weights are being ignored for now, since this is not
@@ -31,126 +44,75 @@ func istioMatchDestinationIds(cbCtx *ConfigBuilderContext) ([]istioMatchIdentifi
 					this is supported */
 				}
 				destinationID := generateIstioDestinationID(virtualService, &routeDestination.Destination)
-				destinationIDs[destinationID] = nil
+				if existing, exists := destinationIDs[destinationID]; exists {
+					if !sameIstioHTTPSettings(existing.rule, rule) {
+						logLine := fmt.Sprintf("Routes of VirtualService %s/%s to %s use different rewrites or timeouts; using the first one", virtualService.Namespace, virtualService.Name, destinationID.DestinationHost)
+						glog.Warning(logLine)
+						c.recorder.Event(virtualService, v1.EventTypeWarning, events.ReasonInvalidIstioRoute, logLine)
+					}
+					continue
+				}
+				destinationIDs[destinationID] = istioRoute{virtualService: virtualService, rule: rule}
 			}
-			for _, match := range rule.Match {
+			for matchIdx := range rule.Match {
+				match := &rule.Match[matchIdx]
 				if match.URI == nil {
 					glog.V(5).Infof("Skipped match request, no URI field. Other forms of match requests are not supported.")
 					continue
 				}
-				matchID := generateIstioMatchID(virtualService, &rule, &match, destinations)
+				matchID := generateIstioMatchID(virtualService, rule, match, destinations)
 				matchIDs = append(matchIDs, matchID)
 			}
 		}
 	}
-	/* TODO(rhea): Filter out destinations for virtual services referencing non-existent Services */
 	return matchIDs, destinationIDs
 }
 
-func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBuilderContext) ([]n.ApplicationGatewayBackendHTTPSettings, map[istioDestinationIdentifier]*n.ApplicationGatewayBackendHTTPSettings, map[istioDestinationIdentifier]serviceBackendPortPair, error) {
-	serviceBackendPairsMap := make(map[istioDestinationIdentifier]map[serviceBackendPortPair]interface{})
+// getIstioDestinationsAndSettingsMap creates the HTTP settings of the destinations of the virtual services once per config build.
+// Destinations, which cannot be resolved to a service port, are skipped and an event is emitted on their virtual service.
+func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBuilderContext) ([]n.ApplicationGatewayBackendHTTPSettings, map[istioDestinationIdentifier]*n.ApplicationGatewayBackendHTTPSettings, map[istioDestinationIdentifier]serviceBackendPortPair) {
+	if c.mem.istioSettings != nil && c.mem.istioSettingsByDestination != nil && c.mem.istioPairsByDestination != nil {
+		return *c.mem.istioSettings, *c.mem.istioSettingsByDestination, *c.mem.istioPairsByDestination
+	}
+
 	backendHTTPSettingsMap := make(map[istioDestinationIdentifier]*n.ApplicationGatewayBackendHTTPSettings)
 	finalServiceBackendPairMap := make(map[istioDestinationIdentifier]serviceBackendPortPair)
+	httpSettingsCollection := make(map[string]n.ApplicationGatewayBackendHTTPSettings)
 
-	var unresolvedDestinationID []istioDestinationIdentifier
-	_, destinationIDs := istioMatchDestinationIds(cbCtx)
-	for destinationID := range destinationIDs {
-		resolvedBackendPorts := make(map[serviceBackendPortPair]interface{})
-
+	_, destinationIDs := c.istioMatchDestinationIds(cbCtx)
+	for destinationID, route := range destinationIDs {
 		service := c.k8sContext.GetService(destinationID.serviceKey())
-		destinationPortNum := Port(destinationID.DestinationPort)
 		if service == nil {
-			// Once services are filtered in the istioMatchDestinationIDs function, this should never happen
 			logLine := fmt.Sprintf("Unable to get the service [%s]", destinationID.serviceKey())
-			glog.Errorf(logLine)
-			// TODO(rhea): add error event
-			pair := serviceBackendPortPair{
-				ServicePort: Port(destinationPortNum),
-				BackendPort: Port(destinationPortNum),
-			}
-			resolvedBackendPorts[pair] = nil
-		} else {
-			for _, sp := range service.Spec.Ports {
-				// find the backend port number
-				// check if any service ports matches the specified ports
-				if sp.Protocol != v1.ProtocolTCP {
-					// ignore UDP ports
-					continue
-				}
+			glog.Error(logLine)
+			c.recorder.Event(route.virtualService, v1.EventTypeWarning, events.ReasonServiceNotFound, logLine)
+			continue
+		}
 
-				// TODO(delqn): implement correctly port lookup by name
-				if Port(sp.Port) == destinationPortNum || sp.TargetPort.String() == fmt.Sprint(destinationPortNum) {
-					// matched a service port with a port from the service
-					if sp.TargetPort.String() == "" {
-						// targetPort is not defined, by default targetPort == port
-						pair := serviceBackendPortPair{
-							ServicePort: Port(sp.Port),
-							BackendPort: Port(sp.Port),
-						}
-						resolvedBackendPorts[pair] = nil
-					} else {
-						// target port is defined as name or port number
-						if sp.TargetPort.Type == intstr.Int {
-							// port is defined as port number
-							pair := serviceBackendPortPair{
-								ServicePort: Port(sp.Port),
-								BackendPort: Port(sp.TargetPort.IntVal),
-							}
-							resolvedBackendPorts[pair] = nil
-						} else {
-							// if service port is defined by name, need to resolve
-							targetPortName := sp.TargetPort.StrVal
-							glog.V(1).Infof("resolving port name %s", targetPortName)
-							targetPortsResolved := c.resolveIstioPortName(targetPortName, &destinationID)
-							for targetPort := range targetPortsResolved {
-								pair := serviceBackendPortPair{
-									ServicePort: Port(sp.Port),
-									BackendPort: Port(targetPort),
-								}
-								resolvedBackendPorts[pair] = nil
-							}
-						}
-					}
-					break
-				}
+		if destinationID.DestinationSubset != "" {
+			if _, err := c.getIstioSubsetLabels(destinationID); err != nil {
+				logLine := fmt.Sprintf("Unable to use the subset %s of service [%s]: %s", destinationID.DestinationSubset, destinationID.serviceKey(), err)
+				glog.Error(logLine)
+				c.recorder.Event(route.virtualService, v1.EventTypeWarning, events.ReasonIstioSubsetNotFound, logLine)
+				continue
 			}
 		}
-		if len(resolvedBackendPorts) == 0 {
+
+		serviceBackendPairs := c.resolveIstioServicePorts(destinationID, service)
+		if len(serviceBackendPairs) == 0 {
 			logLine := fmt.Sprintf("Unable to resolve any backend port for service [%s]", destinationID.serviceKey())
 			glog.Error(logLine)
-			//TODO(rhea): Add error event
-
-			unresolvedDestinationID = append(unresolvedDestinationID, destinationID)
-			break
-		}
-
-		// Merge serviceBackendPairsMap[backendID] into resolvedBackendPorts
-		if _, ok := serviceBackendPairsMap[destinationID]; !ok {
-			serviceBackendPairsMap[destinationID] = make(map[serviceBackendPortPair]interface{})
-		}
-		for portPair := range resolvedBackendPorts {
-			serviceBackendPairsMap[destinationID][portPair] = nil
+			c.recorder.Event(route.virtualService, v1.EventTypeWarning, events.ReasonPortResolutionError, logLine)
+			continue
 		}
-	}
-	if len(unresolvedDestinationID) > 0 {
-		return nil, nil, nil, errors.New("unable to resolve backend port for some services")
-	}
 
-	httpSettingsCollection := make(map[string]n.ApplicationGatewayBackendHTTPSettings)
-	for destinationID, serviceBackendPairs := range serviceBackendPairsMap {
 		if len(serviceBackendPairs) > 1 {
-			// more than one possible backend port exposed through ingress
-			backendServicePort := ""
-			if destinationID.DestinationPort != 0 {
-				backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-			} else {
-				// TODO(delqn): implement port lookup by name
-			}
+			// more than one possible backend port exposed through the virtual service
 			logLine := fmt.Sprintf("service:port [%s:%s] has more than one service-backend port binding",
-				destinationID.serviceKey(), backendServicePort)
+				destinationID.serviceKey(), destinationID.servicePort())
 			glog.Warning(logLine)
-			//TODO(rhea): add error event recorder
-			return nil, nil, nil, errors.New("more than one service-backend port binding is not allowed")
+			c.recorder.Event(route.virtualService, v1.EventTypeWarning, events.ReasonPortResolutionError, logLine)
+			continue
 		}
 
 		// At this point there will be only one pair
@@ -160,7 +122,7 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 		}
 
 		finalServiceBackendPairMap[destinationID] = uniquePair
-		httpSettings := c.generateIstioHTTPSettings(destinationID, uniquePair.BackendPort, cbCtx)
+		httpSettings := c.generateIstioHTTPSettings(destinationID, uniquePair.BackendPort, route)
 		httpSettingsCollection[*httpSettings.Name] = httpSettings
 		backendHTTPSettingsMap[destinationID] = &httpSettings
 	}
@@ -170,17 +132,75 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 		httpSettings = append(httpSettings, backend)
 	}
 
-	return httpSettings, backendHTTPSettingsMap, finalServiceBackendPairMap, nil
+	c.mem.istioSettings = &httpSettings
+	c.mem.istioSettingsByDestination = &backendHTTPSettingsMap
+	c.mem.istioPairsByDestination = &finalServiceBackendPairMap
+	return httpSettings, backendHTTPSettingsMap, finalServiceBackendPairMap
 }
 
-func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestinationIdentifier, port Port, cbCtx *ConfigBuilderContext) n.ApplicationGatewayBackendHTTPSettings {
-	backendServicePort := ""
-	if destinationID.DestinationPort != 0 {
-		backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-	} else {
-		// TODO(delqn): Implement port lookup by name
+// resolveIstioServicePorts returns the service and backend ports of the service port the destination selects by number or by name;
+// a destination without a port selects the port of a service with a single port.
+func (c *appGwConfigBuilder) resolveIstioServicePorts(destinationID istioDestinationIdentifier, service *v1.Service) map[serviceBackendPortPair]interface{} {
+	resolvedBackendPorts := make(map[serviceBackendPortPair]interface{})
+	destinationPortNum := Port(destinationID.DestinationPort)
+	for _, sp := range service.Spec.Ports {
+		// find the backend port number
+		// check if any service ports matches the specified ports
+		if sp.Protocol != v1.ProtocolTCP {
+			// ignore UDP ports
+			continue
+		}
+
+		var matched bool
+		switch {
+		case destinationPortNum != 0:
+			matched = Port(sp.Port) == destinationPortNum || sp.TargetPort.String() == fmt.Sprint(destinationPortNum)
+		case destinationID.DestinationPortName != "":
+			matched = sp.Name == destinationID.DestinationPortName
+		default:
+			matched = len(service.Spec.Ports) == 1
+		}
+		if !matched {
+			continue
+		}
+
+		// matched a service port with a port from the service
+		if sp.TargetPort.String() == "" {
+			// targetPort is not defined, by default targetPort == port
+			pair := serviceBackendPortPair{
+				ServicePort: Port(sp.Port),
+				BackendPort: Port(sp.Port),
+			}
+			resolvedBackendPorts[pair] = nil
+		} else if sp.TargetPort.Type == intstr.Int {
+			// port is defined as port number
+			pair := serviceBackendPortPair{
+				ServicePort: Port(sp.Port),
+				BackendPort: Port(sp.TargetPort.IntVal),
+			}
+			resolvedBackendPorts[pair] = nil
+		} else {
+			// if service port is defined by name, need to resolve
+			targetPortName := sp.TargetPort.StrVal
+			glog.V(1).Infof("resolving port name %s", targetPortName)
+			targetPortsResolved := c.resolveIstioPortName(targetPortName, &destinationID)
+			for targetPort := range targetPortsResolved {
+				pair := serviceBackendPortPair{
+					ServicePort: Port(sp.Port),
+					BackendPort: Port(targetPort),
+				}
+				resolvedBackendPorts[pair] = nil
+			}
+		}
+		break
 	}
-	httpSettingsName := generateHTTPSettingsName(destinationID.serviceFullName(), backendServicePort, port, destinationID.istioVirtualServiceIdentifier.Name)
+	return resolvedBackendPorts
+}
+
+// generateIstioHTTPSettings creates the HTTP settings of a destination; the URI rewrite of the route overrides the backend path,
+// and the timeout of the route is the request timeout.
+func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestinationIdentifier, port Port, route istioRoute) n.ApplicationGatewayBackendHTTPSettings {
+	httpSettingsName := generateHTTPSettingsName(destinationID.destinationFullName(), destinationID.servicePort(), port, destinationID.istioVirtualServiceIdentifier.Name)
 	glog.V(5).Infof("Created a new HTTP setting w/ name: %s\n", httpSettingsName)
 	httpSettings := n.ApplicationGatewayBackendHTTPSettings{
 		Etag: to.StringPtr("*"),
@@ -192,7 +212,37 @@ func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestin
 		},
 	}
 
-	//TODO(rhea): check relevant annotations and modify http settings accordingly
+	if route.rule.Rewrite != nil && route.rule.Rewrite.URI != "" {
+		httpSettings.Path = to.StringPtr(route.rule.Rewrite.URI)
+	}
+
+	if route.rule.Timeout != "" {
+		if timeout, err := parseIstioTimeout(route.rule.Timeout); err == nil {
+			httpSettings.RequestTimeout = to.Int32Ptr(timeout)
+		} else {
+			logLine := fmt.Sprintf("VirtualService %s/%s has an invalid timeout %q: %s", route.virtualService.Namespace, route.virtualService.Name, route.rule.Timeout, err)
+			glog.Error(logLine)
+			c.recorder.Event(route.virtualService, v1.EventTypeWarning, events.ReasonInvalidIstioRoute, logLine)
+		}
+	}
 
 	return httpSettings
 }
+
+// parseIstioTimeout converts the duration of an Istio timeout, like 1.5s, to the seconds App Gateway waits for a response; rounded up.
+func parseIstioTimeout(timeout string) (int32, error) {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, err
+	}
+	seconds := math.Ceil(duration.Seconds())
+	if seconds < 1 || seconds > maxIstioRequestTimeout {
+		return 0, errors.New("the timeout must be between 1 second and 24 hours")
+	}
+	return int32(seconds), nil
+}
+
+// sameIstioHTTPSettings tells whether both routes rewrite and time out the same way.
+func sameIstioHTTPSettings(route, other *v1alpha3.HTTPRoute) bool {
+	return reflect.DeepEqual(route.Rewrite, other.Rewrite) && route.Timeout == other.Timeout
+}
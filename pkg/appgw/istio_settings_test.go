@@ -0,0 +1,140 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test the HTTP settings and pools of Istio destinations", func() {
+	var configBuilder appGwConfigBuilder
+	var virtualService *v1alpha3.VirtualService
+	var cbCtx *ConfigBuilderContext
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+
+		endpoints := tests.NewEndpointsFixture()
+		endpoints.Subsets[0].Addresses = []v1.EndpointAddress{
+			{IP: "10.0.0.1", TargetRef: &v1.ObjectReference{Kind: "Pod", Namespace: tests.Namespace, Name: "web-v1"}},
+			{IP: "10.0.0.2", TargetRef: &v1.ObjectReference{Kind: "Pod", Namespace: tests.Namespace, Name: "web-v2"}},
+		}
+		podV1 := tests.NewPodFixture("web-v1", tests.Namespace, tests.ContainerName, tests.ContainerPort)
+		podV1.Labels["version"] = "v1"
+		podV2 := tests.NewPodFixture("web-v2", tests.Namespace, tests.ContainerName, tests.ContainerPort)
+		podV2.Labels["version"] = "v2"
+		destinationRule := &v1alpha3.DestinationRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: tests.Namespace},
+			Spec: v1alpha3.DestinationRuleSpec{
+				Host: tests.ServiceName,
+				Subsets: []v1alpha3.Subset{
+					{Name: "v1", Labels: map[string]string{"version": "v1"}},
+					{Name: "v2", Labels: map[string]string{"version": "v2"}},
+				},
+			},
+		}
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoints)
+		_ = configBuilder.k8sContext.Caches.Service.Add(tests.NewServiceFixture(*tests.NewServicePortsFixture()...))
+		_ = configBuilder.k8sContext.Caches.Pods.Add(podV1)
+		_ = configBuilder.k8sContext.Caches.Pods.Add(podV2)
+		_ = configBuilder.k8sContext.Caches.IstioDestinationRule.Add(destinationRule)
+
+		virtualService = &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: tests.Namespace},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts: []string{tests.Host},
+				HTTP: []v1alpha3.HTTPRoute{
+					{
+						Match: []v1alpha3.HTTPMatchRequest{{URI: &v1alpha1.StringMatch{Prefix: "/api/"}}},
+						Route: []v1alpha3.HTTPRouteDestination{
+							{
+								Destination: v1alpha3.Destination{Host: tests.ServiceName, Port: v1alpha3.PortSelector{Number: 80}},
+								Weight:      100,
+							},
+						},
+					},
+				},
+			},
+		}
+
+		env := environment.GetFakeEnv()
+		env.EnableIstioIntegration = true
+		cbCtx = &ConfigBuilderContext{
+			IstioVirtualServices: []*v1alpha3.VirtualService{virtualService},
+			EnvVariables:         env,
+		}
+	})
+
+	destination := func() istioDestinationIdentifier {
+		return generateIstioDestinationID(virtualService, &virtualService.Spec.HTTP[0].Route[0].Destination)
+	}
+
+	settings := func() *n.ApplicationGatewayBackendHTTPSettings {
+		_, settingsByDestination, _ := configBuilder.getIstioDestinationsAndSettingsMap(cbCtx)
+		return settingsByDestination[destination()]
+	}
+
+	It("sets the path and the request timeout from the rewrite and the timeout of the route", func() {
+		virtualService.Spec.HTTP[0].Rewrite = &v1alpha3.HTTPRewrite{URI: "/v2/api/"}
+		virtualService.Spec.HTTP[0].Timeout = "1.5s"
+
+		httpSettings := settings()
+		Expect(httpSettings).ToNot(BeNil())
+		Expect(httpSettings.Path).To(Equal(to.StringPtr("/v2/api/")))
+		Expect(httpSettings.RequestTimeout).To(Equal(to.Int32Ptr(2)))
+		Expect(*httpSettings.Port).To(Equal(tests.ContainerPort))
+	})
+
+	It("resolves a named destination port", func() {
+		virtualService.Spec.HTTP[0].Route[0].Destination.Port = v1alpha3.PortSelector{Name: tests.ServiceHTTPPort}
+
+		httpSettings := settings()
+		Expect(httpSettings).ToNot(BeNil())
+		Expect(*httpSettings.Port).To(Equal(tests.ContainerPort))
+		Expect(httpSettings.RequestTimeout).To(BeNil())
+	})
+
+	It("emits an event for an invalid timeout", func() {
+		virtualService.Spec.HTTP[0].Timeout = "48h"
+
+		httpSettings := settings()
+		Expect(httpSettings).ToNot(BeNil())
+		Expect(httpSettings.RequestTimeout).To(BeNil())
+		recorder := configBuilder.recorder.(*record.FakeRecorder)
+		Expect(len(recorder.Events)).To(Equal(1))
+		Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidIstioRoute))
+	})
+
+	It("creates a pool with the pods of the subset", func() {
+		virtualService.Spec.HTTP[0].Route[0].Destination.Subset = "v2"
+
+		pool := configBuilder.newIstioBackendPoolMap(cbCtx)[destination()]
+		Expect(pool).ToNot(BeNil())
+		Expect(*pool.Name).To(ContainSubstring("-v2-"))
+		Expect(*pool.BackendAddresses).To(Equal([]n.ApplicationGatewayBackendAddress{{IPAddress: to.StringPtr("10.0.0.2")}}))
+	})
+
+	It("emits an event when the subset is not defined", func() {
+		virtualService.Spec.HTTP[0].Route[0].Destination.Subset = "v3"
+
+		Expect(settings()).To(BeNil())
+		recorder := configBuilder.recorder.(*record.FakeRecorder)
+		Expect(len(recorder.Events)).To(Equal(1))
+		Expect(<-recorder.Events).To(ContainSubstring(events.ReasonIstioSubsetNotFound))
+	})
+})
@@ -5,7 +5,11 @@
 
 package appgw
 
-import "github.com/knative/pkg/apis/istio/v1alpha3"
+import (
+	"fmt"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+)
 
 type istioMatchIdentifier struct {
 	Namespace      string
@@ -25,7 +29,30 @@ type istioDestinationIdentifier struct {
 	serviceIdentifier
 	istioVirtualServiceIdentifier
 
-	DestinationHost   string
-	DestinationSubset string
-	DestinationPort   uint32
+	DestinationHost     string
+	DestinationSubset   string
+	DestinationPort     uint32
+	DestinationPortName string
+}
+
+// istioRoute is the HTTP route of a virtual service, which sets the HTTP settings of its destinations.
+type istioRoute struct {
+	virtualService *v1alpha3.VirtualService
+	rule           *v1alpha3.HTTPRoute
+}
+
+// destinationFullName names the service of the destination, and its subset when it has one.
+func (d istioDestinationIdentifier) destinationFullName() string {
+	if d.DestinationSubset == "" {
+		return d.serviceFullName()
+	}
+	return fmt.Sprintf("%s-%s", d.serviceFullName(), d.DestinationSubset)
+}
+
+// servicePort returns the port number of the destination, or else its port name; empty when the destination has no port.
+func (d istioDestinationIdentifier) servicePort() string {
+	if d.DestinationPort != 0 {
+		return fmt.Sprint(d.DestinationPort)
+	}
+	return d.DestinationPortName
 }
@@ -6,17 +6,18 @@
 package appgw
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"strings"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	testclient "k8s.io/client-go/kubernetes/fake"
@@ -24,9 +25,11 @@ import (
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
@@ -57,7 +60,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 		},
 	}
 
-	ingressPublicIP := &v1beta1.Ingress{
+	ingressPublicIP := &networking.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
 				annotations.IngressClassKey: annotations.ApplicationGatewayIngressClass,
@@ -65,19 +68,20 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			Namespace: ingressNS,
 			Name:      "external-ingress-resource",
 		},
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
+		Spec: networking.IngressSpec{
+			Rules: []networking.IngressRule{
 				{
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
+					IngressRuleValue: networking.IngressRuleValue{
+						HTTP: &networking.HTTPIngressRuleValue{
+							Paths: []networking.HTTPIngressPath{
 								{
 									Path: "/*",
-									Backend: v1beta1.IngressBackend{
-										ServiceName: tests.ServiceName,
-										ServicePort: intstr.IntOrString{
-											Type:   intstr.Int,
-											IntVal: 443,
+									Backend: networking.IngressBackend{
+										Service: &networking.IngressServiceBackend{
+											Name: tests.ServiceName,
+											Port: networking.ServiceBackendPort{
+												Number: 443,
+											},
 										},
 									},
 								},
@@ -86,7 +90,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 					},
 				},
 			},
-			TLS: []v1beta1.IngressTLS{
+			TLS: []networking.IngressTLS{
 				{
 					Hosts: []string{
 						"pub.lic",
@@ -106,7 +110,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	}
 
 	// Create the Ingress resource.
-	ingressPrivateIP := &v1beta1.Ingress{
+	ingressPrivateIP := &networking.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
 				annotations.IngressClassKey: annotations.ApplicationGatewayIngressClass,
@@ -115,19 +119,20 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			Namespace: ingressNS,
 			Name:      "internal-ingress-resource",
 		},
-		Spec: v1beta1.IngressSpec{
-			Rules: []v1beta1.IngressRule{
+		Spec: networking.IngressSpec{
+			Rules: []networking.IngressRule{
 				{
-					IngressRuleValue: v1beta1.IngressRuleValue{
-						HTTP: &v1beta1.HTTPIngressRuleValue{
-							Paths: []v1beta1.HTTPIngressPath{
+					IngressRuleValue: networking.IngressRuleValue{
+						HTTP: &networking.HTTPIngressRuleValue{
+							Paths: []networking.HTTPIngressPath{
 								{
 									Path: "/*",
-									Backend: v1beta1.IngressBackend{
-										ServiceName: tests.ServiceName,
-										ServicePort: intstr.IntOrString{
-											Type:   intstr.Int,
-											IntVal: 80,
+									Backend: networking.IngressBackend{
+										Service: &networking.IngressServiceBackend{
+											Name: tests.ServiceName,
+											Port: networking.ServiceBackendPort{
+												Number: 80,
+											},
 										},
 									},
 								},
@@ -215,15 +220,17 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 	// Create the mock K8s client.
 	k8sClient := testclient.NewSimpleClientset()
+	tests.AddNetworkingV1Resources(k8sClient)
 
 	It("should have not failed", func() {
-		_, err := k8sClient.CoreV1().Namespaces().Create(nameSpace)
+		_, err := k8sClient.CoreV1().Namespaces().Create(context.TODO(), nameSpace, metav1.CreateOptions{})
 		Expect(err).ToNot(HaveOccurred())
 	})
 
 	crdClient := fake.NewSimpleClientset()
 	istioCrdClient := istio_fake.NewSimpleClientset()
-	ctxt := k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, []string{ingressNS}, 1000*time.Second)
+	gatewayCrdClient := gateway_fake.NewSimpleClientset()
+	ctxt := k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, 1000*time.Second, metricstore.NewFakeMetricStore())
 
 	secret := tests.NewSecretTestFixture()
 
@@ -241,31 +248,31 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 	ctxtSecret := ctxt.GetSecret(secKey)
 	It("should have found the secret", func() { Expect(ctxtSecret).To(Equal(secret)) })
 
-	_, err = k8sClient.CoreV1().Nodes().Create(node)
+	_, err = k8sClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Create(ingressPublicIP)
+	_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Create(context.TODO(), ingressPublicIP, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Update(ingressPublicIP)
+	_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Update(context.TODO(), ingressPublicIP, metav1.UpdateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred(), "Unable to update ingress resource due to: %v", err) })
 
-	_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Create(ingressPrivateIP)
+	_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Create(context.TODO(), ingressPrivateIP, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Update(ingressPrivateIP)
+	_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Update(context.TODO(), ingressPrivateIP, metav1.UpdateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred(), "Unable to update ingress resource due to: %v", err) })
 
-	_, err = k8sClient.CoreV1().Services(ingressNS).Create(service)
+	_, err = k8sClient.CoreV1().Services(ingressNS).Create(context.TODO(), service, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.CoreV1().Endpoints(ingressNS).Create(endpoints)
+	_, err = k8sClient.CoreV1().Endpoints(ingressNS).Create(context.TODO(), endpoints, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.CoreV1().Pods(ingressNS).Create(pod1)
+	_, err = k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod1, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
-	_, err = k8sClient.CoreV1().Pods(ingressNS).Create(pod2)
+	_, err = k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod2, metav1.CreateOptions{})
 	It("should have not failed", func() { Expect(err).ToNot(HaveOccurred()) })
 
 	appGwy := &n.ApplicationGateway{
@@ -277,7 +284,7 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 	Context("Tests Application Gateway config creation", func() {
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{
+			IngressList: []*networking.Ingress{
 				ingressPrivateIP,
 				ingressPublicIP,
 			},
@@ -301,6 +308,9 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			c := (b[0]).(map[string]interface{})
 			d := (c["properties"]).(map[string]interface{})
 			d["data"] = "hhh"
+			// The password is random; it must be the one protecting the PFX.
+			Expect(d["password"]).To(Equal(pfx.Password))
+			d["password"] = "--password--"
 
 			jsonBlob, err = json.MarshalIndent(into, "--", "    ")
 			Expect(err).ToNot(HaveOccurred())
@@ -320,7 +330,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        ],
 --        "backendHttpSettingsCollection": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/backendHttpSettingsCollection/bp---namespace-----service-name---443-443-external-ingress-resource",
 --                "name": "bp---namespace-----service-name---443-443-external-ingress-resource",
 --                "properties": {
@@ -332,7 +341,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --                }
 --            },
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/backendHttpSettingsCollection/bp---namespace-----service-name---80-80-internal-ingress-resource",
 --                "name": "bp---namespace-----service-name---80-80-internal-ingress-resource",
 --                "properties": {
@@ -357,29 +365,24 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        ],
 --        "frontendIPConfigurations": [
 --            {
---                "etag": "xx2",
 --                "id": "--front-end-ip-id-1--",
 --                "name": "xx3",
 --                "properties": {
 --                    "publicIPAddress": {
 --                        "id": "xyz"
 --                    }
---                },
---                "type": "xx1"
+--                }
 --            },
 --            {
---                "etag": "yy2",
 --                "id": "--front-end-ip-id-2--",
 --                "name": "yy3",
 --                "properties": {
 --                    "privateIPAddress": "abc"
---                },
---                "type": "yy1"
+--                }
 --            }
 --        ],
 --        "frontendPorts": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/frontEndPorts/fp-443",
 --                "name": "fp-443",
 --                "properties": {
@@ -387,7 +390,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --                }
 --            },
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/frontEndPorts/fp-80",
 --                "name": "fp-80",
 --                "properties": {
@@ -397,7 +399,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        ],
 --        "httpListeners": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/httpListeners/fl-443",
 --                "name": "fl-443",
 --                "properties": {
@@ -415,7 +416,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --                }
 --            },
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/httpListeners/fl-80-privateip",
 --                "name": "fl-80-privateip",
 --                "properties": {
@@ -459,7 +459,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        "redirectConfigurations": null,
 --        "requestRoutingRules": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/requestRoutingRules/rr-443",
 --                "name": "rr-443",
 --                "properties": {
@@ -476,7 +475,6 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --                }
 --            },
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/requestRoutingRules/rr-80",
 --                "name": "rr-80",
 --                "properties": {
@@ -500,12 +498,11 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 --        },
 --        "sslCertificates": [
 --            {
---                "etag": "*",
 --                "id": "/subscriptions/--subscription--/resourceGroups/--resource-group--/providers/Microsoft.Network/applicationGateways/--app-gw-name--/sslCertificates/--namespace-----the-name-of-the-secret--",
 --                "name": "--namespace-----the-name-of-the-secret--",
 --                "properties": {
 --                    "data": "hhh",
---                    "password": "msazure"
+--                    "password": "--password--"
 --                }
 --            }
 --        ],
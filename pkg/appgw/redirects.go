@@ -8,14 +8,28 @@ package appgw
 import (
 	"sort"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
+// redirectTypes maps the status codes of the redirect-type annotation to App Gateway redirect types.
+var redirectTypes = map[int32]n.ApplicationGatewayRedirectType{
+	301: n.Permanent,
+	302: n.Found,
+	303: n.SeeOther,
+	307: n.Temporary,
+}
+
 // getRedirectConfigurations creates App Gateway redirect configuration based on Ingress annotations.
 func (c *appGwConfigBuilder) getRedirectConfigurations(cbCtx *ConfigBuilderContext) *[]n.ApplicationGatewayRedirectConfiguration {
 	if c.mem.redirectConfigs != nil {
@@ -38,8 +52,25 @@ func (c *appGwConfigBuilder) getRedirectConfigurations(cbCtx *ConfigBuilderConte
 		}
 	}
 
+	// HTTP servers of the Istio Gateways with httpsRedirect redirect to the HTTPS listener of their host
+	if cbCtx.EnvVariables.EnableIstioIntegration {
+		for listenerID, listenerConfig := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
+			if listenerConfig.Protocol == n.HTTPS && listenerConfig.SslRedirectConfigurationName != "" {
+				targetListener := resourceRef(c.appGwIdentifier.listenerID(generateListenerName(listenerID)))
+				redirectConfigs = append(redirectConfigs, c.newSSLRedirectConfig(listenerConfig, targetListener))
+			}
+		}
+	}
+
+	// Ingresses with a redirect-target-url annotation redirect all of their requests to that URL
+	for _, ingress := range cbCtx.IngressList {
+		if redirect := c.getURLRedirectConfig(ingress); redirect != nil {
+			redirectConfigs = append(redirectConfigs, *redirect)
+		}
+	}
+
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil, cbCtx.FrontendIPAddresses)
 
 		// Listeners we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedRedirects()
@@ -81,6 +112,67 @@ func (c *appGwConfigBuilder) newSSLRedirectConfig(listenerConfig listenerAzConfi
 	}
 }
 
+// getURLRedirectConfig creates the redirect of the ingress to the URL of its redirect-target-url annotation once per config build;
+// nil when the ingress has no valid target URL. Invalid annotations emit an event on the ingress.
+func (c *appGwConfigBuilder) getURLRedirectConfig(ingress *networking.Ingress) *n.ApplicationGatewayRedirectConfiguration {
+	if c.mem.urlRedirectConfigs == nil {
+		redirects := make(map[string]*n.ApplicationGatewayRedirectConfiguration)
+		c.mem.urlRedirectConfigs = &redirects
+	}
+	ingressKey := utils.GetResourceKey(ingress.Namespace, ingress.Name)
+	if redirect, exists := (*c.mem.urlRedirectConfigs)[ingressKey]; exists {
+		return redirect
+	}
+
+	redirect := c.newURLRedirectConfig(ingress)
+	(*c.mem.urlRedirectConfigs)[ingressKey] = redirect
+	return redirect
+}
+
+func (c *appGwConfigBuilder) newURLRedirectConfig(ingress *networking.Ingress) *n.ApplicationGatewayRedirectConfiguration {
+	targetURL, err := annotations.RedirectTargetURL(ingress)
+	if err != nil {
+		if !errors.IsMissingAnnotations(err) {
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+		}
+		return nil
+	}
+
+	redirectType := n.Permanent
+	if statusCode, err := annotations.RedirectType(ingress); err == nil {
+		redirectType = redirectTypes[statusCode]
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
+	includePath := true
+	if include, err := annotations.IsRedirectIncludePath(ingress); err == nil {
+		includePath = include
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
+	includeQueryString := true
+	if include, err := annotations.IsRedirectIncludeQueryString(ingress); err == nil {
+		includeQueryString = include
+	} else if !errors.IsMissingAnnotations(err) {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+	}
+
+	configName := generateURLRedirectConfigurationName(ingress.Namespace, ingress.Name)
+	return &n.ApplicationGatewayRedirectConfiguration{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(configName),
+		ID:   to.StringPtr(c.appGwIdentifier.redirectConfigurationID(configName)),
+		ApplicationGatewayRedirectConfigurationPropertiesFormat: &n.ApplicationGatewayRedirectConfigurationPropertiesFormat{
+			RedirectType:       redirectType,
+			TargetURL:          to.StringPtr(targetURL),
+			IncludePath:        to.BoolPtr(includePath),
+			IncludeQueryString: to.BoolPtr(includeQueryString),
+		},
+	}
+}
+
 func (c *appGwConfigBuilder) groupRedirectsByID(redirects *[]n.ApplicationGatewayRedirectConfiguration) *map[string]interface{} {
 	redirectsSet := make(map[string]interface{})
 	for _, redirect := range *redirects {
@@ -89,6 +181,19 @@ func (c *appGwConfigBuilder) groupRedirectsByID(redirects *[]n.ApplicationGatewa
 	return &redirectsSet
 }
 
+// sslRedirectTargetListener returns the HTTPS listener, which the HTTP listener of the ingress redirects to.
+func (c *appGwConfigBuilder) sslRedirectTargetListener(listenerID listenerIdentifier, ingress *networking.Ingress) listenerIdentifier {
+	targetListener := listenerIdentifier{
+		HostName:     listenerID.HostName,
+		FrontendPort: 443,
+		UsePrivateIP: listenerID.UsePrivateIP,
+	}
+	if port := c.getFrontendPortOverrides(ingress).listener; port != nil {
+		targetListener.FrontendPort = *port
+	}
+	return targetListener
+}
+
 func (c *appGwConfigBuilder) getSslRedirectConfigResourceReference(targetListener listenerIdentifier) *n.SubResource {
 	configName := generateSSLRedirectConfigurationName(targetListener)
 	sslRedirectConfigID := c.appGwIdentifier.redirectConfigurationID(configName)
@@ -8,13 +8,17 @@ package appgw
 import (
 	"fmt"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/api/extensions/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
@@ -46,7 +50,7 @@ var _ = Describe("Test SSL Redirect Annotations", func() {
 	Context("Test RequestRoutingRules with TLS and with SSL Redirect Annotation", func() {
 		cb := newConfigBuilderFixture(nil)
 		ingress := tests.NewIngressFixture()
-		ingressList := []*v1beta1.Ingress{ingress}
+		ingressList := []*networking.Ingress{ingress}
 		cbCtx := ConfigBuilderContext{
 			IngressList: ingressList,
 		}
@@ -96,7 +100,7 @@ var _ = Describe("Test SSL Redirect Annotations", func() {
 		cb := newConfigBuilderFixture(nil)
 		ingress := tests.NewIngressFixture()
 		ingress.Spec.TLS = nil
-		ingressList := []*v1beta1.Ingress{ingress}
+		ingressList := []*networking.Ingress{ingress}
 		cbCtx := ConfigBuilderContext{
 			IngressList: ingressList,
 		}
@@ -122,7 +126,7 @@ var _ = Describe("Test SSL Redirect Annotations", func() {
 		cb := newConfigBuilderFixture(nil)
 		ingress := tests.NewIngressFixture()
 		delete(ingress.Annotations, annotations.SslRedirectKey)
-		ingressList := []*v1beta1.Ingress{ingress}
+		ingressList := []*networking.Ingress{ingress}
 		cbCtx := ConfigBuilderContext{
 			IngressList: ingressList,
 		}
@@ -152,3 +156,148 @@ var _ = Describe("Test SSL Redirect Annotations", func() {
 		})
 	})
 })
+
+var _ = Describe("Test redirects to a target URL", func() {
+	var configBuilder appGwConfigBuilder
+	var ingress *networking.Ingress
+	var cbCtx *ConfigBuilderContext
+
+	redirectName := "rd-" + tests.Namespace + "-" + tests.Name
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+		endpoint := tests.NewEndpointsFixture()
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+		ingress = tests.NewIngressFixture()
+		ingress.Annotations[annotations.RedirectTargetURLKey] = "https://www.contoso.com"
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoint)
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+
+		cbCtx = &ConfigBuilderContext{
+			IngressList:  []*networking.Ingress{ingress},
+			ServiceList:  []*v1.Service{service},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+	})
+
+	build := func() {
+		Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.BackendAddressPools(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.Listeners(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.RequestRoutingRules(cbCtx)).ToNot(HaveOccurred())
+	}
+
+	// urlRedirect returns the redirect configuration of the ingress to its target URL.
+	urlRedirect := func() *n.ApplicationGatewayRedirectConfiguration {
+		for _, redirect := range *configBuilder.appGw.RedirectConfigurations {
+			if *redirect.Name == redirectName {
+				return &redirect
+			}
+		}
+		return nil
+	}
+
+	Context("with a redirect-target-url annotation", func() {
+		It("redirects all paths of the ingress permanently, including path and query string", func() {
+			build()
+
+			redirectID := configBuilder.appGwIdentifier.redirectConfigurationID(redirectName)
+			Expect(urlRedirect()).To(Equal(&n.ApplicationGatewayRedirectConfiguration{
+				Etag: to.StringPtr("*"),
+				Name: to.StringPtr(redirectName),
+				ID:   to.StringPtr(redirectID),
+				ApplicationGatewayRedirectConfigurationPropertiesFormat: &n.ApplicationGatewayRedirectConfigurationPropertiesFormat{
+					RedirectType:       n.Permanent,
+					TargetURL:          to.StringPtr("https://www.contoso.com"),
+					IncludePath:        to.BoolPtr(true),
+					IncludeQueryString: to.BoolPtr(true),
+				},
+			}))
+
+			// The redirect to the target URL takes precedence over the SSL redirect on the HTTP listener
+			Expect(*configBuilder.appGw.URLPathMaps).ToNot(BeEmpty())
+			for _, pathMap := range *configBuilder.appGw.URLPathMaps {
+				Expect(pathMap.DefaultRedirectConfiguration).To(Equal(resourceRef(redirectID)))
+				Expect(pathMap.DefaultBackendAddressPool).To(BeNil())
+				for _, pathRule := range *pathMap.PathRules {
+					Expect(pathRule.RedirectConfiguration).To(Equal(resourceRef(redirectID)))
+					Expect(pathRule.BackendAddressPool).To(BeNil())
+					Expect(pathRule.BackendHTTPSettings).To(BeNil())
+				}
+			}
+			Expect(validateURLPathMaps(configBuilder.recorder, configBuilder.appGw.ApplicationGatewayPropertiesFormat, cbCtx.EnvVariables, cbCtx.IngressList, cbCtx.ServiceList)).ToNot(HaveOccurred())
+		})
+
+		It("applies the redirect type and the include annotations", func() {
+			ingress.Annotations[annotations.RedirectTypeKey] = "307"
+			ingress.Annotations[annotations.RedirectIncludePathKey] = "false"
+			ingress.Annotations[annotations.RedirectIncludeQueryStringKey] = "false"
+			build()
+
+			redirect := urlRedirect()
+			Expect(redirect.RedirectType).To(Equal(n.Temporary))
+			Expect(*redirect.IncludePath).To(BeFalse())
+			Expect(*redirect.IncludeQueryString).To(BeFalse())
+		})
+
+		It("emits an event and falls back to a permanent redirect for unsupported redirect types", func() {
+			ingress.Annotations[annotations.RedirectTypeKey] = "308"
+			build()
+
+			Expect(urlRedirect().RedirectType).To(Equal(n.Permanent))
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidAnnotation))
+		})
+	})
+
+	Context("with the paths of the host in several ingresses", func() {
+		It("redirects the paths of the annotated ingress only", func() {
+			// The annotation redirects every path of the ingress; the paths, which must not be redirected, go in another ingress.
+			other := tests.NewIngressFixture()
+			other.Name = "other"
+			delete(other.Annotations, annotations.SslRedirectKey)
+			other.Spec.Rules = []networking.IngressRule{
+				tests.NewIngressRuleFixture(tests.Host, "/api", *tests.NewIngressBackendFixture(tests.ServiceName, 80)),
+			}
+			_ = configBuilder.k8sContext.Caches.Ingress.Add(other)
+			cbCtx.IngressList = append(cbCtx.IngressList, other)
+			build()
+
+			redirectID := configBuilder.appGwIdentifier.redirectConfigurationID(redirectName)
+			redirectedPaths, routedPaths := 0, 0
+			for _, pathMap := range *configBuilder.appGw.URLPathMaps {
+				for _, pathRule := range *pathMap.PathRules {
+					if (*pathRule.Paths)[0] == "/api" {
+						Expect(pathRule.BackendAddressPool).ToNot(BeNil())
+						Expect(pathRule.RedirectConfiguration).To(BeNil())
+						routedPaths++
+					} else if pathRule.RedirectConfiguration != nil && *pathRule.RedirectConfiguration.ID == redirectID {
+						Expect(pathRule.BackendAddressPool).To(BeNil())
+						redirectedPaths++
+					}
+				}
+			}
+			Expect(routedPaths).To(BeNumerically(">", 0))
+			Expect(redirectedPaths).To(BeNumerically(">", 0))
+		})
+	})
+
+	Context("with an invalid redirect-target-url annotation", func() {
+		It("emits an event and routes the ingress to its backends", func() {
+			ingress.Annotations[annotations.RedirectTargetURLKey] = "www.contoso.com"
+			build()
+
+			Expect(urlRedirect()).To(BeNil())
+			for _, pathMap := range *configBuilder.appGw.URLPathMaps {
+				for _, pathRule := range *pathMap.PathRules {
+					Expect(pathRule.RedirectConfiguration == nil || *pathRule.RedirectConfiguration.ID != configBuilder.appGwIdentifier.redirectConfigurationID(redirectName)).To(BeTrue())
+				}
+			}
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidAnnotation))
+		})
+	})
+})
@@ -8,11 +8,12 @@ package appgw
 import (
 	"sort"
 	"strconv"
+	"strings"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
@@ -21,10 +22,13 @@ import (
 )
 
 func (c *appGwConfigBuilder) RequestRoutingRules(cbCtx *ConfigBuilderContext) error {
+	// The rules and path maps link to the rewrite rule sets of the ingresses.
+	c.rewriteRuleSets(cbCtx)
+
 	requestRoutingRules, pathMaps := c.getRules(cbCtx)
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil, cbCtx.FrontendIPAddresses)
 		{
 			// PathMaps we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 			existingBlacklisted, existingNonBlacklisted := rCtx.GetBlacklistedPathMaps()
@@ -41,7 +45,7 @@ func (c *appGwConfigBuilder) RequestRoutingRules(cbCtx *ConfigBuilderContext) er
 	c.appGw.URLPathMaps = &pathMaps
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil, cbCtx.FrontendIPAddresses)
 		{
 			// RoutingRules we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 			existingBlacklisted, existingNonBlacklisted := rCtx.GetBlacklistedRoutingRules()
@@ -92,6 +96,7 @@ func (c *appGwConfigBuilder) getRules(cbCtx *ConfigBuilderContext) ([]n.Applicat
 			if rule.RedirectConfiguration == nil {
 				rule.BackendAddressPool = urlPathMap.DefaultBackendAddressPool
 				rule.BackendHTTPSettings = urlPathMap.DefaultBackendHTTPSettings
+				rule.RewriteRuleSet = urlPathMap.DefaultRewriteRuleSet
 			}
 		} else {
 			// Path-based Rule
@@ -147,9 +152,14 @@ func (c *appGwConfigBuilder) getPathMaps(cbCtx *ConfigBuilderContext) map[listen
 		}
 	}
 
+	var gatewayAPIPathMaps map[listenerIdentifier]*n.ApplicationGatewayURLPathMap
+	if cbCtx.EnvVariables.EnableGatewayAPI {
+		gatewayAPIPathMaps = c.getGatewayAPIPathMaps(cbCtx)
+	}
+
 	// if no url pathmaps were created, then add a default path map since this will be translated to
 	// a basic request routing rule which is needed on Application Gateway to avoid validation error.
-	if len(urlPathMaps) == 0 {
+	if len(urlPathMaps) == 0 && len(gatewayAPIPathMaps) == 0 {
 		defaultAddressPoolID := c.appGwIdentifier.AddressPoolID(DefaultBackendAddressPoolName)
 		defaultHTTPSettingsID := c.appGwIdentifier.HTTPSettingsID(DefaultBackendHTTPSettingsName)
 		listenerID := defaultFrontendListenerIdentifier()
@@ -164,6 +174,12 @@ func (c *appGwConfigBuilder) getPathMaps(cbCtx *ConfigBuilderContext) map[listen
 		}
 	}
 
+	for listenerID, pathMap := range gatewayAPIPathMaps {
+		if _, exists := urlPathMaps[listenerID]; !exists {
+			urlPathMaps[listenerID] = pathMap
+		}
+	}
+
 	if cbCtx.EnvVariables.EnableIstioIntegration {
 		for listenerID, pathMap := range c.getIstioPathMaps(cbCtx) {
 			if _, exists := urlPathMaps[listenerID]; !exists {
@@ -175,7 +191,7 @@ func (c *appGwConfigBuilder) getPathMaps(cbCtx *ConfigBuilderContext) map[listen
 	return urlPathMaps
 }
 
-func (c *appGwConfigBuilder) getPathMap(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, listenerAzConfig listenerAzConfig, ingress *v1beta1.Ingress, rule *v1beta1.IngressRule) *n.ApplicationGatewayURLPathMap {
+func (c *appGwConfigBuilder) getPathMap(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, listenerAzConfig listenerAzConfig, ingress *networking.Ingress, rule *networking.IngressRule) *n.ApplicationGatewayURLPathMap {
 	// initialize a path map for this listener if doesn't exists
 	pathMap := n.ApplicationGatewayURLPathMap{
 		Etag: to.StringPtr("*"),
@@ -193,6 +209,7 @@ func (c *appGwConfigBuilder) getPathMap(cbCtx *ConfigBuilderContext, listenerID
 	} else if defaultAddressPoolID != nil && defaultHTTPSettingsID != nil {
 		pathMap.DefaultBackendAddressPool = resourceRef(*defaultAddressPoolID)
 		pathMap.DefaultBackendHTTPSettings = resourceRef(*defaultHTTPSettingsID)
+		pathMap.DefaultRewriteRuleSet = c.getRewriteRuleSetRef(cbCtx, ingress)
 	}
 
 	pathMap.PathRules = c.getPathRules(cbCtx, listenerID, listenerAzConfig, ingress, rule)
@@ -200,13 +217,14 @@ func (c *appGwConfigBuilder) getPathMap(cbCtx *ConfigBuilderContext, listenerID
 	return &pathMap
 }
 
-func (c *appGwConfigBuilder) getDefaultFromRule(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, listenerAzConfig listenerAzConfig, ingress *v1beta1.Ingress, rule *v1beta1.IngressRule) (*string, *string, *string) {
+func (c *appGwConfigBuilder) getDefaultFromRule(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, listenerAzConfig listenerAzConfig, ingress *networking.Ingress, rule *networking.IngressRule) (*string, *string, *string) {
+	if redirect := c.getURLRedirectConfig(ingress); redirect != nil {
+		glog.V(5).Infof("Attached default redirection %s to rule %+v", *redirect.ID, *rule)
+		return nil, nil, redirect.ID
+	}
+
 	if sslRedirect, _ := annotations.IsSslRedirect(ingress); sslRedirect && listenerAzConfig.Protocol == n.HTTP {
-		targetListener := listenerIdentifier{
-			HostName:     listenerID.HostName,
-			FrontendPort: 443,
-			UsePrivateIP: listenerID.UsePrivateIP,
-		}
+		targetListener := c.sslRedirectTargetListener(listenerID, ingress)
 
 		// We could end up in a situation where we are attempting to attach a redirect, which does not exist.
 		redirectRef := c.getSslRedirectConfigResourceReference(targetListener)
@@ -219,12 +237,12 @@ func (c *appGwConfigBuilder) getDefaultFromRule(cbCtx *ConfigBuilderContext, lis
 		glog.Errorf("Will not attach default redirect to rule; SSL Redirect does not exist: %s", *redirectRef.ID)
 	}
 
-	var defRule *v1beta1.IngressRule
-	var defPath *v1beta1.HTTPIngressPath
-	defBackend := ingress.Spec.Backend
+	var defRule *networking.IngressRule
+	var defPath *networking.HTTPIngressPath
+	defBackend := ingress.Spec.DefaultBackend
 	for pathIdx := range rule.HTTP.Paths {
 		path := &rule.HTTP.Paths[pathIdx]
-		if path.Path == "" || path.Path == "/*" || path.Path == "/" {
+		if isDefaultPath(path) {
 			defBackend = &path.Backend
 			defPath = path
 			defRule = rule
@@ -233,7 +251,7 @@ func (c *appGwConfigBuilder) getDefaultFromRule(cbCtx *ConfigBuilderContext, lis
 
 	backendPools := c.newBackendPoolMap(cbCtx)
 	_, backendHTTPSettingsMap, _, _ := c.getBackendsAndSettingsMap(cbCtx)
-	if defBackend != nil {
+	if isServiceBackend(defBackend) {
 		// has default backend
 		defaultBackendID := generateBackendID(ingress, defRule, defPath, defBackend)
 		defaultHTTPSettings := backendHTTPSettingsMap[defaultBackendID]
@@ -248,30 +266,39 @@ func (c *appGwConfigBuilder) getDefaultFromRule(cbCtx *ConfigBuilderContext, lis
 	return cbCtx.DefaultAddressPoolID, cbCtx.DefaultHTTPSettingsID, nil
 }
 
-func (c *appGwConfigBuilder) getPathRules(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, listenerAzConfig listenerAzConfig, ingress *v1beta1.Ingress, rule *v1beta1.IngressRule) *[]n.ApplicationGatewayPathRule {
+func (c *appGwConfigBuilder) getPathRules(cbCtx *ConfigBuilderContext, listenerID listenerIdentifier, listenerAzConfig listenerAzConfig, ingress *networking.Ingress, rule *networking.IngressRule) *[]n.ApplicationGatewayPathRule {
 	backendPools := c.newBackendPoolMap(cbCtx)
 	_, backendHTTPSettingsMap, _, _ := c.getBackendsAndSettingsMap(cbCtx)
 	pathRules := make([]n.ApplicationGatewayPathRule, 0)
 	for pathIdx := range rule.HTTP.Paths {
 		path := &rule.HTTP.Paths[pathIdx]
-		if len(path.Path) == 0 || path.Path == "/*" || path.Path == "/" {
+		if isDefaultPath(path) {
 			continue
 		}
 
+		paths := generatePaths(path)
+
 		pathRule := n.ApplicationGatewayPathRule{
 			Etag: to.StringPtr("*"),
 			Name: to.StringPtr(generatePathRuleName(ingress.Namespace, ingress.Name, strconv.Itoa(pathIdx))),
 			ApplicationGatewayPathRulePropertiesFormat: &n.ApplicationGatewayPathRulePropertiesFormat{
-				Paths: &[]string{path.Path},
+				Paths: &paths,
 			},
 		}
+		if listenerAzConfig.FirewallPolicy != "" {
+			pathRule.FirewallPolicy = resourceRef(listenerAzConfig.FirewallPolicy)
+		}
+
+		if redirect := c.getURLRedirectConfig(ingress); redirect != nil {
+			// The redirect to the target URL of the ingress takes precedence over the SSL Redirect
+			pathRule.RedirectConfiguration = resourceRef(*redirect.ID)
+			glog.V(5).Infof("Attached redirection %s to path rule: %s", *redirect.ID, *pathRule.Name)
+			pathRules = append(pathRules, pathRule)
+			continue
+		}
 
 		if sslRedirect, _ := annotations.IsSslRedirect(ingress); sslRedirect && listenerAzConfig.Protocol == n.HTTP {
-			targetListener := listenerIdentifier{
-				HostName:     listenerID.HostName,
-				FrontendPort: 443,
-				UsePrivateIP: listenerID.UsePrivateIP,
-			}
+			targetListener := c.sslRedirectTargetListener(listenerID, ingress)
 
 			// We could end up in a situation where we are attempting to attach a redirect, which does not exist.
 			redirectRef := c.getSslRedirectConfigResourceReference(targetListener)
@@ -289,6 +316,9 @@ func (c *appGwConfigBuilder) getPathRules(cbCtx *ConfigBuilderContext, listenerI
 			}
 
 		}
+		if !isServiceBackend(&path.Backend) {
+			continue
+		}
 		backendID := generateBackendID(ingress, rule, path, &path.Backend)
 		backendPool := backendPools[backendID]
 		backendHTTPSettings := backendHTTPSettingsMap[backendID]
@@ -298,6 +328,7 @@ func (c *appGwConfigBuilder) getPathRules(cbCtx *ConfigBuilderContext, listenerI
 
 		pathRule.BackendAddressPool = &n.SubResource{ID: backendPool.ID}
 		pathRule.BackendHTTPSettings = &n.SubResource{ID: backendHTTPSettings.ID}
+		pathRule.RewriteRuleSet = c.getRewriteRuleSetRef(cbCtx, ingress)
 		glog.V(5).Infof("Attached pool %s and http setting %s to path rule: %s", *backendPool.Name, *backendHTTPSettings.Name, *pathRule.Name)
 
 		pathRules = append(pathRules, pathRule)
@@ -309,6 +340,7 @@ func (c *appGwConfigBuilder) getPathRules(cbCtx *ConfigBuilderContext, listenerI
 func (c *appGwConfigBuilder) mergePathMap(existingPathMap *n.ApplicationGatewayURLPathMap, pathMapToMerge *n.ApplicationGatewayURLPathMap) *n.ApplicationGatewayURLPathMap {
 	if pathMapToMerge.DefaultBackendAddressPool != nil {
 		existingPathMap.DefaultBackendAddressPool = pathMapToMerge.DefaultBackendAddressPool
+		existingPathMap.DefaultRewriteRuleSet = pathMapToMerge.DefaultRewriteRuleSet
 	}
 	if pathMapToMerge.DefaultBackendHTTPSettings != nil {
 		existingPathMap.DefaultBackendHTTPSettings = pathMapToMerge.DefaultBackendHTTPSettings
@@ -317,6 +349,7 @@ func (c *appGwConfigBuilder) mergePathMap(existingPathMap *n.ApplicationGatewayU
 		existingPathMap.DefaultRedirectConfiguration = pathMapToMerge.DefaultRedirectConfiguration
 		existingPathMap.DefaultBackendAddressPool = nil
 		existingPathMap.DefaultBackendHTTPSettings = nil
+		existingPathMap.DefaultRewriteRuleSet = nil
 	}
 	if pathMapToMerge.PathRules == nil || len(*pathMapToMerge.PathRules) == 0 {
 		return existingPathMap
@@ -330,3 +363,28 @@ func (c *appGwConfigBuilder) mergePathMap(existingPathMap *n.ApplicationGatewayU
 	}
 	return existingPathMap
 }
+
+// isDefaultPath determines whether the Ingress path matches all requests, in which case its backend
+// becomes the default backend of the URL path map rather than a path rule.
+func isDefaultPath(path *networking.HTTPIngressPath) bool {
+	if path.PathType != nil {
+		switch *path.PathType {
+		case networking.PathTypeExact:
+			return false
+		case networking.PathTypePrefix:
+			return strings.TrimRight(path.Path, "/") == ""
+		}
+	}
+	return path.Path == "" || path.Path == "/*" || path.Path == "/"
+}
+
+// generatePaths translates the Ingress path into the paths of an App Gateway path rule based on the path type.
+// Exact paths are used as-is; Prefix paths match the path itself as well as every sub-path. The interpretation
+// of ImplementationSpecific paths is left to App Gateway, which is how AGIC treated paths before path types existed.
+func generatePaths(path *networking.HTTPIngressPath) []string {
+	if path.PathType != nil && *path.PathType == networking.PathTypePrefix {
+		prefix := strings.TrimRight(path.Path, "/")
+		return []string{prefix, prefix + "/*"}
+	}
+	return []string{path.Path}
+}
@@ -6,12 +6,12 @@
 package appgw
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
@@ -38,7 +38,7 @@ var _ = Describe("Test routing rules generations", func() {
 		testService.Name = "test"
 		testBackend := tests.NewIngressBackendFixture("test", 80)
 		testRule := tests.NewIngressRuleFixture(tests.Host, tests.URLPath3, *testBackend)
-		ingressPathBased2.Spec.Rules = []v1beta1.IngressRule{
+		ingressPathBased2.Spec.Rules = []networking.IngressRule{
 			testRule,
 		}
 		_ = configBuilder.k8sContext.Caches.Endpoints.Add(testEndpoint)
@@ -46,7 +46,7 @@ var _ = Describe("Test routing rules generations", func() {
 		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingressPathBased2)
 
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingressPathBased1, ingressPathBased2},
+			IngressList: []*networking.Ingress{ingressPathBased1, ingressPathBased2},
 			ServiceList: []*v1.Service{service},
 		}
 
@@ -76,8 +76,8 @@ var _ = Describe("Test routing rules generations", func() {
 				for _, rule := range ingress.Spec.Rules {
 					for _, path := range rule.HTTP.Paths {
 						backendID := generateBackendID(ingress, &rule, &path, &path.Backend)
-						backendPoolID := configBuilder.appGwIdentifier.AddressPoolID(generateAddressPoolName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), Port(tests.ContainerPort)))
-						httpSettingID := configBuilder.appGwIdentifier.HTTPSettingsID(generateHTTPSettingsName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), Port(tests.ContainerPort), backendID.Ingress.Name))
+						backendPoolID := configBuilder.appGwIdentifier.AddressPoolID(generateAddressPoolName(backendID.serviceFullName(), serviceBackendPortToStr(backendID.Backend.Service.Port), Port(tests.ContainerPort)))
+						httpSettingID := configBuilder.appGwIdentifier.HTTPSettingsID(generateHTTPSettingsName(backendID.serviceFullName(), serviceBackendPortToStr(backendID.Backend.Service.Port), Port(tests.ContainerPort), backendID.Ingress.Name))
 						expectedPathRule := n.ApplicationGatewayPathRule{
 							Name: to.StringPtr(generatePathRuleName(backendID.Ingress.Namespace, backendID.Ingress.Name, "0")),
 							Etag: to.StringPtr("*"),
@@ -112,7 +112,7 @@ var _ = Describe("Test routing rules generations", func() {
 		backendBasic := tests.NewIngressBackendFixture(tests.ServiceName, 80)
 		ruleBasic := tests.NewIngressRuleFixture(tests.Host, "", *backendBasic)
 		pathBasic := &ruleBasic.HTTP.Paths[0]
-		ingressBasic.Spec.Rules = []v1beta1.IngressRule{
+		ingressBasic.Spec.Rules = []networking.IngressRule{
 			ruleBasic,
 		}
 
@@ -122,7 +122,7 @@ var _ = Describe("Test routing rules generations", func() {
 		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingressBasic)
 
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingressPathBased, ingressBasic},
+			IngressList: []*networking.Ingress{ingressPathBased, ingressBasic},
 			ServiceList: []*v1.Service{service},
 		}
 
@@ -140,11 +140,11 @@ var _ = Describe("Test routing rules generations", func() {
 		generatedPathMap := pathMaps[sharedListenerID]
 		backendIDBasic := generateBackendID(ingressBasic, &ruleBasic, pathBasic, backendBasic)
 		It("has default backend pool coming from basic ingress", func() {
-			backendPoolID := configBuilder.appGwIdentifier.AddressPoolID(generateAddressPoolName(backendIDBasic.serviceFullName(), backendIDBasic.Backend.ServicePort.String(), Port(tests.ContainerPort)))
+			backendPoolID := configBuilder.appGwIdentifier.AddressPoolID(generateAddressPoolName(backendIDBasic.serviceFullName(), serviceBackendPortToStr(backendIDBasic.Backend.Service.Port), Port(tests.ContainerPort)))
 			Expect(*generatedPathMap.DefaultBackendAddressPool.ID).To(Equal(backendPoolID))
 		})
 		It("has default backend http settings coming from basic ingress", func() {
-			httpSettingID := configBuilder.appGwIdentifier.HTTPSettingsID(generateHTTPSettingsName(backendIDBasic.serviceFullName(), backendIDBasic.Backend.ServicePort.String(), Port(tests.ContainerPort), ingressBasic.Name))
+			httpSettingID := configBuilder.appGwIdentifier.HTTPSettingsID(generateHTTPSettingsName(backendIDBasic.serviceFullName(), serviceBackendPortToStr(backendIDBasic.Backend.Service.Port), Port(tests.ContainerPort), ingressBasic.Name))
 			Expect(*generatedPathMap.DefaultBackendHTTPSettings.ID).To(Equal(httpSettingID))
 		})
 		It("should has 2 path rules", func() {
@@ -154,8 +154,8 @@ var _ = Describe("Test routing rules generations", func() {
 			for _, rule := range ingressPathBased.Spec.Rules {
 				for _, path := range rule.HTTP.Paths {
 					backendID := generateBackendID(ingressPathBased, &rule, &path, &path.Backend)
-					backendPoolID := configBuilder.appGwIdentifier.AddressPoolID(generateAddressPoolName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), Port(tests.ContainerPort)))
-					httpSettingID := configBuilder.appGwIdentifier.HTTPSettingsID(generateHTTPSettingsName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), Port(tests.ContainerPort), backendID.Ingress.Name))
+					backendPoolID := configBuilder.appGwIdentifier.AddressPoolID(generateAddressPoolName(backendID.serviceFullName(), serviceBackendPortToStr(backendID.Backend.Service.Port), Port(tests.ContainerPort)))
+					httpSettingID := configBuilder.appGwIdentifier.HTTPSettingsID(generateHTTPSettingsName(backendID.serviceFullName(), serviceBackendPortToStr(backendID.Backend.Service.Port), Port(tests.ContainerPort), backendID.Ingress.Name))
 					expectedPathRule := n.ApplicationGatewayPathRule{
 						Name: to.StringPtr(generatePathRuleName(backendID.Ingress.Namespace, backendID.Ingress.Name, "0")),
 						Etag: to.StringPtr("*"),
@@ -184,7 +184,7 @@ var _ = Describe("Test routing rules generations", func() {
 		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
 
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingress},
+			IngressList: []*networking.Ingress{ingress},
 			ServiceList: []*v1.Service{service},
 		}
 
@@ -244,7 +244,7 @@ var _ = Describe("Test routing rules generations", func() {
 		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
 
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingress},
+			IngressList: []*networking.Ingress{ingress},
 			ServiceList: []*v1.Service{service},
 		}
 
@@ -289,7 +289,7 @@ var _ = Describe("Test routing rules generations", func() {
 		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
 
 		cbCtx := &ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{ingress},
+			IngressList: []*networking.Ingress{ingress},
 			ServiceList: []*v1.Service{service},
 		}
 
@@ -316,7 +316,7 @@ var _ = Describe("Test routing rules generations", func() {
 						ID: to.StringPtr("/subscriptions/--subscription--/resourceGroups/--resource-group--" +
 							"/providers/Microsoft.Network/applicationGateways/--app-gw-name--" +
 							"/redirectConfigurations/sslr-fl-foo.baz-443")},
-					ProvisioningState: nil,
+					ProvisioningState: "",
 				},
 				Name: to.StringPtr("rr-foo.baz-80"),
 				Etag: to.StringPtr("*"),
@@ -344,7 +344,7 @@ var _ = Describe("Test routing rules generations", func() {
 					URLPathMap:            nil,
 					RewriteRuleSet:        nil,
 					RedirectConfiguration: nil,
-					ProvisioningState:     nil,
+					ProvisioningState:     "",
 				},
 				Name: to.StringPtr("rr-foo.baz-443"),
 				Etag: to.StringPtr("*"),
@@ -357,4 +357,78 @@ var _ = Describe("Test routing rules generations", func() {
 			Expect(len(*configBuilder.appGw.URLPathMaps)).To(Equal(0))
 		})
 	})
+
+	Context("test path rules generated from Ingress path types", func() {
+		configBuilder := newConfigBuilderFixture(nil)
+		endpoint := tests.NewEndpointsFixture()
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+
+		prefix := networking.PathTypePrefix
+		exact := networking.PathTypeExact
+		ingress := tests.NewIngressFixture()
+		ingress.Annotations[annotations.SslRedirectKey] = "false"
+		backend := tests.NewIngressBackendFixture(tests.ServiceName, 80)
+		rule := tests.NewIngressRuleFixture(tests.Host, "/prefix/", *backend)
+		rule.HTTP.Paths[0].PathType = &prefix
+		rule.HTTP.Paths = append(rule.HTTP.Paths,
+			networking.HTTPIngressPath{Path: "/exact", PathType: &exact, Backend: *backend},
+			networking.HTTPIngressPath{Path: "/", PathType: &prefix, Backend: *backend},
+		)
+		ingress.Spec.Rules = []networking.IngressRule{rule}
+
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoint)
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+
+		cbCtx := &ConfigBuilderContext{
+			IngressList: []*networking.Ingress{ingress},
+			ServiceList: []*v1.Service{service},
+		}
+
+		_ = configBuilder.BackendHTTPSettingsCollection(cbCtx)
+		_ = configBuilder.BackendAddressPools(cbCtx)
+		_ = configBuilder.Listeners(cbCtx)
+		pathMaps := configBuilder.getPathMaps(cbCtx)
+		generatedPathMap := pathMaps[generateListenerID(&rule, n.HTTPS, nil, false)]
+
+		It("should use the Prefix \"/\" path as the default backend", func() {
+			Expect(generatedPathMap.DefaultBackendAddressPool).ToNot(BeNil())
+			Expect(len(*generatedPathMap.PathRules)).To(Equal(2))
+		})
+
+		It("should match the Prefix path and all of its sub-paths", func() {
+			Expect(*(*generatedPathMap.PathRules)[0].Paths).To(Equal([]string{"/prefix", "/prefix/*"}))
+		})
+
+		It("should use the Exact path as-is", func() {
+			Expect(*(*generatedPathMap.PathRules)[1].Paths).To(Equal([]string{"/exact"}))
+		})
+	})
+
+	Context("test isDefaultPath", func() {
+		prefix := networking.PathTypePrefix
+		exact := networking.PathTypeExact
+		implementationSpecific := networking.PathTypeImplementationSpecific
+
+		It("treats catch-all paths without a path type as default", func() {
+			for _, path := range []string{"", "/", "/*"} {
+				Expect(isDefaultPath(&networking.HTTPIngressPath{Path: path})).To(BeTrue())
+			}
+			Expect(isDefaultPath(&networking.HTTPIngressPath{Path: "/api"})).To(BeFalse())
+		})
+
+		It("treats ImplementationSpecific paths like paths without a path type", func() {
+			Expect(isDefaultPath(&networking.HTTPIngressPath{Path: "/*", PathType: &implementationSpecific})).To(BeTrue())
+			Expect(isDefaultPath(&networking.HTTPIngressPath{Path: "/api", PathType: &implementationSpecific})).To(BeFalse())
+		})
+
+		It("treats only the root Prefix path as default", func() {
+			Expect(isDefaultPath(&networking.HTTPIngressPath{Path: "/", PathType: &prefix})).To(BeTrue())
+			Expect(isDefaultPath(&networking.HTTPIngressPath{Path: "/*", PathType: &prefix})).To(BeFalse())
+		})
+
+		It("never treats Exact paths as default", func() {
+			Expect(isDefaultPath(&networking.HTTPIngressPath{Path: "/", PathType: &exact})).To(BeFalse())
+		})
+	})
 })
@@ -0,0 +1,201 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"sort"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	rewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// defaultRewriteRuleSequence is the sequence of the first rewrite rule without one; the following rules are evaluated in the order they are listed.
+const defaultRewriteRuleSequence = 100
+
+// rewriteRuleSets sets the rewrite rule sets of the AzureIngressRewrites the ingresses reference; the request routing rules
+// and path rules of the ingresses link to them.
+func (c *appGwConfigBuilder) rewriteRuleSets(cbCtx *ConfigBuilderContext) {
+	ruleSetsByName := make(map[string]n.ApplicationGatewayRewriteRuleSet)
+	for _, ingress := range cbCtx.IngressList {
+		if ruleSet := c.getRewriteRuleSet(cbCtx, ingress); ruleSet != nil {
+			ruleSetsByName[*ruleSet.Name] = *ruleSet
+		}
+	}
+
+	var ruleSets []n.ApplicationGatewayRewriteRuleSet
+	for _, ruleSet := range ruleSetsByName {
+		ruleSets = append(ruleSets, ruleSet)
+	}
+
+	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil, cbCtx.FrontendIPAddresses)
+
+		// Rewrite rule sets we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
+		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedRewriteRuleSets()
+
+		brownfield.LogRewriteRuleSets(existingBlacklisted, existingNonBlacklisted, ruleSets)
+
+		// MergeRewriteRuleSets would produce unique list of rewrite rule sets based on Name. Blacklisted rewrite rule sets,
+		// which have the same name as a managed rewrite rule set would be overwritten.
+		ruleSets = brownfield.MergeRewriteRuleSets(existingBlacklisted, ruleSets)
+	}
+
+	sort.Sort(sorter.ByRewriteRuleSetName(ruleSets))
+	if len(ruleSets) > 0 || c.appGw.RewriteRuleSets != nil {
+		c.appGw.RewriteRuleSets = &ruleSets
+	}
+}
+
+// getRewriteRuleSetRef returns a reference to the rewrite rule set of the ingress; nil when it has none.
+func (c *appGwConfigBuilder) getRewriteRuleSetRef(cbCtx *ConfigBuilderContext, ingress *networking.Ingress) *n.SubResource {
+	ruleSet := c.getRewriteRuleSet(cbCtx, ingress)
+	if ruleSet == nil {
+		return nil
+	}
+	return resourceRef(*ruleSet.ID)
+}
+
+// getRewriteRuleSet translates the AzureIngressRewrite of the ingress once per config build, and emits an event on the ingress when it is unusable.
+// AzureIngressRewrites are only used when the EnableRewriteVarName env variable is set to true.
+func (c *appGwConfigBuilder) getRewriteRuleSet(cbCtx *ConfigBuilderContext, ingress *networking.Ingress) *n.ApplicationGatewayRewriteRuleSet {
+	if c.mem.rewriteRuleSets == nil {
+		ruleSets := make(map[string]*n.ApplicationGatewayRewriteRuleSet)
+		c.mem.rewriteRuleSets = &ruleSets
+	}
+	ingressKey := utils.GetResourceKey(ingress.Namespace, ingress.Name)
+	if ruleSet, exists := (*c.mem.rewriteRuleSets)[ingressKey]; exists {
+		return ruleSet
+	}
+
+	var ruleSet *n.ApplicationGatewayRewriteRuleSet
+	name, err := annotations.RewriteRuleSet(ingress)
+	if err != nil {
+		if !errors.IsMissingAnnotations(err) {
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+		}
+	} else if !cbCtx.EnvVariables.EnableRewrite {
+		logLine := fmt.Sprintf("Unable to use AzureIngressRewrite %s: %s is not enabled", utils.GetResourceKey(ingress.Namespace, name), environment.EnableRewriteVarName)
+		glog.Error(logLine)
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidRewriteRuleSet, logLine)
+	} else if ruleSet, err = c.newRewriteRuleSet(ingress.Namespace, name); err != nil {
+		logLine := fmt.Sprintf("Unable to use AzureIngressRewrite %s: %s", utils.GetResourceKey(ingress.Namespace, name), err)
+		glog.Error(logLine)
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidRewriteRuleSet, logLine)
+	}
+
+	(*c.mem.rewriteRuleSets)[ingressKey] = ruleSet
+	return ruleSet
+}
+
+func (c *appGwConfigBuilder) newRewriteRuleSet(namespace, name string) (*n.ApplicationGatewayRewriteRuleSet, error) {
+	rewrite := c.k8sContext.GetAzureIngressRewrite(utils.GetResourceKey(namespace, name))
+	if rewrite == nil {
+		return nil, ErrRewriteRuleSetNotFound
+	}
+	if len(rewrite.Spec.RewriteRules) == 0 {
+		return nil, ErrNoRewriteRules
+	}
+
+	var rules []n.ApplicationGatewayRewriteRule
+	ruleNames := make(map[string]interface{})
+	for idx, rewriteRule := range rewrite.Spec.RewriteRules {
+		if _, exists := ruleNames[rewriteRule.Name]; exists || rewriteRule.Name == "" {
+			return nil, ErrInvalidRewriteRule
+		}
+		ruleNames[rewriteRule.Name] = nil
+
+		rule, err := newRewriteRule(rewriteRule, int32(defaultRewriteRuleSequence+idx))
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	ruleSetName := generateRewriteRuleSetName(namespace, name)
+	return &n.ApplicationGatewayRewriteRuleSet{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(ruleSetName),
+		ID:   to.StringPtr(c.appGwIdentifier.rewriteRuleSetID(ruleSetName)),
+		ApplicationGatewayRewriteRuleSetPropertiesFormat: &n.ApplicationGatewayRewriteRuleSetPropertiesFormat{
+			RewriteRules: &rules,
+		},
+	}, nil
+}
+
+// newRewriteRule creates an App Gateway rewrite rule; defaultSequence is used when the rule has no sequence.
+func newRewriteRule(rewriteRule rewritev1.RewriteRule, defaultSequence int32) (n.ApplicationGatewayRewriteRule, error) {
+	if len(rewriteRule.Actions.RequestHeaders) == 0 && len(rewriteRule.Actions.ResponseHeaders) == 0 {
+		return n.ApplicationGatewayRewriteRule{}, ErrInvalidRewriteRule
+	}
+
+	var conditions []n.ApplicationGatewayRewriteRuleCondition
+	for _, condition := range rewriteRule.Conditions {
+		if condition.Variable == "" {
+			return n.ApplicationGatewayRewriteRule{}, ErrInvalidRewriteRule
+		}
+		conditions = append(conditions, n.ApplicationGatewayRewriteRuleCondition{
+			Variable:   to.StringPtr(condition.Variable),
+			Pattern:    to.StringPtr(condition.Pattern),
+			IgnoreCase: to.BoolPtr(condition.IgnoreCase),
+			Negate:     to.BoolPtr(condition.Negate),
+		})
+	}
+
+	requestHeaders, err := newHeaderConfigurations(rewriteRule.Actions.RequestHeaders)
+	if err != nil {
+		return n.ApplicationGatewayRewriteRule{}, err
+	}
+	responseHeaders, err := newHeaderConfigurations(rewriteRule.Actions.ResponseHeaders)
+	if err != nil {
+		return n.ApplicationGatewayRewriteRule{}, err
+	}
+
+	sequence := rewriteRule.RuleSequence
+	if sequence == 0 {
+		sequence = defaultSequence
+	}
+
+	rule := n.ApplicationGatewayRewriteRule{
+		Name:         to.StringPtr(rewriteRule.Name),
+		RuleSequence: to.Int32Ptr(sequence),
+		ActionSet: &n.ApplicationGatewayRewriteRuleActionSet{
+			RequestHeaderConfigurations:  &requestHeaders,
+			ResponseHeaderConfigurations: &responseHeaders,
+		},
+	}
+	if len(conditions) > 0 {
+		rule.Conditions = &conditions
+	}
+	return rule, nil
+}
+
+// newHeaderConfigurations creates the header configurations of a rewrite rule; App Gateway removes the headers with an empty value.
+func newHeaderConfigurations(headers []rewritev1.HeaderConfiguration) ([]n.ApplicationGatewayHeaderConfiguration, error) {
+	headerConfigs := make([]n.ApplicationGatewayHeaderConfiguration, 0, len(headers))
+	for _, header := range headers {
+		if header.Name == "" {
+			return nil, ErrInvalidRewriteRule
+		}
+		headerConfigs = append(headerConfigs, n.ApplicationGatewayHeaderConfiguration{
+			HeaderName:  to.StringPtr(header.Name),
+			HeaderValue: to.StringPtr(header.Value),
+		})
+	}
+	return headerConfigs, nil
+}
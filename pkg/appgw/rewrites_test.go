@@ -0,0 +1,202 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	rewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test the rewrite rule sets of AzureIngressRewrites", func() {
+	var configBuilder appGwConfigBuilder
+	var ingress *networking.Ingress
+	var rewrite *rewritev1.AzureIngressRewrite
+	var cbCtx *ConfigBuilderContext
+
+	ruleSetName := "rw-" + tests.Namespace + "-security-headers"
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+		endpoint := tests.NewEndpointsFixture()
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+		ingress = tests.NewIngressFixture()
+		ingress.Annotations[annotations.SslRedirectKey] = "false"
+		ingress.Annotations[annotations.RewriteRuleSetKey] = "security-headers"
+		rewrite = &rewritev1.AzureIngressRewrite{
+			ObjectMeta: metav1.ObjectMeta{Name: "security-headers", Namespace: tests.Namespace},
+			Spec: rewritev1.AzureIngressRewriteSpec{
+				RewriteRules: []rewritev1.RewriteRule{
+					{
+						Name: "hsts",
+						Actions: rewritev1.RewriteRuleActions{
+							ResponseHeaders: []rewritev1.HeaderConfiguration{
+								{Name: "Strict-Transport-Security", Value: "max-age=31536000"},
+								{Name: "Server"},
+							},
+						},
+					},
+					{
+						Name:         "location",
+						RuleSequence: 300,
+						Conditions: []rewritev1.RewriteRuleCondition{
+							{Variable: "http_resp_Location", Pattern: "(https?)://.*azurewebsites\\.net(.*)$", IgnoreCase: true},
+						},
+						Actions: rewritev1.RewriteRuleActions{
+							ResponseHeaders: []rewritev1.HeaderConfiguration{
+								{Name: "Location", Value: "{http_resp_Location_1}://contoso.com{http_resp_Location_2}"},
+							},
+						},
+					},
+				},
+			},
+		}
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoint)
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+		_ = configBuilder.k8sContext.Caches.AzureIngressRewrite.Add(rewrite)
+
+		env := environment.GetFakeEnv()
+		env.EnableRewrite = true
+		cbCtx = &ConfigBuilderContext{
+			IngressList:  []*networking.Ingress{ingress},
+			ServiceList:  []*v1.Service{service},
+			EnvVariables: env,
+		}
+	})
+
+	build := func() {
+		Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.BackendAddressPools(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.Listeners(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.RequestRoutingRules(cbCtx)).ToNot(HaveOccurred())
+	}
+
+	// pathRules returns the path rules of all URL path maps.
+	pathRules := func() []n.ApplicationGatewayPathRule {
+		var rules []n.ApplicationGatewayPathRule
+		for _, pathMap := range *configBuilder.appGw.URLPathMaps {
+			if pathMap.PathRules != nil {
+				rules = append(rules, *pathMap.PathRules...)
+			}
+		}
+		Expect(rules).ToNot(BeEmpty())
+		return rules
+	}
+
+	Context("with a valid AzureIngressRewrite", func() {
+		It("creates the rewrite rule set and links it from the path rules", func() {
+			build()
+
+			ruleSetID := configBuilder.appGwIdentifier.rewriteRuleSetID(ruleSetName)
+			Expect(*configBuilder.appGw.RewriteRuleSets).To(Equal([]n.ApplicationGatewayRewriteRuleSet{
+				{
+					Etag: to.StringPtr("*"),
+					Name: to.StringPtr(ruleSetName),
+					ID:   to.StringPtr(ruleSetID),
+					ApplicationGatewayRewriteRuleSetPropertiesFormat: &n.ApplicationGatewayRewriteRuleSetPropertiesFormat{
+						RewriteRules: &[]n.ApplicationGatewayRewriteRule{
+							{
+								Name:         to.StringPtr("hsts"),
+								RuleSequence: to.Int32Ptr(100),
+								ActionSet: &n.ApplicationGatewayRewriteRuleActionSet{
+									RequestHeaderConfigurations: &[]n.ApplicationGatewayHeaderConfiguration{},
+									ResponseHeaderConfigurations: &[]n.ApplicationGatewayHeaderConfiguration{
+										{HeaderName: to.StringPtr("Strict-Transport-Security"), HeaderValue: to.StringPtr("max-age=31536000")},
+										{HeaderName: to.StringPtr("Server"), HeaderValue: to.StringPtr("")},
+									},
+								},
+							},
+							{
+								Name:         to.StringPtr("location"),
+								RuleSequence: to.Int32Ptr(300),
+								Conditions: &[]n.ApplicationGatewayRewriteRuleCondition{
+									{
+										Variable:   to.StringPtr("http_resp_Location"),
+										Pattern:    to.StringPtr("(https?)://.*azurewebsites\\.net(.*)$"),
+										IgnoreCase: to.BoolPtr(true),
+										Negate:     to.BoolPtr(false),
+									},
+								},
+								ActionSet: &n.ApplicationGatewayRewriteRuleActionSet{
+									RequestHeaderConfigurations: &[]n.ApplicationGatewayHeaderConfiguration{},
+									ResponseHeaderConfigurations: &[]n.ApplicationGatewayHeaderConfiguration{
+										{HeaderName: to.StringPtr("Location"), HeaderValue: to.StringPtr("{http_resp_Location_1}://contoso.com{http_resp_Location_2}")},
+									},
+								},
+							},
+						},
+					},
+				},
+			}))
+
+			for _, pathRule := range pathRules() {
+				Expect(pathRule.RewriteRuleSet).To(Equal(resourceRef(ruleSetID)))
+			}
+		})
+
+		It("links the rewrite rule set from basic rules", func() {
+			backend := tests.NewIngressBackendFixture(tests.ServiceName, 80)
+			ingress.Spec.Rules = []networking.IngressRule{tests.NewIngressRuleFixture(tests.Host, "/", *backend)}
+			build()
+
+			Expect(*configBuilder.appGw.RequestRoutingRules).ToNot(BeEmpty())
+			for _, rule := range *configBuilder.appGw.RequestRoutingRules {
+				Expect(rule.RuleType).To(Equal(n.Basic))
+				Expect(rule.RewriteRuleSet).To(Equal(resourceRef(configBuilder.appGwIdentifier.rewriteRuleSetID(ruleSetName))))
+			}
+		})
+	})
+
+	Context("without a usable AzureIngressRewrite", func() {
+		It("emits an event when the AzureIngressRewrite does not exist", func() {
+			ingress.Annotations[annotations.RewriteRuleSetKey] = "missing"
+			build()
+
+			Expect(configBuilder.appGw.RewriteRuleSets).To(BeNil())
+			for _, pathRule := range pathRules() {
+				Expect(pathRule.RewriteRuleSet).To(BeNil())
+			}
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidRewriteRuleSet))
+		})
+
+		It("emits an event when AzureIngressRewrites are not enabled", func() {
+			cbCtx.EnvVariables.EnableRewrite = false
+			build()
+
+			Expect(configBuilder.appGw.RewriteRuleSets).To(BeNil())
+			for _, pathRule := range pathRules() {
+				Expect(pathRule.RewriteRuleSet).To(BeNil())
+			}
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(environment.EnableRewriteVarName))
+		})
+
+		It("emits an event when a rewrite rule has no actions", func() {
+			rewrite.Spec.RewriteRules[1].Actions = rewritev1.RewriteRuleActions{}
+			build()
+
+			Expect(configBuilder.appGw.RewriteRuleSets).To(BeNil())
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(ErrInvalidRewriteRule.Error()))
+		})
+	})
+})
@@ -0,0 +1,65 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/golang/glog"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+)
+
+// v1OnlyCipherSuites are the DHE cipher suites, which only the v1 SKUs of App Gateway support.
+var v1OnlyCipherSuites = map[n.ApplicationGatewaySslCipherSuite]interface{}{
+	n.TLSDHEDSSWITH3DESEDECBCSHA:   nil,
+	n.TLSDHEDSSWITHAES128CBCSHA:    nil,
+	n.TLSDHEDSSWITHAES128CBCSHA256: nil,
+	n.TLSDHEDSSWITHAES256CBCSHA:    nil,
+	n.TLSDHEDSSWITHAES256CBCSHA256: nil,
+	n.TLSDHERSAWITHAES128CBCSHA:    nil,
+	n.TLSDHERSAWITHAES128GCMSHA256: nil,
+	n.TLSDHERSAWITHAES256CBCSHA:    nil,
+	n.TLSDHERSAWITHAES256GCMSHA384: nil,
+}
+
+// sslPolicy sets the SSL policy configured in the environment; App Gateway keeps its SSL policy when none is configured.
+// The environment is validated at startup, and the cipher suites against the tier of App Gateway before every build.
+func (c *appGwConfigBuilder) sslPolicy(cbCtx *ConfigBuilderContext) error {
+	policy, err := environment.NewSslPolicy(cbCtx.EnvVariables)
+	if err != nil {
+		glog.Error(err)
+		return ErrInvalidSslPolicy
+	}
+	if policy == nil {
+		return nil
+	}
+
+	c.appGw.SslPolicy = policy
+	return nil
+}
+
+// validateSslPolicy validates that the tier of App Gateway supports the cipher suites of the SSL policy configured in the environment.
+func validateSslPolicy(eventRecorder record.EventRecorder, config n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables) error {
+	policy, err := environment.NewSslPolicy(envVariables)
+	if err != nil {
+		glog.Error(err)
+		return ErrInvalidSslPolicy
+	}
+	if policy == nil || policy.CipherSuites == nil || config.Sku == nil {
+		return nil
+	}
+
+	if config.Sku.Tier == n.ApplicationGatewayTierStandardV2 || config.Sku.Tier == n.ApplicationGatewayTierWAFV2 {
+		for _, cipherSuite := range *policy.CipherSuites {
+			if _, exists := v1OnlyCipherSuites[cipherSuite]; exists {
+				glog.Errorf("Cipher suite %s is not supported by App Gateway tier %s", cipherSuite, config.Sku.Tier)
+				return ErrUnsupportedSslCipherSuite
+			}
+		}
+	}
+	return nil
+}
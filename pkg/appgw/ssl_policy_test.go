@@ -0,0 +1,86 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+)
+
+var _ = Describe("Test the SSL policy of the environment", func() {
+	var configBuilder appGwConfigBuilder
+	var cbCtx *ConfigBuilderContext
+
+	existingPolicy := &n.ApplicationGatewaySslPolicy{
+		PolicyType: n.Predefined,
+		PolicyName: n.AppGwSslPolicy20150501,
+	}
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+		configBuilder.appGw.Sku = &n.ApplicationGatewaySku{Name: n.StandardV2, Tier: n.ApplicationGatewayTierStandardV2}
+		configBuilder.appGw.SslPolicy = existingPolicy
+		cbCtx = &ConfigBuilderContext{
+			EnvVariables: environment.GetFakeEnv(),
+		}
+	})
+
+	It("keeps the existing SSL policy when none is configured", func() {
+		Expect(configBuilder.sslPolicy(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.appGw.SslPolicy).To(Equal(existingPolicy))
+	})
+
+	It("sets a predefined SSL policy", func() {
+		cbCtx.EnvVariables.SslPolicyName = "AppGwSslPolicy20170401S"
+		Expect(configBuilder.sslPolicy(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.appGw.SslPolicy).To(Equal(&n.ApplicationGatewaySslPolicy{
+			PolicyType: n.Predefined,
+			PolicyName: n.AppGwSslPolicy20170401S,
+		}))
+	})
+
+	It("sets a custom SSL policy with the cipher suites in order", func() {
+		cbCtx.EnvVariables.SslPolicyMinProtocolVersion = "TLSv1_2"
+		cbCtx.EnvVariables.SslPolicyCipherSuites = "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"
+		Expect(configBuilder.sslPolicy(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.appGw.SslPolicy).To(Equal(&n.ApplicationGatewaySslPolicy{
+			PolicyType:         n.Custom,
+			MinProtocolVersion: n.TLSv12,
+			CipherSuites: &[]n.ApplicationGatewaySslCipherSuite{
+				n.TLSECDHERSAWITHAES256GCMSHA384,
+				n.TLSECDHERSAWITHAES128GCMSHA256,
+			},
+		}))
+	})
+
+	It("rejects invalid SSL policies and keeps the existing one", func() {
+		for _, env := range []environment.EnvVariables{
+			{SslPolicyName: "AppGwSslPolicy20990101"},
+			{SslPolicyName: "AppGwSslPolicy20170401S", SslPolicyMinProtocolVersion: "TLSv1_2"},
+			{SslPolicyMinProtocolVersion: "TLSv1_2"},
+			{SslPolicyMinProtocolVersion: "TLSv1_3", SslPolicyCipherSuites: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+			{SslPolicyMinProtocolVersion: "TLSv1_2", SslPolicyCipherSuites: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,RC4"},
+		} {
+			cbCtx.EnvVariables = env
+			Expect(configBuilder.sslPolicy(cbCtx)).To(Equal(ErrInvalidSslPolicy), "%+v", env)
+			Expect(configBuilder.appGw.SslPolicy).To(Equal(existingPolicy))
+		}
+	})
+
+	It("rejects the DHE cipher suites on the v2 SKUs only", func() {
+		cbCtx.EnvVariables.SslPolicyMinProtocolVersion = "TLSv1_2"
+		cbCtx.EnvVariables.SslPolicyCipherSuites = "TLS_DHE_RSA_WITH_AES_256_GCM_SHA384"
+		Expect(FatalValidateOnExistingConfig(configBuilder.recorder, configBuilder.appGw.ApplicationGatewayPropertiesFormat, cbCtx.EnvVariables)).To(Equal(ErrUnsupportedSslCipherSuite))
+
+		configBuilder.appGw.Sku = &n.ApplicationGatewaySku{Name: n.StandardMedium, Tier: n.ApplicationGatewayTierStandard}
+		Expect(FatalValidateOnExistingConfig(configBuilder.recorder, configBuilder.appGw.ApplicationGatewayPropertiesFormat, cbCtx.EnvVariables)).ToNot(HaveOccurred())
+		Expect(configBuilder.sslPolicy(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.appGw.SslPolicy.PolicyType).To(Equal(n.Custom))
+	})
+})
@@ -8,13 +8,15 @@ package appgw
 import (
 	"fmt"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/api/core/v1"
-	v1beta1 "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 
+	rewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
@@ -62,7 +64,7 @@ func newSecretStoreFixture(toAdd *map[string]interface{}) k8scontext.SecretsKeep
 	c.Add(ingressKey, tests.Host)
 
 	key := tests.Namespace + "/" + tests.NameOfSecret
-	c.Add(key, []byte("xyz"))
+	c.Add(key, &k8scontext.PfxCertificate{Data: []byte("xyz"), Password: "msazure"})
 
 	if toAdd != nil {
 		for k, v := range *toAdd {
@@ -82,9 +84,24 @@ func keyFunc(obj interface{}) (string, error) {
 	if endpoints, ok := obj.(*v1.Endpoints); ok {
 		return fmt.Sprintf("%s/%s", endpoints.Namespace, endpoints.Name), nil
 	}
-	if ingress, ok := obj.(*v1beta1.Ingress); ok {
+	if ingress, ok := obj.(*networking.Ingress); ok {
 		return fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name), nil
 	}
+	if secret, ok := obj.(*v1.Secret); ok {
+		return fmt.Sprintf("%s/%s", secret.Namespace, secret.Name), nil
+	}
+	if configMap, ok := obj.(*v1.ConfigMap); ok {
+		return fmt.Sprintf("%s/%s", configMap.Namespace, configMap.Name), nil
+	}
+	if rewrite, ok := obj.(*rewritev1.AzureIngressRewrite); ok {
+		return fmt.Sprintf("%s/%s", rewrite.Namespace, rewrite.Name), nil
+	}
+	if pod, ok := obj.(*v1.Pod); ok {
+		return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name), nil
+	}
+	if destinationRule, ok := obj.(*v1alpha3.DestinationRule); ok {
+		return fmt.Sprintf("%s/%s", destinationRule.Namespace, destinationRule.Name), nil
+	}
 	return fmt.Sprintf("%s/%s", tests.Namespace, tests.ServiceName), nil
 }
 
@@ -99,11 +116,15 @@ func newConfigBuilderFixture(certs *map[string]interface{}) appGwConfigBuilder {
 		appGw: n.ApplicationGateway{ApplicationGatewayPropertiesFormat: appGwConfig},
 		k8sContext: &k8scontext.Context{
 			Caches: &k8scontext.CacheCollection{
+				ConfigMap: cache.NewStore(keyFunc),
 				Endpoints: cache.NewStore(keyFunc),
 				Secret:    cache.NewStore(keyFunc),
 				Service:   cache.NewStore(keyFunc),
 				Pods:      cache.NewStore(keyFunc),
 				Ingress:   cache.NewStore(keyFunc),
+
+				AzureIngressRewrite:  cache.NewStore(keyFunc),
+				IstioDestinationRule: cache.NewStore(keyFunc),
 			},
 			CertificateSecretStore: newSecretStoreFixture(certs),
 		},
@@ -143,7 +164,7 @@ func newURLPathMap() n.ApplicationGatewayURLPathMap {
 			BackendHTTPSettings: resourceRef("--BackendHTTPSettings--"),
 
 			RewriteRuleSet:    resourceRef("--RewriteRuleSet--"),
-			ProvisioningState: to.StringPtr("--provisionStateExpected--"),
+			ProvisioningState: n.Succeeded,
 		},
 	}
 
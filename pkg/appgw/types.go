@@ -9,27 +9,37 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
 )
 
 // ConfigBuilderContext holds the structs we have fetches from Kubernetes + environment, based on which
 // we will construct App Gateway config.
 type ConfigBuilderContext struct {
-	IngressList          []*v1beta1.Ingress
+	IngressList          []*networking.Ingress
 	ServiceList          []*v1.Service
 	ProhibitedTargets    []*ptv1.AzureIngressProhibitedTarget
 	EnvVariables         environment.EnvVariables
 	IstioGateways        []*v1alpha3.Gateway
 	IstioVirtualServices []*v1alpha3.VirtualService
+	GatewayAPIGateways   []*gatewayv1beta1.Gateway
+	GatewayAPIRoutes     []*gatewayv1beta1.HTTPRoute
+
+	// FrontendIPAddresses are the IP addresses of the frontend IP configurations of App Gateway, keyed by their ID.
+	FrontendIPAddresses map[string]string
+
+	// GatewayAPIConflictedListeners are the reasons why Build left out the listeners of the Gateways, keyed by
+	// k8scontext.GatewayListenerKey; filled in by Build.
+	GatewayAPIConflictedListeners map[string]string
 
 	DefaultAddressPoolID  *string
 	DefaultHTTPSettingsID *string
 }
 
 // InIngressList returns true if an ingress is in the ingress list
-func (cbCtx *ConfigBuilderContext) InIngressList(ingress *v1beta1.Ingress) bool {
+func (cbCtx *ConfigBuilderContext) InIngressList(ingress *networking.Ingress) bool {
 	for _, prunedIngress := range cbCtx.IngressList {
 		if ingress.Name == prunedIngress.Name && ingress.Namespace == prunedIngress.Namespace {
 			return true
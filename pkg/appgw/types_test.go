@@ -8,7 +8,7 @@ package appgw
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
@@ -21,7 +21,7 @@ var _ = Describe("Test ConfigBuilderContext", func() {
 	Context("test InIngressList", func() {
 		It("makes sure that ingress with different name or namespace is not true", func() {
 			cbCtx = &ConfigBuilderContext{
-				IngressList: []*v1beta1.Ingress{
+				IngressList: []*networking.Ingress{
 					&ingress1,
 					&ingress2,
 				},
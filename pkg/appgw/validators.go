@@ -9,10 +9,10 @@ import (
 	"fmt"
 	"strconv"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
@@ -26,6 +26,7 @@ const (
 	errKeyEitherBorR     = "either-backend-or-redirect"
 	errKeyNoPrivateIP    = "no-private-ip"
 	errKeyNoPublicIP     = "no-public-ip"
+	errKeyEitherTarget   = "either-redirect-target"
 )
 
 var validationErrors = map[string]error{
@@ -35,14 +36,15 @@ var validationErrors = map[string]error{
 	errKeyEitherBorR:     ErrKeyEitherBorR,
 	errKeyNoPrivateIP:    ErrKeyNoPrivateIP,
 	errKeyNoPublicIP:     ErrKeyNoPublicIP,
+	errKeyEitherTarget:   ErrKeyEitherRedirectTarget,
 }
 
-func validateServiceDefinition(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*v1beta1.Ingress, serviceList []*v1.Service) error {
+func validateServiceDefinition(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*networking.Ingress, serviceList []*v1.Service) error {
 	// TODO(draychev): reuse newBackendIds() to get backendIDs oncehttps://github.com/Azure/application-gateway-kubernetes-ingress/pull/262 is merged
 	backendIDs := make(map[backendIdentifier]interface{})
 	for _, ingress := range ingressList {
-		if ingress.Spec.Backend != nil {
-			backendIDs[generateBackendID(ingress, nil, nil, ingress.Spec.Backend)] = nil
+		if isServiceBackend(ingress.Spec.DefaultBackend) {
+			backendIDs[generateBackendID(ingress, nil, nil, ingress.Spec.DefaultBackend)] = nil
 		}
 		for ruleIdx := range ingress.Spec.Rules {
 			rule := &ingress.Spec.Rules[ruleIdx]
@@ -51,6 +53,9 @@ func validateServiceDefinition(eventRecorder record.EventRecorder, config *n.App
 			}
 			for pathIdx := range rule.HTTP.Paths {
 				path := &rule.HTTP.Paths[pathIdx]
+				if !isServiceBackend(&path.Backend) {
+					continue
+				}
 				backendIDs[generateBackendID(ingress, rule, path, &path.Backend)] = nil
 			}
 		}
@@ -69,11 +74,18 @@ func validateServiceDefinition(eventRecorder record.EventRecorder, config *n.App
 	return nil
 }
 
-func validateURLPathMaps(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*v1beta1.Ingress, serviceList []*v1.Service) error {
+func validateURLPathMaps(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*networking.Ingress, serviceList []*v1.Service) error {
 	if config.URLPathMaps == nil {
 		return nil
 	}
 
+	redirectsByID := make(map[string]n.ApplicationGatewayRedirectConfiguration)
+	if config.RedirectConfigurations != nil {
+		for _, redirect := range *config.RedirectConfigurations {
+			redirectsByID[*redirect.ID] = redirect
+		}
+	}
+
 	for _, pathMap := range *config.URLPathMaps {
 		if len(*pathMap.PathRules) == 0 {
 			// There are no paths. This is a rule of type "Basic"
@@ -90,6 +102,10 @@ func validateURLPathMaps(eventRecorder record.EventRecorder, config *n.Applicati
 				return validationErrors[errKeyEitherDefaults]
 			}
 
+			if !hasValidRedirectTarget(redirectsByID, pathMap.DefaultRedirectConfiguration) {
+				return validationErrors[errKeyEitherTarget]
+			}
+
 		} else {
 			// There are paths defined. This is a rule of type "Path-based"
 			for _, rule := range *pathMap.PathRules {
@@ -103,6 +119,10 @@ func validateURLPathMaps(eventRecorder record.EventRecorder, config *n.Applicati
 				if validRedirect && validBackend || !validRedirect && !validBackend {
 					return validationErrors[errKeyEitherBorR]
 				}
+
+				if !hasValidRedirectTarget(redirectsByID, rule.RedirectConfiguration) {
+					return validationErrors[errKeyEitherTarget]
+				}
 			}
 
 		}
@@ -110,6 +130,20 @@ func validateURLPathMaps(eventRecorder record.EventRecorder, config *n.Applicati
 	return nil
 }
 
+// hasValidRedirectTarget checks that a referenced redirect configuration redirects either to a listener or to a URL.
+func hasValidRedirectTarget(redirectsByID map[string]n.ApplicationGatewayRedirectConfiguration, redirectRef *n.SubResource) bool {
+	if redirectRef == nil || redirectRef.ID == nil {
+		return true
+	}
+	redirect, exists := redirectsByID[*redirectRef.ID]
+	if !exists || redirect.ApplicationGatewayRedirectConfigurationPropertiesFormat == nil {
+		return true
+	}
+	hasListener := redirect.TargetListener != nil
+	hasURL := redirect.TargetURL != nil && *redirect.TargetURL != ""
+	return hasListener != hasURL
+}
+
 func validateFrontendIPConfiguration(eventRecorder record.EventRecorder, config n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables) error {
 	privateIPPresent := false
 	publicIPPresent := false
@@ -146,6 +180,7 @@ func FatalValidateOnExistingConfig(eventRecorder record.EventRecorder, config *n
 
 	validators := []func(eventRecorder record.EventRecorder, config n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables) error{
 		validateFrontendIPConfiguration,
+		validateSslPolicy,
 	}
 
 	for _, fn := range validators {
@@ -8,12 +8,12 @@ package appgw
 import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/client-go/tools/record"
 )
 
@@ -23,7 +23,7 @@ var _ = Describe("Test ConfigBuilder validator functions", func() {
 	Context("test validateURLPathMaps", func() {
 
 		eventRecorder := record.NewFakeRecorder(100)
-		ingressList := []*v1beta1.Ingress{}
+		ingressList := []*networking.Ingress{}
 		serviceList := []*v1.Service{}
 		envVariables := environment.GetFakeEnv()
 
@@ -108,6 +108,28 @@ var _ = Describe("Test ConfigBuilder validator functions", func() {
 			err := validateURLPathMaps(eventRecorder, config, envVariables, ingressList, serviceList)
 			Expect(err).To(BeNil())
 		})
+
+		It("should error out when a redirect has both a target listener and a target URL", func() {
+			config.RedirectConfigurations = &[]n.ApplicationGatewayRedirectConfiguration{
+				{
+					ID: to.StringPtr("x"),
+					ApplicationGatewayRedirectConfigurationPropertiesFormat: &n.ApplicationGatewayRedirectConfigurationPropertiesFormat{
+						TargetListener: &n.SubResource{ID: to.StringPtr("y")},
+						TargetURL:      to.StringPtr("https://www.contoso.com"),
+					},
+				},
+			}
+			pathMap := n.ApplicationGatewayURLPathMap{
+				ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+					PathRules:                    &[]n.ApplicationGatewayPathRule{},
+					DefaultRedirectConfiguration: &n.SubResource{ID: to.StringPtr("x")},
+				},
+			}
+			config.URLPathMaps = &[]n.ApplicationGatewayURLPathMap{pathMap}
+			err := validateURLPathMaps(eventRecorder, config, envVariables, ingressList, serviceList)
+			Expect(err).To(Equal(ErrKeyEitherRedirectTarget))
+			config.RedirectConfigurations = nil
+		})
 	})
 
 	Context("test validateFrontendIPConfiguration", func() {
@@ -0,0 +1,55 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// supportsFirewallPolicies tells whether App Gateway can apply WAF policies to listeners and path rules; only the WAF_v2 tier can.
+// The tier is unknown when the config has no SKU, in which case App Gateway validates the policies.
+func (c *appGwConfigBuilder) supportsFirewallPolicies() bool {
+	return c.appGw.Sku == nil || c.appGw.Sku.Tier == n.ApplicationGatewayTierWAFV2
+}
+
+// getFirewallPolicy returns the resource ID of the WAF policy of the ingress; empty when it has none, or App Gateway cannot apply it.
+// The events for the ingress are emitted once per config build.
+func (c *appGwConfigBuilder) getFirewallPolicy(ingress *networking.Ingress) string {
+	if c.mem.firewallPolicies == nil {
+		policies := make(map[string]string)
+		c.mem.firewallPolicies = &policies
+	}
+	ingressKey := utils.GetResourceKey(ingress.Namespace, ingress.Name)
+	if policyID, exists := (*c.mem.firewallPolicies)[ingressKey]; exists {
+		return policyID
+	}
+
+	policyID, err := annotations.WAFPolicy(ingress)
+	if err != nil {
+		if !errors.IsMissingAnnotations(err) {
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, err.Error())
+		}
+		policyID = ""
+	} else if !c.supportsFirewallPolicies() {
+		logLine := fmt.Sprintf("Ignoring WAF policy %s; App Gateway tier %s does not support WAF policies, %s does", policyID, c.appGw.Sku.Tier, n.ApplicationGatewayTierWAFV2)
+		glog.Error(logLine)
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonUnsupportedWAFPolicy, logLine)
+		policyID = ""
+	}
+
+	(*c.mem.firewallPolicies)[ingressKey] = policyID
+	return policyID
+}
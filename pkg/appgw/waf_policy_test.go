@@ -0,0 +1,121 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test the WAF policies of listeners and path rules", func() {
+	policyID := "/subscriptions/subid/resourceGroups/rg/providers/Microsoft.Network/ApplicationGatewayWebApplicationFirewallPolicies/prevention"
+
+	var configBuilder appGwConfigBuilder
+	var ingress *networking.Ingress
+	var cbCtx *ConfigBuilderContext
+
+	BeforeEach(func() {
+		configBuilder = newConfigBuilderFixture(nil)
+		configBuilder.appGw.Sku = &n.ApplicationGatewaySku{Name: n.WAFV2, Tier: n.ApplicationGatewayTierWAFV2}
+		endpoint := tests.NewEndpointsFixture()
+		service := tests.NewServiceFixture(*tests.NewServicePortsFixture()...)
+		ingress = tests.NewIngressFixture()
+		ingress.Annotations[annotations.SslRedirectKey] = "false"
+		ingress.Annotations[annotations.WAFPolicyKey] = policyID
+		_ = configBuilder.k8sContext.Caches.Endpoints.Add(endpoint)
+		_ = configBuilder.k8sContext.Caches.Service.Add(service)
+		_ = configBuilder.k8sContext.Caches.Ingress.Add(ingress)
+
+		cbCtx = &ConfigBuilderContext{
+			IngressList:  []*networking.Ingress{ingress},
+			ServiceList:  []*v1.Service{service},
+			EnvVariables: environment.GetFakeEnv(),
+		}
+	})
+
+	build := func() {
+		Expect(configBuilder.BackendHTTPSettingsCollection(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.BackendAddressPools(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.Listeners(cbCtx)).ToNot(HaveOccurred())
+		Expect(configBuilder.RequestRoutingRules(cbCtx)).ToNot(HaveOccurred())
+	}
+
+	// pathRules returns the path rules of all URL path maps.
+	pathRules := func() []n.ApplicationGatewayPathRule {
+		var rules []n.ApplicationGatewayPathRule
+		for _, pathMap := range *configBuilder.appGw.URLPathMaps {
+			if pathMap.PathRules != nil {
+				rules = append(rules, *pathMap.PathRules...)
+			}
+		}
+		Expect(rules).ToNot(BeEmpty())
+		return rules
+	}
+
+	Context("with a WAF_v2 SKU", func() {
+		It("attaches the policy to the listeners and path rules of the ingress", func() {
+			build()
+
+			Expect(*configBuilder.appGw.HTTPListeners).ToNot(BeEmpty())
+			for _, listener := range *configBuilder.appGw.HTTPListeners {
+				Expect(*listener.FirewallPolicy.ID).To(Equal(policyID))
+			}
+			for _, pathRule := range pathRules() {
+				Expect(*pathRule.FirewallPolicy.ID).To(Equal(policyID))
+			}
+		})
+
+		It("attaches no policy without the annotation", func() {
+			delete(ingress.Annotations, annotations.WAFPolicyKey)
+			build()
+
+			for _, listener := range *configBuilder.appGw.HTTPListeners {
+				Expect(listener.FirewallPolicy).To(BeNil())
+			}
+			for _, pathRule := range pathRules() {
+				Expect(pathRule.FirewallPolicy).To(BeNil())
+			}
+		})
+
+		It("emits an event when the annotation is not a WAF policy resource ID", func() {
+			ingress.Annotations[annotations.WAFPolicyKey] = "prevention"
+			build()
+
+			for _, listener := range *configBuilder.appGw.HTTPListeners {
+				Expect(listener.FirewallPolicy).To(BeNil())
+			}
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonInvalidAnnotation))
+		})
+	})
+
+	Context("with a Standard_v2 SKU", func() {
+		It("ignores the policy and emits an event", func() {
+			configBuilder.appGw.Sku = &n.ApplicationGatewaySku{Name: n.StandardV2, Tier: n.ApplicationGatewayTierStandardV2}
+			build()
+
+			for _, listener := range *configBuilder.appGw.HTTPListeners {
+				Expect(listener.FirewallPolicy).To(BeNil())
+			}
+			for _, pathRule := range pathRules() {
+				Expect(pathRule.FirewallPolicy).To(BeNil())
+			}
+			recorder := configBuilder.recorder.(*record.FakeRecorder)
+			Expect(len(recorder.Events)).To(Equal(1))
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonUnsupportedWAFPolicy))
+		})
+	})
+})
@@ -10,7 +10,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 )
 
 var _ = Describe("Test MergeCerts", func() {
@@ -29,5 +30,25 @@ var _ = Describe("Test MergeCerts", func() {
 			Expect(actual).To(ContainElement(fixtures.GetCertificate2()))
 			Expect(actual).To(ContainElement(fixtures.GetCertificate3()))
 		})
+
+		It("should keep certificates from Key Vault and replace existing certificates of the other kind", func() {
+			existingPfx := fixtures.GetCertificate1()
+			existingPfx.ApplicationGatewaySslCertificatePropertiesFormat = &n.ApplicationGatewaySslCertificatePropertiesFormat{
+				PublicCertData: to.StringPtr("--public-cert-data--"),
+			}
+			existingKeyVault := fixtures.GetCertificate2()
+			existingKeyVault.ApplicationGatewaySslCertificatePropertiesFormat = &n.ApplicationGatewaySslCertificatePropertiesFormat{
+				KeyVaultSecretID: to.StringPtr("https://contoso.vault.azure.net/secrets/cert-2"),
+			}
+			generatedKeyVault := fixtures.GetCertificate1()
+			generatedKeyVault.ApplicationGatewaySslCertificatePropertiesFormat = &n.ApplicationGatewaySslCertificatePropertiesFormat{
+				KeyVaultSecretID: to.StringPtr("https://contoso.vault.azure.net/secrets/cert-1"),
+			}
+
+			actual := MergeCerts([]n.ApplicationGatewaySslCertificate{existingPfx, existingKeyVault}, []n.ApplicationGatewaySslCertificate{generatedKeyVault})
+			Expect(actual).To(HaveLen(2))
+			Expect(actual).To(ContainElement(existingKeyVault))
+			Expect(actual).To(ContainElement(generatedKeyVault))
+		})
 	})
 })
@@ -7,7 +7,7 @@ package brownfield
 
 import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/mocks"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -29,7 +29,7 @@ var _ = Describe("Test blacklist health probes", func() {
 
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // /fox  /bar
 
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			blacklisted, nonBlacklisted := er.GetBlacklistedProbes()
 
@@ -52,7 +52,7 @@ var _ = Describe("Test blacklist health probes", func() {
 			}
 			prohibitedTargets := append(fixtures.GetAzureIngressProhibitedTargets(), wildcard)
 
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			// Everything is blacklisted
 			blacklisted, nonBlacklisted := er.GetBlacklistedProbes()
@@ -96,7 +96,7 @@ var _ = Describe("Test blacklist health probes", func() {
 	Context("Test getBlacklistedProbesSet()", func() {
 		It("should create a set of blacklisted probes", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			set := er.getBlacklistedProbesSet()
 			Expect(len(set)).To(Equal(2))
 			_, exists := set[fixtures.ProbeName1]
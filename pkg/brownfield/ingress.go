@@ -6,13 +6,23 @@
 package brownfield
 
 import (
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 )
 
+// ListenerFrontend is the frontend IP address and port of a listener; an empty IP address is not known.
+type ListenerFrontend struct {
+	IP   string
+	Port int32
+}
+
+// RuleFrontends returns the frontends of the listeners AGIC creates for the rule of an ingress.
+type RuleFrontends func(rule *networking.IngressRule) []ListenerFrontend
+
 // PruneIngressRules transforms the given ingress struct to remove targets, which AGIC should not create configuration for.
-func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget) []v1beta1.IngressRule {
+// A rule or path is removed when the target on any of the frontends of the rule is blacklisted.
+func PruneIngressRules(ing *networking.Ingress, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, getFrontends RuleFrontends) []networking.IngressRule {
 
 	if ing.Spec.Rules == nil || len(ing.Spec.Rules) == 0 {
 		return ing.Spec.Rules
@@ -24,34 +34,50 @@ func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngr
 		return ing.Spec.Rules
 	}
 
-	var rules []v1beta1.IngressRule
+	var rules []networking.IngressRule
 
 	for _, rule := range ing.Spec.Rules {
 		if rule.HTTP == nil {
 			continue
 		}
+		frontends := getFrontends(&rule)
+		if len(frontends) == 0 {
+			// The frontend is not known
+			frontends = []ListenerFrontend{{}}
+		}
+		isBlacklisted := func(target Target) bool {
+			for _, frontend := range frontends {
+				target.IP = frontend.IP
+				target.Port = frontend.Port
+				if target.IsBlacklisted(blacklist) {
+					return true
+				}
+			}
+			return false
+		}
+
 		target := Target{
 			Hostname: rule.Host,
 		}
 		if rule.HTTP.Paths == nil {
-			if target.IsBlacklisted(blacklist) {
+			if isBlacklisted(target) {
 				continue
 			}
 			rules = append(rules, rule)
 			continue // to next rule
 		}
 
-		newRule := v1beta1.IngressRule{
+		newRule := networking.IngressRule{
 			Host: rule.Host,
-			IngressRuleValue: v1beta1.IngressRuleValue{
-				HTTP: &v1beta1.HTTPIngressRuleValue{
-					Paths: []v1beta1.HTTPIngressPath{},
+			IngressRuleValue: networking.IngressRuleValue{
+				HTTP: &networking.HTTPIngressRuleValue{
+					Paths: []networking.HTTPIngressPath{},
 				},
 			},
 		}
 		for _, path := range rule.HTTP.Paths {
 			target.Path = TargetPath(path.Path)
-			if target.IsBlacklisted(blacklist) {
+			if isBlacklisted(target) {
 				continue
 			}
 			newRule.HTTP.Paths = append(newRule.HTTP.Paths, path)
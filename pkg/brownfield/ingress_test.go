@@ -8,9 +8,9 @@ package brownfield
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"k8s.io/api/extensions/v1beta1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	networking "k8s.io/api/networking/v1"
 
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -20,39 +20,41 @@ var _ = Describe("test pruning Ingress based on white/white lists", func() {
 	Context("Test PruneIngressRules()", func() {
 		prohibited := fixtures.GetAzureIngressProhibitedTargets()
 
-		ingress := v1beta1.Ingress{
-			Spec: v1beta1.IngressSpec{
-				Rules: []v1beta1.IngressRule{
+		ingress := networking.Ingress{
+			Spec: networking.IngressSpec{
+				Rules: []networking.IngressRule{
 					{
 						// Rule with no Paths
 						Host: tests.OtherHost,
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{},
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{},
 						},
 					},
 					{
 						// Rule with Paths
 						Host: tests.Host,
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{
-								Paths: []v1beta1.HTTPIngressPath{
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{
+								Paths: []networking.HTTPIngressPath{
 									{
 										Path: fixtures.PathFoo,
-										Backend: v1beta1.IngressBackend{
-											ServiceName: tests.ServiceName,
-											ServicePort: intstr.IntOrString{
-												Type:   intstr.Int,
-												IntVal: 80,
+										Backend: networking.IngressBackend{
+											Service: &networking.IngressServiceBackend{
+												Name: tests.ServiceName,
+												Port: networking.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
 									{
 										Path: fixtures.PathFox,
-										Backend: v1beta1.IngressBackend{
-											ServiceName: tests.ServiceName,
-											ServicePort: intstr.IntOrString{
-												Type:   intstr.Int,
-												IntVal: 443,
+										Backend: networking.IngressBackend{
+											Service: &networking.IngressServiceBackend{
+												Name: tests.ServiceName,
+												Port: networking.ServiceBackendPort{
+													Number: 443,
+												},
 											},
 										},
 									},
@@ -64,24 +66,28 @@ var _ = Describe("test pruning Ingress based on white/white lists", func() {
 			},
 		}
 
-		actualRules := PruneIngressRules(&ingress, prohibited)
+		getFrontends := func(rule *networking.IngressRule) []ListenerFrontend {
+			return []ListenerFrontend{{IP: "1.2.3.4", Port: 80}}
+		}
+		actualRules := PruneIngressRules(&ingress, prohibited, getFrontends)
 
-		expected := v1beta1.Ingress{
-			Spec: v1beta1.IngressSpec{
-				Rules: []v1beta1.IngressRule{
+		expected := networking.Ingress{
+			Spec: networking.IngressSpec{
+				Rules: []networking.IngressRule{
 					{
 						// Should have kept one of the Paths of this Rule
 						Host: tests.Host,
-						IngressRuleValue: v1beta1.IngressRuleValue{
-							HTTP: &v1beta1.HTTPIngressRuleValue{
-								Paths: []v1beta1.HTTPIngressPath{
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{
+								Paths: []networking.HTTPIngressPath{
 									{
 										Path: fixtures.PathFoo,
-										Backend: v1beta1.IngressBackend{
-											ServiceName: tests.ServiceName,
-											ServicePort: intstr.IntOrString{
-												Type:   intstr.Int,
-												IntVal: 80,
+										Backend: networking.IngressBackend{
+											Service: &networking.IngressServiceBackend{
+												Name: tests.ServiceName,
+												Port: networking.ServiceBackendPort{
+													Number: 80,
+												},
 											},
 										},
 									},
@@ -98,4 +104,62 @@ var _ = Describe("test pruning Ingress based on white/white lists", func() {
 		})
 	})
 
+	Context("Test PruneIngressRules() with a prohibited frontend IP and port", func() {
+		prohibited := []*ptv1.AzureIngressProhibitedTarget{
+			{
+				Spec: ptv1.AzureIngressProhibitedTargetSpec{
+					IP:   "10.0.0.4",
+					Port: 8443,
+				},
+			},
+		}
+
+		ingress := networking.Ingress{
+			Spec: networking.IngressSpec{
+				Rules: []networking.IngressRule{
+					{
+						Host: tests.Host,
+						IngressRuleValue: networking.IngressRuleValue{
+							HTTP: &networking.HTTPIngressRuleValue{
+								Paths: []networking.HTTPIngressPath{
+									{
+										Path: fixtures.PathFoo,
+										Backend: networking.IngressBackend{
+											Service: &networking.IngressServiceBackend{
+												Name: tests.ServiceName,
+												Port: networking.ServiceBackendPort{
+													Number: 80,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		frontendsOf := func(frontends ...ListenerFrontend) RuleFrontends {
+			return func(rule *networking.IngressRule) []ListenerFrontend {
+				return frontends
+			}
+		}
+
+		It("should remove the rules routed onto the prohibited port of the IP", func() {
+			Expect(PruneIngressRules(&ingress, prohibited, frontendsOf(ListenerFrontend{IP: "10.0.0.4", Port: 8443}))).To(BeEmpty())
+			Expect(PruneIngressRules(&ingress, prohibited, frontendsOf(ListenerFrontend{IP: "10.0.0.4", Port: 80}, ListenerFrontend{IP: "10.0.0.4", Port: 8443}))).To(BeEmpty())
+		})
+
+		It("should remove the rules on a frontend IP with an unknown address", func() {
+			Expect(PruneIngressRules(&ingress, prohibited, frontendsOf(ListenerFrontend{Port: 8443}))).To(BeEmpty())
+		})
+
+		It("should keep the rules on other ports and IPs", func() {
+			Expect(PruneIngressRules(&ingress, prohibited, frontendsOf(ListenerFrontend{IP: "10.0.0.4", Port: 443}))).To(Equal(ingress.Spec.Rules))
+			Expect(PruneIngressRules(&ingress, prohibited, frontendsOf(ListenerFrontend{IP: "1.2.3.4", Port: 8443}))).To(Equal(ingress.Spec.Rules))
+		})
+	})
+
 })
@@ -8,7 +8,7 @@ package brownfield
 import (
 	"strings"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/golang/glog"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
@@ -129,6 +129,14 @@ func (er ExistingResources) getBlacklistedListenersSet() map[listenerName]interf
 		}
 	}
 
+	// Prohibit the listeners on the frontend IPs and ports of the prohibited targets
+	prohibitedFrontendTargets := er.getProhibitedFrontendTargets()
+	for _, listener := range er.Listeners {
+		if er.getListenerTarget(listener).IsBlacklisted(prohibitedFrontendTargets) {
+			blacklistedListenersSet[listenerName(*listener.Name)] = nil
+		}
+	}
+
 	// Augment the list of prohibited listeners by looking at the rules
 	blacklistedRoutingRules, _ := er.GetBlacklistedRoutingRules()
 	for _, rule := range blacklistedRoutingRules {
@@ -139,3 +147,41 @@ func (er ExistingResources) getBlacklistedListenersSet() map[listenerName]interf
 	}
 	return blacklistedListenersSet
 }
+
+// getListenerTarget returns the Target of the host name, frontend IP and frontend port of the listener. The IP or port is left
+// empty when it is not known.
+func (er ExistingResources) getListenerTarget(listener n.ApplicationGatewayHTTPListener) Target {
+	var target Target
+	if listener.HostName != nil {
+		target.Hostname = *listener.HostName
+	}
+	if listener.FrontendIPConfiguration != nil && listener.FrontendIPConfiguration.ID != nil {
+		target.IP = er.getFrontendIPAddress(*listener.FrontendIPConfiguration.ID)
+	}
+	if target.IP == "" {
+		glog.V(3).Infof("[brownfield] The frontend IP address of listener %s is not known", *listener.Name)
+	}
+	if listener.FrontendPort != nil && listener.FrontendPort.ID != nil {
+		portName := utils.GetLastChunkOfSlashed(*listener.FrontendPort.ID)
+		for _, port := range er.Ports {
+			if port.Name != nil && *port.Name == portName && port.Port != nil {
+				target.Port = *port.Port
+			}
+		}
+	}
+	return target
+}
+
+// getFrontendIPAddress returns the private IP address of the frontend IP configuration, or the address of its public IP;
+// empty when the address of the public IP was not looked up.
+func (er ExistingResources) getFrontendIPAddress(ipConfID string) string {
+	for _, ipConf := range er.FrontendIPConfigurations {
+		if ipConf.ID == nil || *ipConf.ID != ipConfID {
+			continue
+		}
+		if ipConf.ApplicationGatewayFrontendIPConfigurationPropertiesFormat != nil && ipConf.PrivateIPAddress != nil {
+			return *ipConf.PrivateIPAddress
+		}
+	}
+	return er.FrontendIPAddresses[ipConfID]
+}
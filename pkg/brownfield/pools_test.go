@@ -6,7 +6,7 @@
 package brownfield
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -57,7 +57,7 @@ var _ = Describe("Test blacklisting backend pools", func() {
 
 	prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
 
-	brownfieldContext := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+	brownfieldContext := NewExistingResources(appGw, prohibitedTargets, &defaultPool, nil)
 
 	prohibitWildcard := &ptv1.AzureIngressProhibitedTarget{
 		Spec: ptv1.AzureIngressProhibitedTargetSpec{},
@@ -124,7 +124,7 @@ var _ = Describe("Test blacklisting backend pools", func() {
 
 		It("blacklists everything linked to a listener", func() {
 			prohibitedTargets := append(fixtures.GetAzureIngressProhibitedTargets(), prohibitWildcard)
-			bfCtx := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+			bfCtx := NewExistingResources(appGw, prohibitedTargets, &defaultPool, nil)
 			blacklisted, notBlacklisted := bfCtx.GetBlacklistedPools()
 
 			Expect(len(blacklisted)).To(Equal(3))
@@ -10,7 +10,7 @@ import (
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/golang/glog"
 )
 
@@ -7,7 +7,7 @@ package brownfield
 
 import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -25,7 +25,7 @@ var _ = Describe("Test GetBlacklistedRedirects", func() {
 	}
 	appGw := fixtures.GetAppGateway()
 
-	er := NewExistingResources(appGw, prohibitedTargets, nil)
+	er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 	Context("Test GetBlacklistedRedirects()", func() {
 		It("should work as expected", func() {
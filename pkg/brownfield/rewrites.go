@@ -0,0 +1,118 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/golang/glog"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+type rewriteRuleSetName string
+type rewriteRuleSetsByName map[rewriteRuleSetName]n.ApplicationGatewayRewriteRuleSet
+
+// GetBlacklistedRewriteRuleSets removes the managed rewrite rule sets from the given list; resulting in a list of rewrite rule sets not managed by AGIC.
+func (er ExistingResources) GetBlacklistedRewriteRuleSets() ([]n.ApplicationGatewayRewriteRuleSet, []n.ApplicationGatewayRewriteRuleSet) {
+	blacklisted := er.getBlacklistedRewriteRuleSetsSet()
+	var blacklistedRuleSets []n.ApplicationGatewayRewriteRuleSet
+	var nonBlacklistedRuleSets []n.ApplicationGatewayRewriteRuleSet
+	for _, ruleSet := range er.RewriteRuleSets {
+		if _, isBlacklisted := blacklisted[rewriteRuleSetName(*ruleSet.Name)]; isBlacklisted {
+			blacklistedRuleSets = append(blacklistedRuleSets, ruleSet)
+			glog.V(5).Infof("[brownfield] Rewrite rule set %s is blacklisted", *ruleSet.Name)
+			continue
+		}
+		glog.V(5).Infof("[brownfield] Rewrite rule set %s is not blacklisted", *ruleSet.Name)
+		nonBlacklistedRuleSets = append(nonBlacklistedRuleSets, ruleSet)
+	}
+	return blacklistedRuleSets, nonBlacklistedRuleSets
+}
+
+// LogRewriteRuleSets emits a few log lines detailing what rewrite rule sets are created, blacklisted, and removed from ARM.
+func LogRewriteRuleSets(existingBlacklisted []n.ApplicationGatewayRewriteRuleSet, existingNonBlacklisted []n.ApplicationGatewayRewriteRuleSet, managedRuleSets []n.ApplicationGatewayRewriteRuleSet) {
+	var garbage []n.ApplicationGatewayRewriteRuleSet
+
+	blacklistedSet := indexRewriteRuleSetsByName(existingBlacklisted)
+	managedSet := indexRewriteRuleSetsByName(managedRuleSets)
+
+	for ruleSetName, ruleSet := range indexRewriteRuleSetsByName(existingNonBlacklisted) {
+		_, existsInBlacklist := blacklistedSet[ruleSetName]
+		_, existsInNewRuleSets := managedSet[ruleSetName]
+		if !existsInBlacklist && !existsInNewRuleSets {
+			garbage = append(garbage, ruleSet)
+		}
+	}
+
+	glog.V(3).Info("[brownfield] Rewrite rule sets AGIC created: ", getRewriteRuleSetNames(managedRuleSets))
+	glog.V(3).Info("[brownfield] Existing Blacklisted Rewrite rule sets AGIC will retain: ", getRewriteRuleSetNames(existingBlacklisted))
+	glog.V(3).Info("[brownfield] Existing Rewrite rule sets AGIC will remove: ", getRewriteRuleSetNames(garbage))
+}
+
+// MergeRewriteRuleSets merges list of lists of rewrite rule sets into a single list, maintaining uniqueness.
+func MergeRewriteRuleSets(ruleSetBuckets ...[]n.ApplicationGatewayRewriteRuleSet) []n.ApplicationGatewayRewriteRuleSet {
+	uniqRuleSets := make(rewriteRuleSetsByName)
+	for _, bucket := range ruleSetBuckets {
+		for _, ruleSet := range bucket {
+			uniqRuleSets[rewriteRuleSetName(*ruleSet.Name)] = ruleSet
+		}
+	}
+	var merged []n.ApplicationGatewayRewriteRuleSet
+	for _, ruleSet := range uniqRuleSets {
+		merged = append(merged, ruleSet)
+	}
+	return merged
+}
+
+func getRewriteRuleSetNames(ruleSets []n.ApplicationGatewayRewriteRuleSet) string {
+	var names []string
+	for _, ruleSet := range ruleSets {
+		names = append(names, *ruleSet.Name)
+	}
+	if len(names) == 0 {
+		return "n/a"
+	}
+	return strings.Join(names, ", ")
+}
+
+func indexRewriteRuleSetsByName(ruleSets []n.ApplicationGatewayRewriteRuleSet) rewriteRuleSetsByName {
+	indexed := make(rewriteRuleSetsByName)
+	for _, ruleSet := range ruleSets {
+		indexed[rewriteRuleSetName(*ruleSet.Name)] = ruleSet
+	}
+	return indexed
+}
+
+// getBlacklistedRewriteRuleSetsSet returns the rewrite rule sets used by the routing rules and path maps AGIC is not allowed to change.
+func (er ExistingResources) getBlacklistedRewriteRuleSetsSet() map[rewriteRuleSetName]interface{} {
+	blacklistedRoutingRules, _ := er.GetBlacklistedRoutingRules()
+	blacklisted := make(map[rewriteRuleSetName]interface{})
+	for _, rule := range blacklistedRoutingRules {
+		if rule.RewriteRuleSet != nil && rule.RewriteRuleSet.ID != nil {
+			blacklisted[rewriteRuleSetName(utils.GetLastChunkOfSlashed(*rule.RewriteRuleSet.ID))] = nil
+		}
+	}
+
+	blacklistedPathMaps, _ := er.GetBlacklistedPathMaps()
+	for _, pathMap := range blacklistedPathMaps {
+		if pathMap.DefaultRewriteRuleSet != nil && pathMap.DefaultRewriteRuleSet.ID != nil {
+			blacklisted[rewriteRuleSetName(utils.GetLastChunkOfSlashed(*pathMap.DefaultRewriteRuleSet.ID))] = nil
+		}
+		if pathMap.PathRules == nil {
+			glog.Errorf("PathMap %s does not have PathRules", *pathMap.Name)
+			continue
+		}
+		for _, rule := range *pathMap.PathRules {
+			if rule.RewriteRuleSet != nil && rule.RewriteRuleSet.ID != nil {
+				blacklisted[rewriteRuleSetName(utils.GetLastChunkOfSlashed(*rule.RewriteRuleSet.ID))] = nil
+			}
+		}
+	}
+
+	return blacklisted
+}
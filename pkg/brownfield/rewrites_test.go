@@ -0,0 +1,69 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test GetBlacklistedRewriteRuleSets", func() {
+
+	prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
+
+	ruleSets := []n.ApplicationGatewayRewriteRuleSet{
+		{
+			Name: to.StringPtr("rewrite-security-team"),
+			ApplicationGatewayRewriteRuleSetPropertiesFormat: &n.ApplicationGatewayRewriteRuleSetPropertiesFormat{},
+		},
+		{
+			Name: to.StringPtr("rewrite-stale"),
+			ApplicationGatewayRewriteRuleSetPropertiesFormat: &n.ApplicationGatewayRewriteRuleSetPropertiesFormat{},
+		},
+	}
+
+	appGw := fixtures.GetAppGateway()
+	appGw.RewriteRuleSets = &ruleSets
+	// The basic routing rule is on a prohibited target.
+	for idx, rule := range *appGw.RequestRoutingRules {
+		if *rule.Name == *fixtures.GetRequestRoutingRuleBasic().Name {
+			(*appGw.RequestRoutingRules)[idx].RewriteRuleSet = &n.SubResource{ID: to.StringPtr("x/y/z/rewrite-security-team")}
+		}
+	}
+
+	er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
+
+	Context("Test GetBlacklistedRewriteRuleSets()", func() {
+		It("should retain the rewrite rule sets of prohibited targets", func() {
+			blacklistedRuleSets, nonBlacklistedRuleSets := er.GetBlacklistedRewriteRuleSets()
+			Expect(blacklistedRuleSets).To(Equal([]n.ApplicationGatewayRewriteRuleSet{ruleSets[0]}))
+			Expect(nonBlacklistedRuleSets).To(Equal([]n.ApplicationGatewayRewriteRuleSet{ruleSets[1]}))
+		})
+	})
+
+	Context("Test MergeRewriteRuleSets()", func() {
+		It("should produce a list of unique rewrite rule sets", func() {
+			managed := n.ApplicationGatewayRewriteRuleSet{Name: to.StringPtr("rewrite-security-team")}
+			merged := MergeRewriteRuleSets([]n.ApplicationGatewayRewriteRuleSet{ruleSets[0]}, []n.ApplicationGatewayRewriteRuleSet{managed})
+			Expect(merged).To(Equal([]n.ApplicationGatewayRewriteRuleSet{managed}))
+		})
+	})
+
+	Context("Test indexRewriteRuleSetsByName()", func() {
+		It("should create a set of the index names", func() {
+			actual := indexRewriteRuleSetsByName(ruleSets)
+			expected := rewriteRuleSetsByName{
+				"rewrite-security-team": ruleSets[0],
+				"rewrite-stale":         ruleSets[1],
+			}
+			Expect(actual).To(Equal(expected))
+		})
+	})
+
+})
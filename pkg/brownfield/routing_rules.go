@@ -8,7 +8,7 @@ package brownfield
 import (
 	"strings"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/golang/glog"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
@@ -149,21 +149,20 @@ func indexRulesByName(rules []n.ApplicationGatewayRequestRoutingRule) rulesByNam
 	return indexed
 }
 
-func (er ExistingResources) getHostNameForRoutingRule(rule n.ApplicationGatewayRequestRoutingRule) (string, error) {
+func (er ExistingResources) getTargetForRoutingRule(rule n.ApplicationGatewayRequestRoutingRule) (Target, error) {
 	listenerName := listenerName(utils.GetLastChunkOfSlashed(*rule.HTTPListener.ID))
-	if listener, found := er.getListenersByName()[listenerName]; !found {
+	listener, found := er.getListenersByName()[listenerName]
+	if !found {
 		glog.Errorf("[brownfield] Could not find listener %s in index", listenerName)
 		// TODO(draychev): move this error into a top-level file
-		return "", ErrListenerLookup
-	} else if listener.HostName != nil {
-		return *listener.HostName, nil
+		return Target{}, ErrListenerLookup
 	}
-	return "", nil
+	return er.getListenerTarget(listener), nil
 }
 
 // getRuleToTargets creates a map from backend pool to targets this backend pool is responsible for.
 // We rely on the configuration that AGIC has already constructed: Frontend Listener, Routing Rules, etc.
-// We use the Listener to obtain the target hostname, the RoutingRule to get the URL etc.
+// We use the Listener to obtain the target hostname, frontend IP and port, the RoutingRule to get the URL etc.
 func (er ExistingResources) getRuleToTargets() (ruleToTargets, pathmapToTargets) {
 	ruleToTargets := make(ruleToTargets)
 	pathMapToTargets := make(pathmapToTargets)
@@ -171,17 +170,15 @@ func (er ExistingResources) getRuleToTargets() (ruleToTargets, pathmapToTargets)
 		if rule.HTTPListener == nil || rule.HTTPListener.ID == nil {
 			continue
 		}
-		hostName, err := er.getHostNameForRoutingRule(rule)
+		listenerTarget, err := er.getTargetForRoutingRule(rule)
 		if err != nil {
-			glog.Errorf("[brownfield] Could not obtain hostname for rule %s; Skipping rule", ruleName(*rule.Name))
+			glog.Errorf("[brownfield] Could not obtain target for rule %s; Skipping rule", ruleName(*rule.Name))
 			continue
 		}
 
 		// Regardless of whether we have a URL PathMap or not. This matches the default backend pool.
-		ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], Target{
-			Hostname: hostName,
-			// Path deliberately omitted
-		})
+		// Path deliberately omitted
+		ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], listenerTarget)
 
 		// SSL Redirects do not have BackendAddressPool
 		if rule.URLPathMap != nil {
@@ -193,7 +190,8 @@ func (er ExistingResources) getRuleToTargets() (ruleToTargets, pathmapToTargets)
 					continue
 				}
 				for _, path := range *pathRule.Paths {
-					target := Target{hostName, TargetPath(path)}
+					target := listenerTarget
+					target.Path = TargetPath(path)
 					ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], target)
 					pathMapToTargets[pathMapName] = append(pathMapToTargets[pathMapName], target)
 				}
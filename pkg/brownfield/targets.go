@@ -23,12 +23,15 @@ type TargetPath string
 // Target uniquely identifies a subset of App Gateway configuration, which AGIC will manage or be prohibited from managing.
 type Target struct {
 	Hostname string     `json:"Hostname,omitempty"`
+	IP       string     `json:"IP,omitempty"`
+	Port     int32      `json:"Port,omitempty"`
 	Path     TargetPath `json:"Path,omitempty"`
 }
 
 // IsBlacklisted figures out whether a given Target objects in a list of blacklisted targets.
 func (t Target) IsBlacklisted(blacklist TargetBlacklist) bool {
 	jsonTarget, _ := json.Marshal(t)
+	blacklistedByUnknownIP := false
 	for _, blTarget := range *blacklist {
 
 		// An empty blacklist hostname indicates that any hostname would be blacklisted.
@@ -36,16 +39,31 @@ func (t Target) IsBlacklisted(blacklist TargetBlacklist) bool {
 		// AGIC is allowed to create and modify App Gwy config for blank host.
 		hostIsBlacklisted := blTarget.Hostname == "" || strings.ToLower(t.Hostname) == strings.ToLower(blTarget.Hostname)
 
+		// An empty blacklist IP or port indicates that any frontend IP or port would be blacklisted.
+		// A target, whose frontend IP or port is not known, is blacklisted by any IP or port; AGIC fails closed
+		// rather than overwrite config, which may be prohibited.
+		ipIsBlacklisted := blTarget.IP == "" || t.IP == "" || t.IP == blTarget.IP
+		portIsBlacklisted := blTarget.Port == 0 || t.Port == 0 || t.Port == blTarget.Port
+
 		pathIsBlacklisted := blTarget.Path == "" || blTarget.Path == "/*" || t.Path.lower() == blTarget.Path.lower() || blTarget.Path.contains(t.Path) // TODO(draychev): || t.Path.contains(blTarget.Path)
 
 		// With this version we keep things as simple as possible: match host and exact path to determine
 		// whether given target is in the blacklist. Ideally this would be URL Path set overlap operation,
 		// which we deliberately leave for a later time.
-		if hostIsBlacklisted && pathIsBlacklisted {
+		if hostIsBlacklisted && ipIsBlacklisted && portIsBlacklisted && pathIsBlacklisted {
+			if blTarget.IP != "" && t.IP == "" {
+				// Look for a target, which blacklists this one regardless of its IP.
+				blacklistedByUnknownIP = true
+				continue
+			}
 			glog.V(5).Infof("[brownfield] Target %s is blacklisted", jsonTarget)
 			return true // Found it
 		}
 	}
+	if blacklistedByUnknownIP {
+		glog.Warningf("[brownfield] Target %s is blacklisted only because its frontend IP address is not known", jsonTarget)
+		return true
+	}
 	glog.V(5).Infof("[brownfield] Target %s is not blacklisted", jsonTarget)
 	return false // Did not find it
 }
@@ -58,11 +76,15 @@ func GetTargetBlacklist(prohibitedTargets []*ptv1.AzureIngressProhibitedTarget)
 		if len(prohibitedTarget.Spec.Paths) == 0 {
 			target = append(target, Target{
 				Hostname: prohibitedTarget.Spec.Hostname,
+				IP:       prohibitedTarget.Spec.IP,
+				Port:     prohibitedTarget.Spec.Port,
 			})
 		}
 		for _, path := range prohibitedTarget.Spec.Paths {
 			target = append(target, Target{
 				Hostname: prohibitedTarget.Spec.Hostname,
+				IP:       prohibitedTarget.Spec.IP,
+				Port:     prohibitedTarget.Spec.Port,
 				Path:     TargetPath(strings.ToLower(path)),
 			})
 		}
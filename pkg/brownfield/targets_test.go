@@ -7,6 +7,8 @@ package brownfield
 
 import (
 	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -88,6 +90,175 @@ var _ = Describe("Test blacklisting targets", func() {
 		})
 	})
 
+	Context("Test IsBlacklisted with frontend IP and port", func() {
+		blacklist := []Target{
+			{
+				IP:   "10.0.0.1",
+				Port: 8443,
+			},
+			{
+				Hostname: tests.OtherHost,
+				Port:     443,
+				Path:     fixtures.PathBar,
+			},
+		}
+
+		It("should match the frontend IP and port of the blacklisted targets", func() {
+			Expect(Target{Hostname: tests.Host, IP: "10.0.0.1", Port: 8443}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{IP: "10.0.0.1", Port: 8443, Path: fixtures.PathFoo}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.OtherHost, IP: "1.2.3.4", Port: 443, Path: fixtures.PathBar}.IsBlacklisted(&blacklist)).To(BeTrue())
+
+			Expect(Target{IP: "10.0.0.1", Port: 443}.IsBlacklisted(&blacklist)).To(BeFalse())
+			Expect(Target{IP: "10.0.0.2", Port: 8443}.IsBlacklisted(&blacklist)).To(BeFalse())
+			Expect(Target{Hostname: tests.OtherHost, Port: 80, Path: fixtures.PathBar}.IsBlacklisted(&blacklist)).To(BeFalse())
+		})
+
+		It("should match targets with an unknown frontend IP or port", func() {
+			Expect(Target{Hostname: tests.Host, Port: 8443, Path: fixtures.PathFoo}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.OtherHost, IP: "1.2.3.4", Path: fixtures.PathBar}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.Host, Port: 80, Path: fixtures.PathFoo}.IsBlacklisted(&blacklist)).To(BeFalse())
+		})
+	})
+
+	Context("Test GetTargetBlacklist with frontend IP and port", func() {
+		prohibitedTargets := []*v1.AzureIngressProhibitedTarget{
+			{
+				Spec: v1.AzureIngressProhibitedTargetSpec{
+					IP:    "10.0.0.1",
+					Port:  8443,
+					Paths: []string{fixtures.PathFoo},
+				},
+			},
+			{
+				Spec: v1.AzureIngressProhibitedTargetSpec{
+					Hostname: tests.OtherHost,
+					Port:     443,
+				},
+			},
+		}
+
+		It("should copy the IP and port of the prohibited targets", func() {
+			Expect(*GetTargetBlacklist(prohibitedTargets)).To(Equal([]Target{
+				{IP: "10.0.0.1", Port: 8443, Path: fixtures.PathFoo},
+				{Hostname: tests.OtherHost, Port: 443},
+			}))
+		})
+	})
+
+	Context("Test segmenting the config of a prohibited frontend IP and port", func() {
+		publicIPConf := fixtures.GetPublicIPConfiguration()
+		privateIPConf := fixtures.GetPrivateIPConfiguration()
+		listener := func(name string, ipConf n.ApplicationGatewayFrontendIPConfiguration, portName string) n.ApplicationGatewayHTTPListener {
+			return n.ApplicationGatewayHTTPListener{
+				Name: to.StringPtr(name),
+				ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+					FrontendIPConfiguration: &n.SubResource{ID: ipConf.ID},
+					FrontendPort:            &n.SubResource{ID: to.StringPtr("/x/y/z/" + portName)},
+					HostName:                to.StringPtr(tests.Host),
+				},
+			}
+		}
+		rule := func(name string, listenerName string, poolName string) n.ApplicationGatewayRequestRoutingRule {
+			return n.ApplicationGatewayRequestRoutingRule{
+				Name: to.StringPtr(name),
+				ApplicationGatewayRequestRoutingRulePropertiesFormat: &n.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+					RuleType:           n.Basic,
+					HTTPListener:       &n.SubResource{ID: to.StringPtr("/x/y/z/" + listenerName)},
+					BackendAddressPool: &n.SubResource{ID: to.StringPtr("/x/y/z/" + poolName)},
+				},
+			}
+		}
+		port := func(name string, port int32) n.ApplicationGatewayFrontendPort {
+			return n.ApplicationGatewayFrontendPort{
+				Name: to.StringPtr(name),
+				ApplicationGatewayFrontendPortPropertiesFormat: &n.ApplicationGatewayFrontendPortPropertiesFormat{Port: to.Int32Ptr(port)},
+			}
+		}
+		pool := func(name string) n.ApplicationGatewayBackendAddressPool {
+			return n.ApplicationGatewayBackendAddressPool{Name: to.StringPtr(name)}
+		}
+
+		appGw := n.ApplicationGateway{
+			ApplicationGatewayPropertiesFormat: &n.ApplicationGatewayPropertiesFormat{
+				FrontendIPConfigurations: &[]n.ApplicationGatewayFrontendIPConfiguration{publicIPConf, privateIPConf},
+				FrontendPorts:            &[]n.ApplicationGatewayFrontendPort{port("fp-443", 443), port("fp-8443", 8443)},
+				HTTPListeners: &[]n.ApplicationGatewayHTTPListener{
+					listener("public-443", publicIPConf, "fp-443"),
+					listener("public-8443", publicIPConf, "fp-8443"),
+					listener("private-8443", privateIPConf, "fp-8443"),
+				},
+				RequestRoutingRules: &[]n.ApplicationGatewayRequestRoutingRule{
+					rule("rule-public-443", "public-443", "pool-public-443"),
+					rule("rule-public-8443", "public-8443", "pool-public-8443"),
+					rule("rule-private-8443", "private-8443", "pool-private-8443"),
+				},
+				BackendAddressPools: &[]n.ApplicationGatewayBackendAddressPool{
+					pool("pool-public-443"),
+					pool("pool-public-8443"),
+					pool("pool-private-8443"),
+				},
+			},
+		}
+		frontendIPAddresses := map[string]string{*publicIPConf.ID: "1.2.3.4"}
+
+		prohibit := func(ip string, port int32) []*v1.AzureIngressProhibitedTarget {
+			return []*v1.AzureIngressProhibitedTarget{
+				{
+					Spec: v1.AzureIngressProhibitedTargetSpec{
+						IP:   ip,
+						Port: port,
+					},
+				},
+			}
+		}
+
+		getNames := func(er ExistingResources) []string {
+			var names []string
+			listeners, _ := er.GetBlacklistedListeners()
+			for _, listener := range listeners {
+				names = append(names, *listener.Name)
+			}
+			rules, _ := er.GetBlacklistedRoutingRules()
+			for _, rule := range rules {
+				names = append(names, *rule.Name)
+			}
+			pools, _ := er.GetBlacklistedPools()
+			for _, pool := range pools {
+				names = append(names, *pool.Name)
+			}
+			return names
+		}
+
+		It("should blacklist the config of the port on the private IP", func() {
+			er := NewExistingResources(appGw, prohibit(*privateIPConf.PrivateIPAddress, 8443), nil, frontendIPAddresses)
+			Expect(getNames(er)).To(ConsistOf("private-8443", "rule-private-8443", "pool-private-8443"))
+		})
+
+		It("should blacklist the config of the public IP", func() {
+			er := NewExistingResources(appGw, prohibit("1.2.3.4", 0), nil, frontendIPAddresses)
+			Expect(getNames(er)).To(ConsistOf(
+				"public-443", "rule-public-443", "pool-public-443",
+				"public-8443", "rule-public-8443", "pool-public-8443",
+			))
+		})
+
+		It("should blacklist the config of the port on any IP", func() {
+			er := NewExistingResources(appGw, prohibit("", 8443), nil, frontendIPAddresses)
+			Expect(getNames(er)).To(ConsistOf(
+				"public-8443", "rule-public-8443", "pool-public-8443",
+				"private-8443", "rule-private-8443", "pool-private-8443",
+			))
+		})
+
+		It("should blacklist the config of a public IP with an unknown address", func() {
+			er := NewExistingResources(appGw, prohibit("1.2.3.4", 0), nil, nil)
+			Expect(getNames(er)).To(ConsistOf(
+				"public-443", "rule-public-443", "pool-public-443",
+				"public-8443", "rule-public-8443", "pool-public-8443",
+			))
+		})
+	})
+
 	Context("test TargetPath.contains(TargetPath)", func() {
 		It("TargetPath.contains(TargetPath) should work correctly", func() {
 			Expect(TargetPath("/*").contains("/blah")).To(BeTrue())
@@ -7,7 +7,7 @@ package brownfield
 
 import (
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 )
 
 // Logger is an abstraction over a logging facility.
@@ -32,16 +32,22 @@ type ExistingResources struct {
 	Ports              []n.ApplicationGatewayFrontendPort
 	Probes             []n.ApplicationGatewayProbe
 	Redirects          []n.ApplicationGatewayRedirectConfiguration
+	RewriteRuleSets    []n.ApplicationGatewayRewriteRuleSet
 	ProhibitedTargets  []*ptv1.AzureIngressProhibitedTarget
 	DefaultBackendPool *n.ApplicationGatewayBackendAddressPool
 
+	// FrontendIPConfigurations and the IP addresses of the public ones, keyed by the ID of the configuration,
+	// resolve the frontend IP of a listener.
+	FrontendIPConfigurations []n.ApplicationGatewayFrontendIPConfiguration
+	FrontendIPAddresses      map[string]string
+
 	// Cache helper structs
 	listenersByName   map[listenerName]n.ApplicationGatewayHTTPListener
 	urlPathMapsByName pathMapsByName
 }
 
 // NewExistingResources creates a new ExistingResources struct.
-func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, defaultPool *n.ApplicationGatewayBackendAddressPool) ExistingResources {
+func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, defaultPool *n.ApplicationGatewayBackendAddressPool, frontendIPAddresses map[string]string) ExistingResources {
 	var allExistingSettings []n.ApplicationGatewayBackendHTTPSettings
 	if appGw.BackendHTTPSettingsCollection != nil {
 		allExistingSettings = *appGw.BackendHTTPSettingsCollection
@@ -87,6 +93,16 @@ func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.
 		allExistingRedirects = *appGw.RedirectConfigurations
 	}
 
+	var allExistingRewriteRuleSets []n.ApplicationGatewayRewriteRuleSet
+	if appGw.RewriteRuleSets != nil {
+		allExistingRewriteRuleSets = *appGw.RewriteRuleSets
+	}
+
+	var allExistingFrontendIPConfigurations []n.ApplicationGatewayFrontendIPConfiguration
+	if appGw.FrontendIPConfigurations != nil {
+		allExistingFrontendIPConfigurations = *appGw.FrontendIPConfigurations
+	}
+
 	return ExistingResources{
 		BackendPools:       allExistingBackendPools,
 		Certificates:       allExistingCertificates,
@@ -97,8 +113,12 @@ func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.
 		Ports:              allExistingPorts,
 		Probes:             allExistingHealthProbes,
 		Redirects:          allExistingRedirects,
+		RewriteRuleSets:    allExistingRewriteRuleSets,
 		ProhibitedTargets:  prohibitedTargets,
 		DefaultBackendPool: defaultPool,
+
+		FrontendIPConfigurations: allExistingFrontendIPConfigurations,
+		FrontendIPAddresses:      frontendIPAddresses,
 	}
 }
 
@@ -112,3 +132,20 @@ func (er ExistingResources) getProhibitedHostnames() map[string]interface{} {
 	}
 	return prohibitedHostnames
 }
+
+// getProhibitedFrontendTargets returns the Targets of the prohibited targets, which name a frontend IP or port; regardless of
+// their paths, the listeners of these Targets are prohibited.
+func (er ExistingResources) getProhibitedFrontendTargets() TargetBlacklist {
+	var targets []Target
+	for _, pt := range er.ProhibitedTargets {
+		if pt.Spec.IP == "" && pt.Spec.Port == 0 {
+			continue
+		}
+		targets = append(targets, Target{
+			Hostname: pt.Spec.Hostname,
+			IP:       pt.Spec.IP,
+			Port:     pt.Spec.Port,
+		})
+	}
+	return &targets
+}
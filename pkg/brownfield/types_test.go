@@ -7,7 +7,7 @@ package brownfield
 
 import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -23,7 +23,7 @@ var _ = Describe("Test NewExistingResources", func() {
 			}
 			defaultPool := n.ApplicationGatewayBackendAddressPool{}
 
-			actual := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+			actual := NewExistingResources(appGw, prohibitedTargets, &defaultPool, nil)
 			expected := ExistingResources{
 				ProhibitedTargets:  prohibitedTargets,
 				DefaultBackendPool: &n.ApplicationGatewayBackendAddressPool{},
@@ -38,7 +38,7 @@ var _ = Describe("Test NewExistingResources", func() {
 				ApplicationGatewayPropertiesFormat: &n.ApplicationGatewayPropertiesFormat{},
 			}
 			defaultPool := n.ApplicationGatewayBackendAddressPool{}
-			er := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+			er := NewExistingResources(appGw, prohibitedTargets, &defaultPool, nil)
 			actual := er.getProhibitedHostnames()
 			expected := map[string]interface{}{
 				"bye.com":                 nil,
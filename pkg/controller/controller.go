@@ -6,14 +6,19 @@
 package controller
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"strconv"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
+	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/worker"
 )
 
@@ -28,20 +33,31 @@ type AppGwIngressController struct {
 
 	configCache *[]byte
 
-	recorder record.EventRecorder
+	recorder    record.EventRecorder
+	metricStore metricstore.MetricStore
+
+	// dryRun is nil unless the controller is in dry-run mode.
+	dryRun *dryRunState
+
+	// leaderElector is nil when leader election is disabled.
+	leaderElector      *leaderelection.LeaderElector
+	leaderElectionDone chan struct{}
+	leader             *int32
 
 	stopChannel chan struct{}
 }
 
 // NewAppGwIngressController constructs a controller object.
-func NewAppGwIngressController(appGwClient n.ApplicationGatewaysClient, appGwIdentifier appgw.Identifier, k8sContext *k8scontext.Context, recorder record.EventRecorder) *AppGwIngressController {
+func NewAppGwIngressController(appGwClient n.ApplicationGatewaysClient, appGwIdentifier appgw.Identifier, k8sContext *k8scontext.Context, recorder record.EventRecorder, metricStore metricstore.MetricStore) *AppGwIngressController {
 	controller := &AppGwIngressController{
 		appGwClient:     appGwClient,
 		appGwIdentifier: appGwIdentifier,
 		k8sContext:      k8sContext,
 		recorder:        recorder,
+		metricStore:     metricStore,
 		configCache:     to.ByteSlicePtr([]byte{}),
 		ipAddressMap:    map[string]k8scontext.IPAddress{},
+		leader:          to.Int32Ptr(1),
 		stopChannel:     make(chan struct{}),
 	}
 
@@ -61,14 +77,31 @@ func (c *AppGwIngressController) Start(envVariables environment.EnvVariables) er
 		return err
 	}
 
+	if envVariables.EnableDryRun && !c.IsDryRun() {
+		c.EnableDryRun()
+	}
+
+	if reconcilePeriod, err := strconv.Atoi(envVariables.ReconcilePeriodSeconds); err == nil {
+		c.worker.ResyncPeriod = time.Duration(reconcilePeriod) * time.Second
+	}
+
 	// Starts Worker processing events from k8sContext
 	go c.worker.Run(c.k8sContext.Work, c.stopChannel)
+
+	if c.leaderElector != nil {
+		c.leaderElectionDone = make(chan struct{})
+		go c.runLeaderElection()
+	}
 	return nil
 }
 
 // Stop function terminates the k8scontext and signal the stopchannel
 func (c *AppGwIngressController) Stop() {
 	close(c.stopChannel)
+	if c.leaderElectionDone != nil {
+		// Wait for the lock to be released, so a standby replica can take over right away.
+		<-c.leaderElectionDone
+	}
 }
 
 // Liveness fulfills the health.HealthProbe interface; It is evaluated when K8s liveness-checks the AGIC pod.
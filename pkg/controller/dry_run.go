@@ -0,0 +1,114 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// maxEventMessageLength keeps the dry-run event within the size Kubernetes accepts for an event message.
+const maxEventMessageLength = 1024
+
+// DryRunReport is the outcome of the last reconcile in dry-run mode.
+type DryRunReport struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Diff      *appgw.ConfigDiff `json:"diff"`
+}
+
+// dryRunState holds the last DryRunReport; it is shared between copies of the controller.
+type dryRunState struct {
+	lock   sync.RWMutex
+	report *DryRunReport
+}
+
+// EnableDryRun makes the controller compute the App Gateway config without applying it.
+func (c *AppGwIngressController) EnableDryRun() {
+	glog.Info("Dry-run mode is enabled; App Gateway will not be updated")
+	c.dryRun = &dryRunState{}
+}
+
+// IsDryRun tells whether the controller is in dry-run mode.
+func (c *AppGwIngressController) IsDryRun() bool {
+	return c.dryRun != nil
+}
+
+// LastDryRunReport returns the report of the last reconcile in dry-run mode; nil when there was none.
+func (c *AppGwIngressController) LastDryRunReport() *DryRunReport {
+	if c.dryRun == nil {
+		return nil
+	}
+	c.dryRun.lock.RLock()
+	defer c.dryRun.lock.RUnlock()
+	return c.dryRun.report
+}
+
+// DryRunHandler serves the last DryRunReport as JSON.
+func (c *AppGwIngressController) DryRunHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := c.LastDryRunReport()
+		if report == nil {
+			http.Error(w, "no dry-run report yet", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+// reportDryRun records, logs and emits an event with the changes the generated config would make to App Gateway.
+func (c *AppGwIngressController) reportDryRun(existingJSON []byte, generatedAppGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext) error {
+	generatedJSON, err := generatedAppGw.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	diff, err := appgw.NewConfigDiffFromJSON(existingJSON, generatedJSON)
+	if err != nil {
+		glog.Error("Could not compute the App Gateway config diff: ", err)
+		return err
+	}
+
+	c.dryRun.lock.Lock()
+	c.dryRun.report = &DryRunReport{Timestamp: time.Now(), Diff: diff}
+	c.dryRun.lock.Unlock()
+
+	if diff.IsEmpty() {
+		glog.V(1).Info("[dry-run] App Gateway config would not change")
+		return nil
+	}
+
+	lines := diff.Lines()
+	for _, line := range lines {
+		glog.Infof("[dry-run] %s", line)
+	}
+
+	if cbCtx.EnvVariables.AGICPodName == "" || cbCtx.EnvVariables.AGICPodNamespace == "" {
+		glog.V(3).Info("[dry-run] AGIC pod is unknown; not emitting an event")
+		return nil
+	}
+	pod := &v1.ObjectReference{
+		Kind:      "Pod",
+		Name:      cbCtx.EnvVariables.AGICPodName,
+		Namespace: cbCtx.EnvVariables.AGICPodNamespace,
+	}
+	message := fmt.Sprintf("App Gateway would change: %s", strings.Join(lines, "; "))
+	if len(message) > maxEventMessageLength {
+		message = message[:maxEventMessageLength-3] + "..."
+	}
+	c.recorder.Event(pod, v1.EventTypeNormal, events.ReasonDryRunDiff, message)
+	return nil
+}
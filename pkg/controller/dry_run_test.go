@@ -0,0 +1,95 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("test dry-run mode", func() {
+	var controller *AppGwIngressController
+	var recorder *record.FakeRecorder
+	var cbCtx *appgw.ConfigBuilderContext
+	var existingJSON []byte
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		controller = NewAppGwIngressController(n.ApplicationGatewaysClient{}, appgw.Identifier{}, &k8scontext.Context{}, recorder, metricstore.NewFakeMetricStore())
+		controller.EnableDryRun()
+
+		cbCtx = &appgw.ConfigBuilderContext{EnvVariables: environment.GetFakeEnv()}
+		cbCtx.EnvVariables.AGICPodName = "agic-1234"
+		cbCtx.EnvVariables.AGICPodNamespace = "agic"
+
+		existing := fixtures.GetAppGateway()
+		var err error
+		existingJSON, err = existing.MarshalJSON()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	getReport := func() *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		controller.DryRunHandler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/dry-run", nil))
+		return response
+	}
+
+	It("should not serve a report before the first reconcile", func() {
+		Expect(controller.LastDryRunReport()).To(BeNil())
+		Expect(getReport().Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should report the diff as an event and through the handler", func() {
+		generated := fixtures.GetAppGateway()
+		pool := fixtures.GetBackendPool1()
+		generated.BackendAddressPools = &[]n.ApplicationGatewayBackendAddressPool{pool}
+
+		Expect(controller.reportDryRun(existingJSON, &generated, cbCtx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(Equal("Normal DryRunDiff App Gateway would change: + pool " + *pool.Name)))
+
+		response := getReport()
+		Expect(response.Code).To(Equal(http.StatusOK))
+		var report DryRunReport
+		Expect(json.Unmarshal(response.Body.Bytes(), &report)).To(Succeed())
+		Expect(report.Diff.Changes).To(ConsistOf(appgw.ResourceChange{Kind: "pool", Name: *pool.Name, Change: appgw.ChangeAdded}))
+	})
+
+	It("should not emit an event when nothing changes", func() {
+		generated := fixtures.GetAppGateway()
+		Expect(controller.reportDryRun(existingJSON, &generated, cbCtx)).To(Succeed())
+		Expect(recorder.Events).ToNot(Receive())
+		Expect(controller.LastDryRunReport().Diff.IsEmpty()).To(BeTrue())
+	})
+
+	It("should truncate long event messages", func() {
+		generated := fixtures.GetAppGateway()
+		var pools []n.ApplicationGatewayBackendAddressPool
+		for i := 0; i < 100; i++ {
+			pool := fixtures.GetBackendPool1()
+			pool.Name = to.StringPtr(*pool.Name + string(rune('a'+i%26)) + string(rune('a'+i/26)))
+			pools = append(pools, pool)
+		}
+		generated.BackendAddressPools = &pools
+
+		Expect(controller.reportDryRun(existingJSON, &generated, cbCtx)).To(Succeed())
+		var event string
+		Expect(recorder.Events).To(Receive(&event))
+		Expect(len(event)).To(BeNumerically("<=", len("Normal DryRunDiff ")+maxEventMessageLength))
+	})
+})
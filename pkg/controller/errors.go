@@ -13,4 +13,7 @@ var (
 
 	// ErrDeployingAppGatewayConfig is an error.
 	ErrDeployingAppGatewayConfig = errors.New("unable to deploy App Gateway config")
+
+	// ErrNoIngressInPrunedList is an error.
+	ErrNoIngressInPrunedList = errors.New("no Ingress in the pruned Ingress list")
 )
@@ -0,0 +1,179 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+)
+
+// ipAddressType is the type of the addresses of App Gateway in the status of a Gateway.
+const ipAddressType = "IPAddress"
+
+// updateGatewayAPIStatus accepts the GatewayClasses of AGIC, and writes the address of App Gateway, and whether App Gateway
+// implements their listeners and routes, to the status of the Gateways and HTTPRoutes.
+func (c AppGwIngressController) updateGatewayAPIStatus(appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext) {
+	if !cbCtx.EnvVariables.EnableGatewayAPI {
+		return
+	}
+
+	for _, gatewayClass := range c.k8sContext.ListGatewayClasses() {
+		conditions := []metav1.Condition{
+			gatewayAPICondition(gatewayv1beta1.ConditionAccepted, gatewayv1beta1.ReasonAccepted, ""),
+		}
+		if err := c.k8sContext.UpdateGatewayClassStatus(gatewayClass, conditions); err != nil {
+			c.recorder.Event(gatewayClass, v1.EventTypeWarning, events.ReasonUnableToUpdateGatewayAPIStatus, err.Error())
+		}
+	}
+
+	attachedRoutes := make(map[string]int32)
+	for _, route := range cbCtx.GatewayAPIRoutes {
+		resolvedRefs := c.httpRouteResolvedRefsCondition(route)
+		var parents []gatewayv1beta1.RouteParentStatus
+		for _, parentRef := range route.Spec.ParentRefs {
+			gateway := k8scontext.GetHTTPRouteParent(route, parentRef, cbCtx.GatewayAPIGateways)
+			if gateway == nil {
+				// the parent is not a Gateway of AGIC
+				continue
+			}
+			attachments, reason := c.k8sContext.AttachHTTPRoute(route, parentRef, gateway)
+			for _, attachment := range attachments {
+				listenerKey := k8scontext.GatewayListenerKey(gateway, attachment.Listener.Name)
+				if _, conflicted := cbCtx.GatewayAPIConflictedListeners[listenerKey]; conflicted {
+					// App Gateway does not implement the listener; the route is not attached to it
+					continue
+				}
+				attachedRoutes[listenerKey]++
+			}
+			if reason == "" {
+				reason = gatewayv1beta1.ReasonAccepted
+			}
+			parents = append(parents, gatewayv1beta1.RouteParentStatus{
+				ParentRef: parentRef,
+				Conditions: []metav1.Condition{
+					gatewayAPICondition(gatewayv1beta1.ConditionAccepted, reason, gatewayv1beta1.ReasonAccepted),
+					resolvedRefs,
+				},
+			})
+		}
+		if len(parents) == 0 && !hasApplicationGatewayParentStatus(route) {
+			continue
+		}
+		if err := c.k8sContext.UpdateHTTPRouteStatus(route, parents); err != nil {
+			c.recorder.Event(route, v1.EventTypeWarning, events.ReasonUnableToUpdateGatewayAPIStatus, err.Error())
+		}
+	}
+
+	var addresses []gatewayv1beta1.GatewayAddress
+	usePrivateIP := cbCtx.EnvVariables.UsePrivateIP == "true"
+	if ipConf := appgw.LookupIPConfigurationByType(appGw.FrontendIPConfigurations, usePrivateIP); ipConf != nil {
+		if ipAddress, ok := c.ipAddressMap[*ipConf.ID]; ok {
+			addresses = append(addresses, gatewayv1beta1.GatewayAddress{Type: to.StringPtr(ipAddressType), Value: string(ipAddress)})
+		}
+	}
+	programmedReason := gatewayv1beta1.ReasonProgrammed
+	if len(addresses) == 0 {
+		programmedReason = gatewayv1beta1.ReasonInvalid
+	}
+
+	for _, gateway := range cbCtx.GatewayAPIGateways {
+		var listeners []gatewayv1beta1.ListenerStatus
+		for idx := range gateway.Spec.Listeners {
+			listener := &gateway.Spec.Listeners[idx]
+			listeners = append(listeners, gatewayv1beta1.ListenerStatus{
+				Name:           listener.Name,
+				SupportedKinds: gatewayListenerSupportedKinds(listener),
+				AttachedRoutes: attachedRoutes[k8scontext.GatewayListenerKey(gateway, listener.Name)],
+				Conditions:     c.gatewayListenerConditions(gateway, listener, cbCtx.GatewayAPIConflictedListeners),
+			})
+		}
+		conditions := []metav1.Condition{
+			gatewayAPICondition(gatewayv1beta1.ConditionAccepted, gatewayv1beta1.ReasonAccepted, ""),
+			gatewayAPICondition(gatewayv1beta1.ConditionProgrammed, programmedReason, gatewayv1beta1.ReasonProgrammed),
+		}
+		if err := c.k8sContext.UpdateGatewayStatus(gateway, addresses, conditions, listeners); err != nil {
+			c.recorder.Event(gateway, v1.EventTypeWarning, events.ReasonUnableToUpdateGatewayAPIStatus, err.Error())
+		}
+	}
+}
+
+// httpRouteResolvedRefsCondition tells whether all backends of the route reference a service App Gateway can send requests to.
+func (c AppGwIngressController) httpRouteResolvedRefsCondition(route *gatewayv1beta1.HTTPRoute) metav1.Condition {
+	for _, rule := range route.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			if _, reason := c.k8sContext.ResolveHTTPBackendRef(route, backendRef); reason != "" {
+				return gatewayAPICondition(gatewayv1beta1.ConditionResolvedRefs, reason, gatewayv1beta1.ReasonResolvedRefs)
+			}
+		}
+	}
+	return gatewayAPICondition(gatewayv1beta1.ConditionResolvedRefs, gatewayv1beta1.ReasonResolvedRefs, gatewayv1beta1.ReasonResolvedRefs)
+}
+
+// gatewayListenerConditions tells whether App Gateway implements the protocol of the listener, found its certificate, and
+// created the listener without a conflict with another listener.
+func (c AppGwIngressController) gatewayListenerConditions(gateway *gatewayv1beta1.Gateway, listener *gatewayv1beta1.Listener, conflictedListeners map[string]string) []metav1.Condition {
+	_, reason := c.k8sContext.ResolveGatewayListener(gateway, listener)
+	acceptedReason, resolvedRefsReason, programmedReason := gatewayv1beta1.ReasonAccepted, gatewayv1beta1.ReasonResolvedRefs, gatewayv1beta1.ReasonProgrammed
+	switch reason {
+	case gatewayv1beta1.ReasonUnsupportedProtocol:
+		acceptedReason, programmedReason = reason, gatewayv1beta1.ReasonInvalid
+	case gatewayv1beta1.ReasonInvalidCertificateRef, gatewayv1beta1.ReasonRefNotPermitted:
+		resolvedRefsReason, programmedReason = reason, gatewayv1beta1.ReasonInvalid
+	}
+
+	conflicted := metav1.Condition{Type: gatewayv1beta1.ConditionConflicted, Status: metav1.ConditionFalse, Reason: gatewayv1beta1.ReasonNoConflicts}
+	if conflictReason, exists := conflictedListeners[k8scontext.GatewayListenerKey(gateway, listener.Name)]; exists {
+		conflicted.Status, conflicted.Reason = metav1.ConditionTrue, conflictReason
+		acceptedReason, programmedReason = conflictReason, gatewayv1beta1.ReasonInvalid
+	}
+
+	return []metav1.Condition{
+		gatewayAPICondition(gatewayv1beta1.ConditionAccepted, acceptedReason, gatewayv1beta1.ReasonAccepted),
+		gatewayAPICondition(gatewayv1beta1.ConditionResolvedRefs, resolvedRefsReason, gatewayv1beta1.ReasonResolvedRefs),
+		gatewayAPICondition(gatewayv1beta1.ConditionProgrammed, programmedReason, gatewayv1beta1.ReasonProgrammed),
+		conflicted,
+	}
+}
+
+// hasApplicationGatewayParentStatus tells whether the route has a status written for a parent of the Application Gateway
+// ingress controller; it is removed once the route no longer references a Gateway of AGIC.
+func hasApplicationGatewayParentStatus(route *gatewayv1beta1.HTTPRoute) bool {
+	for _, parentStatus := range route.Status.Parents {
+		if parentStatus.ControllerName == annotations.ApplicationGatewayIngressClass {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayListenerSupportedKinds returns the kinds of routes App Gateway attaches to the listener; none for the protocols it does not implement.
+func gatewayListenerSupportedKinds(listener *gatewayv1beta1.Listener) []gatewayv1beta1.RouteGroupKind {
+	if listener.Protocol != gatewayv1beta1.HTTPProtocolType && listener.Protocol != gatewayv1beta1.HTTPSProtocolType {
+		return []gatewayv1beta1.RouteGroupKind{}
+	}
+	return []gatewayv1beta1.RouteGroupKind{{Group: to.StringPtr(k8scontext.GatewayAPIGroup), Kind: k8scontext.HTTPRouteKind}}
+}
+
+// gatewayAPICondition returns a condition, which is true when its reason is the reason of success; any success reason when it is empty.
+func gatewayAPICondition(conditionType string, reason string, successReason string) metav1.Condition {
+	status := metav1.ConditionTrue
+	if successReason != "" && reason != successReason {
+		status = metav1.ConditionFalse
+	}
+	return metav1.Condition{
+		Type:   conditionType,
+		Status: status,
+		Reason: reason,
+	}
+}
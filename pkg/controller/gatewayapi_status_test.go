@@ -0,0 +1,139 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("Gateway API status tests", func() {
+	var controller *AppGwIngressController
+	var gatewayCrdClient *gateway_fake.Clientset
+	var gateway *gatewayv1beta1.Gateway
+	var route *gatewayv1beta1.HTTPRoute
+	var cbCtx *appgw.ConfigBuilderContext
+
+	BeforeEach(func() {
+		gatewayCrdClient = gateway_fake.NewSimpleClientset()
+		ctxt := k8scontext.NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istio_fake.NewSimpleClientset(), gatewayCrdClient, []string{tests.Namespace}, 1000*time.Second, metricstore.NewFakeMetricStore())
+		controller = &AppGwIngressController{
+			metricStore: metricstore.NewFakeMetricStore(),
+			k8sContext:  ctxt,
+			recorder:    record.NewFakeRecorder(100),
+		}
+
+		gateway = &gatewayv1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "contoso", Namespace: tests.Namespace},
+			Spec: gatewayv1beta1.GatewaySpec{
+				GatewayClassName: "azure-application-gateway",
+				Listeners: []gatewayv1beta1.Listener{{
+					Name:     "http",
+					Hostname: to.StringPtr(tests.Host),
+					Port:     80,
+					Protocol: gatewayv1beta1.HTTPProtocolType,
+				}},
+			},
+		}
+		route = &gatewayv1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: tests.Namespace},
+			Spec: gatewayv1beta1.HTTPRouteSpec{
+				ParentRefs: []gatewayv1beta1.ParentReference{{Name: gateway.Name}},
+			},
+		}
+		_, err := gatewayCrdClient.GatewayV1beta1().Gateways(gateway.Namespace).Create(context.TODO(), gateway, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		env := environment.GetFakeEnv()
+		env.EnableGatewayAPI = true
+		cbCtx = &appgw.ConfigBuilderContext{
+			GatewayAPIGateways: []*gatewayv1beta1.Gateway{gateway},
+			GatewayAPIRoutes:   []*gatewayv1beta1.HTTPRoute{route},
+			EnvVariables:       env,
+		}
+	})
+
+	getListenerStatus := func() gatewayv1beta1.ListenerStatus {
+		updated, err := gatewayCrdClient.GatewayV1beta1().Gateways(gateway.Namespace).Get(context.TODO(), gateway.Name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated.Status.Listeners).To(HaveLen(1))
+		return updated.Status.Listeners[0]
+	}
+
+	Context("Test the status of the Gateway listeners", func() {
+		BeforeEach(func() {
+			_, err := gatewayCrdClient.GatewayV1beta1().HTTPRoutes(route.Namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("counts the attached routes of a listener without conflicts", func() {
+			appGw := fixtures.GetAppGateway()
+			controller.updateGatewayAPIStatus(&appGw, cbCtx)
+
+			listenerStatus := getListenerStatus()
+			Expect(listenerStatus.AttachedRoutes).To(Equal(int32(1)))
+			Expect(meta.IsStatusConditionFalse(listenerStatus.Conditions, gatewayv1beta1.ConditionConflicted)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(listenerStatus.Conditions, gatewayv1beta1.ConditionAccepted)).To(BeTrue())
+		})
+
+		It("marks a conflicted listener as not programmed, without attached routes", func() {
+			cbCtx.GatewayAPIConflictedListeners = map[string]string{
+				k8scontext.GatewayListenerKey(gateway, "http"): gatewayv1beta1.ReasonHostnameConflict,
+			}
+			appGw := fixtures.GetAppGateway()
+			controller.updateGatewayAPIStatus(&appGw, cbCtx)
+
+			listenerStatus := getListenerStatus()
+			Expect(listenerStatus.AttachedRoutes).To(Equal(int32(0)))
+			conflicted := meta.FindStatusCondition(listenerStatus.Conditions, gatewayv1beta1.ConditionConflicted)
+			Expect(conflicted).ToNot(BeNil())
+			Expect(conflicted.Status).To(Equal(metav1.ConditionTrue))
+			Expect(conflicted.Reason).To(Equal(gatewayv1beta1.ReasonHostnameConflict))
+			Expect(meta.IsStatusConditionFalse(listenerStatus.Conditions, gatewayv1beta1.ConditionAccepted)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(listenerStatus.Conditions, gatewayv1beta1.ConditionProgrammed)).To(BeTrue())
+		})
+	})
+
+	Context("Test the status of the HTTPRoutes", func() {
+		It("removes the parent statuses of a route, which no longer references a Gateway of AGIC", func() {
+			route.Spec.ParentRefs = []gatewayv1beta1.ParentReference{{Name: "other"}}
+			route.Status.Parents = []gatewayv1beta1.RouteParentStatus{
+				{ParentRef: gatewayv1beta1.ParentReference{Name: gateway.Name}, ControllerName: annotations.ApplicationGatewayIngressClass},
+				{ParentRef: gatewayv1beta1.ParentReference{Name: "other"}, ControllerName: "example.com/other-controller"},
+			}
+			_, err := gatewayCrdClient.GatewayV1beta1().HTTPRoutes(route.Namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			appGw := fixtures.GetAppGateway()
+			controller.updateGatewayAPIStatus(&appGw, cbCtx)
+
+			updated, err := gatewayCrdClient.GatewayV1beta1().HTTPRoutes(route.Namespace).Get(context.TODO(), route.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.Status.Parents).To(HaveLen(1))
+			Expect(updated.Status.Parents[0].ControllerName).To(Equal("example.com/other-controller"))
+		})
+	})
+})
@@ -9,11 +9,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/golang/glog"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
@@ -40,6 +42,19 @@ func (c *AppGwIngressController) updateCache(appGw *n.ApplicationGateway) {
 	*c.configCache = sanitized
 }
 
+// armStatusCode returns the HTTP status code of an ARM call; 0 when no response was received.
+func armStatusCode(response *http.Response, err error) int {
+	if detailedErr, ok := err.(autorest.DetailedError); ok {
+		if statusCode, ok := detailedErr.StatusCode.(int); ok {
+			return statusCode
+		}
+	}
+	if response != nil {
+		return response.StatusCode
+	}
+	return 0
+}
+
 // configIsSame compares the newly created App Gwy configuration with a cache to determine whether anything has changed.
 func (c *AppGwIngressController) configIsSame(appGw *n.ApplicationGateway) bool {
 	if c.configCache == nil {
@@ -73,12 +88,9 @@ func dumpSanitizedJSON(appGw *n.ApplicationGateway, logToFile bool, overwritePre
 		prefix = *overwritePrefix
 	}
 
-	// Remove sensitive data from the JSON config to be logged
-	keysToDelete := []string{
-		"sslCertificates",
-	}
+	// Remove sensitive data from the JSON config to be logged; certificates from Key Vault are only referenced by ID.
 	var sanitized []byte
-	if sanitized, err = deleteKeyFromJSON(jsonConfig, keysToDelete...); err != nil {
+	if sanitized, err = sanitizeSslCertificates(jsonConfig); err != nil {
 		return nil, err
 	}
 
@@ -94,6 +106,26 @@ func dumpSanitizedJSON(appGw *n.ApplicationGateway, logToFile bool, overwritePre
 	return prettyJSON, err
 }
 
+// sanitizeSslCertificates removes the PFX and its password from the SSL certificates in the App Gateway JSON.
+func sanitizeSslCertificates(jsonConfig []byte) ([]byte, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(jsonConfig, &m); err != nil {
+		glog.Error("Could not unmarshal App Gwy config JSON to remove certificates.", err)
+		return nil, err
+	}
+	if properties, ok := m["properties"].(map[string]interface{}); ok {
+		if certificates, ok := properties["sslCertificates"].([]interface{}); ok {
+			for _, certificate := range certificates {
+				if certificateMap, ok := certificate.(map[string]interface{}); ok {
+					deleteKey(&certificateMap, "data")
+					deleteKey(&certificateMap, "password")
+				}
+			}
+		}
+	}
+	return json.Marshal(m)
+}
+
 func isMap(v interface{}) bool {
 	return v != nil && reflect.ValueOf(v).Type().Kind() == reflect.Map
 }
@@ -6,7 +6,11 @@
 package controller
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"errors"
+	"net/http"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -63,6 +67,36 @@ var _ = Describe("test helpers", func() {
 		})
 	})
 
+	Context("ensure dumpSanitizedJSON works as expected", func() {
+		It("should strip the PFX and the password, and keep the Key Vault secret ID", func() {
+			appGw := n.ApplicationGateway{
+				ApplicationGatewayPropertiesFormat: &n.ApplicationGatewayPropertiesFormat{
+					SslCertificates: &[]n.ApplicationGatewaySslCertificate{
+						{
+							Name: to.StringPtr("default-secret"),
+							ApplicationGatewaySslCertificatePropertiesFormat: &n.ApplicationGatewaySslCertificatePropertiesFormat{
+								Data:     to.StringPtr("--pfx--"),
+								Password: to.StringPtr("--password--"),
+							},
+						},
+						{
+							Name: to.StringPtr("kv-contoso-www"),
+							ApplicationGatewaySslCertificatePropertiesFormat: &n.ApplicationGatewaySslCertificatePropertiesFormat{
+								KeyVaultSecretID: to.StringPtr("https://contoso.vault.azure.net/secrets/www"),
+							},
+						},
+					},
+				},
+			}
+			sanitized, err := dumpSanitizedJSON(&appGw, false, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(sanitized)).To(ContainSubstring("default-secret"))
+			Expect(string(sanitized)).ToNot(ContainSubstring("--pfx--"))
+			Expect(string(sanitized)).ToNot(ContainSubstring("--password--"))
+			Expect(string(sanitized)).To(ContainSubstring(`"keyVaultSecretId": "https://contoso.vault.azure.net/secrets/www"`))
+		})
+	})
+
 	Context("ensure configIsSame works as expected", func() {
 		It("should deal with empty cache and store stuff in it", func() {
 			c := AppGwIngressController{
@@ -101,4 +135,17 @@ var _ = Describe("test helpers", func() {
 			Expect(isSlice(make(map[string]interface{}))).To(BeFalse())
 		})
 	})
+
+	Context("ensure armStatusCode works as expected", func() {
+		It("should prefer the status code of a detailed error", func() {
+			err := autorest.DetailedError{StatusCode: http.StatusConflict}
+			Expect(armStatusCode(&http.Response{StatusCode: http.StatusOK}, err)).To(Equal(http.StatusConflict))
+		})
+		It("should fall back to the status code of the response", func() {
+			Expect(armStatusCode(&http.Response{StatusCode: http.StatusOK}, nil)).To(Equal(http.StatusOK))
+		})
+		It("should return 0 when there was no response", func() {
+			Expect(armStatusCode(nil, errors.New("connection refused"))).To(Equal(0))
+		})
+	})
 })
@@ -0,0 +1,132 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+const (
+	// RoleLeader is reported by the replica, which updates App Gateway.
+	RoleLeader = "leader"
+
+	// RoleStandby is reported by replicas, which keep their caches warm while waiting to take over.
+	RoleStandby = "standby"
+)
+
+// NewLeaseLock creates the Lease, which AGIC replicas compete for; the pod name identifies the holder.
+func NewLeaseLock(kubeClient kubernetes.Interface, recorder record.EventRecorder, env environment.EnvVariables) resourcelock.Interface {
+	return &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: env.AGICPodNamespace,
+			Name:      env.LeaderElectionLeaseName,
+		},
+		Client: kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity:      env.AGICPodName,
+			EventRecorder: recorder,
+		},
+	}
+}
+
+// EnableLeaderElection makes the controller update App Gateway only while it holds the lock; the election begins with Start.
+// When the leader stops renewing the lock, a standby replica takes over within leaseDuration plus a retry period.
+func (c *AppGwIngressController) EnableLeaderElection(lock resourcelock.Interface, leaseDuration time.Duration) error {
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		// The leader gives up before the lease expires, so it stops updating App Gateway before a standby can take over.
+		RenewDeadline:   leaseDuration * 2 / 3,
+		RetryPeriod:     leaseDuration / 5,
+		ReleaseOnCancel: true,
+		Name:            lock.Describe(),
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: c.onStartedLeading,
+			OnStoppedLeading: c.onStoppedLeading,
+			OnNewLeader: func(identity string) {
+				glog.V(1).Infof("Leader of %s is %s", lock.Describe(), identity)
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	c.leaderElector = elector
+	c.setLeader(false)
+	return nil
+}
+
+// IsLeader tells whether this replica updates App Gateway; always true when leader election is disabled.
+func (c *AppGwIngressController) IsLeader() bool {
+	return atomic.LoadInt32(c.leader) == 1
+}
+
+// Role reports whether this replica is the leader or on standby.
+func (c *AppGwIngressController) Role() string {
+	if c.IsLeader() {
+		return RoleLeader
+	}
+	return RoleStandby
+}
+
+func (c *AppGwIngressController) setLeader(isLeader bool) {
+	var leader int32
+	if isLeader {
+		leader = 1
+	}
+	atomic.StoreInt32(c.leader, leader)
+}
+
+// runLeaderElection competes for the lock until stopChannel is closed; a replica, which lost the lock, rejoins as standby.
+func (c *AppGwIngressController) runLeaderElection() {
+	defer close(c.leaderElectionDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stopChannel
+		cancel()
+	}()
+
+	for {
+		c.leaderElector.Run(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			glog.V(1).Info("Rejoining leader election as standby")
+		}
+	}
+}
+
+func (c *AppGwIngressController) onStartedLeading(ctx context.Context) {
+	glog.V(1).Info("Became the leader; reconciling App Gateway")
+	c.setLeader(true)
+
+	// Events, which arrived while on standby, were dropped; reconcile the whole config.
+	select {
+	case c.k8sContext.Work <- events.Event{Type: events.Resync}:
+	case <-ctx.Done():
+	}
+}
+
+func (c *AppGwIngressController) onStoppedLeading() {
+	glog.V(1).Info("Stopped leading; App Gateway will not be updated by this replica")
+	c.setLeader(false)
+}
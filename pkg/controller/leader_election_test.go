@@ -0,0 +1,88 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+)
+
+var _ = Describe("test leader election", func() {
+	const leaseDuration = 1 * time.Second
+
+	var kubeClient kubernetes.Interface
+
+	newReplica := func(podName string) *AppGwIngressController {
+		k8sContext := &k8scontext.Context{Work: make(chan events.Event, 10)}
+		c := NewAppGwIngressController(n.ApplicationGatewaysClient{}, appgw.Identifier{}, k8sContext, record.NewFakeRecorder(100), metricstore.NewFakeMetricStore())
+
+		env := environment.GetFakeEnv()
+		env.AGICPodName = podName
+		env.AGICPodNamespace = "agic"
+		env.LeaderElectionLeaseName = "agic-leader"
+		Expect(c.EnableLeaderElection(NewLeaseLock(kubeClient, c.recorder, env), leaseDuration)).To(Succeed())
+
+		c.leaderElectionDone = make(chan struct{})
+		go c.runLeaderElection()
+		return c
+	}
+
+	BeforeEach(func() {
+		kubeClient = testclient.NewSimpleClientset()
+	})
+
+	It("should be the leader when leader election is disabled", func() {
+		c := NewAppGwIngressController(n.ApplicationGatewaysClient{}, appgw.Identifier{}, &k8scontext.Context{}, record.NewFakeRecorder(0), metricstore.NewFakeMetricStore())
+		Expect(c.IsLeader()).To(BeTrue())
+		Expect(c.Role()).To(Equal(RoleLeader))
+	})
+
+	It("should elect a single leader and hand over once it stops", func() {
+		first := newReplica("agic-1")
+		Eventually(first.IsLeader, 5*time.Second, 50*time.Millisecond).Should(BeTrue())
+		Expect(first.Role()).To(Equal(RoleLeader))
+
+		// The new leader reconciles the whole config.
+		Eventually(first.k8sContext.Work).Should(Receive(Equal(events.Event{Type: events.Resync})))
+
+		second := newReplica("agic-2")
+		Consistently(second.IsLeader, 2*leaseDuration, 50*time.Millisecond).Should(BeFalse())
+		Expect(second.Role()).To(Equal(RoleStandby))
+
+		lease, err := kubeClient.CoordinationV1().Leases("agic").Get(context.TODO(), "agic-leader", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("agic-1"))
+
+		first.Stop()
+		Expect(first.IsLeader()).To(BeFalse())
+
+		// The lock was released, so the standby takes over well within the lease duration.
+		Eventually(second.IsLeader, leaseDuration, 20*time.Millisecond).Should(BeTrue())
+		Eventually(second.k8sContext.Work).Should(Receive(Equal(events.Event{Type: events.Resync})))
+		second.Stop()
+	})
+
+	It("should skip Process while on standby", func() {
+		c := NewAppGwIngressController(n.ApplicationGatewaysClient{}, appgw.Identifier{}, &k8scontext.Context{}, record.NewFakeRecorder(0), metricstore.NewFakeMetricStore())
+		c.setLeader(false)
+		// A leader would fail fetching App Gateway with an empty client.
+		Expect(c.Process(events.Event{Type: events.Resync})).To(Succeed())
+	})
+})
@@ -9,14 +9,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
@@ -30,10 +31,17 @@ import (
 // Process is the callback function that will be executed for every event
 // in the EventQueue.
 func (c AppGwIngressController) Process(event events.Event) error {
+	if !c.IsLeader() {
+		glog.V(5).Info("Standby replica; skipping App Gateway update")
+		return nil
+	}
+
 	ctx := context.Background()
 
 	// Get current application gateway config
+	getStart := time.Now()
 	appGw, err := c.appGwClient.Get(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName)
+	c.metricStore.ObserveArmAPICall(http.MethodGet, armStatusCode(appGw.Response.Response, err), err, time.Since(getStart))
 	if err != nil {
 		glog.Errorf("unable to get specified AppGateway [%v], check AppGateway identifier, error=[%v]", c.appGwIdentifier.AppGwName, err.Error())
 		return ErrFetchingAppGatewayConfig
@@ -44,10 +52,88 @@ func (c AppGwIngressController) Process(event events.Event) error {
 	existingConfigJSON, _ := dumpSanitizedJSON(&appGw, false, to.StringPtr("-- Existing App Gwy Config --"))
 	glog.V(5).Info("Existing App Gateway config: ", string(existingConfigJSON))
 
+	// The config builder updates appGw in place; keep the existing config to compare against in dry-run mode.
+	var existingJSON []byte
+	if c.IsDryRun() {
+		if existingJSON, err = appGw.MarshalJSON(); err != nil {
+			glog.Error("Could not marshal the existing App Gateway config: ", err)
+			return err
+		}
+	}
+
+	cbCtx, generatedAppGw, err := c.MutateAppGateway(&appGw, environment.GetEnv())
+	if err == ErrNoIngressInPrunedList {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.IsDryRun() {
+		return c.reportDryRun(existingJSON, generatedAppGw, cbCtx)
+	}
+
+	if c.configIsSame(&appGw) {
+		// update ingresses with appgw gateway ip address
+		c.updateIngressStatus(generatedAppGw, cbCtx, event)
+		c.updateGatewayAPIStatus(generatedAppGw, cbCtx)
+
+		glog.V(3).Info("cache: Config has NOT changed! No need to connect to ARM.")
+		c.metricStore.IncConfigUnchangedCounter()
+		return nil
+	}
+
+	glog.V(3).Info("BEGIN AppGateway deployment")
+	defer glog.V(3).Info("END AppGateway deployment")
+
+	deploymentStart := time.Now()
+	// Initiate deployment
+	appGwFuture, err := c.appGwClient.CreateOrUpdate(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, *generatedAppGw)
+	if err != nil {
+		c.metricStore.ObserveArmAPICall(http.MethodPut, armStatusCode(appGwFuture.Response(), err), err, time.Since(deploymentStart))
+		// Reset cache
+		c.configCache = nil
+		configJSON, _ := dumpSanitizedJSON(&appGw, cbCtx.EnvVariables.EnableSaveConfigToFile, nil)
+		glogIt := glog.Errorf
+		if cbCtx.EnvVariables.EnablePanicOnPutError {
+			glogIt = glog.Fatalf
+		}
+		glogIt("Failed applying App Gwy configuration: %s -- %s", err, string(configJSON))
+		return err
+	}
+	// Wait until deployment finshes and save the error message
+	err = appGwFuture.WaitForCompletionRef(ctx, c.appGwClient.BaseClient.Client)
+	c.metricStore.ObserveArmAPICall(http.MethodPut, armStatusCode(appGwFuture.Response(), err), err, time.Since(deploymentStart))
+	configJSON, _ := dumpSanitizedJSON(&appGw, cbCtx.EnvVariables.EnableSaveConfigToFile, nil)
+	glog.V(5).Info(string(configJSON))
+
+	// We keep this at log level 1 to show some heartbeat in the logs. Without this it is way too quiet.
+	glog.V(1).Infof("Applied App Gateway config in %+v", time.Now().Sub(deploymentStart).String())
+
+	if err != nil {
+		// Reset cache
+		c.configCache = nil
+		glog.Warning("Unable to deploy App Gateway config.", err)
+		return ErrDeployingAppGatewayConfig
+	}
+
+	glog.V(3).Info("cache: Updated with latest applied config.")
+	c.updateCache(&appGw)
+
+	// update ingresses with appgw gateway ip address
+	c.updateIngressStatus(generatedAppGw, cbCtx, event)
+	c.updateGatewayAPIStatus(generatedAppGw, cbCtx)
+
+	return nil
+}
+
+// MutateAppGateway runs the Kubernetes resources in the cache through the config builder and generates the App Gateway config.
+// The generated config is based on, and shares sub-resources with, appGw.
+func (c AppGwIngressController) MutateAppGateway(appGw *n.ApplicationGateway, env environment.EnvVariables) (*appgw.ConfigBuilderContext, *n.ApplicationGateway, error) {
 	cbCtx := &appgw.ConfigBuilderContext{
 		ServiceList:  c.k8sContext.ListServices(),
 		IngressList:  c.k8sContext.ListHTTPIngresses(),
-		EnvVariables: environment.GetEnv(),
+		EnvVariables: env,
 
 		DefaultAddressPoolID:  to.StringPtr(c.appGwIdentifier.AddressPoolID(appgw.DefaultBackendAddressPoolName)),
 		DefaultHTTPSettingsID: to.StringPtr(c.appGwIdentifier.HTTPSettingsID(appgw.DefaultBackendHTTPSettingsName)),
@@ -57,6 +143,10 @@ func (c AppGwIngressController) Process(event events.Event) error {
 		prohibitedTargets := c.k8sContext.ListAzureProhibitedTargets()
 		if len(prohibitedTargets) > 0 {
 			cbCtx.ProhibitedTargets = prohibitedTargets
+			cbCtx.FrontendIPAddresses = make(map[string]string)
+			for ipConfID, ipAddress := range c.ipAddressMap {
+				cbCtx.FrontendIPAddresses[ipConfID] = string(ipAddress)
+			}
 			var prohibitedTargetsList []string
 			for _, target := range *brownfield.GetTargetBlacklist(prohibitedTargets) {
 				targetJSON, _ := json.Marshal(target)
@@ -71,7 +161,7 @@ func (c AppGwIngressController) Process(event events.Event) error {
 
 	if cbCtx.EnvVariables.EnableIstioIntegration {
 		istioServices := c.k8sContext.ListIstioVirtualServices()
-		istioGateways := c.k8sContext.ListIstioGateways()
+		istioGateways := c.k8sContext.GetGateways()
 		if len(istioGateways) > 0 && len(istioServices) > 0 {
 			cbCtx.IstioGateways = istioGateways
 			cbCtx.IstioVirtualServices = istioServices
@@ -81,11 +171,22 @@ func (c AppGwIngressController) Process(event events.Event) error {
 		}
 	}
 
-	cbCtx.IngressList = c.PruneIngress(&appGw, cbCtx)
-	if len(cbCtx.IngressList) == 0 && !cbCtx.EnvVariables.EnableIstioIntegration {
+	if cbCtx.EnvVariables.EnableGatewayAPI {
+		gateways := c.k8sContext.ListGatewayAPIGateways()
+		if len(gateways) > 0 {
+			cbCtx.GatewayAPIGateways = gateways
+			cbCtx.GatewayAPIRoutes = c.k8sContext.ListHTTPRoutes()
+		} else {
+			glog.Warning("Gateway API is enabled, but AGIC did not find any Gateways of its GatewayClasses; Disabling Gateway API.")
+			cbCtx.EnvVariables.EnableGatewayAPI = false
+		}
+	}
+
+	cbCtx.IngressList = c.PruneIngress(appGw, cbCtx)
+	if len(cbCtx.IngressList) == 0 && !cbCtx.EnvVariables.EnableIstioIntegration && !cbCtx.EnvVariables.EnableGatewayAPI {
 		errorLine := "no Ingress in the pruned Ingress list. Please check Ingress events to get more information"
 		glog.Error(errorLine)
-		return nil
+		return cbCtx, nil, ErrNoIngressInPrunedList
 	}
 
 	if cbCtx.EnvVariables.EnableIstioIntegration {
@@ -99,86 +200,40 @@ func (c AppGwIngressController) Process(event events.Event) error {
 	// Run fatal validations on the existing config of the Application Gateway.
 	if err := appgw.FatalValidateOnExistingConfig(c.recorder, appGw.ApplicationGatewayPropertiesFormat, cbCtx.EnvVariables); err != nil {
 		glog.Error("Got a fatal validation error on existing Application Gateway config. Will retry getting Application Gateway until error is resolved:", err)
-		return err
+		return cbCtx, nil, err
 	}
 
 	// Create a configbuilder based on current appgw config
-	configBuilder := appgw.NewConfigBuilder(c.k8sContext, &c.appGwIdentifier, &appGw, c.recorder)
+	configBuilder := appgw.NewConfigBuilder(c.k8sContext, &c.appGwIdentifier, appGw, c.recorder)
 
 	// Run validations on the Kubernetes resources which can suggest misconfiguration.
-	if err = configBuilder.PreBuildValidate(cbCtx); err != nil {
+	if err := configBuilder.PreBuildValidate(cbCtx); err != nil {
 		glog.Error("ConfigBuilder PostBuildValidate returned error:", err)
 	}
 
-	var generatedAppGw *n.ApplicationGateway
 	// Replace the current appgw config with the generated one
-	if generatedAppGw, err = configBuilder.Build(cbCtx); err != nil {
+	generatedAppGw, err := configBuilder.Build(cbCtx)
+	if err != nil {
 		glog.Error("ConfigBuilder Build returned error:", err)
-		return err
+		return cbCtx, nil, err
 	}
 
 	// Run post validations to report errors in the config generation.
-	if err = configBuilder.PostBuildValidate(cbCtx); err != nil {
+	if err := configBuilder.PostBuildValidate(cbCtx); err != nil {
 		glog.Error("ConfigBuilder PostBuildValidate returned error:", err)
 	}
 
-	if c.configIsSame(&appGw) {
-		// update ingresses with appgw gateway ip address
-		c.updateIngressStatus(generatedAppGw, cbCtx, event)
-
-		glog.V(3).Info("cache: Config has NOT changed! No need to connect to ARM.")
-		return nil
-	}
-
-	glog.V(3).Info("BEGIN AppGateway deployment")
-	defer glog.V(3).Info("END AppGateway deployment")
-
-	deploymentStart := time.Now()
-	// Initiate deployment
-	appGwFuture, err := c.appGwClient.CreateOrUpdate(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, *generatedAppGw)
-	if err != nil {
-		// Reset cache
-		c.configCache = nil
-		configJSON, _ := dumpSanitizedJSON(&appGw, cbCtx.EnvVariables.EnableSaveConfigToFile, nil)
-		glogIt := glog.Errorf
-		if cbCtx.EnvVariables.EnablePanicOnPutError {
-			glogIt = glog.Fatalf
-		}
-		glogIt("Failed applying App Gwy configuration: %s -- %s", err, string(configJSON))
-		return err
-	}
-	// Wait until deployment finshes and save the error message
-	err = appGwFuture.WaitForCompletionRef(ctx, c.appGwClient.BaseClient.Client)
-	configJSON, _ := dumpSanitizedJSON(&appGw, cbCtx.EnvVariables.EnableSaveConfigToFile, nil)
-	glog.V(5).Info(string(configJSON))
-
-	// We keep this at log level 1 to show some heartbeat in the logs. Without this it is way too quiet.
-	glog.V(1).Infof("Applied App Gateway config in %+v", time.Now().Sub(deploymentStart).String())
-
-	if err != nil {
-		// Reset cache
-		c.configCache = nil
-		glog.Warning("Unable to deploy App Gateway config.", err)
-		return ErrDeployingAppGatewayConfig
-	}
-
-	glog.V(3).Info("cache: Updated with latest applied config.")
-	c.updateCache(&appGw)
-
-	// update ingresses with appgw gateway ip address
-	c.updateIngressStatus(generatedAppGw, cbCtx, event)
-
-	return nil
+	return cbCtx, generatedAppGw, nil
 }
 
 func (c AppGwIngressController) updateIngressStatus(appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, event events.Event) {
-	ingress, ok := event.Value.(*v1beta1.Ingress)
+	ingress, ok := event.Value.(*networking.Ingress)
 	if !ok {
 		return
 	}
 
 	// check if this ingress is for AGIC or not, it might have been updated
-	if !k8scontext.IsIngressApplicationGateway(ingress) || !cbCtx.InIngressList(ingress) {
+	if !c.k8sContext.IsIngressApplicationGateway(ingress) || !cbCtx.InIngressList(ingress) {
 		if err := c.k8sContext.UpdateIngressStatus(*ingress, ""); err != nil {
 			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonUnableToUpdateIngressStatus, err.Error())
 		}
@@ -200,7 +255,8 @@ func (c AppGwIngressController) updateIngressStatus(appGw *n.ApplicationGateway,
 func (c AppGwIngressController) updateIPAddressMap(appGw *n.ApplicationGateway) {
 	for _, ipConf := range *appGw.FrontendIPConfigurations {
 		if _, ok := c.ipAddressMap[*ipConf.ID]; ok {
-			return
+			// Keep looking up the addresses, which are not known yet; a lookup of a public IP may have failed.
+			continue
 		}
 
 		if ipConf.PrivateIPAddress != nil {
@@ -215,7 +271,7 @@ func (c AppGwIngressController) updateIPAddressMap(appGw *n.ApplicationGateway)
 func (c AppGwIngressController) getPublicIPAddress(subscriptionID azure.SubscriptionID, resourceGroup azure.ResourceGroup, publicIPName azure.ResourceName) *k8scontext.IPAddress {
 	ctx := context.Background()
 	// initialize public ip client using auth used with appgw client
-	publicIPClient := n.NewPublicIPAddressesClient(string(subscriptionID))
+	publicIPClient := n.NewPublicIPAddressesClientWithBaseURI(c.appGwClient.BaseURI, string(subscriptionID))
 	publicIPClient.Authorizer = c.appGwClient.Authorizer
 	// get public ip
 	publicIP, err := publicIPClient.Get(ctx, string(resourceGroup), string(publicIPName), "")
@@ -6,13 +6,18 @@
 package controller
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"time"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
@@ -20,9 +25,11 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -34,7 +41,7 @@ var _ = Describe("process function tests", func() {
 	var k8sClient kubernetes.Interface
 	var ctxt *k8scontext.Context
 	var stopChannel chan struct{}
-	var ingress *v1beta1.Ingress
+	var ingress *networking.Ingress
 	ns := &v1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: tests.Namespace,
@@ -47,18 +54,20 @@ var _ = Describe("process function tests", func() {
 
 		// Create the mock K8s client.
 		k8sClient = testclient.NewSimpleClientset()
+		tests.AddNetworkingV1Resources(k8sClient)
 		crdClient := fake.NewSimpleClientset()
 		istioCrdClient := istio_fake.NewSimpleClientset()
+		gatewayCrdClient := gateway_fake.NewSimpleClientset()
 		ingress = tests.NewIngressFixture()
 
 		// Create a `k8scontext` to start listening to ingress resources.
-		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, []string{tests.Namespace}, 1000*time.Second)
+		ctxt = k8scontext.NewContext(k8sClient, crdClient, istioCrdClient, gatewayCrdClient, []string{tests.Namespace}, 1000*time.Second, metricstore.NewFakeMetricStore())
 
-		_, err := k8sClient.CoreV1().Namespaces().Create(ns)
+		_, err := k8sClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
 		Expect(err).Should(BeNil(), "Unable to create the namespace %s: %v", tests.Name, err)
 
 		// create ingress in namespace
-		_, err = k8sClient.ExtensionsV1beta1().Ingresses(tests.Namespace).Create(ingress)
+		_, err = k8sClient.NetworkingV1().Ingresses(tests.Namespace).Create(context.TODO(), ingress, metav1.CreateOptions{})
 		Expect(err).Should(BeNil(), "Unabled to create ingress resource due to: %v", err)
 
 		Expect(ctxt).ShouldNot(BeNil(), "Unable to create `k8scontext`")
@@ -67,14 +76,15 @@ var _ = Describe("process function tests", func() {
 		newConfs := append(*appGw.FrontendIPConfigurations, fixtures.GetPrivateIPConfiguration())
 		appGw.FrontendIPConfigurations = &newConfs
 		controller = &AppGwIngressController{
-			k8sContext: ctxt,
+			metricStore: metricstore.NewFakeMetricStore(),
+			k8sContext:  ctxt,
 			ipAddressMap: map[string]k8scontext.IPAddress{
 				*fixtures.GetPublicIPConfiguration().ID:  publicIP,
 				*fixtures.GetPrivateIPConfiguration().ID: privateIP,
 			},
 		}
 		cbCtx = &appgw.ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{
+			IngressList: []*networking.Ingress{
 				ingress,
 			},
 		}
@@ -88,7 +98,7 @@ var _ = Describe("process function tests", func() {
 				Type:  events.Update,
 				Value: ingress,
 			})
-			updatedIngress, _ := k8sClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Get(ingress.Name, metav1.GetOptions{})
+			updatedIngress, _ := k8sClient.NetworkingV1().Ingresses(ingress.Namespace).Get(context.TODO(), ingress.Name, metav1.GetOptions{})
 			Expect(updatedIngress.Status.LoadBalancer.Ingress).Should(ContainElement(v1.LoadBalancerIngress{
 				Hostname: "",
 				IP:       string(publicIP),
@@ -98,20 +108,20 @@ var _ = Describe("process function tests", func() {
 
 		It("ensure that updateIngressStatus removes ip to ingress not for AGIC", func() {
 			ingress.Annotations[annotations.IngressClassKey] = "otheric"
-			updatedIngress, _ := k8sClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Update(ingress)
+			updatedIngress, _ := k8sClient.NetworkingV1().Ingresses(ingress.Namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{})
 			controller.k8sContext.UpdateIngressStatus(*ingress, k8scontext.IPAddress(publicIP))
 			controller.updateIngressStatus(&appGw, cbCtx, events.Event{
 				Type:  events.Update,
 				Value: ingress,
 			})
-			updatedIngress, _ = k8sClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Get(ingress.Name, metav1.GetOptions{})
+			updatedIngress, _ = k8sClient.NetworkingV1().Ingresses(ingress.Namespace).Get(context.TODO(), ingress.Name, metav1.GetOptions{})
 			Expect(annotations.IsApplicationGatewayIngress(updatedIngress)).To(BeFalse())
 			Expect(len(updatedIngress.Status.LoadBalancer.Ingress)).To(Equal(0))
 		})
 
 		It("ensure that updateIngressStatus adds private ip when annotation is present", func() {
 			ingress.Annotations[annotations.UsePrivateIPKey] = "true"
-			updatedIngress, _ := k8sClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Update(ingress)
+			updatedIngress, _ := k8sClient.NetworkingV1().Ingresses(ingress.Namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{})
 			Expect(annotations.UsePrivateIP(updatedIngress)).To(BeTrue())
 
 			controller.updateIngressStatus(&appGw, cbCtx, events.Event{
@@ -119,7 +129,7 @@ var _ = Describe("process function tests", func() {
 				Value: ingress,
 			})
 
-			updatedIngress, _ = k8sClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Get(ingress.Name, metav1.GetOptions{})
+			updatedIngress, _ = k8sClient.NetworkingV1().Ingresses(ingress.Namespace).Get(context.TODO(), ingress.Name, metav1.GetOptions{})
 			Expect(updatedIngress.Status.LoadBalancer.Ingress).Should(ContainElement(v1.LoadBalancerIngress{
 				Hostname: "",
 				IP:       string(privateIP),
@@ -127,4 +137,38 @@ var _ = Describe("process function tests", func() {
 			Expect(len(updatedIngress.Status.LoadBalancer.Ingress)).To(Equal(1))
 		})
 	})
+
+	Context("test updateIPAddressMap", func() {
+		It("retries the lookup of a public IP address, which failed, when the private IP configuration comes first", func() {
+			var lookups int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&lookups, 1) == 1 {
+					// e.g. the role assignment of AGIC has not propagated yet
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"properties": {"ipAddress": "20.1.2.3"}}`))
+			}))
+			defer server.Close()
+
+			publicIPConf := fixtures.GetPublicIPConfiguration()
+			publicIPConf.PublicIPAddress = &n.SubResource{ID: to.StringPtr("/subscriptions/subid/resourceGroups/rg/providers/Microsoft.Network/publicIPAddresses/appgw-ip")}
+			appGw.FrontendIPConfigurations = &[]n.ApplicationGatewayFrontendIPConfiguration{fixtures.GetPrivateIPConfiguration(), publicIPConf}
+			controller.appGwClient = n.NewApplicationGatewaysClientWithBaseURI(server.URL, "subid")
+			controller.ipAddressMap = map[string]k8scontext.IPAddress{}
+
+			controller.updateIPAddressMap(&appGw)
+			Expect(controller.ipAddressMap).To(Equal(map[string]k8scontext.IPAddress{
+				*fixtures.GetPrivateIPConfiguration().ID: "abc",
+			}))
+
+			controller.updateIPAddressMap(&appGw)
+			Expect(atomic.LoadInt32(&lookups)).To(Equal(int32(2)))
+			Expect(controller.ipAddressMap).To(Equal(map[string]k8scontext.IPAddress{
+				*fixtures.GetPrivateIPConfiguration().ID: "abc",
+				*publicIPConf.ID:                         "20.1.2.3",
+			}))
+		})
+	})
 })
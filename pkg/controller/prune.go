@@ -4,10 +4,10 @@ import (
 	"fmt"
 	"sync"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
@@ -16,43 +16,66 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
-type pruneFunc func(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress
+type pruneFunc func(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*networking.Ingress) []*networking.Ingress
+
+// namedPruneFunc pairs a pruneFunc with the reason reported in metrics for the Ingresses it drops.
+type namedPruneFunc struct {
+	reason string
+	prune  pruneFunc
+}
 
 var once sync.Once
-var pruneFuncList []pruneFunc
+var pruneFuncList []namedPruneFunc
 
 // PruneIngress filters ingress list based on filter functions and returns a filtered ingress list
-func (c *AppGwIngressController) PruneIngress(appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext) []*v1beta1.Ingress {
+func (c *AppGwIngressController) PruneIngress(appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext) []*networking.Ingress {
 	once.Do(func() {
 		if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-			pruneFuncList = append(pruneFuncList, pruneProhibitedIngress)
+			pruneFuncList = append(pruneFuncList, namedPruneFunc{"prohibited-target", pruneProhibitedIngress})
 		}
-		pruneFuncList = append(pruneFuncList, pruneNoPrivateIP)
-		pruneFuncList = append(pruneFuncList, pruneRedirectWithNoTLS)
+		pruneFuncList = append(pruneFuncList, namedPruneFunc{"no-private-ip", pruneNoPrivateIP})
+		pruneFuncList = append(pruneFuncList, namedPruneFunc{"ssl-certificate-not-found", pruneAppGwSslCertificateNotFound})
+		pruneFuncList = append(pruneFuncList, namedPruneFunc{"redirect-with-no-tls", pruneRedirectWithNoTLS})
 	})
 	prunedIngresses := cbCtx.IngressList
-	for _, prune := range pruneFuncList {
-		prunedIngresses = prune(c, appGw, cbCtx, prunedIngresses)
+	for _, pruner := range pruneFuncList {
+		ingressCount := len(prunedIngresses)
+		prunedIngresses = pruner.prune(c, appGw, cbCtx, prunedIngresses)
+		if dropped := ingressCount - len(prunedIngresses); dropped > 0 {
+			c.metricStore.AddPrunedIngressCounter(pruner.reason, dropped)
+		}
 	}
 
 	return prunedIngresses
 }
 
-// pruneProhibitedIngress filters rules that are specified by prohibited target CRD
-func pruneProhibitedIngress(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
+// pruneProhibitedIngress filters rules that are specified by prohibited target CRD; Ingresses left without rules and
+// without a default backend are dropped.
+func pruneProhibitedIngress(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*networking.Ingress) []*networking.Ingress {
+	var prunedIngresses []*networking.Ingress
 	// Mutate the list of Ingresses by removing ones that AGIC should not be creating configuration.
 	for idx, ingress := range ingressList {
 		glog.V(5).Infof("Original Ingress[%d] Rules: %+v", idx, ingress.Spec.Rules)
-		ingressList[idx].Spec.Rules = brownfield.PruneIngressRules(ingress, cbCtx.ProhibitedTargets)
+		getFrontends := func(rule *networking.IngressRule) []brownfield.ListenerFrontend {
+			return appgw.IngressRuleFrontends(c.k8sContext, appGw, c.recorder, cbCtx, ingress, rule)
+		}
+		hasRules := len(ingress.Spec.Rules) > 0
+		ingressList[idx].Spec.Rules = brownfield.PruneIngressRules(ingress, cbCtx.ProhibitedTargets, getFrontends)
 		glog.V(5).Infof("Sanitized Ingress[%d] Rules: %+v", idx, ingress.Spec.Rules)
+
+		if hasRules && len(ingress.Spec.Rules) == 0 && ingress.Spec.DefaultBackend == nil {
+			glog.V(3).Infof("Ingress %s/%s is dropped; all of its rules are prohibited", ingress.Namespace, ingress.Name)
+			continue
+		}
+		prunedIngresses = append(prunedIngresses, ingress)
 	}
 
-	return ingressList
+	return prunedIngresses
 }
 
 // pruneNoPrivateIP filters ingresses which use private IP annotation when AppGw doesn't have a private IP
-func pruneNoPrivateIP(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
-	var prunedIngresses []*v1beta1.Ingress
+func pruneNoPrivateIP(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*networking.Ingress) []*networking.Ingress {
+	var prunedIngresses []*networking.Ingress
 	appGwHasPrivateIP := appgw.LookupIPConfigurationByType(appGw.FrontendIPConfigurations, true) != nil
 	for _, ingress := range ingressList {
 		usePrivateIP, err := annotations.UsePrivateIP(ingress)
@@ -73,14 +96,45 @@ func pruneNoPrivateIP(c *AppGwIngressController, appGw *n.ApplicationGateway, cb
 	return prunedIngresses
 }
 
+// pruneAppGwSslCertificateNotFound filters ingresses which reference a certificate, which is not installed on App Gateway
+func pruneAppGwSslCertificateNotFound(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*networking.Ingress) []*networking.Ingress {
+	installedCertificates := make(map[string]interface{})
+	if appGw.SslCertificates != nil {
+		for _, cert := range *appGw.SslCertificates {
+			if cert.Name != nil {
+				installedCertificates[*cert.Name] = nil
+			}
+		}
+	}
+
+	var prunedIngresses []*networking.Ingress
+	for _, ingress := range ingressList {
+		certName, err := annotations.AppGwSslCertificate(ingress)
+		if err != nil && errors.IsInvalidContent(err) {
+			glog.Errorf("Ingress %s/%s has invalid value for annotation %s", ingress.Namespace, ingress.Name, annotations.AppGwSslCertificateKey)
+		}
+		if _, installed := installedCertificates[certName]; err == nil && !installed {
+			errorLine := fmt.Sprintf("ignoring Ingress %s/%s as it references certificate %s, which is not installed on Application Gateway %s", ingress.Namespace, ingress.Name, certName, c.appGwIdentifier.AppGwName)
+			glog.Error(errorLine)
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonAppGwSslCertificateNotFound, errorLine)
+		} else {
+			prunedIngresses = append(prunedIngresses, ingress)
+		}
+	}
+
+	return prunedIngresses
+}
+
 // pruneRedirectWithNoTLS filters ingresses which are annotated for ssl redirect but don't have a TLS section in the spec
-func pruneRedirectWithNoTLS(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
-	var prunedIngresses []*v1beta1.Ingress
+func pruneRedirectWithNoTLS(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*networking.Ingress) []*networking.Ingress {
+	var prunedIngresses []*networking.Ingress
 	for _, ingress := range ingressList {
-		hasTLS := ingress.Spec.TLS != nil && len(ingress.Spec.TLS) > 0
+		_, keyVaultErr := annotations.KeyVaultSecretID(ingress)
+		_, installedErr := annotations.AppGwSslCertificate(ingress)
+		hasTLS := (ingress.Spec.TLS != nil && len(ingress.Spec.TLS) > 0) || keyVaultErr == nil || installedErr == nil
 		sslRedirect, _ := annotations.IsSslRedirect(ingress)
 		if !hasTLS && sslRedirect {
-			errorLine := fmt.Sprintf("ignoring Ingress %s/%s as it has an invalid spec. It is annotated with ssl-redirect: true but is missing a TLS secret. Please add a TLS secret or remove ssl-redirect annotation", ingress.Namespace, ingress.Name)
+			errorLine := fmt.Sprintf("ignoring Ingress %s/%s as it has an invalid spec. It is annotated with ssl-redirect: true but is missing a TLS secret. Please add a TLS secret, a Key Vault secret ID, an App Gateway certificate, or remove ssl-redirect annotation", ingress.Namespace, ingress.Name)
 			glog.Error(errorLine)
 			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonRedirectWithNoTLS, errorLine)
 		} else {
@@ -6,15 +6,24 @@
 package controller
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	v1beta1 "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -24,6 +33,7 @@ var _ = Describe("prune function tests", func() {
 
 	BeforeEach(func() {
 		controller = &AppGwIngressController{
+			metricStore: metricstore.NewFakeMetricStore(),
 			appGwIdentifier: appgw.Identifier{
 				SubscriptionID: "xxxx",
 				ResourceGroup:  "xxxx",
@@ -40,7 +50,7 @@ var _ = Describe("prune function tests", func() {
 		}
 		ingressPublic := tests.NewIngressFixture()
 		cbCtx := &appgw.ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{
+			IngressList: []*networking.Ingress{
 				ingressPrivate,
 				ingressPublic,
 			},
@@ -68,6 +78,34 @@ var _ = Describe("prune function tests", func() {
 		})
 	})
 
+	Context("ensure pruneAppGwSslCertificateNotFound prunes ingress", func() {
+		ingressInstalled := tests.NewIngressFixture()
+		ingressInstalled.Annotations = map[string]string{
+			annotations.AppGwSslCertificateKey: fixtures.CertificateName1,
+		}
+		ingressMissing := tests.NewIngressFixture()
+		ingressMissing.Annotations = map[string]string{
+			annotations.AppGwSslCertificateKey: "not-installed",
+		}
+		ingressWithoutAnnotation := tests.NewIngressFixture()
+		cbCtx := &appgw.ConfigBuilderContext{
+			IngressList: []*networking.Ingress{
+				ingressInstalled,
+				ingressMissing,
+				ingressWithoutAnnotation,
+			},
+		}
+		appGw := fixtures.GetAppGateway()
+
+		It("removes the ingress referencing a certificate, which is not installed", func() {
+			prunedIngresses := pruneAppGwSslCertificateNotFound(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(HaveLen(2))
+			Expect(prunedIngresses).To(ContainElement(ingressInstalled))
+			Expect(prunedIngresses).To(ContainElement(ingressWithoutAnnotation))
+			Expect(controller.recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("not-installed")))
+		})
+	})
+
 	Context("ensure pruneRedirectNoTLS prunes ingress", func() {
 		// invalid ingress without https and redirect
 		ingressInvalid := tests.NewIngressFixture()
@@ -101,11 +139,20 @@ var _ = Describe("prune function tests", func() {
 			Expect(ingressValid2.Spec.TLS).To(BeNil())
 		})
 
+		// valid ingress with a Key Vault certificate and redirect
+		ingressValid3 := tests.NewIngressFixture()
+		ingressValid3.Annotations = map[string]string{
+			annotations.SslRedirectKey:      "true",
+			annotations.KeyVaultSecretIDKey: "https://contoso.vault.azure.net/secrets/www-contoso-com",
+		}
+		ingressValid3.Spec.TLS = nil
+
 		cbCtx := &appgw.ConfigBuilderContext{
-			IngressList: []*v1beta1.Ingress{
+			IngressList: []*networking.Ingress{
 				ingressInvalid,
 				ingressValid1,
 				ingressValid2,
+				ingressValid3,
 			},
 			ServiceList: []*v1.Service{
 				tests.NewServiceFixture(),
@@ -114,11 +161,39 @@ var _ = Describe("prune function tests", func() {
 		appGw := fixtures.GetAppGateway()
 		It("removes the invalid ingresses", func() {
 			prunedIngresses := pruneRedirectWithNoTLS(controller, &appGw, cbCtx, cbCtx.IngressList)
-			Expect(len(cbCtx.IngressList)).To(Equal(3))
-			Expect(len(prunedIngresses)).To(Equal(2))
+			Expect(len(cbCtx.IngressList)).To(Equal(4))
+			Expect(len(prunedIngresses)).To(Equal(3))
 			Expect(prunedIngresses).To(Not(ContainElement(ingressInvalid)))
 			Expect(prunedIngresses).To(ContainElement(ingressValid1))
 			Expect(prunedIngresses).To(ContainElement(ingressValid2))
+			Expect(prunedIngresses).To(ContainElement(ingressValid3))
+		})
+	})
+
+	Context("ensure pruneProhibitedIngress prunes ingress", func() {
+		It("removes the ingress, whose rules are all prohibited, and keeps others", func() {
+			controller.k8sContext = k8scontext.NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istio_fake.NewSimpleClientset(), gateway_fake.NewSimpleClientset(), []string{tests.Namespace}, 1000*time.Second, metricstore.NewFakeMetricStore())
+			prohibited := tests.NewIngressFixture()
+			prohibited.Name = "prohibited"
+			allowed := tests.NewIngressFixture()
+			allowed.Spec.Rules = append(allowed.Spec.Rules, tests.NewIngressRuleFixture(tests.OtherHost, tests.URLPath1, *tests.NewIngressBackendFixture(tests.ServiceName, 80)))
+			defaultBackend := tests.NewIngressFixture()
+			defaultBackend.Name = "default-backend"
+			defaultBackend.Spec.DefaultBackend = tests.NewIngressBackendFixture(tests.ServiceName, 80)
+
+			cbCtx := &appgw.ConfigBuilderContext{
+				IngressList: []*networking.Ingress{prohibited, allowed, defaultBackend},
+				ProhibitedTargets: []*ptv1.AzureIngressProhibitedTarget{
+					{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host}},
+				},
+			}
+			appGw := fixtures.GetAppGateway()
+
+			prunedIngresses := pruneProhibitedIngress(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(ConsistOf(allowed, defaultBackend))
+			Expect(allowed.Spec.Rules).To(HaveLen(1))
+			Expect(allowed.Spec.Rules[0].Host).To(Equal(tests.OtherHost))
+			Expect(defaultBackend.Spec.Rules).To(BeEmpty())
 		})
 	})
 })
@@ -9,13 +9,23 @@ import (
 	"fmt"
 
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/api/core/v1"
 
+	rewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
 // ShouldProcess determines whether to process an event.
 func (c AppGwIngressController) ShouldProcess(event events.Event) (bool, *string) {
+	shouldProcess, reason := c.shouldProcess(event)
+	if !shouldProcess {
+		c.metricStore.IncSkippedEventCounter(eventResource(event))
+	}
+	return shouldProcess, reason
+}
+
+func (c AppGwIngressController) shouldProcess(event events.Event) (bool, *string) {
 	if pod, ok := event.Value.(*v1.Pod); ok {
 		if pod.Namespace == "kube-system" {
 			// Ignore kube-system namespace events
@@ -36,5 +46,51 @@ func (c AppGwIngressController) ShouldProcess(event events.Event) (bool, *string
 		return c.k8sContext.IsEndpointReferencedByAnyIngress(endpoints), to.StringPtr(reason)
 	}
 
+	if configMap, ok := event.Value.(*v1.ConfigMap); ok {
+		// only the config maps with backend CA certificates change the App Gateway config
+		reason := fmt.Sprintf("config map %s/%s is not used by any Ingress", configMap.Namespace, configMap.Name)
+		return c.k8sContext.IsConfigMapReferencedByAnyIngress(configMap), to.StringPtr(reason)
+	}
+
+	if rewrite, ok := event.Value.(*rewritev1.AzureIngressRewrite); ok {
+		reason := fmt.Sprintf("AzureIngressRewrite %s/%s is not used by any Ingress", rewrite.Namespace, rewrite.Name)
+		return c.k8sContext.IsAzureIngressRewriteReferencedByAnyIngress(rewrite), to.StringPtr(reason)
+	}
+
+	if namespace, ok := event.Value.(*v1.Namespace); ok {
+		// only the labels of namespaces selected by the listeners of the Gateways change which routes attach to them
+		reason := fmt.Sprintf("namespace %s is not selected by any Gateway listener", namespace.Name)
+		return c.k8sContext.IsNamespaceSelectedByAnyGateway(), to.StringPtr(reason)
+	}
+
+	if virtualService, ok := event.Value.(*v1alpha3.VirtualService); ok {
+		// a VirtualService unbound from the Gateways must still remove its config from App Gateway
+		if oldVirtualService, ok := event.OldValue.(*v1alpha3.VirtualService); ok && c.k8sContext.IsVirtualServiceReferencedByAnyGateway(oldVirtualService) {
+			return true, nil
+		}
+		reason := fmt.Sprintf("VirtualService %s/%s is not bound to any Istio Gateway annotated for App Gateway", virtualService.Namespace, virtualService.Name)
+		return c.k8sContext.IsVirtualServiceReferencedByAnyGateway(virtualService), to.StringPtr(reason)
+	}
+
 	return true, nil
 }
+
+// eventResource returns the kind of the Kubernetes resource an event is about; used to label metrics.
+func eventResource(event events.Event) string {
+	switch event.Value.(type) {
+	case *v1.Pod:
+		return "pod"
+	case *v1.Endpoints:
+		return "endpoints"
+	case *v1.ConfigMap:
+		return "configmap"
+	case *rewritev1.AzureIngressRewrite:
+		return "azureingressrewrite"
+	case *v1alpha3.VirtualService:
+		return "virtualservice"
+	case *v1.Namespace:
+		return "namespace"
+	default:
+		return "other"
+	}
+}
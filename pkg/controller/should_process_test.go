@@ -0,0 +1,80 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"time"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gateway_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+)
+
+var _ = Describe("should process tests", func() {
+	var controller *AppGwIngressController
+	var bound, unbound *v1alpha3.VirtualService
+
+	BeforeEach(func() {
+		ctxt := k8scontext.NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istio_fake.NewSimpleClientset(), gateway_fake.NewSimpleClientset(), []string{"ns"}, 1000*time.Second, metricstore.NewFakeMetricStore())
+		gateway := &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "appgw",
+				Namespace:   "ns",
+				Annotations: map[string]string{annotations.IstioGatewayKey: annotations.ApplicationGatewayIngressClass},
+			},
+		}
+		Expect(ctxt.Caches.IstioGateway.Add(gateway)).To(Succeed())
+
+		controller = &AppGwIngressController{
+			metricStore: metricstore.NewFakeMetricStore(),
+			k8sContext:  ctxt,
+		}
+
+		bound = &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "vs", Namespace: "ns"},
+			Spec:       v1alpha3.VirtualServiceSpec{Gateways: []string{"appgw"}},
+		}
+		unbound = bound.DeepCopy()
+		unbound.Spec.Gateways = []string{"mesh"}
+	})
+
+	Context("Test virtual service events", func() {
+		It("should process the events of a virtual service bound to the gateway", func() {
+			shouldProcess, _ := controller.ShouldProcess(events.Event{Type: events.Create, Value: bound})
+			Expect(shouldProcess).To(BeTrue())
+			shouldProcess, _ = controller.ShouldProcess(events.Event{Type: events.Delete, Value: bound})
+			Expect(shouldProcess).To(BeTrue())
+		})
+
+		It("should skip the events of a virtual service not bound to the gateway", func() {
+			shouldProcess, reason := controller.ShouldProcess(events.Event{Type: events.Create, Value: unbound})
+			Expect(shouldProcess).To(BeFalse())
+			Expect(reason).ToNot(BeNil())
+			shouldProcess, _ = controller.ShouldProcess(events.Event{Type: events.Update, Value: unbound, OldValue: unbound.DeepCopy()})
+			Expect(shouldProcess).To(BeFalse())
+		})
+
+		It("should process the update of a virtual service unbound from the gateway", func() {
+			shouldProcess, _ := controller.ShouldProcess(events.Event{Type: events.Update, Value: unbound, OldValue: bound})
+			Expect(shouldProcess).To(BeTrue())
+		})
+
+		It("should process the update of a virtual service bound to the gateway", func() {
+			shouldProcess, _ := controller.ShouldProcess(events.Event{Type: events.Update, Value: bound, OldValue: unbound})
+			Expect(shouldProcess).To(BeTrue())
+		})
+	})
+})
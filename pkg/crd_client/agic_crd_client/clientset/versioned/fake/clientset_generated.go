@@ -22,6 +22,8 @@ import (
 	clientset "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
 	azureingressprohibitedtargetsv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/typed/azureingressprohibitedtarget/v1"
 	fakeazureingressprohibitedtargetsv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/typed/azureingressprohibitedtarget/v1/fake"
+	azureingressrewritesv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/typed/azureingressrewrite/v1"
+	fakeazureingressrewritesv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/typed/azureingressrewrite/v1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -80,3 +82,8 @@ var _ clientset.Interface = &Clientset{}
 func (c *Clientset) AzureingressprohibitedtargetsV1() azureingressprohibitedtargetsv1.AzureingressprohibitedtargetsV1Interface {
 	return &fakeazureingressprohibitedtargetsv1.FakeAzureingressprohibitedtargetsV1{Fake: &c.Fake}
 }
+
+// AzureingressrewritesV1 retrieves the AzureingressrewritesV1Client
+func (c *Clientset) AzureingressrewritesV1() azureingressrewritesv1.AzureingressrewritesV1Interface {
+	return &fakeazureingressrewritesv1.FakeAzureingressrewritesV1{Fake: &c.Fake}
+}
@@ -19,6 +19,7 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"time"
 
 	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
@@ -37,14 +38,14 @@ type AzureIngressProhibitedTargetsGetter interface {
 
 // AzureIngressProhibitedTargetInterface has methods to work with AzureIngressProhibitedTarget resources.
 type AzureIngressProhibitedTargetInterface interface {
-	Create(*v1.AzureIngressProhibitedTarget) (*v1.AzureIngressProhibitedTarget, error)
-	Update(*v1.AzureIngressProhibitedTarget) (*v1.AzureIngressProhibitedTarget, error)
-	Delete(name string, options *metav1.DeleteOptions) error
-	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
-	Get(name string, options metav1.GetOptions) (*v1.AzureIngressProhibitedTarget, error)
-	List(opts metav1.ListOptions) (*v1.AzureIngressProhibitedTargetList, error)
-	Watch(opts metav1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AzureIngressProhibitedTarget, err error)
+	Create(ctx context.Context, azureIngressProhibitedTarget *v1.AzureIngressProhibitedTarget, opts metav1.CreateOptions) (*v1.AzureIngressProhibitedTarget, error)
+	Update(ctx context.Context, azureIngressProhibitedTarget *v1.AzureIngressProhibitedTarget, opts metav1.UpdateOptions) (*v1.AzureIngressProhibitedTarget, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.AzureIngressProhibitedTarget, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.AzureIngressProhibitedTargetList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.AzureIngressProhibitedTarget, err error)
 	AzureIngressProhibitedTargetExpansion
 }
 
@@ -63,20 +64,20 @@ func newAzureIngressProhibitedTargets(c *AzureingressprohibitedtargetsV1Client,
 }
 
 // Get takes name of the azureIngressProhibitedTarget, and returns the corresponding azureIngressProhibitedTarget object, and an error if there is any.
-func (c *azureIngressProhibitedTargets) Get(name string, options metav1.GetOptions) (result *v1.AzureIngressProhibitedTarget, err error) {
+func (c *azureIngressProhibitedTargets) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.AzureIngressProhibitedTarget, err error) {
 	result = &v1.AzureIngressProhibitedTarget{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("azureingressprohibitedtargets").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of AzureIngressProhibitedTargets that match those selectors.
-func (c *azureIngressProhibitedTargets) List(opts metav1.ListOptions) (result *v1.AzureIngressProhibitedTargetList, err error) {
+func (c *azureIngressProhibitedTargets) List(ctx context.Context, opts metav1.ListOptions) (result *v1.AzureIngressProhibitedTargetList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -87,13 +88,13 @@ func (c *azureIngressProhibitedTargets) List(opts metav1.ListOptions) (result *v
 		Resource("azureingressprohibitedtargets").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested azureIngressProhibitedTargets.
-func (c *azureIngressProhibitedTargets) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+func (c *azureIngressProhibitedTargets) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -104,71 +105,74 @@ func (c *azureIngressProhibitedTargets) Watch(opts metav1.ListOptions) (watch.In
 		Resource("azureingressprohibitedtargets").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a azureIngressProhibitedTarget and creates it.  Returns the server's representation of the azureIngressProhibitedTarget, and an error, if there is any.
-func (c *azureIngressProhibitedTargets) Create(azureIngressProhibitedTarget *v1.AzureIngressProhibitedTarget) (result *v1.AzureIngressProhibitedTarget, err error) {
+func (c *azureIngressProhibitedTargets) Create(ctx context.Context, azureIngressProhibitedTarget *v1.AzureIngressProhibitedTarget, opts metav1.CreateOptions) (result *v1.AzureIngressProhibitedTarget, err error) {
 	result = &v1.AzureIngressProhibitedTarget{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("azureingressprohibitedtargets").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(azureIngressProhibitedTarget).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a azureIngressProhibitedTarget and updates it. Returns the server's representation of the azureIngressProhibitedTarget, and an error, if there is any.
-func (c *azureIngressProhibitedTargets) Update(azureIngressProhibitedTarget *v1.AzureIngressProhibitedTarget) (result *v1.AzureIngressProhibitedTarget, err error) {
+func (c *azureIngressProhibitedTargets) Update(ctx context.Context, azureIngressProhibitedTarget *v1.AzureIngressProhibitedTarget, opts metav1.UpdateOptions) (result *v1.AzureIngressProhibitedTarget, err error) {
 	result = &v1.AzureIngressProhibitedTarget{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("azureingressprohibitedtargets").
 		Name(azureIngressProhibitedTarget.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(azureIngressProhibitedTarget).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the azureIngressProhibitedTarget and deletes it. Returns an error if one occurs.
-func (c *azureIngressProhibitedTargets) Delete(name string, options *metav1.DeleteOptions) error {
+func (c *azureIngressProhibitedTargets) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("azureingressprohibitedtargets").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *azureIngressProhibitedTargets) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+func (c *azureIngressProhibitedTargets) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("azureingressprohibitedtargets").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched azureIngressProhibitedTarget.
-func (c *azureIngressProhibitedTargets) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AzureIngressProhibitedTarget, err error) {
+func (c *azureIngressProhibitedTargets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.AzureIngressProhibitedTarget, err error) {
 	result = &v1.AzureIngressProhibitedTarget{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("azureingressprohibitedtargets").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
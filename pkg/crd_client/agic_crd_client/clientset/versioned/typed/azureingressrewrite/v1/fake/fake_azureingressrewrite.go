@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	azureingressrewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeAzureIngressRewrites implements AzureIngressRewriteInterface
+type FakeAzureIngressRewrites struct {
+	Fake *FakeAzureingressrewritesV1
+	ns   string
+}
+
+var azureingressrewritesResource = schema.GroupVersionResource{Group: "appgw.ingress.k8s.io", Version: "v1", Resource: "azureingressrewrites"}
+
+var azureingressrewritesKind = schema.GroupVersionKind{Group: "appgw.ingress.k8s.io", Version: "v1", Kind: "AzureIngressRewrite"}
+
+// Get takes name of the azureIngressRewrite, and returns the corresponding azureIngressRewrite object, and an error if there is any.
+func (c *FakeAzureIngressRewrites) Get(ctx context.Context, name string, options v1.GetOptions) (result *azureingressrewritev1.AzureIngressRewrite, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(azureingressrewritesResource, c.ns, name), &azureingressrewritev1.AzureIngressRewrite{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressrewritev1.AzureIngressRewrite), err
+}
+
+// List takes label and field selectors, and returns the list of AzureIngressRewrites that match those selectors.
+func (c *FakeAzureIngressRewrites) List(ctx context.Context, opts v1.ListOptions) (result *azureingressrewritev1.AzureIngressRewriteList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(azureingressrewritesResource, azureingressrewritesKind, c.ns, opts), &azureingressrewritev1.AzureIngressRewriteList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &azureingressrewritev1.AzureIngressRewriteList{ListMeta: obj.(*azureingressrewritev1.AzureIngressRewriteList).ListMeta}
+	for _, item := range obj.(*azureingressrewritev1.AzureIngressRewriteList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested azureIngressRewrites.
+func (c *FakeAzureIngressRewrites) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(azureingressrewritesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a azureIngressRewrite and creates it.  Returns the server's representation of the azureIngressRewrite, and an error, if there is any.
+func (c *FakeAzureIngressRewrites) Create(ctx context.Context, azureIngressRewrite *azureingressrewritev1.AzureIngressRewrite, opts v1.CreateOptions) (result *azureingressrewritev1.AzureIngressRewrite, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(azureingressrewritesResource, c.ns, azureIngressRewrite), &azureingressrewritev1.AzureIngressRewrite{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressrewritev1.AzureIngressRewrite), err
+}
+
+// Update takes the representation of a azureIngressRewrite and updates it. Returns the server's representation of the azureIngressRewrite, and an error, if there is any.
+func (c *FakeAzureIngressRewrites) Update(ctx context.Context, azureIngressRewrite *azureingressrewritev1.AzureIngressRewrite, opts v1.UpdateOptions) (result *azureingressrewritev1.AzureIngressRewrite, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(azureingressrewritesResource, c.ns, azureIngressRewrite), &azureingressrewritev1.AzureIngressRewrite{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressrewritev1.AzureIngressRewrite), err
+}
+
+// Delete takes name of the azureIngressRewrite and deletes it. Returns an error if one occurs.
+func (c *FakeAzureIngressRewrites) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(azureingressrewritesResource, c.ns, name), &azureingressrewritev1.AzureIngressRewrite{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeAzureIngressRewrites) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(azureingressrewritesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &azureingressrewritev1.AzureIngressRewriteList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched azureIngressRewrite.
+func (c *FakeAzureIngressRewrites) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *azureingressrewritev1.AzureIngressRewrite, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(azureingressrewritesResource, c.ns, name, pt, data, subresources...), &azureingressrewritev1.AzureIngressRewrite{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressrewritev1.AzureIngressRewrite), err
+}
@@ -19,6 +19,7 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	time "time"
 
 	azureingressprohibitedtargetv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
@@ -61,13 +62,13 @@ func NewFilteredAzureIngressProhibitedTargetInformer(client versioned.Interface,
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(namespace).List(options)
+				return client.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(namespace).Watch(options)
+				return client.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(namespace).Watch(context.TODO(), options)
 			},
 		},
 		&azureingressprohibitedtargetv1.AzureIngressProhibitedTarget{},
@@ -25,6 +25,7 @@ import (
 
 	versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
 	azureingressprohibitedtarget "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions/azureingressprohibitedtarget"
+	azureingressrewrite "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions/azureingressrewrite"
 	internalinterfaces "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions/internalinterfaces"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -173,8 +174,13 @@ type SharedInformerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 
 	Azureingressprohibitedtargets() azureingressprohibitedtarget.Interface
+	Azureingressrewrites() azureingressrewrite.Interface
 }
 
 func (f *sharedInformerFactory) Azureingressprohibitedtargets() azureingressprohibitedtarget.Interface {
 	return azureingressprohibitedtarget.New(f, f.namespace, f.tweakListOptions)
 }
+
+func (f *sharedInformerFactory) Azureingressrewrites() azureingressrewrite.Interface {
+	return azureingressrewrite.New(f, f.namespace, f.tweakListOptions)
+}
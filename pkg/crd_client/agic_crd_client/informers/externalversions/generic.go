@@ -21,7 +21,8 @@ package externalversions
 import (
 	"fmt"
 
-	azureingressprohibitedtargetv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	azureingressrewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -53,9 +54,13 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=azureingressprohibitedtargets.appgw.ingress.k8s.io, Version=v1
-	case azureingressprohibitedtargetv1.SchemeGroupVersion.WithResource("azureingressprohibitedtargets"):
+	case v1.SchemeGroupVersion.WithResource("azureingressprohibitedtargets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Azureingressprohibitedtargets().V1().AzureIngressProhibitedTargets().Informer()}, nil
 
+		// Group=appgw.ingress.k8s.io, Version=v1
+	case azureingressrewritev1.SchemeGroupVersion.WithResource("azureingressrewrites"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Azureingressrewrites().V1().AzureIngressRewrites().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// AzureIngressRewriteLister helps list AzureIngressRewrites.
+// All objects returned here must be treated as read-only.
+type AzureIngressRewriteLister interface {
+	// List lists all AzureIngressRewrites in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.AzureIngressRewrite, err error)
+	// AzureIngressRewrites returns an object that can list and get AzureIngressRewrites.
+	AzureIngressRewrites(namespace string) AzureIngressRewriteNamespaceLister
+	AzureIngressRewriteListerExpansion
+}
+
+// azureIngressRewriteLister implements the AzureIngressRewriteLister interface.
+type azureIngressRewriteLister struct {
+	indexer cache.Indexer
+}
+
+// NewAzureIngressRewriteLister returns a new AzureIngressRewriteLister.
+func NewAzureIngressRewriteLister(indexer cache.Indexer) AzureIngressRewriteLister {
+	return &azureIngressRewriteLister{indexer: indexer}
+}
+
+// List lists all AzureIngressRewrites in the indexer.
+func (s *azureIngressRewriteLister) List(selector labels.Selector) (ret []*v1.AzureIngressRewrite, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.AzureIngressRewrite))
+	})
+	return ret, err
+}
+
+// AzureIngressRewrites returns an object that can list and get AzureIngressRewrites.
+func (s *azureIngressRewriteLister) AzureIngressRewrites(namespace string) AzureIngressRewriteNamespaceLister {
+	return azureIngressRewriteNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// AzureIngressRewriteNamespaceLister helps list and get AzureIngressRewrites.
+// All objects returned here must be treated as read-only.
+type AzureIngressRewriteNamespaceLister interface {
+	// List lists all AzureIngressRewrites in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.AzureIngressRewrite, err error)
+	// Get retrieves the AzureIngressRewrite from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.AzureIngressRewrite, error)
+	AzureIngressRewriteNamespaceListerExpansion
+}
+
+// azureIngressRewriteNamespaceLister implements the AzureIngressRewriteNamespaceLister
+// interface.
+type azureIngressRewriteNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all AzureIngressRewrites in the indexer for a given namespace.
+func (s azureIngressRewriteNamespaceLister) List(selector labels.Selector) (ret []*v1.AzureIngressRewrite, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.AzureIngressRewrite))
+	})
+	return ret, err
+}
+
+// Get retrieves the AzureIngressRewrite from the indexer for a given namespace and name.
+func (s azureIngressRewriteNamespaceLister) Get(name string) (*v1.AzureIngressRewrite, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("azureingressrewrite"), name)
+	}
+	return obj.(*v1.AzureIngressRewrite), nil
+}
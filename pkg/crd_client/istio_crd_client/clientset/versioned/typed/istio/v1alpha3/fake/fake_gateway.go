@@ -19,6 +19,8 @@ limitations under the License.
 package fake
 
 import (
+	"context"
+
 	v1alpha3 "github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
@@ -39,7 +41,7 @@ var gatewaysResource = schema.GroupVersionResource{Group: "networking.istio.io",
 var gatewaysKind = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Gateway"}
 
 // Get takes name of the gateway, and returns the corresponding gateway object, and an error if there is any.
-func (c *FakeGateways) Get(name string, options v1.GetOptions) (result *v1alpha3.Gateway, err error) {
+func (c *FakeGateways) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha3.Gateway, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(gatewaysResource, c.ns, name), &v1alpha3.Gateway{})
 
@@ -50,7 +52,7 @@ func (c *FakeGateways) Get(name string, options v1.GetOptions) (result *v1alpha3
 }
 
 // List takes label and field selectors, and returns the list of Gateways that match those selectors.
-func (c *FakeGateways) List(opts v1.ListOptions) (result *v1alpha3.GatewayList, err error) {
+func (c *FakeGateways) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha3.GatewayList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(gatewaysResource, gatewaysKind, c.ns, opts), &v1alpha3.GatewayList{})
 
@@ -72,14 +74,14 @@ func (c *FakeGateways) List(opts v1.ListOptions) (result *v1alpha3.GatewayList,
 }
 
 // Watch returns a watch.Interface that watches the requested gateways.
-func (c *FakeGateways) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeGateways) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(gatewaysResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a gateway and creates it.  Returns the server's representation of the gateway, and an error, if there is any.
-func (c *FakeGateways) Create(gateway *v1alpha3.Gateway) (result *v1alpha3.Gateway, err error) {
+func (c *FakeGateways) Create(ctx context.Context, gateway *v1alpha3.Gateway, opts v1.CreateOptions) (result *v1alpha3.Gateway, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(gatewaysResource, c.ns, gateway), &v1alpha3.Gateway{})
 
@@ -90,7 +92,7 @@ func (c *FakeGateways) Create(gateway *v1alpha3.Gateway) (result *v1alpha3.Gatew
 }
 
 // Update takes the representation of a gateway and updates it. Returns the server's representation of the gateway, and an error, if there is any.
-func (c *FakeGateways) Update(gateway *v1alpha3.Gateway) (result *v1alpha3.Gateway, err error) {
+func (c *FakeGateways) Update(ctx context.Context, gateway *v1alpha3.Gateway, opts v1.UpdateOptions) (result *v1alpha3.Gateway, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(gatewaysResource, c.ns, gateway), &v1alpha3.Gateway{})
 
@@ -101,7 +103,7 @@ func (c *FakeGateways) Update(gateway *v1alpha3.Gateway) (result *v1alpha3.Gatew
 }
 
 // Delete takes name of the gateway and deletes it. Returns an error if one occurs.
-func (c *FakeGateways) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeGateways) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(gatewaysResource, c.ns, name), &v1alpha3.Gateway{})
 
@@ -109,15 +111,15 @@ func (c *FakeGateways) Delete(name string, options *v1.DeleteOptions) error {
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeGateways) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(gatewaysResource, c.ns, listOptions)
+func (c *FakeGateways) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(gatewaysResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &v1alpha3.GatewayList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched gateway.
-func (c *FakeGateways) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha3.Gateway, err error) {
+func (c *FakeGateways) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha3.Gateway, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(gatewaysResource, c.ns, name, pt, data, subresources...), &v1alpha3.Gateway{})
 
@@ -19,6 +19,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"context"
 	"time"
 
 	scheme "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/scheme"
@@ -37,14 +38,14 @@ type GatewaysGetter interface {
 
 // GatewayInterface has methods to work with Gateway resources.
 type GatewayInterface interface {
-	Create(*v1alpha3.Gateway) (*v1alpha3.Gateway, error)
-	Update(*v1alpha3.Gateway) (*v1alpha3.Gateway, error)
-	Delete(name string, options *v1.DeleteOptions) error
-	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
-	Get(name string, options v1.GetOptions) (*v1alpha3.Gateway, error)
-	List(opts v1.ListOptions) (*v1alpha3.GatewayList, error)
-	Watch(opts v1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha3.Gateway, err error)
+	Create(ctx context.Context, gateway *v1alpha3.Gateway, opts v1.CreateOptions) (*v1alpha3.Gateway, error)
+	Update(ctx context.Context, gateway *v1alpha3.Gateway, opts v1.UpdateOptions) (*v1alpha3.Gateway, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha3.Gateway, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha3.GatewayList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha3.Gateway, err error)
 	GatewayExpansion
 }
 
@@ -63,20 +64,20 @@ func newGateways(c *NetworkingV1alpha3Client, namespace string) *gateways {
 }
 
 // Get takes name of the gateway, and returns the corresponding gateway object, and an error if there is any.
-func (c *gateways) Get(name string, options v1.GetOptions) (result *v1alpha3.Gateway, err error) {
+func (c *gateways) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha3.Gateway, err error) {
 	result = &v1alpha3.Gateway{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("gateways").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of Gateways that match those selectors.
-func (c *gateways) List(opts v1.ListOptions) (result *v1alpha3.GatewayList, err error) {
+func (c *gateways) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha3.GatewayList, err error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -87,13 +88,13 @@ func (c *gateways) List(opts v1.ListOptions) (result *v1alpha3.GatewayList, err
 		Resource("gateways").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested gateways.
-func (c *gateways) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *gateways) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	var timeout time.Duration
 	if opts.TimeoutSeconds != nil {
 		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
@@ -104,71 +105,74 @@ func (c *gateways) Watch(opts v1.ListOptions) (watch.Interface, error) {
 		Resource("gateways").
 		VersionedParams(&opts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Watch()
+		Watch(ctx)
 }
 
 // Create takes the representation of a gateway and creates it.  Returns the server's representation of the gateway, and an error, if there is any.
-func (c *gateways) Create(gateway *v1alpha3.Gateway) (result *v1alpha3.Gateway, err error) {
+func (c *gateways) Create(ctx context.Context, gateway *v1alpha3.Gateway, opts v1.CreateOptions) (result *v1alpha3.Gateway, err error) {
 	result = &v1alpha3.Gateway{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("gateways").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(gateway).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a gateway and updates it. Returns the server's representation of the gateway, and an error, if there is any.
-func (c *gateways) Update(gateway *v1alpha3.Gateway) (result *v1alpha3.Gateway, err error) {
+func (c *gateways) Update(ctx context.Context, gateway *v1alpha3.Gateway, opts v1.UpdateOptions) (result *v1alpha3.Gateway, err error) {
 	result = &v1alpha3.Gateway{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("gateways").
 		Name(gateway.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(gateway).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the gateway and deletes it. Returns an error if one occurs.
-func (c *gateways) Delete(name string, options *v1.DeleteOptions) error {
+func (c *gateways) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("gateways").
 		Name(name).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *gateways) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+func (c *gateways) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
 	var timeout time.Duration
-	if listOptions.TimeoutSeconds != nil {
-		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
 	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("gateways").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
 		Timeout(timeout).
-		Body(options).
-		Do().
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched gateway.
-func (c *gateways) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha3.Gateway, err error) {
+func (c *gateways) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha3.Gateway, err error) {
 	result = &v1alpha3.Gateway{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("gateways").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(data).
-		Do().
+		Do(ctx).
 		Into(result)
 	return
 }
@@ -19,6 +19,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"context"
 	time "time"
 
 	versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
@@ -61,13 +62,13 @@ func NewFilteredDestinationRuleInformer(client versioned.Interface, namespace st
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.NetworkingV1alpha3().DestinationRules(namespace).List(options)
+				return client.NetworkingV1alpha3().DestinationRules(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.NetworkingV1alpha3().DestinationRules(namespace).Watch(options)
+				return client.NetworkingV1alpha3().DestinationRules(namespace).Watch(context.TODO(), options)
 			},
 		},
 		&istiov1alpha3.DestinationRule{},
@@ -41,6 +41,15 @@ const (
 	// EnableIstioIntegrationVarName is a feature flag enabling observation of Istio specific CRDs
 	EnableIstioIntegrationVarName = "APPGW_ENABLE_ISTIO_INTEGRATION"
 
+	// EnableGatewayAPIVarName is a feature flag enabling observation of the Gateways and HTTPRoutes of the Kubernetes Gateway API
+	EnableGatewayAPIVarName = "APPGW_ENABLE_GATEWAY_API"
+
+	// EnableRewriteVarName is a feature flag enabling observation of the AzureIngressRewrite CRD
+	EnableRewriteVarName = "APPGW_ENABLE_REWRITE"
+
+	// EnableBackendCAConfigMapsVarName is a feature flag enabling observation of the ConfigMaps with backend CA certificates
+	EnableBackendCAConfigMapsVarName = "APPGW_ENABLE_BACKEND_CA_CONFIGMAPS"
+
 	// EnableSaveConfigToFileVarName is a feature flag, which enables saving the App Gwy config to disk.
 	EnableSaveConfigToFileVarName = "APPGW_ENABLE_SAVE_CONFIG_TO_FILE"
 
@@ -49,42 +58,113 @@ const (
 
 	// HealthProbeServicePortVarName is an environment variable name.
 	HealthProbeServicePortVarName = "HEALTH_PROBE_SERVICE_PORT"
+
+	// ReconcilePeriodSecondsVarName is an environment variable name; AGIC reconciles App Gateway at this interval even when no events arrive.
+	ReconcilePeriodSecondsVarName = "RECONCILE_PERIOD_SECONDS"
+
+	// EnableDryRunVarName is a feature flag; in dry-run mode AGIC reports the changes it would make to App Gateway without applying them.
+	EnableDryRunVarName = "APPGW_ENABLE_DRY_RUN"
+
+	// EnableLeaderElectionVarName is a feature flag; when enabled only the AGIC replica holding the Lease updates App Gateway.
+	EnableLeaderElectionVarName = "APPGW_ENABLE_LEADER_ELECTION"
+
+	// LeaderElectionLeaseNameVarName is the name of the Lease used for leader election.
+	LeaderElectionLeaseNameVarName = "LEADER_ELECTION_LEASE_NAME"
+
+	// LeaderElectionLeaseDurationSecondsVarName is an environment variable name; a standby replica takes over at most this long after the leader stopped renewing the Lease.
+	LeaderElectionLeaseDurationSecondsVarName = "LEADER_ELECTION_LEASE_DURATION_SECONDS"
+
+	// ErrorPageURL403VarName is the HTTPS URL of the page App Gateway returns instead of its own 403 Forbidden page;
+	// an Ingress annotation overrides it for the listeners of the Ingress.
+	ErrorPageURL403VarName = "APPGW_ERROR_PAGE_URL_403"
+
+	// ErrorPageURL502VarName is the HTTPS URL of the page App Gateway returns instead of its own 502 Bad Gateway page;
+	// an Ingress annotation overrides it for the listeners of the Ingress.
+	ErrorPageURL502VarName = "APPGW_ERROR_PAGE_URL_502"
+
+	// SslPolicyNameVarName is the name of the predefined SSL policy of App Gateway, like AppGwSslPolicy20170401S.
+	SslPolicyNameVarName = "APPGW_SSL_POLICY_NAME"
+
+	// SslPolicyMinProtocolVersionVarName is the minimum TLS version of the custom SSL policy of App Gateway, like TLSv1_2.
+	SslPolicyMinProtocolVersionVarName = "APPGW_SSL_POLICY_MIN_PROTOCOL_VERSION"
+
+	// SslPolicyCipherSuitesVarName is the comma separated list of the cipher suites of the custom SSL policy of App Gateway.
+	SslPolicyCipherSuitesVarName = "APPGW_SSL_POLICY_CIPHER_SUITES"
+
+	// AGICPodNameVarName is the name of the AGIC pod; set through the downward API.
+	AGICPodNameVarName = "AGIC_POD_NAME"
+
+	// AGICPodNamespaceVarName is the namespace of the AGIC pod; set through the downward API.
+	AGICPodNamespaceVarName = "AGIC_POD_NAMESPACE"
 )
 
 // EnvVariables is a struct storing values for environment variables.
 type EnvVariables struct {
-	SubscriptionID             string
-	ResourceGroupName          string
-	AppGwName                  string
-	AuthLocation               string
-	WatchNamespace             string
-	UsePrivateIP               string
-	VerbosityLevel             string
-	EnableBrownfieldDeployment bool
-	EnableIstioIntegration     bool
-	EnableSaveConfigToFile     bool
-	EnablePanicOnPutError      bool
-	HealthProbeServicePort     string
+	SubscriptionID              string
+	ResourceGroupName           string
+	AppGwName                   string
+	AuthLocation                string
+	WatchNamespace              string
+	UsePrivateIP                string
+	VerbosityLevel              string
+	EnableBrownfieldDeployment  bool
+	EnableIstioIntegration      bool
+	EnableGatewayAPI            bool
+	EnableRewrite               bool
+	EnableBackendCAConfigMaps   bool
+	EnableSaveConfigToFile      bool
+	EnablePanicOnPutError       bool
+	HealthProbeServicePort      string
+	ReconcilePeriodSeconds      string
+	EnableDryRun                bool
+	EnableLeaderElection        bool
+	LeaderElectionLeaseName     string
+	LeaderElectionLeaseSeconds  string
+	AGICPodName                 string
+	AGICPodNamespace            string
+	ErrorPageURL403             string
+	ErrorPageURL502             string
+	SslPolicyName               string
+	SslPolicyMinProtocolVersion string
+	SslPolicyCipherSuites       string
 }
 
 var portNumberValidator = regexp.MustCompile(`^[0-9]{4,5}$`)
 var boolValidator = regexp.MustCompile(`^(?i)(true|false)$`)
+var secondsValidator = regexp.MustCompile(`^[0-9]+$`)
+var positiveSecondsValidator = regexp.MustCompile(`^[1-9][0-9]*$`)
+var httpsURLValidator = regexp.MustCompile(`^https://[^/\s]+(/\S*)?$`)
 
 // GetEnv returns values for defined environment variables for Ingress Controller.
 func GetEnv() EnvVariables {
 	env := EnvVariables{
-		SubscriptionID:             os.Getenv(SubscriptionIDVarName),
-		ResourceGroupName:          os.Getenv(ResourceGroupNameVarName),
-		AppGwName:                  os.Getenv(AppGwNameVarName),
-		AuthLocation:               os.Getenv(AuthLocationVarName),
-		WatchNamespace:             os.Getenv(WatchNamespaceVarName),
-		UsePrivateIP:               os.Getenv(UsePrivateIPVarName),
-		VerbosityLevel:             os.Getenv(VerbosityLevelVarName),
-		EnableBrownfieldDeployment: GetEnvironmentVariable(EnableBrownfieldDeploymentVarName, "false", boolValidator) == "true",
-		EnableIstioIntegration:     GetEnvironmentVariable(EnableIstioIntegrationVarName, "false", boolValidator) == "true",
-		EnableSaveConfigToFile:     GetEnvironmentVariable(EnableSaveConfigToFileVarName, "false", boolValidator) == "true",
-		EnablePanicOnPutError:      GetEnvironmentVariable(EnablePanicOnPutErrorVarName, "false", boolValidator) == "true",
-		HealthProbeServicePort:     GetEnvironmentVariable(HealthProbeServicePortVarName, "8123", portNumberValidator),
+		SubscriptionID:              os.Getenv(SubscriptionIDVarName),
+		ResourceGroupName:           os.Getenv(ResourceGroupNameVarName),
+		AppGwName:                   os.Getenv(AppGwNameVarName),
+		AuthLocation:                os.Getenv(AuthLocationVarName),
+		WatchNamespace:              os.Getenv(WatchNamespaceVarName),
+		UsePrivateIP:                os.Getenv(UsePrivateIPVarName),
+		VerbosityLevel:              os.Getenv(VerbosityLevelVarName),
+		EnableBrownfieldDeployment:  GetEnvironmentVariable(EnableBrownfieldDeploymentVarName, "false", boolValidator) == "true",
+		EnableIstioIntegration:      GetEnvironmentVariable(EnableIstioIntegrationVarName, "false", boolValidator) == "true",
+		EnableGatewayAPI:            GetEnvironmentVariable(EnableGatewayAPIVarName, "false", boolValidator) == "true",
+		EnableRewrite:               GetEnvironmentVariable(EnableRewriteVarName, "false", boolValidator) == "true",
+		EnableBackendCAConfigMaps:   GetEnvironmentVariable(EnableBackendCAConfigMapsVarName, "false", boolValidator) == "true",
+		EnableSaveConfigToFile:      GetEnvironmentVariable(EnableSaveConfigToFileVarName, "false", boolValidator) == "true",
+		EnablePanicOnPutError:       GetEnvironmentVariable(EnablePanicOnPutErrorVarName, "false", boolValidator) == "true",
+		HealthProbeServicePort:      GetEnvironmentVariable(HealthProbeServicePortVarName, "8123", portNumberValidator),
+		ReconcilePeriodSeconds:      GetEnvironmentVariable(ReconcilePeriodSecondsVarName, "300", secondsValidator),
+		EnableDryRun:                GetEnvironmentVariable(EnableDryRunVarName, "false", boolValidator) == "true",
+		EnableLeaderElection:        GetEnvironmentVariable(EnableLeaderElectionVarName, "false", boolValidator) == "true",
+		LeaderElectionLeaseName:     GetEnvironmentVariable(LeaderElectionLeaseNameVarName, "agic-leader", nil),
+		LeaderElectionLeaseSeconds:  GetEnvironmentVariable(LeaderElectionLeaseDurationSecondsVarName, "15", positiveSecondsValidator),
+		AGICPodName:                 os.Getenv(AGICPodNameVarName),
+		AGICPodNamespace:            os.Getenv(AGICPodNamespaceVarName),
+		ErrorPageURL403:             GetEnvironmentVariable(ErrorPageURL403VarName, "", httpsURLValidator),
+		ErrorPageURL502:             GetEnvironmentVariable(ErrorPageURL502VarName, "", httpsURLValidator),
+		SslPolicyName:               os.Getenv(SslPolicyNameVarName),
+		SslPolicyMinProtocolVersion: os.Getenv(SslPolicyMinProtocolVersionVarName),
+		SslPolicyCipherSuites:       os.Getenv(SslPolicyCipherSuitesVarName),
 	}
 
 	return env
@@ -96,6 +176,14 @@ func ValidateEnv(env EnvVariables) error {
 		return errors.New("environment variables SubscriptionID, ResourceGroupname and AppGwName are required")
 	}
 
+	if env.EnableLeaderElection && (len(env.AGICPodName) == 0 || len(env.AGICPodNamespace) == 0) {
+		return errors.Errorf("environment variables %s and %s are required when leader election is enabled", AGICPodNameVarName, AGICPodNamespaceVarName)
+	}
+
+	if _, err := NewSslPolicy(env); err != nil {
+		return err
+	}
+
 	if env.WatchNamespace == "" {
 		glog.V(1).Infof("%s is not set. Watching all available namespaces.", WatchNamespaceVarName)
 	}
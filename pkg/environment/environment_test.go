@@ -64,6 +64,8 @@ var _ = Describe("Environment", func() {
 				_ = os.Setenv(EnableIstioIntegrationVarName, "true")
 				_ = os.Setenv(EnableSaveConfigToFileVarName, "false")
 				_ = os.Setenv(EnablePanicOnPutErrorVarName, "true")
+				_ = os.Setenv(ErrorPageURL403VarName, "http://contoso.com/403.html")
+				_ = os.Setenv(ErrorPageURL502VarName, "https://contoso.com/502.html")
 
 				expected := EnvVariables{
 					SubscriptionID:             "SubscriptionIDVarName",
@@ -78,11 +80,46 @@ var _ = Describe("Environment", func() {
 					EnableSaveConfigToFile:     false,
 					EnablePanicOnPutError:      true,
 					HealthProbeServicePort:     "8123",
+					ReconcilePeriodSeconds:     "300",
+					LeaderElectionLeaseName:    "agic-leader",
+					LeaderElectionLeaseSeconds: "15",
+					ErrorPageURL502:            "https://contoso.com/502.html",
 				}
 
 				Expect(GetEnv()).To(Equal(expected))
 				err := ValidateEnv(GetEnv())
 				Expect(err).ToNot(HaveOccurred())
+				_ = os.Unsetenv(ErrorPageURL403VarName)
+				_ = os.Unsetenv(ErrorPageURL502VarName)
+			})
+
+			It("requires the pod name and namespace when leader election is enabled", func() {
+				env := GetFakeEnv()
+				env.EnableLeaderElection = true
+				Expect(ValidateEnv(env)).To(HaveOccurred())
+
+				env.AGICPodName = "agic-1234"
+				env.AGICPodNamespace = "kube-system"
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
+			})
+
+			It("fails on an invalid SSL policy", func() {
+				env := GetFakeEnv()
+				env.SslPolicyName = "AppGwSslPolicy20170401S"
+				Expect(ValidateEnv(env)).ToNot(HaveOccurred())
+
+				for _, invalid := range []EnvVariables{
+					{SslPolicyName: "AppGwSslPolicy20990101"},
+					{SslPolicyName: "AppGwSslPolicy20170401S", SslPolicyMinProtocolVersion: "TLSv1_2"},
+					{SslPolicyMinProtocolVersion: "TLSv1_2"},
+					{SslPolicyMinProtocolVersion: "TLSv1_2", SslPolicyCipherSuites: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,RC4"},
+				} {
+					env := GetFakeEnv()
+					env.SslPolicyName = invalid.SslPolicyName
+					env.SslPolicyMinProtocolVersion = invalid.SslPolicyMinProtocolVersion
+					env.SslPolicyCipherSuites = invalid.SslPolicyCipherSuites
+					Expect(ValidateEnv(env)).To(HaveOccurred(), "%+v", invalid)
+				}
 			})
 		})
 
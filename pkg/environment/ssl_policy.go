@@ -0,0 +1,73 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package environment
+
+import (
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
+	"github.com/pkg/errors"
+)
+
+// NewSslPolicy creates either the predefined or the custom SSL policy of the environment; nil when none is configured.
+func NewSslPolicy(env EnvVariables) (*n.ApplicationGatewaySslPolicy, error) {
+	if env.SslPolicyName != "" {
+		if env.SslPolicyMinProtocolVersion != "" || env.SslPolicyCipherSuites != "" {
+			return nil, errors.Errorf("SSL policy %s is predefined; %s and %s can't be set", env.SslPolicyName, SslPolicyMinProtocolVersionVarName, SslPolicyCipherSuitesVarName)
+		}
+		for _, policyName := range n.PossibleApplicationGatewaySslPolicyNameValues() {
+			if string(policyName) == env.SslPolicyName {
+				return &n.ApplicationGatewaySslPolicy{
+					PolicyType: n.Predefined,
+					PolicyName: policyName,
+				}, nil
+			}
+		}
+		return nil, errors.Errorf("unknown predefined SSL policy %s in %s", env.SslPolicyName, SslPolicyNameVarName)
+	}
+
+	if env.SslPolicyMinProtocolVersion == "" && env.SslPolicyCipherSuites == "" {
+		return nil, nil
+	}
+
+	minProtocol, known := lookupSslProtocol(env.SslPolicyMinProtocolVersion)
+	if !known {
+		return nil, errors.Errorf("unknown minimum protocol version %q of the custom SSL policy in %s", env.SslPolicyMinProtocolVersion, SslPolicyMinProtocolVersionVarName)
+	}
+
+	var cipherSuites []n.ApplicationGatewaySslCipherSuite
+	for _, name := range strings.Split(env.SslPolicyCipherSuites, ",") {
+		cipherSuite, known := lookupSslCipherSuite(strings.TrimSpace(name))
+		if !known {
+			return nil, errors.Errorf("unknown cipher suite %q of the custom SSL policy in %s", name, SslPolicyCipherSuitesVarName)
+		}
+		cipherSuites = append(cipherSuites, cipherSuite)
+	}
+
+	return &n.ApplicationGatewaySslPolicy{
+		PolicyType:         n.Custom,
+		MinProtocolVersion: minProtocol,
+		CipherSuites:       &cipherSuites,
+	}, nil
+}
+
+func lookupSslProtocol(name string) (n.ApplicationGatewaySslProtocol, bool) {
+	for _, protocol := range n.PossibleApplicationGatewaySslProtocolValues() {
+		if string(protocol) == name {
+			return protocol, true
+		}
+	}
+	return "", false
+}
+
+func lookupSslCipherSuite(name string) (n.ApplicationGatewaySslCipherSuite, bool) {
+	for _, cipherSuite := range n.PossibleApplicationGatewaySslCipherSuiteValues() {
+		if string(cipherSuite) == name {
+			return cipherSuite, true
+		}
+	}
+	return "", false
+}
@@ -17,10 +17,16 @@ const (
 
 	// Delete is a type of a Kubernetes API event.
 	Delete
+
+	// Resync is a periodic reconcile, which is not triggered by a Kubernetes API event.
+	Resync
 )
 
 // Event is the combined type and actual object we received from Kubernetes
 type Event struct {
 	Type  EventType
 	Value interface{}
+
+	// OldValue is the object before an Update; nil for the other types of events.
+	OldValue interface{}
 }
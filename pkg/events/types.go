@@ -35,4 +35,40 @@ const (
 
 	// ReasonInvalidAnnotation is a reason for an event to be emitted.
 	ReasonInvalidAnnotation = "InvalidAnnotation"
+
+	// ReasonAppGwSslCertificateNotFound is a reason for an event to be emitted.
+	ReasonAppGwSslCertificateNotFound = "AppGwSslCertificateNotFound"
+
+	// ReasonInvalidBackendCACertificate is a reason for an event to be emitted.
+	ReasonInvalidBackendCACertificate = "InvalidBackendCACertificate"
+
+	// ReasonUnsupportedWAFPolicy is a reason for an event to be emitted.
+	ReasonUnsupportedWAFPolicy = "UnsupportedWAFPolicy"
+
+	// ReasonInvalidRewriteRuleSet is a reason for an event to be emitted.
+	ReasonInvalidRewriteRuleSet = "InvalidRewriteRuleSet"
+
+	// ReasonFrontendPortConflict is a reason for an event to be emitted.
+	ReasonFrontendPortConflict = "FrontendPortConflict"
+
+	// ReasonSessionAffinityMismatch is a reason for an event to be emitted.
+	ReasonSessionAffinityMismatch = "SessionAffinityMismatch"
+
+	// ReasonInvalidIstioRoute is a reason for an event to be emitted.
+	ReasonInvalidIstioRoute = "InvalidIstioRoute"
+
+	// ReasonIstioSubsetNotFound is a reason for an event to be emitted.
+	ReasonIstioSubsetNotFound = "IstioSubsetNotFound"
+
+	// ReasonInvalidGatewayAPIRoute is a reason for an event to be emitted.
+	ReasonInvalidGatewayAPIRoute = "InvalidGatewayAPIRoute"
+
+	// ReasonUnableToUpdateGatewayAPIStatus is a reason for an event to be emitted.
+	ReasonUnableToUpdateGatewayAPIStatus = "UnableToUpdateGatewayAPIStatus"
+
+	// ReasonGatewayAPIListenerConflict is a reason for an event to be emitted.
+	ReasonGatewayAPIListenerConflict = "GatewayAPIListenerConflict"
+
+	// ReasonDryRunDiff is a reason for an event to be emitted.
+	ReasonDryRunDiff = "DryRunDiff"
 )
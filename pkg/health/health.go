@@ -5,7 +5,11 @@
 
 package health
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+)
 
 // Probe is a type alias for a function.
 type Probe func() bool
@@ -14,26 +18,28 @@ type Probe func() bool
 type Probes interface {
 	Liveness() bool
 	Readiness() bool
+
+	// Role is reported in the body of the readiness probe; "leader" or "standby" when running multiple replicas.
+	Role() string
 }
 
-func makeHandler(router *http.ServeMux, url string, probe Probe) {
+func makeHandler(router *http.ServeMux, url string, probe Probe, body func() string) {
 	router.Handle(url, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		w.WriteHeader(map[bool]int{
 			true:  http.StatusOK,
 			false: http.StatusServiceUnavailable,
 		}[probe()])
+		if body != nil {
+			_, _ = w.Write([]byte(body()))
+		}
 	}))
 }
 
-// NewHealthMux makes a new *http.ServeMux
-func NewHealthMux(healthProbes Probes) *http.ServeMux {
+// NewHealthMux makes a new *http.ServeMux; it also serves the Prometheus metrics on /metrics.
+func NewHealthMux(healthProbes Probes, metricStore metricstore.MetricStore) *http.ServeMux {
 	router := http.NewServeMux()
-	var handlers = map[string]Probe{
-		"/health/ready": healthProbes.Readiness,
-		"/health/alive": healthProbes.Liveness,
-	}
-	for url, probe := range handlers {
-		makeHandler(router, url, probe)
-	}
+	makeHandler(router, "/health/ready", healthProbes.Readiness, healthProbes.Role)
+	makeHandler(router, "/health/alive", healthProbes.Liveness, nil)
+	router.Handle("/metrics", metricStore.Handler())
 	return router
 }
@@ -0,0 +1,54 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+)
+
+type fakeProbes struct {
+	alive bool
+	ready bool
+	role  string
+}
+
+func (p fakeProbes) Liveness() bool  { return p.alive }
+func (p fakeProbes) Readiness() bool { return p.ready }
+func (p fakeProbes) Role() string    { return p.role }
+
+var _ = Describe("Test NewHealthMux", func() {
+	get := func(mux *http.ServeMux, url string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, url, nil))
+		return recorder
+	}
+
+	It("should report the role in the readiness probe", func() {
+		mux := NewHealthMux(fakeProbes{alive: true, ready: true, role: "standby"}, metricstore.NewFakeMetricStore())
+		response := get(mux, "/health/ready")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Body.String()).To(Equal("standby"))
+	})
+
+	It("should fail probes, which are not healthy", func() {
+		mux := NewHealthMux(fakeProbes{alive: false, ready: false, role: "leader"}, metricstore.NewFakeMetricStore())
+		Expect(get(mux, "/health/ready").Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(get(mux, "/health/alive").Code).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("should serve metrics", func() {
+		mux := NewHealthMux(fakeProbes{alive: true, ready: true, role: "leader"}, metricstore.NewMetricStore())
+		response := get(mux, "/metrics")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Body.String()).To(ContainSubstring("agic_config_unchanged_total"))
+	})
+})
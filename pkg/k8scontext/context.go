@@ -6,6 +6,7 @@
 package k8scontext
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -15,7 +16,8 @@ import (
 	"github.com/golang/glog"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -23,13 +25,17 @@ import (
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	prohibitedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	rewritev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressrewrite/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions"
+	gateway_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned"
+	gateway_externalversions "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/informers/externalversions"
 	istio_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
 	istio_externalversions "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/informers/externalversions"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
@@ -38,50 +44,89 @@ const providerPrefix = "azure://"
 const workBuffer = 1024
 
 // NewContext creates a context based on a Kubernetes client instance.
-func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface, istioCrdClient istio_versioned.Interface, namespaces []string, resyncPeriod time.Duration) *Context {
+func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface, istioCrdClient istio_versioned.Interface, gatewayCrdClient gateway_versioned.Interface, namespaces []string, resyncPeriod time.Duration, metricStore metricstore.MetricStore) *Context {
 	var options []informers.SharedInformerOption
 	var crdOptions []externalversions.SharedInformerOption
+	var istioCrdOptions []istio_externalversions.SharedInformerOption
+	var gatewayCrdOptions []gateway_externalversions.SharedInformerOption
 	for _, namespace := range namespaces {
 		options = append(options, informers.WithNamespace(namespace))
 		crdOptions = append(crdOptions, externalversions.WithNamespace(namespace))
+		istioCrdOptions = append(istioCrdOptions, istio_externalversions.WithNamespace(namespace))
+		gatewayCrdOptions = append(gatewayCrdOptions, gateway_externalversions.WithNamespace(namespace))
 	}
 	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod, options...)
+	isNetworkingV1Supported := IsNetworkingV1PackageSupported(kubeClient)
 	crdInformerFactory := externalversions.NewSharedInformerFactoryWithOptions(crdClient, resyncPeriod, crdOptions...)
-	istioCrdInformerFactory := istio_externalversions.NewSharedInformerFactoryWithOptions(istioCrdClient, resyncPeriod)
+	istioCrdInformerFactory := istio_externalversions.NewSharedInformerFactoryWithOptions(istioCrdClient, resyncPeriod, istioCrdOptions...)
+	gatewayCrdInformerFactory := gateway_externalversions.NewSharedInformerFactoryWithOptions(gatewayCrdClient, resyncPeriod, gatewayCrdOptions...)
 
 	informerCollection := InformerCollection{
+		ConfigMap: informerFactory.Core().V1().ConfigMaps().Informer(),
 		Endpoints: informerFactory.Core().V1().Endpoints().Informer(),
-		Ingress:   informerFactory.Extensions().V1beta1().Ingresses().Informer(),
 		Pods:      informerFactory.Core().V1().Pods().Informer(),
 		Secret:    informerFactory.Core().V1().Secrets().Informer(),
 		Service:   informerFactory.Core().V1().Services().Informer(),
+		Namespace: informerFactory.Core().V1().Namespaces().Informer(),
+
+		IngressClass: informerFactory.Networking().V1().IngressClasses().Informer(),
 
 		AzureIngressProhibitedTarget: crdInformerFactory.Azureingressprohibitedtargets().V1().AzureIngressProhibitedTargets().Informer(),
+		AzureIngressRewrite:          crdInformerFactory.Azureingressrewrites().V1().AzureIngressRewrites().Informer(),
+
+		IstioGateway:         istioCrdInformerFactory.Networking().V1alpha3().Gateways().Informer(),
+		IstioVirtualService:  istioCrdInformerFactory.Networking().V1alpha3().VirtualServices().Informer(),
+		IstioDestinationRule: istioCrdInformerFactory.Networking().V1alpha3().DestinationRules().Informer(),
+
+		GatewayClass: gatewayCrdInformerFactory.Gateway().V1beta1().GatewayClasses().Informer(),
+		Gateway:      gatewayCrdInformerFactory.Gateway().V1beta1().Gateways().Informer(),
+		HTTPRoute:    gatewayCrdInformerFactory.Gateway().V1beta1().HTTPRoutes().Informer(),
+	}
 
-		IstioGateway:        istioCrdInformerFactory.Networking().V1alpha3().Gateways().Informer(),
-		IstioVirtualService: istioCrdInformerFactory.Networking().V1alpha3().VirtualServices().Informer(),
+	if isNetworkingV1Supported {
+		informerCollection.Ingress = informerFactory.Networking().V1().Ingresses().Informer()
+	} else {
+		// The informer factory watches a single namespace; the last one wins.
+		legacyNamespace := metav1.NamespaceAll
+		if len(namespaces) > 0 {
+			legacyNamespace = namespaces[len(namespaces)-1]
+		}
+		informerCollection.Ingress = informerFactory.InformerFor(&extensions.Ingress{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+			return newLegacyIngressInformer(client, legacyNamespace, resyncPeriod)
+		})
 	}
 
 	cacheCollection := CacheCollection{
+		ConfigMap:                    informerCollection.ConfigMap.GetStore(),
 		Endpoints:                    informerCollection.Endpoints.GetStore(),
 		Ingress:                      informerCollection.Ingress.GetStore(),
+		IngressClass:                 informerCollection.IngressClass.GetStore(),
 		Pods:                         informerCollection.Pods.GetStore(),
 		Secret:                       informerCollection.Secret.GetStore(),
 		Service:                      informerCollection.Service.GetStore(),
+		Namespaces:                   informerCollection.Namespace.GetStore(),
 		AzureIngressProhibitedTarget: informerCollection.AzureIngressProhibitedTarget.GetStore(),
+		AzureIngressRewrite:          informerCollection.AzureIngressRewrite.GetStore(),
 		IstioGateway:                 informerCollection.IstioGateway.GetStore(),
 		IstioVirtualService:          informerCollection.IstioVirtualService.GetStore(),
+		IstioDestinationRule:         informerCollection.IstioDestinationRule.GetStore(),
+		GatewayClass:                 informerCollection.GatewayClass.GetStore(),
+		Gateway:                      informerCollection.Gateway.GetStore(),
+		HTTPRoute:                    informerCollection.HTTPRoute.GetStore(),
 	}
 
 	context := &Context{
-		kubeClient:     kubeClient,
-		crdClient:      crdClient,
-		istioCrdClient: istioCrdClient,
+		kubeClient:       kubeClient,
+		crdClient:        crdClient,
+		istioCrdClient:   istioCrdClient,
+		gatewayCrdClient: gatewayCrdClient,
+
+		isNetworkingV1Supported: isNetworkingV1Supported,
 
 		informers:              &informerCollection,
 		ingressSecretsMap:      utils.NewThreadsafeMultimap(),
 		Caches:                 &cacheCollection,
-		CertificateSecretStore: NewSecretStore(),
+		CertificateSecretStore: NewSecretStore(metricStore),
 		Work:                   make(chan events.Event, workBuffer),
 		CacheSynced:            make(chan interface{}),
 	}
@@ -106,13 +151,47 @@ func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface,
 		DeleteFunc: h.secretDelete,
 	}
 
+	istioGatewayResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.istioGatewayAdd,
+		UpdateFunc: h.istioGatewayUpdate,
+		DeleteFunc: h.istioGatewayDelete,
+	}
+
+	gatewayClassResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.gatewayClassAdd,
+		UpdateFunc: h.gatewayClassUpdate,
+		DeleteFunc: h.gatewayClassDelete,
+	}
+
+	gatewayResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.gatewayAdd,
+		UpdateFunc: h.gatewayUpdate,
+		DeleteFunc: h.gatewayDelete,
+	}
+
+	httpRouteResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.addFunc,
+		UpdateFunc: h.gatewayAPIUpdate,
+		DeleteFunc: h.deleteFunc,
+	}
+
 	// Register event handlers.
+	informerCollection.ConfigMap.AddEventHandler(resourceHandler)
 	informerCollection.Endpoints.AddEventHandler(resourceHandler)
 	informerCollection.Ingress.AddEventHandler(ingressResourceHandler)
+	informerCollection.IngressClass.AddEventHandler(resourceHandler)
 	informerCollection.Pods.AddEventHandler(resourceHandler)
 	informerCollection.Secret.AddEventHandler(secretResourceHandler)
 	informerCollection.Service.AddEventHandler(resourceHandler)
 	informerCollection.AzureIngressProhibitedTarget.AddEventHandler(resourceHandler)
+	informerCollection.AzureIngressRewrite.AddEventHandler(resourceHandler)
+	informerCollection.IstioGateway.AddEventHandler(istioGatewayResourceHandler)
+	informerCollection.IstioVirtualService.AddEventHandler(resourceHandler)
+	informerCollection.IstioDestinationRule.AddEventHandler(resourceHandler)
+	informerCollection.Namespace.AddEventHandler(resourceHandler)
+	informerCollection.GatewayClass.AddEventHandler(gatewayClassResourceHandler)
+	informerCollection.Gateway.AddEventHandler(gatewayResourceHandler)
+	informerCollection.HTTPRoute.AddEventHandler(httpRouteResourceHandler)
 
 	return context
 }
@@ -127,8 +206,13 @@ func (c *Context) Run(stopChannel chan struct{}, omitCRDs bool, envVariables env
 	}
 	crds := map[cache.SharedInformer]interface{}{
 		c.informers.AzureIngressProhibitedTarget: nil,
+		c.informers.AzureIngressRewrite:          nil,
 		c.informers.IstioGateway:                 nil,
 		c.informers.IstioVirtualService:          nil,
+		c.informers.IstioDestinationRule:         nil,
+		c.informers.GatewayClass:                 nil,
+		c.informers.Gateway:                      nil,
+		c.informers.HTTPRoute:                    nil,
 	}
 
 	sharedInformers := []cache.SharedInformer{
@@ -139,13 +223,33 @@ func (c *Context) Run(stopChannel chan struct{}, omitCRDs bool, envVariables env
 		c.informers.Ingress,
 	}
 
+	// IngressClass is served from networking.k8s.io/v1 alongside the v1 Ingress
+	if c.isNetworkingV1Supported {
+		sharedInformers = append(sharedInformers, c.informers.IngressClass)
+	}
+
 	// For AGIC to watch for these CRDs the EnableBrownfieldDeploymentVarName env variable must be set to true
 	if envVariables.EnableBrownfieldDeployment {
 		sharedInformers = append(sharedInformers, c.informers.AzureIngressProhibitedTarget)
 	}
 
+	// For AGIC to watch for the ConfigMaps with backend CA certificates the EnableBackendCAConfigMapsVarName env variable must be set to true
+	if envVariables.EnableBackendCAConfigMaps {
+		sharedInformers = append(sharedInformers, c.informers.ConfigMap)
+	}
+
+	// For AGIC to watch for the AzureIngressRewrites the EnableRewriteVarName env variable must be set to true
+	if envVariables.EnableRewrite {
+		sharedInformers = append(sharedInformers, c.informers.AzureIngressRewrite)
+	}
+
 	if envVariables.EnableIstioIntegration {
-		sharedInformers = append(sharedInformers, c.informers.IstioGateway, c.informers.IstioVirtualService)
+		sharedInformers = append(sharedInformers, c.informers.IstioGateway, c.informers.IstioVirtualService, c.informers.IstioDestinationRule)
+	}
+
+	// The namespaces are watched for the labels, by which the listeners of the Gateways select the namespaces of their routes
+	if envVariables.EnableGatewayAPI {
+		sharedInformers = append(sharedInformers, c.informers.Namespace, c.informers.GatewayClass, c.informers.Gateway, c.informers.HTTPRoute)
 	}
 
 	for _, informer := range sharedInformers {
@@ -176,7 +280,8 @@ func (c *Context) ListServices() []*v1.Service {
 	var serviceList []*v1.Service
 	for _, serviceInterface := range c.Caches.Service.List() {
 		service := serviceInterface.(*v1.Service)
-		if hasTCPPort(service) {
+		// ExternalName services need no ports; App Gateway uses the port of the Ingress backend.
+		if hasTCPPort(service) || service.Spec.Type == v1.ServiceTypeExternalName {
 			serviceList = append(serviceList, service)
 		}
 	}
@@ -244,11 +349,11 @@ func (c *Context) IsEndpointReferencedByAnyIngress(endpoints *v1.Endpoints) bool
 }
 
 // ListHTTPIngresses returns a list of all the ingresses for HTTP from cache.
-func (c *Context) ListHTTPIngresses() []*v1beta1.Ingress {
-	var ingressList []*v1beta1.Ingress
+func (c *Context) ListHTTPIngresses() []*networking.Ingress {
+	var ingressList []*networking.Ingress
 	for _, ingressInterface := range c.Caches.Ingress.List() {
-		ingress := ingressInterface.(*v1beta1.Ingress)
-		if hasHTTPRule(ingress) && IsIngressApplicationGateway(ingress) {
+		ingress := ingressInterface.(*networking.Ingress)
+		if hasHTTPRule(ingress) && c.IsIngressApplicationGateway(ingress) {
 			ingressList = append(ingressList, ingress)
 		}
 	}
@@ -294,6 +399,23 @@ func (c *Context) GetService(serviceKey string) *v1.Service {
 	return service
 }
 
+// GetPod returns the pod identified by the key.
+func (c *Context) GetPod(podKey string) *v1.Pod {
+	podInterface, exist, err := c.Caches.Pods.GetByKey(podKey)
+
+	if err != nil {
+		glog.V(3).Infof("unable to get pod from store, error occurred %s", err.Error())
+		return nil
+	}
+
+	if !exist {
+		glog.V(9).Infof("Pod %s does not exist", podKey)
+		return nil
+	}
+
+	return podInterface.(*v1.Pod)
+}
+
 // GetSecret returns the secret identified by the key
 func (c *Context) GetSecret(secretKey string) *v1.Secret {
 	secretInterface, exist, err := c.Caches.Secret.GetByKey(secretKey)
@@ -312,6 +434,60 @@ func (c *Context) GetSecret(secretKey string) *v1.Secret {
 	return secret
 }
 
+// GetConfigMap returns the config map identified by the key
+func (c *Context) GetConfigMap(configMapKey string) *v1.ConfigMap {
+	configMapInterface, exist, err := c.Caches.ConfigMap.GetByKey(configMapKey)
+
+	if err != nil {
+		glog.Error("Error fetching config map from store:", err)
+		return nil
+	}
+
+	if !exist {
+		glog.Error("Error fetching config map from store! ConfigMap does not exist:", configMapKey)
+		return nil
+	}
+
+	configMap := configMapInterface.(*v1.ConfigMap)
+	return configMap
+}
+
+// IsConfigMapReferencedByAnyIngress provides whether a ConfigMap holds the backend CA certificates of an ingress
+func (c *Context) IsConfigMapReferencedByAnyIngress(configMap *v1.ConfigMap) bool {
+	return c.isBackendCACertificateReferencedByAnyIngress(annotations.ConfigMapKind, configMap.Namespace, configMap.Name)
+}
+
+// GetAzureIngressRewrite returns the AzureIngressRewrite identified by the key.
+func (c *Context) GetAzureIngressRewrite(rewriteKey string) *rewritev1.AzureIngressRewrite {
+	rewriteInterface, exist, err := c.Caches.AzureIngressRewrite.GetByKey(rewriteKey)
+
+	if err != nil {
+		glog.Error("Error fetching AzureIngressRewrite from store:", err)
+		return nil
+	}
+
+	if !exist {
+		glog.Error("Error fetching AzureIngressRewrite from store! AzureIngressRewrite does not exist:", rewriteKey)
+		return nil
+	}
+
+	return rewriteInterface.(*rewritev1.AzureIngressRewrite)
+}
+
+// IsAzureIngressRewriteReferencedByAnyIngress provides whether an AzureIngressRewrite is used by an ingress
+func (c *Context) IsAzureIngressRewriteReferencedByAnyIngress(rewrite *rewritev1.AzureIngressRewrite) bool {
+	for _, ingress := range c.ListHTTPIngresses() {
+		if ingress.Namespace != rewrite.Namespace {
+			continue
+		}
+		if name, err := annotations.RewriteRuleSet(ingress); err == nil && name == rewrite.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetVirtualServicesForGateway returns the VirtualServices for the provided gateway
 func (c *Context) GetVirtualServicesForGateway(gateway v1alpha3.Gateway) []*v1alpha3.VirtualService {
 	virtualServices := make([]*v1alpha3.VirtualService, 0)
@@ -368,7 +544,7 @@ func (c *Context) GetGateways() []*v1alpha3.Gateway {
 // GetInfrastructureResourceGroupID returns the subscription and resource group name of the underling infrastructure.
 // This uses ProviderID which is ID of the node assigned by the cloud provider in the format: <ProviderName>://<ProviderSpecificNodeID>
 func (c *Context) GetInfrastructureResourceGroupID() (azure.SubscriptionID, azure.ResourceGroup, error) {
-	nodes, err := c.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	nodes, err := c.kubeClient.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return azure.SubscriptionID(""), azure.ResourceGroup(""), err
 	}
@@ -383,23 +559,23 @@ func (c *Context) GetInfrastructureResourceGroupID() (azure.SubscriptionID, azur
 }
 
 // UpdateIngressStatus adds IP address in Ingress Status
-func (c *Context) UpdateIngressStatus(ingressToUpdate v1beta1.Ingress, address IPAddress) error {
-	ingressClient := c.kubeClient.ExtensionsV1beta1().Ingresses(ingressToUpdate.Namespace)
-	ingress, err := ingressClient.Get(ingressToUpdate.Name, metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("Unable to get ingress %s/%s", ingressToUpdate.Namespace, ingressToUpdate.Name)
-	}
-
+func (c *Context) UpdateIngressStatus(ingressToUpdate networking.Ingress, address IPAddress) error {
 	loadBalancerIngresses := []v1.LoadBalancerIngress{}
 	if address != "" {
 		loadBalancerIngresses = append(loadBalancerIngresses, v1.LoadBalancerIngress{
 			IP: string(address),
 		})
 	}
-	ingress.Status.LoadBalancer.Ingress = loadBalancerIngresses
 
-	if _, err := ingressClient.UpdateStatus(ingress); err != nil {
-		errorLine := fmt.Sprintf("Unable to update ingress %s/%s status: error %s", ingress.Namespace, ingress.Name, err.Error())
+	var err error
+	if c.isNetworkingV1Supported {
+		err = c.updateIngressStatus(ingressToUpdate, loadBalancerIngresses)
+	} else {
+		err = c.updateLegacyIngressStatus(ingressToUpdate, loadBalancerIngresses)
+	}
+
+	if err != nil {
+		errorLine := fmt.Sprintf("Unable to update ingress %s/%s status: error %s", ingressToUpdate.Namespace, ingressToUpdate.Name, err.Error())
 		glog.Error(errorLine)
 		return ErrorUnableToUpdateIngress
 	}
@@ -407,13 +583,77 @@ func (c *Context) UpdateIngressStatus(ingressToUpdate v1beta1.Ingress, address I
 	return nil
 }
 
-// IsIngressApplicationGateway checks if applicaiton gateway annotation is present on the ingress
-func IsIngressApplicationGateway(ingress *v1beta1.Ingress) bool {
-	val, _ := annotations.IsApplicationGatewayIngress(ingress)
-	return val
+func (c *Context) updateIngressStatus(ingressToUpdate networking.Ingress, loadBalancerIngresses []v1.LoadBalancerIngress) error {
+	ingressClient := c.kubeClient.NetworkingV1().Ingresses(ingressToUpdate.Namespace)
+	ingress, err := ingressClient.Get(context.TODO(), ingressToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	ingress.Status.LoadBalancer.Ingress = loadBalancerIngresses
+	_, err = ingressClient.UpdateStatus(context.TODO(), ingress, metav1.UpdateOptions{})
+	return err
 }
 
-func hasHTTPRule(ingress *v1beta1.Ingress) bool {
+func (c *Context) updateLegacyIngressStatus(ingressToUpdate networking.Ingress, loadBalancerIngresses []v1.LoadBalancerIngress) error {
+	ingressClient := c.kubeClient.ExtensionsV1beta1().Ingresses(ingressToUpdate.Namespace)
+	ingress, err := ingressClient.Get(context.TODO(), ingressToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	ingress.Status.LoadBalancer.Ingress = loadBalancerIngresses
+	_, err = ingressClient.UpdateStatus(context.TODO(), ingress, metav1.UpdateOptions{})
+	return err
+}
+
+// IsIngressApplicationGateway checks if the ingress is meant for the application gateway ingress controller.
+// This is the case when either the ingress class annotation is set to azure/application-gateway, when
+// spec.ingressClassName references an IngressClass with the AGIC controller, or when the ingress does not
+// specify a class and the default IngressClass has the AGIC controller.
+func (c *Context) IsIngressApplicationGateway(ingress *networking.Ingress) bool {
+	if className, err := annotations.IngressClass(ingress); err == nil {
+		return className == annotations.ApplicationGatewayIngressClass
+	}
+
+	if ingress.Spec.IngressClassName != nil {
+		ingressClass := c.GetIngressClass(*ingress.Spec.IngressClassName)
+		return ingressClass != nil && annotations.IsApplicationGatewayIngressClass(ingressClass)
+	}
+
+	for _, ingressClass := range c.ListIngressClasses() {
+		if annotations.IsDefaultIngressClass(ingressClass) && annotations.IsApplicationGatewayIngressClass(ingressClass) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetIngressClass returns the IngressClass with the given name from cache.
+func (c *Context) GetIngressClass(name string) *networking.IngressClass {
+	ingressClassInterface, exist, err := c.Caches.IngressClass.GetByKey(name)
+	if err != nil {
+		glog.V(3).Infof("unable to get ingress class from store, error occurred %s", err.Error())
+		return nil
+	}
+
+	if !exist {
+		glog.V(9).Infof("IngressClass %s does not exist", name)
+		return nil
+	}
+
+	return ingressClassInterface.(*networking.IngressClass)
+}
+
+// ListIngressClasses returns a list of all the IngressClasses from cache.
+func (c *Context) ListIngressClasses() []*networking.IngressClass {
+	var ingressClassList []*networking.IngressClass
+	for _, ingressClassInterface := range c.Caches.IngressClass.List() {
+		ingressClassList = append(ingressClassList, ingressClassInterface.(*networking.IngressClass))
+	}
+	return ingressClassList
+}
+
+func hasHTTPRule(ingress *networking.Ingress) bool {
 	for _, rule := range ingress.Spec.Rules {
 		if rule.HTTP != nil {
 			return true
@@ -439,6 +679,10 @@ func (c *Context) listServicesByPodSelector(pod *v1.Pod) []*v1.Service {
 
 	var serviceList []*v1.Service
 	for _, service := range c.ListServices() {
+		// ExternalName services select no pods.
+		if service.Spec.Type == v1.ServiceTypeExternalName {
+			continue
+		}
 		serviceLabelSet := mapset.NewSet()
 		for k, v := range service.Spec.Selector {
 			serviceLabelSet.Add(k + ":" + v)
@@ -457,7 +701,7 @@ func (c *Context) isServiceReferencedByAnyIngress(service *v1.Service) bool {
 		for _, rule := range ingress.Spec.Rules {
 			for _, path := range rule.HTTP.Paths {
 				// TODO(akshaysngupta) Use service ports
-				if path.Backend.ServiceName == service.Name {
+				if path.Backend.Service != nil && path.Backend.Service.Name == service.Name {
 					return true
 				}
 			}
@@ -466,3 +710,16 @@ func (c *Context) isServiceReferencedByAnyIngress(service *v1.Service) bool {
 
 	return false
 }
+
+func (c *Context) isBackendCACertificateReferencedByAnyIngress(kind annotations.ResourceKind, namespace string, name string) bool {
+	for _, ingress := range c.ListHTTPIngresses() {
+		if ingress.Namespace != namespace {
+			continue
+		}
+		if ref, err := annotations.BackendCACertificate(ingress); err == nil && ref.Kind == kind && ref.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
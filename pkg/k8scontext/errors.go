@@ -14,17 +14,23 @@ var (
 	// ErrorUnknownSecretType is an error.
 	ErrorUnknownSecretType              = errors.New("unknown secret type")
 
-	// ErrorCreatingFile is an error.
-	ErrorCreatingFile                   = errors.New("unable to create temp file")
-
 	// ErrorMalformedSecret is an error.
 	ErrorMalformedSecret                = errors.New("malformed secret")
 
-	// ErrorWritingToFile is an error.
-	ErrorWritingToFile                  = errors.New("unable to write to file")
+	// ErrorDecodingCertificate is an error.
+	ErrorDecodingCertificate            = errors.New("unable to decode the certificates in tls.crt")
+
+	// ErrorDecodingPrivateKey is an error.
+	ErrorDecodingPrivateKey             = errors.New("unable to decode the private key in tls.key")
+
+	// ErrorUnsupportedPrivateKey is an error.
+	ErrorUnsupportedPrivateKey          = errors.New("private key in tls.key is neither RSA nor ECDSA")
+
+	// ErrorCertificateKeyMismatch is an error.
+	ErrorCertificateKeyMismatch         = errors.New("private key in tls.key does not match any certificate in tls.crt")
 
-	// ErrorExportingWithOpenSSL is an error.
-	ErrorExportingWithOpenSSL           = errors.New("failed export with OpenSSL")
+	// ErrorExportingPfx is an error.
+	ErrorExportingPfx                   = errors.New("unable to export the certificate to PKCS#12")
 
 	// ErrorInformersNotInitialized is an error.
 	ErrorInformersNotInitialized        = errors.New("informers are not initialized")
@@ -0,0 +1,455 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+const (
+	// GatewayAPIGroup is the API group of the Gateway API resources.
+	GatewayAPIGroup = "gateway.networking.k8s.io"
+
+	// HTTPRouteKind is the kind of the routes App Gateway implements.
+	HTTPRouteKind = "HTTPRoute"
+
+	gatewayKind = "Gateway"
+	serviceKind = "Service"
+	secretKind  = "Secret"
+)
+
+// HTTPRouteAttachment is an HTTPRoute attached to a listener of a Gateway.
+type HTTPRouteAttachment struct {
+	Gateway  *gatewayv1beta1.Gateway
+	Listener *gatewayv1beta1.Listener
+	Route    *gatewayv1beta1.HTTPRoute
+
+	// Hostnames are the host names the route serves on the listener; an empty host name matches all hosts.
+	Hostnames []string
+}
+
+// IsGatewayClassApplicationGateway tells whether the GatewayClass names the Application Gateway ingress controller.
+func IsGatewayClassApplicationGateway(gatewayClass *gatewayv1beta1.GatewayClass) bool {
+	return gatewayClass.Spec.ControllerName == annotations.ApplicationGatewayIngressClass
+}
+
+// ListGatewayClasses returns the GatewayClasses of the Application Gateway ingress controller.
+func (c *Context) ListGatewayClasses() []*gatewayv1beta1.GatewayClass {
+	var gatewayClasses []*gatewayv1beta1.GatewayClass
+	for _, obj := range c.Caches.GatewayClass.List() {
+		if gatewayClass := obj.(*gatewayv1beta1.GatewayClass); IsGatewayClassApplicationGateway(gatewayClass) {
+			gatewayClasses = append(gatewayClasses, gatewayClass)
+		}
+	}
+	sort.Slice(gatewayClasses, func(i, j int) bool { return gatewayClasses[i].Name < gatewayClasses[j].Name })
+	return gatewayClasses
+}
+
+// GetGatewayClass returns the GatewayClass with the given name from cache.
+func (c *Context) GetGatewayClass(name string) *gatewayv1beta1.GatewayClass {
+	gatewayClassInterface, exist, err := c.Caches.GatewayClass.GetByKey(name)
+	if err != nil {
+		glog.V(3).Infof("unable to get gateway class from store, error occurred %s", err.Error())
+		return nil
+	}
+
+	if !exist {
+		glog.V(9).Infof("GatewayClass %s does not exist", name)
+		return nil
+	}
+
+	return gatewayClassInterface.(*gatewayv1beta1.GatewayClass)
+}
+
+// IsGatewayApplicationGateway tells whether the class of the Gateway names the Application Gateway ingress controller.
+func (c *Context) IsGatewayApplicationGateway(gateway *gatewayv1beta1.Gateway) bool {
+	gatewayClass := c.GetGatewayClass(gateway.Spec.GatewayClassName)
+	return gatewayClass != nil && IsGatewayClassApplicationGateway(gatewayClass)
+}
+
+// ListGatewayAPIGateways returns the Gateways of the GatewayClasses of the Application Gateway ingress controller.
+func (c *Context) ListGatewayAPIGateways() []*gatewayv1beta1.Gateway {
+	var gateways []*gatewayv1beta1.Gateway
+	for _, obj := range c.Caches.Gateway.List() {
+		if gateway := obj.(*gatewayv1beta1.Gateway); c.IsGatewayApplicationGateway(gateway) {
+			gateways = append(gateways, gateway)
+		}
+	}
+	sort.Slice(gateways, func(i, j int) bool {
+		return utils.GetResourceKey(gateways[i].Namespace, gateways[i].Name) < utils.GetResourceKey(gateways[j].Namespace, gateways[j].Name)
+	})
+	return gateways
+}
+
+// ListHTTPRoutes returns the HTTPRoutes from cache; the oldest route first, as it takes precedence over the newer ones.
+func (c *Context) ListHTTPRoutes() []*gatewayv1beta1.HTTPRoute {
+	var routes []*gatewayv1beta1.HTTPRoute
+	for _, obj := range c.Caches.HTTPRoute.List() {
+		routes = append(routes, obj.(*gatewayv1beta1.HTTPRoute))
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if !routes[i].CreationTimestamp.Equal(&routes[j].CreationTimestamp) {
+			return routes[i].CreationTimestamp.Before(&routes[j].CreationTimestamp)
+		}
+		return utils.GetResourceKey(routes[i].Namespace, routes[i].Name) < utils.GetResourceKey(routes[j].Namespace, routes[j].Name)
+	})
+	return routes
+}
+
+// GetNamespace returns the namespace with the given name from cache.
+func (c *Context) GetNamespace(name string) *v1.Namespace {
+	namespaceInterface, exist, err := c.Caches.Namespaces.GetByKey(name)
+	if err != nil {
+		glog.V(3).Infof("unable to get namespace from store, error occurred %s", err.Error())
+		return nil
+	}
+
+	if !exist {
+		glog.V(9).Infof("Namespace %s does not exist", name)
+		return nil
+	}
+
+	return namespaceInterface.(*v1.Namespace)
+}
+
+// IsNamespaceSelectedByAnyGateway provides whether a listener of a Gateway selects the namespaces of its routes by label,
+// in which case the labels of the namespaces decide which routes attach.
+func (c *Context) IsNamespaceSelectedByAnyGateway() bool {
+	for _, gateway := range c.ListGatewayAPIGateways() {
+		for _, listener := range gateway.Spec.Listeners {
+			if routeNamespacesFrom(&listener) == gatewayv1beta1.NamespacesFromSelector {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetHTTPRouteParent returns the Gateway the parent reference of the route references; nil when it is not one of the gateways.
+func GetHTTPRouteParent(route *gatewayv1beta1.HTTPRoute, parentRef gatewayv1beta1.ParentReference, gateways []*gatewayv1beta1.Gateway) *gatewayv1beta1.Gateway {
+	if parentRef.Group != nil && *parentRef.Group != GatewayAPIGroup {
+		return nil
+	}
+	if parentRef.Kind != nil && *parentRef.Kind != gatewayKind {
+		return nil
+	}
+	namespace := route.Namespace
+	if parentRef.Namespace != nil {
+		namespace = *parentRef.Namespace
+	}
+	for _, gateway := range gateways {
+		if gateway.Namespace == namespace && gateway.Name == parentRef.Name {
+			return gateway
+		}
+	}
+	return nil
+}
+
+// AttachHTTPRoute returns the listeners of the Gateway the route attaches to through the parent reference, and the reason
+// why the route attaches to none of them.
+// The route attaches to the listeners named by the section name and port of the reference, which allow the namespace of the
+// route and whose host names intersect with the host names of the route.
+func (c *Context) AttachHTTPRoute(route *gatewayv1beta1.HTTPRoute, parentRef gatewayv1beta1.ParentReference, gateway *gatewayv1beta1.Gateway) ([]HTTPRouteAttachment, string) {
+	var attachments []HTTPRouteAttachment
+	reason := gatewayv1beta1.ReasonNoMatchingParent
+	for idx := range gateway.Spec.Listeners {
+		listener := &gateway.Spec.Listeners[idx]
+		if parentRef.SectionName != nil && *parentRef.SectionName != listener.Name {
+			continue
+		}
+		if parentRef.Port != nil && *parentRef.Port != listener.Port {
+			continue
+		}
+
+		if !c.isHTTPRouteAllowed(route, gateway, listener) {
+			if reason == gatewayv1beta1.ReasonNoMatchingParent {
+				reason = gatewayv1beta1.ReasonNotAllowedByListeners
+			}
+			continue
+		}
+
+		hostnames, intersect := intersectHostnames(listener.Hostname, route.Spec.Hostnames)
+		if !intersect {
+			reason = gatewayv1beta1.ReasonNoMatchingListenerHostname
+			continue
+		}
+
+		attachments = append(attachments, HTTPRouteAttachment{
+			Gateway:   gateway,
+			Listener:  listener,
+			Route:     route,
+			Hostnames: hostnames,
+		})
+	}
+
+	if len(attachments) > 0 {
+		return attachments, ""
+	}
+	return nil, reason
+}
+
+// isHTTPRouteAllowed tells whether the allowed routes of the listener include the kind and the namespace of the route.
+func (c *Context) isHTTPRouteAllowed(route *gatewayv1beta1.HTTPRoute, gateway *gatewayv1beta1.Gateway, listener *gatewayv1beta1.Listener) bool {
+	if listener.Protocol != gatewayv1beta1.HTTPProtocolType && listener.Protocol != gatewayv1beta1.HTTPSProtocolType {
+		return false
+	}
+
+	if listener.AllowedRoutes != nil && len(listener.AllowedRoutes.Kinds) > 0 {
+		allowedKind := false
+		for _, kind := range listener.AllowedRoutes.Kinds {
+			if kind.Kind == HTTPRouteKind && (kind.Group == nil || *kind.Group == GatewayAPIGroup) {
+				allowedKind = true
+			}
+		}
+		if !allowedKind {
+			return false
+		}
+	}
+
+	switch routeNamespacesFrom(listener) {
+	case gatewayv1beta1.NamespacesFromAll:
+		return true
+	case gatewayv1beta1.NamespacesFromSelector:
+		selector, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			glog.Errorf("Listener %s of Gateway %s/%s has an invalid namespace selector: %s", listener.Name, gateway.Namespace, gateway.Name, err)
+			return false
+		}
+		namespace := c.GetNamespace(route.Namespace)
+		return namespace != nil && selector.Matches(labels.Set(namespace.Labels))
+	default:
+		return route.Namespace == gateway.Namespace
+	}
+}
+
+// routeNamespacesFrom returns the namespaces the listener allows routes from; Same by default.
+func routeNamespacesFrom(listener *gatewayv1beta1.Listener) gatewayv1beta1.FromNamespaces {
+	if listener.AllowedRoutes == nil || listener.AllowedRoutes.Namespaces == nil || listener.AllowedRoutes.Namespaces.From == nil {
+		return gatewayv1beta1.NamespacesFromSame
+	}
+	from := *listener.AllowedRoutes.Namespaces.From
+	if from == gatewayv1beta1.NamespacesFromSelector && listener.AllowedRoutes.Namespaces.Selector == nil {
+		return gatewayv1beta1.NamespacesFromSame
+	}
+	return from
+}
+
+// intersectHostnames returns the host names matched by both the listener and the route; the most specific of two matching host names.
+// A listener without host name serves the host names of the route, and a route without host names the host name of the listener.
+func intersectHostnames(listenerHostname *string, routeHostnames []string) ([]string, bool) {
+	if listenerHostname == nil || *listenerHostname == "" {
+		if len(routeHostnames) == 0 {
+			return []string{""}, true
+		}
+		return routeHostnames, true
+	}
+	if len(routeHostnames) == 0 {
+		return []string{*listenerHostname}, true
+	}
+
+	var hostnames []string
+	for _, routeHostname := range routeHostnames {
+		switch {
+		case routeHostname == *listenerHostname:
+			hostnames = append(hostnames, routeHostname)
+		case matchesHostname(*listenerHostname, routeHostname):
+			hostnames = append(hostnames, routeHostname)
+		case matchesHostname(routeHostname, *listenerHostname):
+			hostnames = append(hostnames, *listenerHostname)
+		}
+	}
+	return hostnames, len(hostnames) > 0
+}
+
+// matchesHostname tells whether the wildcard host name, like *.contoso.com, matches the host name.
+func matchesHostname(wildcard string, hostname string) bool {
+	if !strings.HasPrefix(wildcard, "*.") || strings.HasPrefix(hostname, "*.") {
+		return false
+	}
+	return strings.HasSuffix(hostname, wildcard[1:])
+}
+
+// ResolveHTTPBackendRef returns the service of a backend of the route, and the reason why it cannot be resolved.
+// Only services in the namespace of the route can be referenced.
+func (c *Context) ResolveHTTPBackendRef(route *gatewayv1beta1.HTTPRoute, backendRef gatewayv1beta1.HTTPBackendRef) (*v1.Service, string) {
+	if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != serviceKind) {
+		return nil, gatewayv1beta1.ReasonInvalidKind
+	}
+	if backendRef.Namespace != nil && *backendRef.Namespace != route.Namespace {
+		return nil, gatewayv1beta1.ReasonRefNotPermitted
+	}
+	if backendRef.Port == nil {
+		return nil, gatewayv1beta1.ReasonBackendNotFound
+	}
+	service := c.GetService(utils.GetResourceKey(route.Namespace, backendRef.Name))
+	if service == nil {
+		return nil, gatewayv1beta1.ReasonBackendNotFound
+	}
+	return service, ""
+}
+
+// ResolveGatewayListener returns the key of the TLS secret of an HTTPS listener, and the reason why App Gateway cannot implement the listener.
+// App Gateway terminates TLS with the certificate of a secret in the namespace of the Gateway.
+func (c *Context) ResolveGatewayListener(gateway *gatewayv1beta1.Gateway, listener *gatewayv1beta1.Listener) (string, string) {
+	switch listener.Protocol {
+	case gatewayv1beta1.HTTPProtocolType:
+		return "", ""
+	case gatewayv1beta1.HTTPSProtocolType:
+	default:
+		return "", gatewayv1beta1.ReasonUnsupportedProtocol
+	}
+
+	if listener.TLS != nil && listener.TLS.Mode != nil && *listener.TLS.Mode != gatewayv1beta1.TLSModeTerminate {
+		return "", gatewayv1beta1.ReasonUnsupportedProtocol
+	}
+	secretKey, reason := GatewayListenerSecretKey(gateway, listener)
+	if reason != "" {
+		return "", reason
+	}
+	if c.CertificateSecretStore.GetPfxCertificate(secretKey) == nil {
+		return "", gatewayv1beta1.ReasonInvalidCertificateRef
+	}
+	return secretKey, ""
+}
+
+// GatewayListenerSecretKey returns the key of the secret of the first certificate reference of the listener.
+func GatewayListenerSecretKey(gateway *gatewayv1beta1.Gateway, listener *gatewayv1beta1.Listener) (string, string) {
+	if listener.TLS == nil || len(listener.TLS.CertificateRefs) == 0 {
+		return "", gatewayv1beta1.ReasonInvalidCertificateRef
+	}
+	certificateRef := listener.TLS.CertificateRefs[0]
+	if (certificateRef.Group != nil && *certificateRef.Group != "") || (certificateRef.Kind != nil && *certificateRef.Kind != secretKind) {
+		return "", gatewayv1beta1.ReasonInvalidCertificateRef
+	}
+	if certificateRef.Namespace != nil && *certificateRef.Namespace != gateway.Namespace {
+		return "", gatewayv1beta1.ReasonRefNotPermitted
+	}
+	return utils.GetResourceKey(gateway.Namespace, certificateRef.Name), ""
+}
+
+// UpdateGatewayClassStatus sets the conditions of the status of the GatewayClass; the status is not written when it does not change.
+func (c *Context) UpdateGatewayClassStatus(gatewayClassToUpdate *gatewayv1beta1.GatewayClass, conditions []metav1.Condition) error {
+	client := c.gatewayCrdClient.GatewayV1beta1().GatewayClasses()
+	gatewayClass, err := client.Get(context.TODO(), gatewayClassToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := gatewayClass.Status.DeepCopy()
+	setConditions(&status.Conditions, conditions, gatewayClass.Generation)
+	if reflect.DeepEqual(*status, gatewayClass.Status) {
+		return nil
+	}
+	gatewayClass.Status = *status
+	_, err = client.UpdateStatus(context.TODO(), gatewayClass, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateGatewayStatus sets the addresses, conditions and listener statuses of the Gateway; the status is not written when it does not change.
+func (c *Context) UpdateGatewayStatus(gatewayToUpdate *gatewayv1beta1.Gateway, addresses []gatewayv1beta1.GatewayAddress, conditions []metav1.Condition, listeners []gatewayv1beta1.ListenerStatus) error {
+	client := c.gatewayCrdClient.GatewayV1beta1().Gateways(gatewayToUpdate.Namespace)
+	gateway, err := client.Get(context.TODO(), gatewayToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	status := gateway.Status.DeepCopy()
+	status.Addresses = addresses
+	setConditions(&status.Conditions, conditions, gateway.Generation)
+
+	existingListeners := make(map[string]gatewayv1beta1.ListenerStatus)
+	for _, listenerStatus := range gateway.Status.Listeners {
+		existingListeners[listenerStatus.Name] = listenerStatus
+	}
+	status.Listeners = nil
+	for _, listenerStatus := range listeners {
+		listenerConditions := append([]metav1.Condition(nil), existingListeners[listenerStatus.Name].Conditions...)
+		setConditions(&listenerConditions, listenerStatus.Conditions, gateway.Generation)
+		listenerStatus.Conditions = listenerConditions
+		status.Listeners = append(status.Listeners, listenerStatus)
+	}
+
+	if reflect.DeepEqual(*status, gateway.Status) {
+		return nil
+	}
+	gateway.Status = *status
+	_, err = client.UpdateStatus(context.TODO(), gateway, metav1.UpdateOptions{})
+	return err
+}
+
+// UpdateHTTPRouteStatus sets the statuses of the route for the parents of the Application Gateway ingress controller, and keeps
+// the statuses written by other controllers; the status is not written when it does not change.
+func (c *Context) UpdateHTTPRouteStatus(routeToUpdate *gatewayv1beta1.HTTPRoute, parents []gatewayv1beta1.RouteParentStatus) error {
+	client := c.gatewayCrdClient.GatewayV1beta1().HTTPRoutes(routeToUpdate.Namespace)
+	route, err := client.Get(context.TODO(), routeToUpdate.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	var status gatewayv1beta1.HTTPRouteStatus
+	existingParents := make(map[string]gatewayv1beta1.RouteParentStatus)
+	for _, parentStatus := range route.Status.Parents {
+		if parentStatus.ControllerName != annotations.ApplicationGatewayIngressClass {
+			status.Parents = append(status.Parents, *parentStatus.DeepCopy())
+			continue
+		}
+		existingParents[parentReferenceKey(parentStatus.ParentRef)] = parentStatus
+	}
+	for _, parentStatus := range parents {
+		parentConditions := append([]metav1.Condition(nil), existingParents[parentReferenceKey(parentStatus.ParentRef)].Conditions...)
+		setConditions(&parentConditions, parentStatus.Conditions, route.Generation)
+		parentStatus.ControllerName = annotations.ApplicationGatewayIngressClass
+		parentStatus.Conditions = parentConditions
+		status.Parents = append(status.Parents, parentStatus)
+	}
+
+	if reflect.DeepEqual(status, route.Status) {
+		return nil
+	}
+	route.Status = status
+	_, err = client.UpdateStatus(context.TODO(), route, metav1.UpdateOptions{})
+	return err
+}
+
+// setConditions sets the conditions observed for the generation; the transition time of a condition only changes with its status.
+func setConditions(existing *[]metav1.Condition, conditions []metav1.Condition, generation int64) {
+	for _, condition := range conditions {
+		condition.ObservedGeneration = generation
+		meta.SetStatusCondition(existing, condition)
+	}
+}
+
+// GatewayListenerKey identifies a listener of a Gateway.
+func GatewayListenerKey(gateway *gatewayv1beta1.Gateway, listenerName string) string {
+	return utils.GetResourceKey(gateway.Namespace, gateway.Name) + "/" + listenerName
+}
+
+// parentReferenceKey identifies a parent reference in the statuses of a route.
+func parentReferenceKey(parentRef gatewayv1beta1.ParentReference) string {
+	key := parentRef.Name
+	if parentRef.Namespace != nil {
+		key = utils.GetResourceKey(*parentRef.Namespace, parentRef.Name)
+	}
+	if parentRef.SectionName != nil {
+		key += "#" + *parentRef.SectionName
+	}
+	if parentRef.Port != nil {
+		key += ":" + fmt.Sprint(*parentRef.Port)
+	}
+	return key
+}
@@ -0,0 +1,144 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gatewayFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+)
+
+var _ = ginkgo.Describe("K8scontext Gateway API", func() {
+	var ctxt *Context
+	var gatewayCrdClient *gatewayFake.Clientset
+	var gateway *gatewayv1beta1.Gateway
+	var route *gatewayv1beta1.HTTPRoute
+
+	fromNamespaces := func(from gatewayv1beta1.FromNamespaces) *gatewayv1beta1.FromNamespaces { return &from }
+
+	ginkgo.BeforeEach(func() {
+		gateway = &gatewayv1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "contoso", Namespace: "infra", Generation: 1},
+			Spec: gatewayv1beta1.GatewaySpec{
+				GatewayClassName: "azure-application-gateway",
+				Listeners: []gatewayv1beta1.Listener{{
+					Name:     "http",
+					Hostname: to.StringPtr("*.contoso.com"),
+					Port:     80,
+					Protocol: gatewayv1beta1.HTTPProtocolType,
+				}},
+			},
+		}
+		route = &gatewayv1beta1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "store", Generation: 1},
+			Spec: gatewayv1beta1.HTTPRouteSpec{
+				ParentRefs: []gatewayv1beta1.ParentReference{{Name: gateway.Name, Namespace: to.StringPtr(gateway.Namespace)}},
+				Hostnames:  []string{"store.contoso.com", "www.fabrikam.com"},
+			},
+		}
+
+		// the objects are created through the client; the tracker of the fake client would guess the resource "gatewaies" for them
+		gatewayCrdClient = gatewayFake.NewSimpleClientset()
+		_, err := gatewayCrdClient.GatewayV1beta1().Gateways(gateway.Namespace).Create(context.TODO(), gateway, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		ctxt = NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), gatewayCrdClient, []string{}, 1000*time.Second, metricstore.NewFakeMetricStore())
+		Expect(ctxt.Caches.Namespaces.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "store", Labels: map[string]string{"expose": "true"}}})).To(Succeed())
+	})
+
+	ginkgo.Context("Test the allowed routes of the listeners", func() {
+		ginkgo.It("allows routes in the namespace of the Gateway by default", func() {
+			attachments, reason := ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(attachments).To(BeEmpty())
+			Expect(reason).To(Equal(gatewayv1beta1.ReasonNotAllowedByListeners))
+
+			route.Namespace = gateway.Namespace
+			attachments, reason = ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(reason).To(BeEmpty())
+			Expect(attachments).To(HaveLen(1))
+		})
+
+		ginkgo.It("allows routes in all namespaces", func() {
+			gateway.Spec.Listeners[0].AllowedRoutes = &gatewayv1beta1.AllowedRoutes{
+				Namespaces: &gatewayv1beta1.RouteNamespaces{From: fromNamespaces(gatewayv1beta1.NamespacesFromAll)},
+			}
+			attachments, reason := ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(reason).To(BeEmpty())
+			Expect(attachments).To(HaveLen(1))
+		})
+
+		ginkgo.It("allows routes in the namespaces with the labels of the selector", func() {
+			gateway.Spec.Listeners[0].AllowedRoutes = &gatewayv1beta1.AllowedRoutes{
+				Namespaces: &gatewayv1beta1.RouteNamespaces{
+					From:     fromNamespaces(gatewayv1beta1.NamespacesFromSelector),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"expose": "true"}},
+				},
+			}
+			attachments, _ := ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(attachments).To(HaveLen(1))
+
+			gateway.Spec.Listeners[0].AllowedRoutes.Namespaces.Selector.MatchLabels["expose"] = "false"
+			_, reason := ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(reason).To(Equal(gatewayv1beta1.ReasonNotAllowedByListeners))
+		})
+	})
+
+	ginkgo.Context("Test the host names of the attached routes", func() {
+		ginkgo.BeforeEach(func() {
+			route.Namespace = gateway.Namespace
+		})
+
+		ginkgo.It("serves the host names of the route matched by the listener", func() {
+			attachments, _ := ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(attachments[0].Hostnames).To(Equal([]string{"store.contoso.com"}))
+		})
+
+		ginkgo.It("serves the host name of the listener for a route without host names", func() {
+			route.Spec.Hostnames = nil
+			attachments, _ := ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(attachments[0].Hostnames).To(Equal([]string{"*.contoso.com"}))
+		})
+
+		ginkgo.It("does not attach a route whose host names the listener does not match", func() {
+			route.Spec.Hostnames = []string{"www.fabrikam.com"}
+			attachments, reason := ctxt.AttachHTTPRoute(route, route.Spec.ParentRefs[0], gateway)
+			Expect(attachments).To(BeEmpty())
+			Expect(reason).To(Equal(gatewayv1beta1.ReasonNoMatchingListenerHostname))
+		})
+	})
+
+	ginkgo.Context("Test the status of the Gateways", func() {
+		ginkgo.It("writes the status only when it changes", func() {
+			addresses := []gatewayv1beta1.GatewayAddress{{Type: to.StringPtr("IPAddress"), Value: "1.2.3.4"}}
+			conditions := []metav1.Condition{{Type: gatewayv1beta1.ConditionAccepted, Status: metav1.ConditionTrue, Reason: gatewayv1beta1.ReasonAccepted}}
+			Expect(ctxt.UpdateGatewayStatus(gateway, addresses, conditions, nil)).To(Succeed())
+
+			updated, err := gatewayCrdClient.GatewayV1beta1().Gateways(gateway.Namespace).Get(context.TODO(), gateway.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.Status.Addresses).To(Equal(addresses))
+			Expect(updated.Status.Conditions).To(HaveLen(1))
+			Expect(updated.Status.Conditions[0].ObservedGeneration).To(Equal(gateway.Generation))
+
+			gatewayCrdClient.ClearActions()
+			Expect(ctxt.UpdateGatewayStatus(gateway, addresses, conditions, nil)).To(Succeed())
+			for _, action := range gatewayCrdClient.Actions() {
+				Expect(action).ToNot(BeAssignableToTypeOf(k8stesting.UpdateActionImpl{}))
+			}
+		})
+	})
+})
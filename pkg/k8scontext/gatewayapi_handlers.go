@@ -0,0 +1,131 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+
+	gatewayv1beta1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/gatewayapi/v1beta1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// gateway class resource handlers; only the classes of the Application Gateway ingress controller are followed
+func (h handlers) gatewayClassAdd(obj interface{}) {
+	if !isGatewayClassApplicationGateway(obj) {
+		return
+	}
+	// the Gateways of the class may have been added before the class
+	for _, gateway := range h.context.Caches.Gateway.List() {
+		if gateway := gateway.(*gatewayv1beta1.Gateway); gateway.Spec.GatewayClassName == obj.(*gatewayv1beta1.GatewayClass).Name {
+			h.trackGatewaySecrets(gateway)
+		}
+	}
+	h.addFunc(obj)
+}
+
+func (h handlers) gatewayClassDelete(obj interface{}) {
+	gatewayClass := obj
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		gatewayClass = tombstone.Obj
+	}
+	if !isGatewayClassApplicationGateway(gatewayClass) {
+		return
+	}
+	h.deleteFunc(obj)
+}
+
+func (h handlers) gatewayClassUpdate(oldObj, newObj interface{}) {
+	if !isGatewayClassApplicationGateway(newObj) && !isGatewayClassApplicationGateway(oldObj) {
+		return
+	}
+	h.gatewayAPIUpdate(oldObj, newObj)
+}
+
+// gateway resource handlers; only the Gateways of the classes of the Application Gateway ingress controller are followed
+func (h handlers) gatewayAdd(obj interface{}) {
+	if !h.isGatewayApplicationGateway(obj) {
+		return
+	}
+	h.trackGatewaySecrets(obj.(*gatewayv1beta1.Gateway))
+	h.addFunc(obj)
+}
+
+func (h handlers) gatewayDelete(obj interface{}) {
+	gateway := obj
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		gateway = tombstone.Obj
+	}
+	if !h.isGatewayApplicationGateway(gateway) {
+		return
+	}
+	h.context.ingressSecretsMap.Erase(gatewaySecretsKey(gateway.(*gatewayv1beta1.Gateway)))
+	h.deleteFunc(obj)
+}
+
+func (h handlers) gatewayUpdate(oldObj, newObj interface{}) {
+	// a Gateway moving to another class must still remove its config from App Gateway
+	if !h.isGatewayApplicationGateway(newObj) && !h.isGatewayApplicationGateway(oldObj) {
+		return
+	}
+	gateway := newObj.(*gatewayv1beta1.Gateway)
+	h.context.ingressSecretsMap.Clear(gatewaySecretsKey(gateway))
+	if h.isGatewayApplicationGateway(newObj) {
+		h.trackGatewaySecrets(gateway)
+	}
+	h.gatewayAPIUpdate(oldObj, newObj)
+}
+
+// gatewayAPIUpdate ignores the updates, which do not change the generation of the resource; these are the status updates
+// AGIC writes itself.
+func (h handlers) gatewayAPIUpdate(oldObj, newObj interface{}) {
+	oldMeta, oldErr := meta.Accessor(oldObj)
+	newMeta, newErr := meta.Accessor(newObj)
+	if oldErr == nil && newErr == nil && oldMeta.GetGeneration() == newMeta.GetGeneration() && oldMeta.GetGeneration() != 0 {
+		return
+	}
+	h.updateFunc(oldObj, newObj)
+}
+
+// trackGatewaySecrets converts the TLS secrets of the HTTPS listeners of the Gateway into certificates, and follows the changes
+// of the secrets the same way the secrets of the Ingresses are followed.
+func (h handlers) trackGatewaySecrets(gateway *gatewayv1beta1.Gateway) {
+	gatewayKey := gatewaySecretsKey(gateway)
+	for idx := range gateway.Spec.Listeners {
+		listener := &gateway.Spec.Listeners[idx]
+		if listener.Protocol != gatewayv1beta1.HTTPSProtocolType {
+			continue
+		}
+		secKey, reason := GatewayListenerSecretKey(gateway, listener)
+		if reason != "" || h.context.ingressSecretsMap.ContainsPair(gatewayKey, secKey) {
+			continue
+		}
+		if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
+			if !h.context.ingressSecretsMap.ContainsValue(secKey) {
+				if err := h.context.CertificateSecretStore.ConvertSecret(secKey, secret.(*v1.Secret)); err != nil {
+					continue
+				}
+			}
+		}
+		h.context.ingressSecretsMap.Insert(gatewayKey, secKey)
+	}
+}
+
+func isGatewayClassApplicationGateway(obj interface{}) bool {
+	gatewayClass, ok := obj.(*gatewayv1beta1.GatewayClass)
+	return ok && gatewayClass != nil && IsGatewayClassApplicationGateway(gatewayClass)
+}
+
+func (h handlers) isGatewayApplicationGateway(obj interface{}) bool {
+	gateway, ok := obj.(*gatewayv1beta1.Gateway)
+	return ok && gateway != nil && h.context.IsGatewayApplicationGateway(gateway)
+}
+
+// gatewaySecretsKey keys the secrets of a Gateway apart from the secrets of an Ingress or an Istio Gateway with the same name.
+func gatewaySecretsKey(gateway *gatewayv1beta1.Gateway) string {
+	return "gatewayapi:" + utils.GetResourceKey(gateway.Namespace, gateway.Name)
+}
@@ -23,8 +23,9 @@ func (h handlers) updateFunc(oldObj, newObj interface{}) {
 		return
 	}
 	h.context.Work <- events.Event{
-		Type:  events.Update,
-		Value: newObj,
+		Type:     events.Update,
+		Value:    newObj,
+		OldValue: oldObj,
 	}
 }
 
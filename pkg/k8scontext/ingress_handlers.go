@@ -4,7 +4,7 @@ import (
 	"reflect"
 
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
@@ -13,9 +13,9 @@ import (
 
 // ingress resource handlers
 func (h handlers) ingressAdd(obj interface{}) {
-	ing := obj.(*v1beta1.Ingress)
+	ing := obj.(*networking.Ingress)
 
-	if !IsIngressApplicationGateway(ing) {
+	if !h.context.IsIngressApplicationGateway(ing) {
 		return
 	}
 
@@ -46,19 +46,19 @@ func (h handlers) ingressAdd(obj interface{}) {
 }
 
 func (h handlers) ingressDelete(obj interface{}) {
-	ing, ok := obj.(*v1beta1.Ingress)
+	ing, ok := obj.(*networking.Ingress)
 	if !ok {
 		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
 		if !ok {
 			// unable to get from tombstone
 			return
 		}
-		ing, ok = tombstone.Obj.(*v1beta1.Ingress)
+		ing, ok = tombstone.Obj.(*networking.Ingress)
 	}
 	if ing == nil {
 		return
 	}
-	if !IsIngressApplicationGateway(ing) {
+	if !h.context.IsIngressApplicationGateway(ing) {
 		return
 	}
 	ingKey := utils.GetResourceKey(ing.Namespace, ing.Name)
@@ -74,9 +74,9 @@ func (h handlers) ingressUpdate(oldObj, newObj interface{}) {
 	if reflect.DeepEqual(oldObj, newObj) {
 		return
 	}
-	oldIng := oldObj.(*v1beta1.Ingress)
-	ing := newObj.(*v1beta1.Ingress)
-	if !IsIngressApplicationGateway(ing) && !IsIngressApplicationGateway(oldIng) {
+	oldIng := oldObj.(*networking.Ingress)
+	ing := newObj.(*networking.Ingress)
+	if !h.context.IsIngressApplicationGateway(ing) && !h.context.IsIngressApplicationGateway(oldIng) {
 		return
 	}
 	if ing.Spec.TLS != nil && len(ing.Spec.TLS) > 0 {
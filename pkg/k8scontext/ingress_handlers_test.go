@@ -9,19 +9,21 @@ import (
 	"time"
 
 	"github.com/onsi/ginkgo"
-	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gatewayFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
 
 var _ = ginkgo.Describe("K8scontext Ingress Cache Handlers", func() {
-	var k8sClient kubernetes.Interface
+	k8sClient := testclient.NewSimpleClientset()
 
 	ginkgo.Context("Test ingress handlers", func() {
 		h := handlers{
-			context: NewContext(k8sClient, fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), []string{"ns"}, 1000*time.Second),
+			context: NewContext(k8sClient, fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), gatewayFake.NewSimpleClientset(), []string{"ns"}, 1000*time.Second, metricstore.NewFakeMetricStore()),
 		}
 
 		ginkgo.It("add, delete, update ingress from cache", func() {
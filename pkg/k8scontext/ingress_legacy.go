@@ -0,0 +1,136 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+	extensions "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// IsNetworkingV1PackageSupported checks whether the API server serves Ingress resources from networking.k8s.io/v1.
+// Older clusters only serve extensions/v1beta1, in which case AGIC falls back to the legacy API.
+func IsNetworkingV1PackageSupported(kubeClient kubernetes.Interface) bool {
+	resources, err := kubeClient.Discovery().ServerResourcesForGroupVersion(networking.SchemeGroupVersion.String())
+	if err != nil || resources == nil {
+		glog.V(3).Infof("%s is not served by the API server; falling back to extensions/v1beta1 Ingress", networking.SchemeGroupVersion.String())
+		return false
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "ingresses" {
+			return true
+		}
+	}
+	return false
+}
+
+// newLegacyIngressInformer watches extensions/v1beta1 Ingress resources and stores them
+// in the cache converted to networking.k8s.io/v1, so the rest of AGIC deals with a single type.
+func newLegacyIngressInformer(kubeClient kubernetes.Interface, namespace string, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				legacyList, err := kubeClient.ExtensionsV1beta1().Ingresses(namespace).List(context.TODO(), options)
+				if err != nil {
+					return nil, err
+				}
+				list := &networking.IngressList{ListMeta: legacyList.ListMeta}
+				for idx := range legacyList.Items {
+					list.Items = append(list.Items, *convertV1Beta1Ingress(&legacyList.Items[idx]))
+				}
+				return list, nil
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				w, err := kubeClient.ExtensionsV1beta1().Ingresses(namespace).Watch(context.TODO(), options)
+				if err != nil {
+					return nil, err
+				}
+				return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+					if legacyIngress, ok := in.Object.(*extensions.Ingress); ok {
+						in.Object = convertV1Beta1Ingress(legacyIngress)
+					}
+					return in, true
+				}), nil
+			},
+		},
+		&networking.Ingress{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+// convertV1Beta1Ingress converts an extensions/v1beta1 Ingress into its networking.k8s.io/v1 equivalent.
+func convertV1Beta1Ingress(legacy *extensions.Ingress) *networking.Ingress {
+	ingress := &networking.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: networking.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: *legacy.ObjectMeta.DeepCopy(),
+		Spec: networking.IngressSpec{
+			IngressClassName: legacy.Spec.IngressClassName,
+		},
+	}
+
+	if legacy.Spec.Backend != nil {
+		ingress.Spec.DefaultBackend = convertV1Beta1IngressBackend(*legacy.Spec.Backend)
+	}
+
+	for _, tls := range legacy.Spec.TLS {
+		ingress.Spec.TLS = append(ingress.Spec.TLS, networking.IngressTLS{
+			Hosts:      append([]string(nil), tls.Hosts...),
+			SecretName: tls.SecretName,
+		})
+	}
+
+	for _, legacyRule := range legacy.Spec.Rules {
+		rule := networking.IngressRule{Host: legacyRule.Host}
+		if legacyRule.HTTP != nil {
+			rule.HTTP = &networking.HTTPIngressRuleValue{}
+			for _, legacyPath := range legacyRule.HTTP.Paths {
+				path := networking.HTTPIngressPath{
+					Path:    legacyPath.Path,
+					Backend: *convertV1Beta1IngressBackend(legacyPath.Backend),
+				}
+				if legacyPath.PathType != nil {
+					pathType := networking.PathType(*legacyPath.PathType)
+					path.PathType = &pathType
+				}
+				rule.HTTP.Paths = append(rule.HTTP.Paths, path)
+			}
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, rule)
+	}
+
+	legacy.Status.LoadBalancer.DeepCopyInto(&ingress.Status.LoadBalancer)
+
+	return ingress
+}
+
+func convertV1Beta1IngressBackend(legacy extensions.IngressBackend) *networking.IngressBackend {
+	if legacy.Resource != nil {
+		return &networking.IngressBackend{
+			Resource: legacy.Resource.DeepCopy(),
+		}
+	}
+
+	service := &networking.IngressServiceBackend{Name: legacy.ServiceName}
+	if legacy.ServicePort.Type == intstr.String {
+		service.Port.Name = legacy.ServicePort.StrVal
+	} else {
+		service.Port.Number = legacy.ServicePort.IntVal
+	}
+	return &networking.IngressBackend{Service: service}
+}
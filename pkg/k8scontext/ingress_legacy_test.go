@@ -0,0 +1,127 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"context"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gatewayFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = ginkgo.Describe("K8scontext extensions/v1beta1 Ingress support", func() {
+	ingressNS := "legacy-ns"
+	prefix := extensions.PathTypePrefix
+	legacyIngress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy",
+			Namespace: ingressNS,
+			Annotations: map[string]string{
+				annotations.IngressClassKey: annotations.ApplicationGatewayIngressClass,
+			},
+		},
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{
+				ServiceName: "default",
+				ServicePort: intstr.FromString("http"),
+			},
+			TLS: []extensions.IngressTLS{
+				{Hosts: []string{"hello.com"}, SecretName: "secret"},
+			},
+			Rules: []extensions.IngressRule{
+				{
+					Host: "hello.com",
+					IngressRuleValue: extensions.IngressRuleValue{
+						HTTP: &extensions.HTTPIngressRuleValue{
+							Paths: []extensions.HTTPIngressPath{
+								{
+									Path:     "/hi",
+									PathType: &prefix,
+									Backend: extensions.IngressBackend{
+										ServiceName: tests.ServiceName,
+										ServicePort: intstr.FromInt(80),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ginkgo.Context("Checking IsNetworkingV1PackageSupported", func() {
+		ginkgo.It("is false when the API server does not serve networking.k8s.io/v1", func() {
+			Expect(IsNetworkingV1PackageSupported(testclient.NewSimpleClientset())).To(BeFalse())
+		})
+
+		ginkgo.It("is true when the API server serves networking.k8s.io/v1 Ingress", func() {
+			k8sClient := testclient.NewSimpleClientset()
+			tests.AddNetworkingV1Resources(k8sClient)
+			Expect(IsNetworkingV1PackageSupported(k8sClient)).To(BeTrue())
+		})
+	})
+
+	ginkgo.Context("Checking convertV1Beta1Ingress", func() {
+		ginkgo.It("converts all fields to networking.k8s.io/v1", func() {
+			ingress := convertV1Beta1Ingress(legacyIngress)
+			Expect(ingress.ObjectMeta).To(Equal(legacyIngress.ObjectMeta))
+			Expect(ingress.Spec.DefaultBackend.Service).To(Equal(&networking.IngressServiceBackend{
+				Name: "default",
+				Port: networking.ServiceBackendPort{Name: "http"},
+			}))
+			Expect(ingress.Spec.TLS).To(Equal([]networking.IngressTLS{
+				{Hosts: []string{"hello.com"}, SecretName: "secret"},
+			}))
+
+			path := ingress.Spec.Rules[0].HTTP.Paths[0]
+			Expect(path.Path).To(Equal("/hi"))
+			Expect(*path.PathType).To(Equal(networking.PathTypePrefix))
+			Expect(path.Backend.Service).To(Equal(&networking.IngressServiceBackend{
+				Name: tests.ServiceName,
+				Port: networking.ServiceBackendPort{Number: 80},
+			}))
+		})
+	})
+
+	ginkgo.Context("Checking the legacy Ingress informer", func() {
+		ginkgo.It("caches extensions/v1beta1 Ingress resources as networking.k8s.io/v1 and updates their status", func() {
+			stopChannel := make(chan struct{})
+			defer close(stopChannel)
+
+			k8sClient := testclient.NewSimpleClientset()
+			_, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Create(context.TODO(), legacyIngress, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			ctxt := NewContext(k8sClient, fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), gatewayFake.NewSimpleClientset(), []string{ingressNS}, 1000*time.Second, metricstore.NewFakeMetricStore())
+			Expect(ctxt.Run(stopChannel, true, environment.GetFakeEnv())).ToNot(HaveOccurred())
+
+			ingresses := ctxt.ListHTTPIngresses()
+			Expect(len(ingresses)).To(Equal(1))
+			Expect(ingresses[0].Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name).To(Equal(tests.ServiceName))
+
+			Expect(ctxt.UpdateIngressStatus(*ingresses[0], IPAddress("address"))).ToNot(HaveOccurred())
+			updatedIngress, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Get(context.TODO(), legacyIngress.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedIngress.Status.LoadBalancer.Ingress).To(Equal([]v1.LoadBalancerIngress{{IP: "address"}}))
+		})
+	})
+})
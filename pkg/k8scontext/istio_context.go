@@ -5,7 +5,31 @@
 
 package k8scontext
 
-import "github.com/knative/pkg/apis/istio/v1alpha3"
+import (
+	"fmt"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// istioIngressGatewayCertsSecret is the secret Istio mounts at /etc/istio/ingressgateway-certs, where the serverCertificate of a Gateway server is usually found.
+const istioIngressGatewayCertsSecret = "istio-ingressgateway-certs"
+
+// IstioServerSecretName returns the name of the TLS secret of a Gateway server, which terminates TLS; empty when it has none.
+// The secret is named by the credentialName of the server; a server with a serverCertificate file uses the istio-ingressgateway-certs secret, which holds that file.
+func IstioServerSecretName(server v1alpha3.Server) string {
+	if server.TLS == nil || server.TLS.Mode != v1alpha3.TLSModeSimple {
+		return ""
+	}
+	if server.TLS.CredentialName != "" {
+		return server.TLS.CredentialName
+	}
+	if server.TLS.ServerCertificate != "" {
+		return istioIngressGatewayCertsSecret
+	}
+	return ""
+}
 
 // ListIstioGateways returns a list of discovered Istio Gateways
 func (c *Context) ListIstioGateways() []*v1alpha3.Gateway {
@@ -24,3 +48,45 @@ func (c *Context) ListIstioVirtualServices() []*v1alpha3.VirtualService {
 	}
 	return virtualServices
 }
+
+// ListIstioDestinationRules returns a list of discovered Istio Destination Rules
+func (c *Context) ListIstioDestinationRules() []*v1alpha3.DestinationRule {
+	var destinationRules []*v1alpha3.DestinationRule
+	for _, destinationRule := range c.Caches.IstioDestinationRule.List() {
+		destinationRules = append(destinationRules, destinationRule.(*v1alpha3.DestinationRule))
+	}
+	return destinationRules
+}
+
+// GetIstioDestinationRule returns the DestinationRule in the namespace for the host of a destination; nil when there is none.
+// The host of the DestinationRule is the short name of the service, or its name qualified with the namespace.
+func (c *Context) GetIstioDestinationRule(namespace string, host string) *v1alpha3.DestinationRule {
+	hosts := map[string]interface{}{
+		host:                                  nil,
+		fmt.Sprintf("%s.%s", host, namespace): nil,
+		fmt.Sprintf("%s.%s.svc", host, namespace):               nil,
+		fmt.Sprintf("%s.%s.svc.cluster.local", host, namespace): nil,
+	}
+	for _, destinationRule := range c.ListIstioDestinationRules() {
+		if destinationRule.Namespace != namespace {
+			continue
+		}
+		if _, exists := hosts[destinationRule.Spec.Host]; exists {
+			return destinationRule
+		}
+	}
+	return nil
+}
+
+// IsVirtualServiceReferencedByAnyGateway provides whether a VirtualService is bound to an Istio Gateway annotated for App Gateway.
+// The VirtualService references Gateways by name or by namespace/name.
+func (c *Context) IsVirtualServiceReferencedByAnyGateway(virtualService *v1alpha3.VirtualService) bool {
+	for _, gateway := range c.GetGateways() {
+		for _, gatewayRef := range virtualService.Spec.Gateways {
+			if gatewayRef == gateway.Name || gatewayRef == utils.GetResourceKey(gateway.Namespace, gateway.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// istio gateway resource handlers; only the Gateways annotated for App Gateway are followed
+func (h handlers) istioGatewayAdd(obj interface{}) {
+	if !isIstioGatewayApplicationGateway(obj) {
+		return
+	}
+	h.trackIstioGatewaySecrets(obj.(*v1alpha3.Gateway))
+	h.addFunc(obj)
+}
+
+func (h handlers) istioGatewayDelete(obj interface{}) {
+	gateway := obj
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		gateway = tombstone.Obj
+	}
+	if !isIstioGatewayApplicationGateway(gateway) {
+		return
+	}
+	h.context.ingressSecretsMap.Erase(istioGatewaySecretsKey(gateway.(*v1alpha3.Gateway)))
+	h.deleteFunc(obj)
+}
+
+func (h handlers) istioGatewayUpdate(oldObj, newObj interface{}) {
+	// a Gateway losing the annotation must still remove its config from App Gateway
+	if !isIstioGatewayApplicationGateway(newObj) && !isIstioGatewayApplicationGateway(oldObj) {
+		return
+	}
+	gateway := newObj.(*v1alpha3.Gateway)
+	h.context.ingressSecretsMap.Clear(istioGatewaySecretsKey(gateway))
+	if isIstioGatewayApplicationGateway(newObj) {
+		h.trackIstioGatewaySecrets(gateway)
+	}
+	h.updateFunc(oldObj, newObj)
+}
+
+// trackIstioGatewaySecrets converts the TLS secrets of the Gateway servers into certificates, and follows the changes of the secrets
+// the same way the secrets of the Ingresses are followed.
+func (h handlers) trackIstioGatewaySecrets(gateway *v1alpha3.Gateway) {
+	gatewayKey := istioGatewaySecretsKey(gateway)
+	for _, server := range gateway.Spec.Servers {
+		secretName := IstioServerSecretName(server)
+		if secretName == "" {
+			continue
+		}
+		secKey := utils.GetResourceKey(gateway.Namespace, secretName)
+		if h.context.ingressSecretsMap.ContainsPair(gatewayKey, secKey) {
+			continue
+		}
+		if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
+			if !h.context.ingressSecretsMap.ContainsValue(secKey) {
+				if err := h.context.CertificateSecretStore.ConvertSecret(secKey, secret.(*v1.Secret)); err != nil {
+					continue
+				}
+			}
+		}
+		h.context.ingressSecretsMap.Insert(gatewayKey, secKey)
+	}
+}
+
+func isIstioGatewayApplicationGateway(obj interface{}) bool {
+	gateway, ok := obj.(*v1alpha3.Gateway)
+	if !ok || gateway == nil {
+		return false
+	}
+	annotated, _ := annotations.IsIstioGatewayIngress(gateway)
+	return annotated
+}
+
+// istioGatewaySecretsKey keys the secrets of a Gateway apart from the secrets of an Ingress with the same name.
+func istioGatewaySecretsKey(gateway *v1alpha3.Gateway) string {
+	return "gateway:" + utils.GetResourceKey(gateway.Namespace, gateway.Name)
+}
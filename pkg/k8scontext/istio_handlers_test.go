@@ -0,0 +1,112 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"time"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gatewayFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
+	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+var _ = ginkgo.Describe("K8scontext Istio Cache Handlers", func() {
+	var h handlers
+	var annotated, unannotated *v1alpha3.Gateway
+
+	ginkgo.BeforeEach(func() {
+		h = handlers{
+			context: NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), gatewayFake.NewSimpleClientset(), []string{"ns"}, 1000*time.Second, metricstore.NewFakeMetricStore()),
+		}
+		annotated = &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "appgw",
+				Namespace:   "ns",
+				Annotations: map[string]string{annotations.IstioGatewayKey: annotations.ApplicationGatewayIngressClass},
+			},
+		}
+		unannotated = &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"},
+		}
+	})
+
+	ginkgo.Context("Test istio gateway handlers", func() {
+		ginkgo.It("follows the annotated gateways only", func() {
+			h.istioGatewayAdd(unannotated)
+			h.istioGatewayUpdate(unannotated, unannotated.DeepCopy())
+			h.istioGatewayDelete(unannotated)
+			Expect(h.context.Work).To(BeEmpty())
+
+			h.istioGatewayAdd(annotated)
+			Expect(<-h.context.Work).To(Equal(events.Event{Type: events.Create, Value: annotated}))
+			h.istioGatewayDelete(cache.DeletedFinalStateUnknown{Key: "ns/appgw", Obj: annotated})
+			Expect((<-h.context.Work).Type).To(Equal(events.Delete))
+		})
+
+		ginkgo.It("follows a gateway losing the annotation", func() {
+			updated := annotated.DeepCopy()
+			updated.Annotations = nil
+			h.istioGatewayUpdate(annotated, updated)
+			Expect(<-h.context.Work).To(Equal(events.Event{Type: events.Update, Value: updated, OldValue: annotated}))
+		})
+	})
+
+	ginkgo.Context("Test the TLS secrets of istio gateways", func() {
+		ginkgo.It("converts the credentialName secret and follows its changes", func() {
+			secret := tests.NewSecretTestFixture()
+			secret.Namespace = "ns"
+			secretKey := utils.GetResourceKey(secret.Namespace, secret.Name)
+			Expect(h.context.Caches.Secret.Add(secret)).To(Succeed())
+
+			annotated.Spec.Servers = []v1alpha3.Server{{
+				Port:  v1alpha3.Port{Number: 443, Protocol: v1alpha3.ProtocolHTTPS},
+				Hosts: []string{"www.contoso.com"},
+				TLS:   &v1alpha3.TLSOptions{Mode: v1alpha3.TLSModeSimple, CredentialName: secret.Name},
+			}}
+			h.istioGatewayAdd(annotated)
+			<-h.context.Work
+
+			Expect(h.context.CertificateSecretStore.GetPfxCertificate(secretKey)).ToNot(BeNil())
+			Expect(h.context.ingressSecretsMap.ContainsValue(secretKey)).To(BeTrue())
+
+			h.istioGatewayDelete(annotated)
+			<-h.context.Work
+			Expect(h.context.ingressSecretsMap.ContainsValue(secretKey)).To(BeFalse())
+		})
+	})
+
+	ginkgo.Context("Test virtual services bound to the annotated gateways", func() {
+		ginkgo.It("finds the gateway by name or namespace/name", func() {
+			Expect(h.context.Caches.IstioGateway.Add(annotated)).To(Succeed())
+			Expect(h.context.Caches.IstioGateway.Add(unannotated)).To(Succeed())
+
+			virtualService := &v1alpha3.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{Name: "vs", Namespace: "ns"},
+			}
+			for gatewayRef, expected := range map[string]bool{
+				"appgw":    true,
+				"ns/appgw": true,
+				"other":    false,
+				"mesh":     false,
+			} {
+				virtualService.Spec.Gateways = []string{gatewayRef}
+				Expect(h.context.IsVirtualServiceReferencedByAnyGateway(virtualService)).To(Equal(expected), gatewayRef)
+			}
+		})
+	})
+})
@@ -6,6 +6,7 @@
 package k8scontext
 
 import (
+	"context"
 	"flag"
 	"reflect"
 	"time"
@@ -14,15 +15,17 @@ import (
 	"github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	gatewayFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned/fake"
 	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
@@ -80,18 +83,20 @@ var _ = ginkgo.Describe("K8scontext", func() {
 
 		// Create the mock K8s client.
 		k8sClient = testclient.NewSimpleClientset()
+		tests.AddNetworkingV1Resources(k8sClient)
 		crdClient := fake.NewSimpleClientset()
 		istioCrdClient := istioFake.NewSimpleClientset()
+		gatewayCrdClient := gatewayFake.NewSimpleClientset()
 
-		_, err := k8sClient.CoreV1().Namespaces().Create(ns)
+		_, err := k8sClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{})
 		Expect(err).ToNot(HaveOccurred(), "Unable to create the namespace %s: %v", ingressNS, err)
 
 		// create ingress in namespace
-		_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Create(ingress)
+		_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Create(context.TODO(), ingress, metav1.CreateOptions{})
 		Expect(err).ToNot(HaveOccurred(), "Unabled to create ingress resource due to: %v", err)
 
 		// Create a `k8scontext` to start listening to ingress resources.
-		ctxt = NewContext(k8sClient, crdClient, istioCrdClient, []string{ingressNS}, 1000*time.Second)
+		ctxt = NewContext(k8sClient, crdClient, istioCrdClient, gatewayCrdClient, []string{ingressNS}, 1000*time.Second, metricstore.NewFakeMetricStore())
 
 		Expect(ctxt).ShouldNot(BeNil(), "Unable to create `k8scontext`")
 	})
@@ -103,7 +108,7 @@ var _ = ginkgo.Describe("K8scontext", func() {
 	ginkgo.Context("Checking if we are able to listen to Ingress Resources", func() {
 		ginkgo.It("Should be able to retrieve all Ingress Resources", func() {
 			// Retrieve the Ingress to make sure it was created.
-			ingresses, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).List(metav1.ListOptions{})
+			ingresses, err := k8sClient.NetworkingV1().Ingresses(ingressNS).List(context.TODO(), metav1.ListOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unabled to retrieve stored ingresses resource due to: %v", err)
 			Expect(len(ingresses.Items)).To(Equal(1), "Expected to have a single ingress stored in mock K8s but found: %d ingresses", len(ingresses.Items))
 
@@ -126,11 +131,11 @@ var _ = ginkgo.Describe("K8scontext", func() {
 		ginkgo.It("Should be able to follow modifications to the Ingress Resource.", func() {
 			ingress.Spec.Rules[0].Host = "hellow-1.com"
 
-			_, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Update(ingress)
+			_, err := k8sClient.NetworkingV1().Ingresses(ingressNS).Update(context.TODO(), ingress, metav1.UpdateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unabled to update ingress resource due to: %v", err)
 
 			// Retrieve the Ingress to make sure it was updated.
-			ingresses, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).List(metav1.ListOptions{})
+			ingresses, err := k8sClient.NetworkingV1().Ingresses(ingressNS).List(context.TODO(), metav1.ListOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to retrieve stored ingresses resource due to: %v", err)
 			Expect(len(ingresses.Items)).To(Equal(1), "Expected to have a single ingress stored in mock K8s but found: %d ingresses", len(ingresses.Items))
 
@@ -152,11 +157,11 @@ var _ = ginkgo.Describe("K8scontext", func() {
 		})
 
 		ginkgo.It("Should be able to follow deletion of the Ingress Resource.", func() {
-			err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Delete(ingressName, nil)
+			err := k8sClient.NetworkingV1().Ingresses(ingressNS).Delete(context.TODO(), ingressName, metav1.DeleteOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to delete ingress resource due to: %v", err)
 
 			// Retrieve the Ingress to make sure it was updated.
-			ingresses, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).List(metav1.ListOptions{})
+			ingresses, err := k8sClient.NetworkingV1().Ingresses(ingressNS).List(context.TODO(), metav1.ListOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to retrieve stored ingresses resource due to: %v", err)
 			Expect(len(ingresses.Items)).To(Equal(0), "Expected to have no ingresses stored in mock K8s but found: %d ingresses", len(ingresses.Items))
 
@@ -175,7 +180,7 @@ var _ = ginkgo.Describe("K8scontext", func() {
 		})
 
 		ginkgo.It("Should be following Ingress Resource with Application Gateway specific annotations only.", func() {
-			nonAppGWIngress := &v1beta1.Ingress{}
+			nonAppGWIngress := &networking.Ingress{}
 			err := deepcopy.Copy(nonAppGWIngress, ingress)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -183,11 +188,11 @@ var _ = ginkgo.Describe("K8scontext", func() {
 			// Change the `Annotation` so that the controller doesn't see this Ingress.
 			nonAppGWIngress.Annotations[annotations.IngressClassKey] = annotations.ApplicationGatewayIngressClass + "123"
 
-			_, err = k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).Create(nonAppGWIngress)
+			_, err = k8sClient.NetworkingV1().Ingresses(ingressNS).Create(context.TODO(), nonAppGWIngress, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create non-Application Gateway ingress resource due to: %v", err)
 
 			// Retrieve the Ingress to make sure it was updated.
-			ingresses, err := k8sClient.ExtensionsV1beta1().Ingresses(ingressNS).List(metav1.ListOptions{})
+			ingresses, err := k8sClient.NetworkingV1().Ingresses(ingressNS).List(context.TODO(), metav1.ListOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to retrieve stored ingresses resource due to: %v", err)
 			Expect(len(ingresses.Items)).To(Equal(2), "Expected to have 2 ingresses stored in mock K8s but found: %d ingresses", len(ingresses.Items))
 
@@ -209,7 +214,7 @@ var _ = ginkgo.Describe("K8scontext", func() {
 		})
 
 		ginkgo.It("Should be able to follow add of the Pod Resource.", func() {
-			_, err := k8sClient.CoreV1().Pods(ingressNS).Create(pod)
+			_, err := k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create pod resource due to: %v", err)
 
 			podObj1 := tests.NewPodTestFixture(ingressNS, "pod2")
@@ -218,11 +223,11 @@ var _ = ginkgo.Describe("K8scontext", func() {
 				"app":   "pod2",
 				"extra": "random",
 			}
-			_, err = k8sClient.CoreV1().Pods(ingressNS).Create(pod1)
+			_, err = k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod1, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create pod resource due to: %v", err)
 
 			// Retrieve the Pods to make sure it was updated.
-			podList, err := k8sClient.CoreV1().Pods(ingressNS).List(metav1.ListOptions{})
+			podList, err := k8sClient.CoreV1().Pods(ingressNS).List(context.TODO(), metav1.ListOptions{})
 			Expect(podList).ToNot(BeNil())
 			Expect(len(podList.Items)).To(Equal(2), "Expected to have two pod stored but found: %d pods", len(podList.Items))
 
@@ -271,14 +276,14 @@ var _ = ginkgo.Describe("K8scontext", func() {
 			Expect(runErr).ToNot(HaveOccurred())
 
 			// create a POD with labels
-			_, err := k8sClient.CoreV1().Pods(ingressNS).Create(pod)
+			_, err := k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create pod resource due to: %v", err)
 
 			// create a service with label
 			servicePort := tests.NewServicePortsFixture()
 			service := tests.NewServiceFixture(*servicePort...)
 			service.Namespace = ingressNS
-			_, err = k8sClient.CoreV1().Services(ingressNS).Create(service)
+			_, err = k8sClient.CoreV1().Services(ingressNS).Create(context.TODO(), service, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create service resource due to: %v", err)
 
 			// wait for sync
@@ -300,14 +305,14 @@ var _ = ginkgo.Describe("K8scontext", func() {
 			pod.Labels = map[string]string{
 				"random": "random",
 			}
-			_, err := k8sClient.CoreV1().Pods(ingressNS).Create(pod)
+			_, err := k8sClient.CoreV1().Pods(ingressNS).Create(context.TODO(), pod, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create pod resource due to: %v", err)
 
 			// create a service with label
 			servicePort := tests.NewServicePortsFixture()
 			service := tests.NewServiceFixture(*servicePort...)
 			service.Namespace = ingressNS
-			_, err = k8sClient.CoreV1().Services(ingressNS).Create(service)
+			_, err = k8sClient.CoreV1().Services(ingressNS).Create(context.TODO(), service, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create service resource due to: %v", err)
 
 			// wait for sync
@@ -331,14 +336,14 @@ var _ = ginkgo.Describe("K8scontext", func() {
 			endpoints.Namespace = ingressNS
 
 			// create a POD with labels
-			_, err := k8sClient.CoreV1().Endpoints(ingressNS).Create(endpoints)
+			_, err := k8sClient.CoreV1().Endpoints(ingressNS).Create(context.TODO(), endpoints, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create endpoints resource due to: %v", err)
 
 			// create a service with label
 			servicePort := tests.NewServicePortsFixture()
 			service := tests.NewServiceFixture(*servicePort...)
 			service.Namespace = ingressNS
-			_, err = k8sClient.CoreV1().Services(ingressNS).Create(service)
+			_, err = k8sClient.CoreV1().Services(ingressNS).Create(context.TODO(), service, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create service resource due to: %v", err)
 
 			// wait for sync
@@ -361,14 +366,14 @@ var _ = ginkgo.Describe("K8scontext", func() {
 			endpoints.Namespace = ingressNS
 
 			// create a POD with labels
-			_, err := k8sClient.CoreV1().Endpoints(ingressNS).Create(endpoints)
+			_, err := k8sClient.CoreV1().Endpoints(ingressNS).Create(context.TODO(), endpoints, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create endpoints resource due to: %v", err)
 
 			// create a service with label
 			servicePort := tests.NewServicePortsFixture()
 			service := tests.NewServiceFixture(*servicePort...)
 			service.Namespace = ingressNS
-			_, err = k8sClient.CoreV1().Services(ingressNS).Create(service)
+			_, err = k8sClient.CoreV1().Services(ingressNS).Create(context.TODO(), service, metav1.CreateOptions{})
 			Expect(err).ToNot(HaveOccurred(), "Unable to create service resource due to: %v", err)
 
 			// wait for sync
@@ -382,13 +387,58 @@ var _ = ginkgo.Describe("K8scontext", func() {
 		})
 	})
 
+	ginkgo.Context("Checking if we are able to skip unrelated config map events", func() {
+		ginkgo.It("should be able to select the config maps with backend CA certificates", func() {
+			caIngress := tests.NewIngressTestFixture(ingressNS, "backend-ca")
+			caIngress.Annotations[annotations.BackendCACertificateKey] = "configmap/backend-ca"
+			_, err := k8sClient.NetworkingV1().Ingresses(ingressNS).Create(context.TODO(), &caIngress, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred(), "Unable to create ingress resource due to: %v", err)
+
+			related := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend-ca", Namespace: ingressNS},
+				Data:       map[string]string{"ca.crt": "--ca--"},
+			}
+			unrelated := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "random", Namespace: ingressNS},
+			}
+			for _, configMap := range []*v1.ConfigMap{related, unrelated} {
+				_, err = k8sClient.CoreV1().ConfigMaps(ingressNS).Create(context.TODO(), configMap, metav1.CreateOptions{})
+				Expect(err).ToNot(HaveOccurred(), "Unable to create config map resource due to: %v", err)
+			}
+
+			// start context for syncing
+			env := environment.GetFakeEnv()
+			env.EnableBackendCAConfigMaps = true
+			runErr := ctxt.Run(stopChannel, true, env)
+			Expect(runErr).ToNot(HaveOccurred())
+
+			Expect(ctxt.GetConfigMap(ingressNS + "/backend-ca")).To(Equal(related))
+			Expect(ctxt.GetConfigMap(ingressNS + "/missing")).To(BeNil())
+			Expect(ctxt.IsConfigMapReferencedByAnyIngress(related)).To(BeTrue(), "Expected the config map to be referenced by the ingress.")
+			Expect(ctxt.IsConfigMapReferencedByAnyIngress(unrelated)).To(BeFalse(), "Expected the config map not to be referenced by any ingress.")
+		})
+
+		ginkgo.It("should not watch the config maps unless the backend CA config maps are enabled", func() {
+			configMap := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend-ca", Namespace: ingressNS},
+			}
+			_, err := k8sClient.CoreV1().ConfigMaps(ingressNS).Create(context.TODO(), configMap, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred(), "Unable to create config map resource due to: %v", err)
+
+			runErr := ctxt.Run(stopChannel, true, environment.GetFakeEnv())
+			Expect(runErr).ToNot(HaveOccurred())
+
+			Expect(ctxt.Caches.ConfigMap.List()).To(BeEmpty())
+		})
+	})
+
 	ginkgo.Context("Checking AddIngressStatus and RemoveIngressStatus", func() {
 		ip := IPAddress("address")
 		ginkgo.It("adds IP when not present and then removes", func() {
 			// add test
 			err := ctxt.UpdateIngressStatus(*ingress, ip)
 			Expect(err).ToNot(HaveOccurred())
-			updatedIngress, _ := k8sClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Get(ingress.Name, metav1.GetOptions{})
+			updatedIngress, _ := k8sClient.NetworkingV1().Ingresses(ingress.Namespace).Get(context.TODO(), ingress.Name, metav1.GetOptions{})
 			Expect(updatedIngress.Status.LoadBalancer.Ingress).Should(ContainElement(v1.LoadBalancerIngress{
 				Hostname: "",
 				IP:       string(ip),
@@ -403,7 +453,7 @@ var _ = ginkgo.Describe("K8scontext", func() {
 			// add again
 			err = ctxt.UpdateIngressStatus(*ingress, ip)
 			Expect(err).ToNot(HaveOccurred())
-			updatedIngress, _ := k8sClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Get(ingress.Name, metav1.GetOptions{})
+			updatedIngress, _ := k8sClient.NetworkingV1().Ingresses(ingress.Namespace).Get(context.TODO(), ingress.Name, metav1.GetOptions{})
 			Expect(updatedIngress.Status.LoadBalancer.Ingress).Should(ContainElement(v1.LoadBalancerIngress{
 				Hostname: "",
 				IP:       string(ip),
@@ -411,4 +461,46 @@ var _ = ginkgo.Describe("K8scontext", func() {
 			Expect(len(updatedIngress.Status.LoadBalancer.Ingress)).To(Equal(1))
 		})
 	})
+
+	ginkgo.Context("Checking IsIngressApplicationGateway with IngressClass resources", func() {
+		agicClass := &networking.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "azure-application-gateway"},
+			Spec:       networking.IngressClassSpec{Controller: annotations.ApplicationGatewayIngressClass},
+		}
+		otherClass := &networking.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+			Spec:       networking.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+		}
+
+		newIngress := func(className *string) *networking.Ingress {
+			ing := tests.NewIngressTestFixture(ingressNS, ingressName)
+			delete(ing.Annotations, annotations.IngressClassKey)
+			ing.Spec.IngressClassName = className
+			return &ing
+		}
+
+		ginkgo.It("follows the ingress class annotation", func() {
+			Expect(ctxt.IsIngressApplicationGateway(ingress)).To(BeTrue())
+		})
+
+		ginkgo.It("follows spec.ingressClassName referencing an AGIC IngressClass", func() {
+			_ = ctxt.Caches.IngressClass.Add(agicClass)
+			_ = ctxt.Caches.IngressClass.Add(otherClass)
+			Expect(ctxt.IsIngressApplicationGateway(newIngress(&agicClass.Name))).To(BeTrue())
+			Expect(ctxt.IsIngressApplicationGateway(newIngress(&otherClass.Name))).To(BeFalse())
+		})
+
+		ginkgo.It("ignores spec.ingressClassName referencing a missing IngressClass", func() {
+			Expect(ctxt.IsIngressApplicationGateway(newIngress(&agicClass.Name))).To(BeFalse())
+		})
+
+		ginkgo.It("follows an Ingress without class when AGIC is the default IngressClass", func() {
+			Expect(ctxt.IsIngressApplicationGateway(newIngress(nil))).To(BeFalse())
+
+			defaultClass := agicClass.DeepCopy()
+			defaultClass.Annotations = map[string]string{annotations.IsDefaultIngressClassKey: "true"}
+			_ = ctxt.Caches.IngressClass.Add(defaultClass)
+			Expect(ctxt.IsIngressApplicationGateway(newIngress(nil))).To(BeTrue())
+		})
+	})
 })
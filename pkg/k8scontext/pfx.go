@@ -0,0 +1,146 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	"github.com/golang/glog"
+	pkcs12 "software.sslmate.com/src/go-pkcs12"
+)
+
+// pfxPasswordLength is the number of random bytes in the password protecting a PFX.
+const pfxPasswordLength = 24
+
+// PfxCertificate is a TLS secret in the PKCS#12 format, along with the password protecting it.
+type PfxCertificate struct {
+	Data     []byte
+	Password string
+}
+
+// newPfxCertificate bundles the PEM encoded private key with the certificate chain into a password protected PKCS#12.
+// The certificate matching the private key becomes the leaf; the other certificates are kept in the chain in order.
+func newPfxCertificate(secretKey string, certPEM []byte, keyPEM []byte) (*PfxCertificate, error) {
+	chain, err := parseCertificates(certPEM)
+	if err != nil {
+		glog.Errorf("secret [%v] has an invalid tls.crt: %v", secretKey, err)
+		return nil, ErrorDecodingCertificate
+	}
+	if len(chain) == 0 {
+		glog.Errorf("secret [%v] has no CERTIFICATE block in tls.crt", secretKey)
+		return nil, ErrorDecodingCertificate
+	}
+
+	privateKey, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		glog.Errorf("secret [%v] has an invalid tls.key: %v", secretKey, err)
+		return nil, err
+	}
+
+	leafIdx := -1
+	for idx, cert := range chain {
+		if publicKeyMatches(cert.PublicKey, privateKey) {
+			leafIdx = idx
+			break
+		}
+	}
+	if leafIdx < 0 {
+		glog.Errorf("secret [%v] has a tls.key, which does not match any of the %d certificates in tls.crt (subject of the first one: %s)", secretKey, len(chain), chain[0].Subject)
+		return nil, ErrorCertificateKeyMismatch
+	}
+
+	var caCerts []*x509.Certificate
+	caCerts = append(caCerts, chain[:leafIdx]...)
+	caCerts = append(caCerts, chain[leafIdx+1:]...)
+
+	password, err := newPfxPassword()
+	if err != nil {
+		glog.Errorf("unable to generate a password for secret [%v]: %v", secretKey, err)
+		return nil, ErrorExportingPfx
+	}
+
+	pfx, err := pkcs12.Encode(rand.Reader, privateKey, chain[leafIdx], caCerts, password)
+	if err != nil {
+		glog.Errorf("unable to export secret [%v] to PKCS#12: %v", secretKey, err)
+		return nil, ErrorExportingPfx
+	}
+
+	return &PfxCertificate{
+		Data:     pfx,
+		Password: password,
+	}, nil
+}
+
+// parseCertificates returns every CERTIFICATE block in the PEM data; other blocks are ignored.
+func parseCertificates(certPEM []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+	return chain, nil
+}
+
+// parsePrivateKey returns the first RSA or ECDSA private key in the PEM data, in either PKCS#1, SEC 1 or PKCS#8 form.
+func parsePrivateKey(keyPEM []byte) (interface{}, error) {
+	for block, rest := pem.Decode(keyPEM); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+				return key, nil
+			}
+			return nil, ErrorDecodingPrivateKey
+		case "EC PRIVATE KEY":
+			if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+				return key, nil
+			}
+			return nil, ErrorDecodingPrivateKey
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, ErrorDecodingPrivateKey
+			}
+			switch key.(type) {
+			case *rsa.PrivateKey, *ecdsa.PrivateKey:
+				return key, nil
+			}
+			return nil, ErrorUnsupportedPrivateKey
+		}
+	}
+	return nil, ErrorDecodingPrivateKey
+}
+
+// publicKeyMatches tells whether the public key of a certificate belongs to the private key.
+func publicKeyMatches(publicKey interface{}, privateKey interface{}) bool {
+	switch private := privateKey.(type) {
+	case *rsa.PrivateKey:
+		public, ok := publicKey.(*rsa.PublicKey)
+		return ok && public.E == private.E && public.N.Cmp(private.N) == 0
+	case *ecdsa.PrivateKey:
+		public, ok := publicKey.(*ecdsa.PublicKey)
+		return ok && public.Curve == private.Curve && public.X.Cmp(private.X) == 0 && public.Y.Cmp(private.Y) == 0
+	}
+	return false
+}
+
+func newPfxPassword() (string, error) {
+	randomBytes := make([]byte, pfxPasswordLength)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
@@ -11,6 +11,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
@@ -27,6 +28,11 @@ func (h handlers) secretAdd(obj interface{}) {
 				Value: obj,
 			}
 		}
+	} else if h.context.isBackendCACertificateReferencedByAnyIngress(annotations.SecretKind, sec.Namespace, sec.Name) {
+		h.context.Work <- events.Event{
+			Type:  events.Create,
+			Value: obj,
+		}
 	}
 }
 
@@ -44,6 +50,11 @@ func (h handlers) secretUpdate(oldObj, newObj interface{}) {
 				Value: newObj,
 			}
 		}
+	} else if h.context.isBackendCACertificateReferencedByAnyIngress(annotations.SecretKind, sec.Namespace, sec.Name) {
+		h.context.Work <- events.Event{
+			Type:  events.Update,
+			Value: newObj,
+		}
 	}
 }
 
@@ -63,7 +74,7 @@ func (h handlers) secretDelete(obj interface{}) {
 
 	secKey := utils.GetResourceKey(sec.Namespace, sec.Name)
 	h.context.CertificateSecretStore.delete(secKey)
-	if h.context.ingressSecretsMap.ContainsValue(secKey) {
+	if h.context.ingressSecretsMap.ContainsValue(secKey) || h.context.isBackendCACertificateReferencedByAnyIngress(annotations.SecretKind, sec.Namespace, sec.Name) {
 		h.context.Work <- events.Event{
 			Type:  events.Delete,
 			Value: obj,
@@ -6,15 +6,13 @@
 package k8scontext
 
 import (
-	"bytes"
-	"io/ioutil"
-	"os"
-	"os/exec"
 	"sync"
 
 	"github.com/golang/glog"
 	"k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 )
 
 const (
@@ -25,7 +23,7 @@ const (
 
 // SecretsKeeper is the interface definition for secret store
 type SecretsKeeper interface {
-	GetPfxCertificate(secretKey string) []byte
+	GetPfxCertificate(secretKey string) *PfxCertificate
 	ConvertSecret(secretKey string, secret *v1.Secret) error
 	delete(secretKey string)
 }
@@ -34,19 +32,21 @@ type SecretsKeeper interface {
 type SecretsStore struct {
 	conversionSync sync.Mutex
 	Cache          cache.ThreadSafeStore
+	metricStore    metricstore.MetricStore
 }
 
 // NewSecretStore creates a new SecretsKeeper object
-func NewSecretStore() SecretsKeeper {
+func NewSecretStore(metricStore metricstore.MetricStore) SecretsKeeper {
 	return &SecretsStore{
-		Cache: cache.NewThreadSafeStore(cache.Indexers{}, cache.Indices{}),
+		Cache:       cache.NewThreadSafeStore(cache.Indexers{}, cache.Indices{}),
+		metricStore: metricStore,
 	}
 }
 
 // GetPfxCertificate returns the certificate for the given secret key.
-func (s *SecretsStore) GetPfxCertificate(secretKey string) []byte {
+func (s *SecretsStore) GetPfxCertificate(secretKey string) *PfxCertificate {
 	if certInterface, exists := s.Cache.Get(secretKey); exists {
-		if cert, ok := certInterface.([]byte); ok {
+		if cert, ok := certInterface.(*PfxCertificate); ok {
 			return cert
 		}
 	}
@@ -60,11 +60,19 @@ func (s *SecretsStore) delete(secretKey string) {
 	s.Cache.Delete(secretKey)
 }
 
-// ConvertSecret converts a secret to a PKCS12.
+// ConvertSecret converts a secret to a PKCS12, protected by a random password.
 func (s *SecretsStore) ConvertSecret(secretKey string, secret *v1.Secret) error {
 	s.conversionSync.Lock()
 	defer s.conversionSync.Unlock()
 
+	err := s.convertSecret(secretKey, secret)
+	if err != nil {
+		s.metricStore.IncCertificateConversionFailureCounter()
+	}
+	return err
+}
+
+func (s *SecretsStore) convertSecret(secretKey string, secret *v1.Secret) error {
 	// check if this is a secret with the correct type
 	if secret.Type != recognizedSecretType {
 		glog.Errorf("secret [%v] is not type kubernetes.io/tls", secretKey)
@@ -76,44 +84,11 @@ func (s *SecretsStore) ConvertSecret(secretKey string, secret *v1.Secret) error
 		return ErrorMalformedSecret
 	}
 
-	tempfileCert, err := ioutil.TempFile("", "appgw-ingress-cert")
-	if err != nil {
-		glog.Error("unable to create temporary file for certificate conversion")
-		return ErrorCreatingFile
-	}
-	defer os.Remove(tempfileCert.Name())
-
-	tempfileKey, err := ioutil.TempFile("", "appgw-ingress-key")
+	pfxCert, err := newPfxCertificate(secretKey, secret.Data[tlsCrt], secret.Data[tlsKey])
 	if err != nil {
-		glog.Error("unable to create temporary file for certificate conversion")
-		return ErrorCreatingFile
-	}
-	defer os.Remove(tempfileKey.Name())
-
-	if err := writeFileDecode(secret.Data["tls.crt"], tempfileCert); err != nil {
-		glog.Errorf("unable to write secret [%v].tls.crt to temporary file, error: %v", secretKey, err)
-		return ErrorWritingToFile
-	}
-
-	if err := writeFileDecode(secret.Data["tls.key"], tempfileKey); err != nil {
-		glog.Errorf("unable to write secret [%v].tls.key to temporary file, error: %v", secretKey, err)
-		return ErrorWritingToFile
-	}
-
-	// both cert and key are in temp file now, call openssl
-	var cout, cerr bytes.Buffer
-	cmd := exec.Command("openssl", "pkcs12", "-export", "-in", tempfileCert.Name(), "-inkey", tempfileKey.Name(), "-password", "pass:msazure")
-	cmd.Stderr = &cerr
-	cmd.Stdout = &cout
-
-	// if openssl exited with an error or the output is empty, report error
-	if err := cmd.Run(); err != nil || len(cout.Bytes()) == 0 {
-		glog.Errorf("unable to export using openssl, error=[%v], stderr=[%v]", err, cerr.String())
-		return ErrorExportingWithOpenSSL
+		return err
 	}
 
-	pfxCert := cout.Bytes()
-
 	glog.V(5).Infof("Converted secret [%v]", secretKey)
 	// TODO i'm not sure if comparison against existing certificate can help
 	// us optimize by eliminating some events
@@ -126,13 +101,3 @@ func (s *SecretsStore) ConvertSecret(secretKey string, secret *v1.Secret) error
 
 	return nil
 }
-
-func writeFileDecode(data []byte, fileHandle *os.File) error {
-	if _, err := fileHandle.Write(data); err != nil {
-		return err
-	}
-	if err := fileHandle.Close(); err != nil {
-		return err
-	}
-	return nil
-}
@@ -6,15 +6,63 @@
 package k8scontext
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
 	"github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	pkcs12 "software.sslmate.com/src/go-pkcs12"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metricstore"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 )
 
+// newCertificateFixture returns a certificate for the key, signed by the parent; a self-signed one when parent is nil.
+func newCertificateFixture(commonName string, key crypto.Signer, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).ToNot(HaveOccurred())
+	return cert
+}
+
+func newTLSSecretFixture(keyBlock *pem.Block, chain ...*x509.Certificate) *v1.Secret {
+	var certPEM []byte
+	for _, cert := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return &v1.Secret{
+		Type: recognizedSecretType,
+		Data: map[string][]byte{
+			tlsCrt: certPEM,
+			tlsKey: pem.EncodeToMemory(keyBlock),
+		},
+	}
+}
+
 var _ = ginkgo.Describe("Testing K8sContext.SecretStore", func() {
-	secretsStore := NewSecretStore()
+	secretsStore := NewSecretStore(metricstore.NewFakeMetricStore())
 	ginkgo.Context("Test ConvertSecret function", func() {
 		secret := v1.Secret{}
 		ginkgo.It("Should have returned an error - unrecognized type of secret", func() {
@@ -34,13 +82,113 @@ var _ = ginkgo.Describe("Testing K8sContext.SecretStore", func() {
 			malformed.Data[tlsKey] = []byte("X")
 			malformed.Data[tlsCrt] = []byte("Y")
 			err := secretsStore.ConvertSecret("someKey", &malformed)
-			Expect(err).To(Equal(ErrorExportingWithOpenSSL))
+			Expect(err).To(Equal(ErrorDecodingCertificate))
 		})
 		ginkgo.It("", func() {
-			err := secretsStore.ConvertSecret("someKey", tests.NewSecretTestFixture())
+			fixture := tests.NewSecretTestFixture()
+			err := secretsStore.ConvertSecret("someKey", fixture)
 			Expect(err).ToNot(HaveOccurred())
 			actual := secretsStore.GetPfxCertificate("someKey")
-			Expect(len(actual)).To(Equal(2477))
+			Expect(actual).ToNot(BeNil())
+
+			key, cert, caCerts, err := pkcs12.DecodeChain(actual.Data, actual.Password)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(caCerts).To(BeEmpty())
+			Expect(key).To(BeAssignableToTypeOf(&rsa.PrivateKey{}))
+
+			block, _ := pem.Decode(fixture.Data[tlsCrt])
+			Expect(cert.Raw).To(Equal(block.Bytes))
+		})
+	})
+
+	ginkgo.Context("Test converting keys and chains", func() {
+		rsaRootKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		rsaLeafKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+		ginkgo.It("should convert an RSA key with the intermediate chain", func() {
+			root := newCertificateFixture("root", rsaRootKey, nil, nil)
+			leaf := newCertificateFixture("www.contoso.com", rsaLeafKey, root, rsaRootKey)
+			keyBlock := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaLeafKey)}
+
+			Expect(secretsStore.ConvertSecret("rsa", newTLSSecretFixture(keyBlock, leaf, root))).To(Succeed())
+			actual := secretsStore.GetPfxCertificate("rsa")
+
+			key, cert, caCerts, err := pkcs12.DecodeChain(actual.Data, actual.Password)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.(*rsa.PrivateKey).N).To(Equal(rsaLeafKey.N))
+			Expect(cert.Raw).To(Equal(leaf.Raw))
+			Expect(caCerts).To(HaveLen(1))
+			Expect(caCerts[0].Raw).To(Equal(root.Raw))
+		})
+
+		ginkgo.It("should convert an ECDSA key in SEC 1 and PKCS#8 form", func() {
+			cert := newCertificateFixture("www.contoso.com", ecdsaKey, nil, nil)
+			sec1, err := x509.MarshalECPrivateKey(ecdsaKey)
+			Expect(err).ToNot(HaveOccurred())
+			pkcs8, err := x509.MarshalPKCS8PrivateKey(ecdsaKey)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, keyBlock := range []*pem.Block{{Type: "EC PRIVATE KEY", Bytes: sec1}, {Type: "PRIVATE KEY", Bytes: pkcs8}} {
+				Expect(secretsStore.ConvertSecret("ecdsa", newTLSSecretFixture(keyBlock, cert))).To(Succeed())
+				actual := secretsStore.GetPfxCertificate("ecdsa")
+
+				key, actualCert, err := pkcs12.Decode(actual.Data, actual.Password)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(key.(*ecdsa.PrivateKey).D).To(Equal(ecdsaKey.D))
+				Expect(actualCert.Raw).To(Equal(cert.Raw))
+			}
+		})
+
+		ginkgo.It("should use the certificate matching the key as the leaf, wherever it is in the chain", func() {
+			root := newCertificateFixture("root", rsaRootKey, nil, nil)
+			leaf := newCertificateFixture("www.contoso.com", rsaLeafKey, root, rsaRootKey)
+			keyBlock := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaLeafKey)}
+
+			Expect(secretsStore.ConvertSecret("reversed", newTLSSecretFixture(keyBlock, root, leaf))).To(Succeed())
+			actual := secretsStore.GetPfxCertificate("reversed")
+
+			_, cert, caCerts, err := pkcs12.DecodeChain(actual.Data, actual.Password)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cert.Raw).To(Equal(leaf.Raw))
+			Expect(caCerts[0].Raw).To(Equal(root.Raw))
+		})
+
+		ginkgo.It("should fail when the key does not match the certificate", func() {
+			rsaCert := newCertificateFixture("www.contoso.com", rsaLeafKey, nil, nil)
+			ecdsaCert := newCertificateFixture("www.contoso.com", ecdsaKey, nil, nil)
+			rsaKeyBlock := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaRootKey)}
+
+			Expect(secretsStore.ConvertSecret("mismatch", newTLSSecretFixture(rsaKeyBlock, rsaCert))).To(Equal(ErrorCertificateKeyMismatch))
+			Expect(secretsStore.ConvertSecret("mismatch", newTLSSecretFixture(rsaKeyBlock, ecdsaCert))).To(Equal(ErrorCertificateKeyMismatch))
+			Expect(secretsStore.GetPfxCertificate("mismatch")).To(BeNil())
+		})
+
+		ginkgo.It("should fail on an invalid key", func() {
+			cert := newCertificateFixture("www.contoso.com", rsaLeafKey, nil, nil)
+			invalidKeyBlock := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("not a key")}
+			Expect(secretsStore.ConvertSecret("invalid", newTLSSecretFixture(invalidKeyBlock, cert))).To(Equal(ErrorDecodingPrivateKey))
+
+			certOnly := newTLSSecretFixture(invalidKeyBlock, cert)
+			certOnly.Data[tlsKey] = certOnly.Data[tlsCrt]
+			Expect(secretsStore.ConvertSecret("invalid", certOnly)).To(Equal(ErrorDecodingPrivateKey))
+		})
+
+		ginkgo.It("should protect every certificate with a different random password", func() {
+			cert := newCertificateFixture("www.contoso.com", rsaLeafKey, nil, nil)
+			keyBlock := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaLeafKey)}
+
+			Expect(secretsStore.ConvertSecret("first", newTLSSecretFixture(keyBlock, cert))).To(Succeed())
+			Expect(secretsStore.ConvertSecret("second", newTLSSecretFixture(keyBlock, cert))).To(Succeed())
+
+			first := secretsStore.GetPfxCertificate("first")
+			second := secretsStore.GetPfxCertificate("second")
+			Expect(first.Password).To(HaveLen(32))
+			Expect(first.Password).ToNot(Equal("msazure"))
+			Expect(first.Password).ToNot(Equal(second.Password))
+
+			_, _, err := pkcs12.Decode(first.Data, second.Password)
+			Expect(err).To(HaveOccurred())
 		})
 	})
 })
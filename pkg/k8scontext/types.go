@@ -5,6 +5,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
+	gateway_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/gateway_crd_client/clientset/versioned"
 	istio_versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
@@ -12,38 +13,56 @@ import (
 
 // InformerCollection : all the informers for k8s resources we care about.
 type InformerCollection struct {
+	ConfigMap                    cache.SharedIndexInformer
 	Endpoints                    cache.SharedIndexInformer
 	Ingress                      cache.SharedIndexInformer
+	IngressClass                 cache.SharedIndexInformer
 	Pods                         cache.SharedIndexInformer
 	Secret                       cache.SharedIndexInformer
 	Service                      cache.SharedIndexInformer
 	Namespace                    cache.SharedIndexInformer
 	AzureIngressManagedLocation  cache.SharedInformer
 	AzureIngressProhibitedTarget cache.SharedInformer
+	AzureIngressRewrite          cache.SharedInformer
 	IstioGateway                 cache.SharedIndexInformer
 	IstioVirtualService          cache.SharedIndexInformer
+	IstioDestinationRule         cache.SharedIndexInformer
+	GatewayClass                 cache.SharedIndexInformer
+	Gateway                      cache.SharedIndexInformer
+	HTTPRoute                    cache.SharedIndexInformer
 }
 
 // CacheCollection : all the listers from the informers.
 type CacheCollection struct {
+	ConfigMap                    cache.Store
 	Endpoints                    cache.Store
 	Ingress                      cache.Store
+	IngressClass                 cache.Store
 	Pods                         cache.Store
 	Secret                       cache.Store
 	Service                      cache.Store
 	Namespaces                   cache.Store
 	AzureIngressManagedLocation  cache.Store
 	AzureIngressProhibitedTarget cache.Store
+	AzureIngressRewrite          cache.Store
 	IstioGateway                 cache.Store
 	IstioVirtualService          cache.Store
+	IstioDestinationRule         cache.Store
+	GatewayClass                 cache.Store
+	Gateway                      cache.Store
+	HTTPRoute                    cache.Store
 }
 
 // Context : cache and listener for k8s resources.
 type Context struct {
 	// k8s Clients
-	kubeClient     kubernetes.Interface
-	crdClient      versioned.Interface
-	istioCrdClient istio_versioned.Interface
+	kubeClient       kubernetes.Interface
+	crdClient        versioned.Interface
+	istioCrdClient   istio_versioned.Interface
+	gatewayCrdClient gateway_versioned.Interface
+
+	// isNetworkingV1Supported is true when the API server serves networking.k8s.io/v1 Ingress
+	isNetworkingV1Supported bool
 
 	informers              *InformerCollection
 	Caches                 *CacheCollection
@@ -0,0 +1,40 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package metricstore
+
+import (
+	"net/http"
+	"time"
+)
+
+// FakeMetricStore is a MetricStore, which discards all metrics.
+type FakeMetricStore struct{}
+
+// NewFakeMetricStore returns a MetricStore, which discards all metrics.
+func NewFakeMetricStore() MetricStore {
+	return FakeMetricStore{}
+}
+
+// Handler serves no metrics.
+func (FakeMetricStore) Handler() http.Handler {
+	return http.NotFoundHandler()
+}
+
+// ObserveArmAPICall discards the metric.
+func (FakeMetricStore) ObserveArmAPICall(method string, statusCode int, err error, duration time.Duration) {
+}
+
+// IncConfigUnchangedCounter discards the metric.
+func (FakeMetricStore) IncConfigUnchangedCounter() {}
+
+// IncSkippedEventCounter discards the metric.
+func (FakeMetricStore) IncSkippedEventCounter(resource string) {}
+
+// IncCertificateConversionFailureCounter discards the metric.
+func (FakeMetricStore) IncCertificateConversionFailureCounter() {}
+
+// AddPrunedIngressCounter discards the metric.
+func (FakeMetricStore) AddPrunedIngressCounter(reason string, count int) {}
@@ -0,0 +1,181 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package metricstore
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	namespace = "agic"
+
+	methodLabel     = "method"
+	statusCodeLabel = "status_code"
+	resourceLabel   = "resource"
+	reasonLabel     = "reason"
+	queueLabel      = "queue"
+)
+
+// AGICMetricStore records AGIC metrics in a Prometheus registry.
+type AGICMetricStore struct {
+	registry *prometheus.Registry
+
+	armAPICallDuration          *prometheus.HistogramVec
+	armAPICallCounter           *prometheus.CounterVec
+	armAPICallFailureCounter    *prometheus.CounterVec
+	configUnchangedCounter      prometheus.Counter
+	skippedEventCounter         *prometheus.CounterVec
+	certificateConversionFailed prometheus.Counter
+	prunedIngressCounter        *prometheus.CounterVec
+	workQueueDepth              *prometheus.GaugeVec
+}
+
+// NewMetricStore creates a MetricStore backed by a new Prometheus registry.
+func NewMetricStore() *AGICMetricStore {
+	ms := &AGICMetricStore{
+		registry: prometheus.NewRegistry(),
+
+		armAPICallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "arm_api_call_duration_seconds",
+			Help:      "Latency of calls to Azure Resource Manager for the App Gateway.",
+			Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 20, 40, 60, 120, 300},
+		}, []string{methodLabel}),
+		armAPICallCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "arm_api_calls_total",
+			Help:      "Number of calls to Azure Resource Manager for the App Gateway by HTTP status code.",
+		}, []string{methodLabel, statusCodeLabel}),
+		armAPICallFailureCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "arm_api_call_failures_total",
+			Help:      "Number of failed calls to Azure Resource Manager for the App Gateway by HTTP status code; 0 means no response was received.",
+		}, []string{methodLabel, statusCodeLabel}),
+		configUnchangedCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "config_unchanged_total",
+			Help:      "Number of reconciles, which skipped updating the App Gateway as the generated config did not change.",
+		}),
+		skippedEventCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "skipped_events_total",
+			Help:      "Number of Kubernetes events, which were not processed as they are not relevant to any Ingress.",
+		}, []string{resourceLabel}),
+		certificateConversionFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "certificate_conversion_failures_total",
+			Help:      "Number of Kubernetes secrets, which could not be converted to an App Gateway certificate.",
+		}),
+		prunedIngressCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pruned_ingresses_total",
+			Help:      "Number of Ingresses dropped from the App Gateway config by the reason they were dropped.",
+		}, []string{reasonLabel}),
+		workQueueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workqueue_depth",
+			Help:      "Number of Kubernetes events waiting to be processed.",
+		}, []string{queueLabel}),
+	}
+
+	ms.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		ms.armAPICallDuration,
+		ms.armAPICallCounter,
+		ms.armAPICallFailureCounter,
+		ms.configUnchangedCounter,
+		ms.skippedEventCounter,
+		ms.certificateConversionFailed,
+		ms.prunedIngressCounter,
+		ms.workQueueDepth,
+	)
+
+	return ms
+}
+
+// Handler serves the collected metrics.
+func (ms *AGICMetricStore) Handler() http.Handler {
+	return promhttp.HandlerFor(ms.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveArmAPICall records the latency and the HTTP status code of a call to Azure Resource Manager.
+func (ms *AGICMetricStore) ObserveArmAPICall(method string, statusCode int, err error, duration time.Duration) {
+	code := strconv.Itoa(statusCode)
+	ms.armAPICallDuration.WithLabelValues(method).Observe(duration.Seconds())
+	ms.armAPICallCounter.WithLabelValues(method, code).Inc()
+	if err != nil {
+		ms.armAPICallFailureCounter.WithLabelValues(method, code).Inc()
+	}
+}
+
+// IncConfigUnchangedCounter counts reconciles where the App Gateway PUT was skipped.
+func (ms *AGICMetricStore) IncConfigUnchangedCounter() {
+	ms.configUnchangedCounter.Inc()
+}
+
+// IncSkippedEventCounter counts Kubernetes events, which were not processed.
+func (ms *AGICMetricStore) IncSkippedEventCounter(resource string) {
+	ms.skippedEventCounter.WithLabelValues(resource).Inc()
+}
+
+// IncCertificateConversionFailureCounter counts Kubernetes secrets, which could not be converted to a certificate.
+func (ms *AGICMetricStore) IncCertificateConversionFailureCounter() {
+	ms.certificateConversionFailed.Inc()
+}
+
+// AddPrunedIngressCounter counts Ingresses dropped by a prune function.
+func (ms *AGICMetricStore) AddPrunedIngressCounter(reason string, count int) {
+	ms.prunedIngressCounter.WithLabelValues(reason).Add(float64(count))
+}
+
+// NewDepthMetric fulfills the workqueue.MetricsProvider interface; it tracks the depth of the named work queue.
+func (ms *AGICMetricStore) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return ms.workQueueDepth.WithLabelValues(name)
+}
+
+// NewAddsMetric fulfills the workqueue.MetricsProvider interface; not collected.
+func (ms *AGICMetricStore) NewAddsMetric(name string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+// NewLatencyMetric fulfills the workqueue.MetricsProvider interface; not collected.
+func (ms *AGICMetricStore) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return noopMetric{}
+}
+
+// NewWorkDurationMetric fulfills the workqueue.MetricsProvider interface; not collected.
+func (ms *AGICMetricStore) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return noopMetric{}
+}
+
+// NewUnfinishedWorkSecondsMetric fulfills the workqueue.MetricsProvider interface; not collected.
+func (ms *AGICMetricStore) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+// NewLongestRunningProcessorSecondsMetric fulfills the workqueue.MetricsProvider interface; not collected.
+func (ms *AGICMetricStore) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopMetric{}
+}
+
+// NewRetriesMetric fulfills the workqueue.MetricsProvider interface; not collected.
+func (ms *AGICMetricStore) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+type noopMetric struct{}
+
+func (noopMetric) Inc()            {}
+func (noopMetric) Dec()            {}
+func (noopMetric) Set(float64)     {}
+func (noopMetric) Observe(float64) {}
@@ -0,0 +1,80 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package metricstore
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var _ = Describe("Test MetricStore", func() {
+	var ms *AGICMetricStore
+
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		ms.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		body, err := ioutil.ReadAll(recorder.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return string(body)
+	}
+
+	BeforeEach(func() {
+		ms = NewMetricStore()
+	})
+
+	Context("ensure ARM API calls are recorded", func() {
+		It("should count calls and failures by status code", func() {
+			ms.ObserveArmAPICall("GET", http.StatusOK, nil, 2*time.Second)
+			ms.ObserveArmAPICall("PUT", http.StatusBadRequest, errors.New("bad request"), time.Second)
+			ms.ObserveArmAPICall("PUT", 0, errors.New("no response"), time.Second)
+
+			metrics := scrape()
+			Expect(metrics).To(ContainSubstring(`agic_arm_api_calls_total{method="GET",status_code="200"} 1`))
+			Expect(metrics).To(ContainSubstring(`agic_arm_api_calls_total{method="PUT",status_code="400"} 1`))
+			Expect(metrics).To(ContainSubstring(`agic_arm_api_call_failures_total{method="PUT",status_code="400"} 1`))
+			Expect(metrics).To(ContainSubstring(`agic_arm_api_call_failures_total{method="PUT",status_code="0"} 1`))
+			Expect(metrics).ToNot(ContainSubstring(`agic_arm_api_call_failures_total{method="GET"`))
+			Expect(metrics).To(ContainSubstring(`agic_arm_api_call_duration_seconds_count{method="GET"} 1`))
+			Expect(metrics).To(ContainSubstring(`agic_arm_api_call_duration_seconds_sum{method="GET"} 2`))
+		})
+	})
+
+	Context("ensure reconcile counters are recorded", func() {
+		It("should expose all counters", func() {
+			ms.IncConfigUnchangedCounter()
+			ms.IncSkippedEventCounter("pod")
+			ms.IncSkippedEventCounter("pod")
+			ms.IncCertificateConversionFailureCounter()
+			ms.AddPrunedIngressCounter("no-private-ip", 3)
+
+			metrics := scrape()
+			Expect(metrics).To(ContainSubstring(`agic_config_unchanged_total 1`))
+			Expect(metrics).To(ContainSubstring(`agic_skipped_events_total{resource="pod"} 2`))
+			Expect(metrics).To(ContainSubstring(`agic_certificate_conversion_failures_total 1`))
+			Expect(metrics).To(ContainSubstring(`agic_pruned_ingresses_total{reason="no-private-ip"} 3`))
+		})
+	})
+
+	Context("ensure the work queue depth is recorded", func() {
+		It("should track the depth through the workqueue.MetricsProvider interface", func() {
+			var provider workqueue.MetricsProvider = ms
+			depth := provider.NewDepthMetric("agic")
+			depth.Inc()
+			depth.Inc()
+			depth.Dec()
+
+			Expect(scrape()).To(ContainSubstring(`agic_workqueue_depth{queue="agic"} 1`))
+		})
+	})
+})
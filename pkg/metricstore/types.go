@@ -0,0 +1,33 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package metricstore
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricStore is the interface through which AGIC records Prometheus metrics.
+type MetricStore interface {
+	// Handler serves the collected metrics.
+	Handler() http.Handler
+
+	// ObserveArmAPICall records the latency and the HTTP status code of a call to Azure Resource Manager.
+	// A status code of 0 means that no response was received; a non-nil err counts the call as failed.
+	ObserveArmAPICall(method string, statusCode int, err error, duration time.Duration)
+
+	// IncConfigUnchangedCounter counts reconciles where the App Gateway PUT was skipped as the config did not change.
+	IncConfigUnchangedCounter()
+
+	// IncSkippedEventCounter counts Kubernetes events, which were not processed, by resource type.
+	IncSkippedEventCounter(resource string)
+
+	// IncCertificateConversionFailureCounter counts Kubernetes secrets, which could not be converted to a certificate.
+	IncCertificateConversionFailureCounter()
+
+	// AddPrunedIngressCounter counts Ingresses dropped by a prune function.
+	AddPrunedIngressCounter(reason string, count int)
+}
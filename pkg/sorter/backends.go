@@ -7,7 +7,7 @@ package sorter
 
 import (
 	"fmt"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 )
 
 // ByIPFQDN is a facility to sort slices of ApplicationGatewayBackendAddress by IP, FQDN
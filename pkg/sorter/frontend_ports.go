@@ -6,7 +6,7 @@
 package sorter
 
 import (
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 )
 
 // ByFrontendPortName is a facility to sort slices of ApplicationGatewayFrontendPort by Name
@@ -6,11 +6,11 @@
 package sorter
 
 import (
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 )
 
 // ByIngressUID is a facility to sort slices of Kubernetes Ingress by their UID
-type ByIngressUID []*v1beta1.Ingress
+type ByIngressUID []*networking.Ingress
 
 func (a ByIngressUID) Len() int      { return len(a) }
 func (a ByIngressUID) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
@@ -18,6 +18,6 @@ func (a ByIngressUID) Less(i, j int) bool {
 	return getIngressUID(a[i]) < getIngressUID(a[j])
 }
 
-func getIngressUID(ingress *v1beta1.Ingress) string {
+func getIngressUID(ingress *networking.Ingress) string {
 	return string(ingress.UID)
 }
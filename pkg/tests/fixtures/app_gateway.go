@@ -7,7 +7,7 @@ package fixtures
 
 import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 )
 
@@ -8,7 +8,7 @@ package fixtures
 import (
 	"github.com/Azure/go-autorest/autorest/to"
 
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 )
 
 const (
@@ -8,7 +8,7 @@ package fixtures
 import (
 	"encoding/base64"
 	"fmt"
-	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-09-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"strings"
 
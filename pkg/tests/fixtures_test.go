@@ -6,7 +6,7 @@
 package tests
 
 import (
-	"k8s.io/api/extensions/v1beta1"
+	networking "k8s.io/api/networking/v1"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
@@ -94,13 +94,13 @@ var _ = Describe("Test Fixture Object Factories", func() {
 	Context("Test NewIngressBackendFixture", func() {
 		It("should work", func() {
 			actual := NewIngressBackendFixture("service-name", int32(123))
-			Expect(actual.ServiceName).To(Equal("service-name"))
+			Expect(actual.Service.Name).To(Equal("service-name"))
 		})
 	})
 
 	Context("Test NewIngressRuleFixture", func() {
 		It("should work", func() {
-			actual := NewIngressRuleFixture("host", "urlPath", v1beta1.IngressBackend{})
+			actual := NewIngressRuleFixture("host", "urlPath", networking.IngressBackend{})
 			Expect(actual.Host).To(Equal("host"))
 		})
 	})
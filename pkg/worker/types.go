@@ -6,6 +6,11 @@
 package worker
 
 import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
@@ -15,8 +20,26 @@ type EventProcessor interface {
 	ShouldProcess(events.Event) (bool, *string)
 }
 
-// Worker listens on the eventChannel and runs the EventProcessor.Process
-// for each event.
+// Worker listens on the eventChannel, queues the events in a rate limited work queue
+// and runs the EventProcessor.Process for each queued event.
 type Worker struct {
 	EventProcessor
+
+	// MaxRetries is the number of times a failing event is retried before it is dropped.
+	// Defaults to defaultMaxRetries when zero.
+	MaxRetries int
+
+	// ResyncPeriod is the interval at which a Resync event is processed even when no events arrive.
+	// Zero disables the periodic resync.
+	ResyncPeriod time.Duration
+
+	// RateLimiter determines how long a failed event waits before it is retried.
+	// Defaults to an exponential backoff between defaultBaseDelay and defaultMaxDelay when nil.
+	RateLimiter workqueue.RateLimiter
+
+	queue workqueue.RateLimitingInterface
+
+	// pending holds the event the next reconcile processes; nil when no event arrived since the last one.
+	pending     *events.Event
+	pendingLock sync.Mutex
 }
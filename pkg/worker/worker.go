@@ -9,28 +9,43 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"k8s.io/client-go/util/workqueue"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
-const sleepOnErrorSeconds = 5
+const (
+	queueName = "agic"
 
-func drainChan(ch chan events.Event, defaultEvent events.Event) events.Event {
-	final := defaultEvent
-	glog.V(9).Infof("Draining %d events from work channel", len(ch))
-	for {
-		select {
-		case event := <-ch:
-			final = event
-		default:
-			return final
-		}
-	}
-}
+	// reconcileKey is the only key in the queue: every event reconciles the whole App Gateway, so the events, which
+	// arrive before a reconcile starts, collapse into one reconcile and share one retry budget.
+	reconcileKey = "reconcile"
+
+	defaultMaxRetries = 10
+	defaultBaseDelay  = 1 * time.Second
+	defaultMaxDelay   = 5 * time.Minute
+)
 
 // Run starts the worker which listens for events in eventChannel; stops when stopChannel is closed.
 func (w *Worker) Run(work chan events.Event, stopChannel chan struct{}) {
 	glog.V(1).Infoln("Worker started")
+
+	w.queue = workqueue.NewNamedRateLimitingQueue(w.rateLimiter(), queueName)
+	defer w.queue.ShutDown()
+
+	// A single consumer guarantees that App Gateway is never reconciled concurrently.
+	go func() {
+		for w.processNextItem() {
+		}
+	}()
+
+	var resync <-chan time.Time
+	if w.ResyncPeriod > 0 {
+		ticker := time.NewTicker(w.ResyncPeriod)
+		defer ticker.Stop()
+		resync = ticker.C
+	}
+
 	for {
 		select {
 		case event := <-work:
@@ -42,16 +57,83 @@ func (w *Worker) Run(work chan events.Event, stopChannel chan struct{}) {
 				}
 				continue
 			}
+			w.enqueue(event)
 
-			lastEvent := drainChan(work, event)
-
-			if err := w.Process(lastEvent); err != nil {
-				glog.Error("Processing event failed:", err)
-				time.Sleep(sleepOnErrorSeconds * time.Second)
-			}
+		case <-resync:
+			glog.V(5).Infof("Resync period of %s elapsed; queueing reconcile", w.ResyncPeriod)
+			w.enqueue(events.Event{Type: events.Resync})
 
 		case <-stopChannel:
-			break
+			glog.V(1).Infoln("Worker stopped")
+			return
 		}
 	}
 }
+
+// enqueue stores the event as the pending one and adds the reconcile key to the queue.
+// The events, which arrive before the reconcile starts, collapse into the last one; a Resync does not replace an event of an object.
+func (w *Worker) enqueue(event events.Event) {
+	w.pendingLock.Lock()
+	if w.pending == nil || event.Type != events.Resync {
+		w.pending = &event
+	}
+	w.pendingLock.Unlock()
+	w.queue.Add(reconcileKey)
+}
+
+// processNextItem blocks until the reconcile key is available in the queue and processes the pending event.
+// Returns false when the queue has been shut down.
+func (w *Worker) processNextItem() bool {
+	item, shutdown := w.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer w.queue.Done(item)
+
+	w.pendingLock.Lock()
+	event := w.pending
+	w.pending = nil
+	w.pendingLock.Unlock()
+
+	if event == nil {
+		w.queue.Forget(item)
+		return true
+	}
+
+	err := w.Process(*event)
+	if err == nil {
+		w.queue.Forget(item)
+		return true
+	}
+
+	if w.queue.NumRequeues(item) >= w.maxRetries() {
+		glog.Errorf("Reconciling App Gateway failed %d times; dropping event: %s", w.queue.NumRequeues(item)+1, err)
+		w.queue.Forget(item)
+		return true
+	}
+
+	glog.Errorf("Reconciling App Gateway failed; will retry: %s", err)
+
+	// Retry with the failed event unless a newer event arrived in the meantime.
+	w.pendingLock.Lock()
+	if w.pending == nil {
+		w.pending = event
+	}
+	w.pendingLock.Unlock()
+	w.queue.AddRateLimited(item)
+	return true
+}
+
+func (w *Worker) maxRetries() int {
+	if w.MaxRetries > 0 {
+		return w.MaxRetries
+	}
+	return defaultMaxRetries
+}
+
+func (w *Worker) rateLimiter() workqueue.RateLimiter {
+	if w.RateLimiter != nil {
+		return w.RateLimiter
+	}
+	return workqueue.NewItemExponentialFailureRateLimiter(defaultBaseDelay, defaultMaxDelay)
+}
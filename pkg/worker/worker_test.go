@@ -6,10 +6,13 @@
 package worker
 
 import (
+	"errors"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
@@ -18,6 +21,8 @@ import (
 var _ = Describe("Worker Test", func() {
 	var stopChannel chan struct{}
 	var work chan events.Event
+	errProcess := errors.New("failed processing")
+	fastRateLimiter := workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 10*time.Millisecond)
 
 	BeforeEach(func() {
 		stopChannel = make(chan struct{})
@@ -25,7 +30,11 @@ var _ = Describe("Worker Test", func() {
 	})
 
 	AfterEach(func() {
-		close(stopChannel)
+		select {
+		case <-stopChannel:
+		default:
+			close(stopChannel)
+		}
 	})
 
 	Context("Check that worker executes the process", func() {
@@ -59,39 +68,105 @@ var _ = Describe("Worker Test", func() {
 		})
 	})
 
-	Context("Verify that drainChan works", func() {
-		It("Should drain the channel and return the last element", func() {
-			buffSize := 10
-			counter := int64(0)
-
-			// Create and fill the channel
-			work := make(chan events.Event, buffSize)
-		Fill:
-			for {
-				select {
-				case work <- events.Event{}:
-					counter++
-				default:
-					break Fill
+	Context("Check that worker retries failed events", func() {
+		It("Should requeue the event until process succeeds", func() {
+			var calls int32
+			eventProcessor := NewFakeProcessor(func(events.Event) error {
+				if atomic.AddInt32(&calls, 1) < 3 {
+					return errProcess
 				}
+				return nil
+			})
+			worker := Worker{
+				EventProcessor: eventProcessor,
+				RateLimiter:    fastRateLimiter,
+			}
+			go worker.Run(work, stopChannel)
+
+			work <- events.Event{Type: events.Create, Value: tests.NewIngressFixture()}
+
+			Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(3)))
+			Consistently(func() int32 { return atomic.LoadInt32(&calls) }, 100*time.Millisecond).Should(Equal(int32(3)))
+		})
+
+		It("Should drop the event after MaxRetries", func() {
+			var calls int32
+			eventProcessor := NewFakeProcessor(func(events.Event) error {
+				atomic.AddInt32(&calls, 1)
+				return errProcess
+			})
+			worker := Worker{
+				EventProcessor: eventProcessor,
+				RateLimiter:    fastRateLimiter,
+				MaxRetries:     2,
+			}
+			go worker.Run(work, stopChannel)
+
+			work <- events.Event{Type: events.Create, Value: tests.NewIngressFixture()}
+
+			// The first attempt and 2 retries
+			Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(3)))
+			Consistently(func() int32 { return atomic.LoadInt32(&calls) }, 100*time.Millisecond).Should(Equal(int32(3)))
+		})
+	})
+
+	Context("Check that worker resyncs periodically", func() {
+		It("Should process a Resync event without any incoming events", func() {
+			resyncs := make(chan events.Event, 10)
+			eventProcessor := NewFakeProcessor(func(event events.Event) error {
+				resyncs <- event
+				return nil
+			})
+			worker := Worker{
+				EventProcessor: eventProcessor,
+				ResyncPeriod:   10 * time.Millisecond,
 			}
-			Expect(counter).To(Equal(int64(len(work))))
-			def := events.Event{}
-			lastEvent := drainChan(work, def)
-			Expect(len(work)).To(Equal(0))
-			Expect(lastEvent).To(Equal(events.Event{}))
+			go worker.Run(work, stopChannel)
+
+			var event events.Event
+			Eventually(resyncs).Should(Receive(&event))
+			Expect(event.Type).To(Equal(events.Resync))
 		})
 	})
 
-	Context("Verify that drainChan works", func() {
-		It("Should drain the channel and return the default element", func() {
-			buffSize := 10
+	Context("Check that worker stops", func() {
+		It("Should return from Run when the stop channel is closed", func() {
+			worker := Worker{
+				EventProcessor: NewFakeProcessor(func(events.Event) error { return nil }),
+			}
+			done := make(chan struct{})
+			go func() {
+				worker.Run(work, stopChannel)
+				close(done)
+			}()
+
+			close(stopChannel)
+			Eventually(done).Should(BeClosed())
+		})
+	})
+
+	Context("Check that events collapse into one reconcile", func() {
+		It("Should process a burst of events for different objects once", func() {
+			var processed []events.Event
+			worker := Worker{
+				EventProcessor: NewFakeProcessor(func(event events.Event) error {
+					processed = append(processed, event)
+					return nil
+				}),
+			}
+			worker.queue = workqueue.NewNamedRateLimitingQueue(worker.rateLimiter(), queueName)
+			defer worker.queue.ShutDown()
+
+			for _, name := range []string{"a", "b", "c"} {
+				worker.enqueue(events.Event{Type: events.Update, Value: tests.NewIngressTestFixture("ns", name)})
+			}
+			worker.enqueue(events.Event{Type: events.Resync})
+			Expect(worker.queue.Len()).To(Equal(1))
 
-			// Keep the channel empty
-			work := make(chan events.Event, buffSize)
-			def := events.Event{}
-			lastEvent := drainChan(work, def)
-			Expect(lastEvent).To(Equal(def))
+			Expect(worker.processNextItem()).To(BeTrue())
+			Expect(processed).To(HaveLen(1))
+			Expect(processed[0].Value).To(Equal(tests.NewIngressTestFixture("ns", "c")))
+			Expect(worker.queue.Len()).To(Equal(0))
 		})
 	})
 })